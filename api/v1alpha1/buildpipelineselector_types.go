@@ -28,10 +28,12 @@ import (
 //
 //	language: java
 //	projectType: spring,quarkus
+//	projectFiles: pom.xml,build.gradle
 //	annotations:
 //	   builder: gradle,maven
 //
 // which means that language is 'java' AND (project type is 'spring' OR 'quarkus') AND
+// ('pom.xml' OR 'build.gradle' file is present in the component sources) AND
 // annotation 'builder' is present with value 'gradle' OR 'maven'.
 type WhenCondition struct {
 	// Defines component language to match, e.g. 'java'.
@@ -50,6 +52,11 @@ type WhenCondition struct {
 	// +kubebuilder:validation:Optional
 	DockerfileRequired *bool `json:"dockerfile,omitempty"`
 
+	// Defines files which presence identifies the project type, e.g. 'package.json', 'pom.xml,build.gradle' or 'go.mod'.
+	// The condition is met if any of the files is present in the component context directory of the git repository.
+	// +kubebuilder:validation:Optional
+	ProjectFiles string `json:"projectFiles,omitempty"`
+
 	// Defines list of allowed component names to match, e.g. 'my-component'.
 	// The value to compare with is taken from component.metadata.name field.
 	// +kubebuilder:validation:Optional
@@ -80,6 +87,8 @@ type PipelineSelector struct {
 	Name string `json:"name,omitempty"`
 
 	// Build Pipeline to use if the selector conditions are met.
+	// Supported are the Tekton bundles resolver (with 'name' and 'bundle' params)
+	// and the Tekton git resolver (with 'url', 'revision' and 'pathInRepo' params).
 	// +kubebuilder:validation:Required
 	PipelineRef tektonapi.PipelineRef `json:"pipelineRef"`
 
@@ -93,17 +102,44 @@ type PipelineSelector struct {
 	// If the section is omitted, then the condition is considered true (usually used for fallback condition).
 	// +kubebuilder:validation:Optional
 	WhenConditions WhenCondition `json:"when,omitempty"`
+
+	// Priority of the selector item, 0 if omitted.
+	// If several items match the component, the one with the highest priority is used.
+	// Items with the same priority are evaluated in the order they are listed.
+	// +kubebuilder:validation:Optional
+	Priority int32 `json:"priority,omitempty"`
 }
 
+// BuildPipelineSelectorMode describes how the BuildPipelineSelector relates to the less specific ones.
+type BuildPipelineSelectorMode string
+
+const (
+	// The less specific BuildPipelineSelectors are evaluated if no item of the BuildPipelineSelector matches the component.
+	BuildPipelineSelectorModeExtend BuildPipelineSelectorMode = "Extend"
+	// The less specific BuildPipelineSelectors are never evaluated, the BuildPipelineSelector replaces them.
+	BuildPipelineSelectorModeOverride BuildPipelineSelectorMode = "Override"
+)
+
 // BuildPipelineSelectorSpec defines the desired state of BuildPipelineSelector
 type BuildPipelineSelectorSpec struct {
 	// Defines chain of pipeline selectors.
-	// The first matching item is used.
+	// The matching item with the highest priority is used, the first one of them if several items have the same priority.
 	// +kubebuilder:validation:Required
 	Selectors []PipelineSelector `json:"selectors"`
+
+	// Defines how the selector relates to the less specific ones. BuildPipelineSelectors are evaluated from the most specific:
+	// the one named after the Application, the 'build-pipeline-selector' one in the namespace of the component
+	// and the global 'build-pipeline-selector' one in the build-service namespace.
+	// 'Extend' falls back to the less specific selectors if no item matches the component.
+	// 'Override' uses only this selector, so a namespace can replace the global default pipeline without falling back to it.
+	// Defaults to 'Extend'.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=Extend;Override
+	Mode BuildPipelineSelectorMode `json:"mode,omitempty"`
 }
 
 //+kubebuilder:object:root=true
+//+kubebuilder:storageversion
 
 // BuildPipelineSelector is the Schema for the BuildPipelineSelectors API
 type BuildPipelineSelector struct {
@@ -0,0 +1,231 @@
+/*
+Copyright 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// BuildServiceConfigName is the name of the BuildServiceConfig object used by Build Service.
+// Objects with other names are ignored.
+const BuildServiceConfigName = "build-service-config"
+
+// BuildServiceRenovateConfig defines the operator wide defaults of renovate Jobs.
+// RenovateTektonConfig takes precedence over the values.
+type BuildServiceRenovateConfig struct {
+	// Renovate image to run, e.g. 'quay.io/redhat-appstudio/renovate:v37.74.1'.
+	// Overrides RENOVATE_IMAGE environment variable.
+	// +kubebuilder:validation:Optional
+	Image string `json:"image,omitempty"`
+
+	// Regular expressions to match Tekton references to update. The expressions must not contain commas.
+	// Overrides RENOVATE_PATTERN environment variable.
+	// +kubebuilder:validation:Optional
+	// +listType=atomic
+	MatchPatterns []string `json:"matchPatterns,omitempty"`
+
+	// Number of renovate tasks, i.e. GitHub App installations or sets of repositories with the same credentials, processed by one Job.
+	// Overrides RENOVATE_INSTALLATIONS_PER_JOB environment variable.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=99
+	InstallationsPerJob *int32 `json:"installationsPerJob,omitempty"`
+
+	// Maximum number of repositories processed by one Job, 0 means no limit.
+	// Overrides RENOVATE_REPOSITORIES_PER_JOB environment variable.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	RepositoriesPerJob *int32 `json:"repositoriesPerJob,omitempty"`
+
+	// Maximum number of simultaneously running renovate Jobs, 0 means no limit.
+	// Overrides RENOVATE_MAX_PARALLEL_JOBS environment variable.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	MaxParallelJobs *int32 `json:"maxParallelJobs,omitempty"`
+
+	// Whether renovate runs in Jobs or in Tekton TaskRuns.
+	// Overrides RENOVATE_EXECUTION_MODE environment variable.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=Job;TaskRun
+	ExecutionMode string `json:"executionMode,omitempty"`
+
+	// Interval between periodic renovate sweeps, e.g. '6h'. Must be at least 10 minutes.
+	// Overrides RENOVATE_SWEEP_INTERVAL environment variable.
+	// +kubebuilder:validation:Optional
+	SweepInterval *metav1.Duration `json:"sweepInterval,omitempty"`
+
+	// Minimum interval between the last renovate sweep and a sweep triggered by a change of Components or GitHub App secrets, e.g. '1h'.
+	// Overrides RENOVATE_TRIGGERED_SWEEP_MIN_INTERVAL environment variable.
+	// +kubebuilder:validation:Optional
+	TriggeredSweepMinInterval *metav1.Duration `json:"triggeredSweepMinInterval,omitempty"`
+
+	// Image of the CronJob which triggers scheduled renovate sweeps.
+	// Overrides RENOVATE_SWEEP_TRIGGER_IMAGE environment variable.
+	// +kubebuilder:validation:Optional
+	SweepTriggerImage string `json:"sweepTriggerImage,omitempty"`
+
+	// Image used to verify signatures of updated bundles.
+	// Overrides RENOVATE_COSIGN_IMAGE environment variable.
+	// +kubebuilder:validation:Optional
+	CosignImage string `json:"cosignImage,omitempty"`
+}
+
+// BuildServicePipelinesAsCodeConfig defines the operator wide Pipelines as Code settings.
+type BuildServicePipelinesAsCodeConfig struct {
+	// URL of the Pipelines as Code controller used as the target of git provider webhooks.
+	// Overrides PAC_WEBHOOK_URL environment variable.
+	// +kubebuilder:validation:Optional
+	WebhookURL string `json:"webhookURL,omitempty"`
+
+	// Disables TLS verification of the webhooks, e.g. on development clusters with self-signed certificates.
+	// Overrides PAC_WEBHOOK_INSECURE_SSL environment variable.
+	// +kubebuilder:validation:Optional
+	WebhookInsecureSSL *bool `json:"webhookInsecureSSL,omitempty"`
+
+	// Period after which the webhook secret of webhook based Components is rotated, e.g. '720h'. 0 disables the rotation.
+	// Overrides PAC_WEBHOOK_SECRET_ROTATION_PERIOD environment variable.
+	// +kubebuilder:validation:Optional
+	WebhookSecretRotationPeriod *metav1.Duration `json:"webhookSecretRotationPeriod,omitempty"`
+
+	// How Pipelines as Code configuration is delivered into the Component repositories.
+	// Component annotation takes precedence. Overrides PAC_PROVISION_MODE environment variable.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=pull-request;direct-push
+	ProvisionMode string `json:"provisionMode,omitempty"`
+
+	// Allows Components to request direct push of the configuration into the base branch with the provision mode annotation.
+	// Otherwise such Components get the configuration via pull request. Not required if direct push is the provision mode.
+	// Overrides PAC_DIRECT_PUSH_ALLOWED environment variable, defaults to false.
+	// +kubebuilder:validation:Optional
+	DirectPushAllowed *bool `json:"directPushAllowed,omitempty"`
+
+	// Expiration of the images built on pull requests, e.g. '5d'.
+	// Overrides IMAGE_TAG_ON_PR_EXPIRATION environment variable.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=`^[1-9][0-9]{0,2}[hdw]$`
+	PullRequestImageExpiration string `json:"pullRequestImageExpiration,omitempty"`
+
+	// Hosts of self-hosted Gitea instances, e.g. 'gitea.example.com'.
+	// Overrides GITEA_HOSTS environment variable.
+	// +kubebuilder:validation:Optional
+	// +listType=atomic
+	GiteaHosts []string `json:"giteaHosts,omitempty"`
+
+	// URL of GitHub Enterprise server, e.g. 'https://github.example.com'.
+	// Overrides GITHUB_ENTERPRISE_URL environment variable.
+	// +kubebuilder:validation:Optional
+	GithubEnterpriseURL string `json:"githubEnterpriseURL,omitempty"`
+
+	// Templates of the configuration merge request source branch, commit message and description.
+	// Component annotations take precedence. Override PAC_MERGE_REQUEST_BRANCH_TEMPLATE,
+	// PAC_MERGE_REQUEST_COMMIT_MESSAGE_TEMPLATE and PAC_MERGE_REQUEST_DESCRIPTION_TEMPLATE environment variables.
+	// +kubebuilder:validation:Optional
+	MergeRequestBranchTemplate string `json:"mergeRequestBranchTemplate,omitempty"`
+	// +kubebuilder:validation:Optional
+	MergeRequestCommitMessageTemplate string `json:"mergeRequestCommitMessageTemplate,omitempty"`
+	// +kubebuilder:validation:Optional
+	MergeRequestDescriptionTemplate string `json:"mergeRequestDescriptionTemplate,omitempty"`
+}
+
+// BuildServicePipelineRunRetentionConfig defines which completed build PipelineRuns of a Component are kept.
+type BuildServicePipelineRunRetentionConfig struct {
+	// Number of the latest succeeded build PipelineRuns to keep per Component and event type.
+	// Overrides PIPELINERUN_RETENTION_KEEP_SUCCEEDED environment variable.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	KeepSucceeded *int32 `json:"keepSucceeded,omitempty"`
+
+	// Number of the latest failed build PipelineRuns to keep per Component and event type.
+	// Overrides PIPELINERUN_RETENTION_KEEP_FAILED environment variable.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	KeepFailed *int32 `json:"keepFailed,omitempty"`
+
+	// Maximum age of completed build PipelineRuns, e.g. '168h'.
+	// Overrides PIPELINERUN_RETENTION_MAX_AGE environment variable.
+	// +kubebuilder:validation:Optional
+	MaxAge *metav1.Duration `json:"maxAge,omitempty"`
+}
+
+// BuildServiceConfigSpec defines the desired configuration of Build Service.
+// Unset fields fall back to the environment variables of the Build Service and then to the built-in defaults.
+// Changes are applied without restart of the Build Service.
+type BuildServiceConfigSpec struct {
+	// Operator wide defaults of renovate Jobs.
+	// +kubebuilder:validation:Optional
+	Renovate *BuildServiceRenovateConfig `json:"renovate,omitempty"`
+
+	// Pipelines as Code settings.
+	// +kubebuilder:validation:Optional
+	PipelinesAsCode *BuildServicePipelinesAsCodeConfig `json:"pipelinesAsCode,omitempty"`
+
+	// Retention of completed build PipelineRuns.
+	// +kubebuilder:validation:Optional
+	PipelineRunRetention *BuildServicePipelineRunRetentionConfig `json:"pipelineRunRetention,omitempty"`
+
+	// Period after which image registry secrets of Components are rotated, e.g. '720h'. 0 disables the rotation.
+	// Overrides IMAGE_REGISTRY_SECRET_ROTATION_PERIOD environment variable.
+	// +kubebuilder:validation:Optional
+	ImageRegistrySecretRotationPeriod *metav1.Duration `json:"imageRegistrySecretRotationPeriod,omitempty"`
+
+	// Enables or disables Build Service capabilities by feature name: Renovater, Nudging, MultiArch or GitLab.
+	// Overrides --feature-gates flag of the Build Service.
+	// +kubebuilder:validation:Optional
+	FeatureGates map[string]bool `json:"featureGates,omitempty"`
+}
+
+const (
+	// Condition reporting whether the configuration is valid and used by Build Service.
+	// Invalid configuration isn't applied, the previously applied one is kept.
+	BuildServiceConfigAppliedConditionType = "Applied"
+)
+
+// BuildServiceConfigStatus defines the observed state of BuildServiceConfig
+type BuildServiceConfigStatus struct {
+	// Conditions of the configuration.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:scope=Cluster
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Applied",type=string,JSONPath=`.status.conditions[?(@.type=="Applied")].status`
+
+// BuildServiceConfig is the Schema for the BuildServiceConfigs API
+type BuildServiceConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   BuildServiceConfigSpec   `json:"spec,omitempty"`
+	Status BuildServiceConfigStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// BuildServiceConfigList contains a list of BuildServiceConfig
+type BuildServiceConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []BuildServiceConfig `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&BuildServiceConfig{}, &BuildServiceConfigList{})
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RenovateRunOutcome describes the result of a renovate update of a repository.
+type RenovateRunOutcome string
+
+const (
+	// The repository is being updated by a renovate Job.
+	RenovateRunOutcomeRunning RenovateRunOutcome = "Running"
+	// The renovate Job updating the repository finished successfully.
+	RenovateRunOutcomeSucceeded RenovateRunOutcome = "Succeeded"
+	// The renovate Job updating the repository failed.
+	RenovateRunOutcomeFailed RenovateRunOutcome = "Failed"
+	// The repository was not updated, see the message for the reason.
+	RenovateRunOutcomeSkipped RenovateRunOutcome = "Skipped"
+)
+
+// RenovateRunSpec defines the repositories to update.
+type RenovateRunSpec struct {
+	// Git URLs of the Component repositories to update, e.g. 'https://github.com/org/repo'.
+	// Only repositories of Components in the RenovateRun namespace are updated.
+	// If empty, repositories of all Components in the namespace are updated.
+	// +kubebuilder:validation:Optional
+	Repositories []string `json:"repositories,omitempty"`
+}
+
+// RenovateRunRepositoryStatus defines the outcome of the update of a single repository.
+type RenovateRunRepositoryStatus struct {
+	// Git URL of the repository.
+	Url string `json:"url"`
+
+	// Name of the renovate Job which updates the repository.
+	// +optional
+	Job string `json:"job,omitempty"`
+
+	// Outcome of the update.
+	Outcome RenovateRunOutcome `json:"outcome"`
+
+	// Human readable details of the outcome.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// Pull requests created by the renovate Job in the repository.
+	// +optional
+	PullRequests []RenovateRunPullRequest `json:"pullRequests,omitempty"`
+}
+
+// RenovateRunPullRequest defines a pull request created by renovate.
+type RenovateRunPullRequest struct {
+	// Number of the pull request in the repository.
+	Number int `json:"number"`
+
+	// Name of the branch with the updates.
+	// +optional
+	Branch string `json:"branch,omitempty"`
+}
+
+// RenovateRunStatus defines the observed state of RenovateRun
+type RenovateRunStatus struct {
+	// Time when the renovate Jobs were created.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// Time when all renovate Jobs finished.
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// Names of the renovate Jobs created in the Build Service namespace.
+	// +optional
+	Jobs []string `json:"jobs,omitempty"`
+
+	// Per repository outcome of the run.
+	// +optional
+	Repositories []RenovateRunRepositoryStatus `json:"repositories,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Started",type=date,JSONPath=`.status.startTime`
+//+kubebuilder:printcolumn:name="Completed",type=date,JSONPath=`.status.completionTime`
+
+// RenovateRun is the Schema for the RenovateRuns API.
+// It requests an immediate renovate update of Component repositories in its namespace.
+type RenovateRun struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RenovateRunSpec   `json:"spec,omitempty"`
+	Status RenovateRunStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// RenovateRunList contains a list of RenovateRun
+type RenovateRunList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RenovateRun `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&RenovateRun{}, &RenovateRunList{})
+}
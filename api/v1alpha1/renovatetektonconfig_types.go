@@ -0,0 +1,529 @@
+/*
+Copyright 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RenovateTektonConfigName is the name of the RenovateTektonConfig object used by Build Service.
+// Objects with other names are ignored.
+const RenovateTektonConfigName = "renovate-tekton-config"
+
+// RenovateProxy defines the HTTP proxy used by renovate.
+type RenovateProxy struct {
+	// Proxy for HTTP requests, e.g. 'http://proxy.example.com:3128'.
+	// +kubebuilder:validation:Optional
+	HttpProxy string `json:"httpProxy,omitempty"`
+
+	// Proxy for HTTPS requests.
+	// +kubebuilder:validation:Optional
+	HttpsProxy string `json:"httpsProxy,omitempty"`
+
+	// Comma separated list of hosts and domains which are accessed directly.
+	// +kubebuilder:validation:Optional
+	NoProxy string `json:"noProxy,omitempty"`
+}
+
+// RenovateJobSettings defines settings of the renovate Job pods.
+type RenovateJobSettings struct {
+	// Number of seconds a finished renovate Job is kept before it is deleted.
+	// Defaults to 24 hours.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	TTLSecondsAfterFinished *int32 `json:"ttlSecondsAfterFinished,omitempty"`
+
+	// Number of retries before a renovate Job is considered failed.
+	// Defaults to 1.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	BackoffLimit *int32 `json:"backoffLimit,omitempty"`
+
+	// Number of times a failed renovate Job of a sweep is created again, e.g. after a transient git provider outage.
+	// Jobs requested by RenovateRuns are not retried. Overrides RENOVATE_RETRY_LIMIT environment variable, defaults to 2.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=10
+	RetryLimit *int32 `json:"retryLimit,omitempty"`
+
+	// Delay before a failed renovate Job is created again, doubled with each retry of the same Job, e.g. '5m'.
+	// Retried Jobs reuse the tokens of the sweep, so the last retry must start within 45 minutes of the first attempt.
+	// Overrides RENOVATE_RETRY_BACKOFF environment variable, defaults to 5 minutes.
+	// +kubebuilder:validation:Optional
+	RetryBackoff *metav1.Duration `json:"retryBackoff,omitempty"`
+
+	// Number of installations of a renovate Job processed in parallel pods, each pod runs renovate for one installation,
+	// so a failure of one installation doesn't delay the others. The backoff limit is multiplied by the number of installations.
+	// Values over 1 are only supported by Jobs, TaskRuns always process the installations sequentially.
+	// Overrides RENOVATE_PARALLELISM environment variable, defaults to 1.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	Parallelism *int32 `json:"parallelism,omitempty"`
+
+	// Compute resources of the renovate container. Set values override the defaults and
+	// RENOVATE_CPU_REQUEST, RENOVATE_MEMORY_REQUEST, RENOVATE_CPU_LIMIT and RENOVATE_MEMORY_LIMIT environment variables,
+	// other values are kept.
+	// +kubebuilder:validation:Optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// Node selector of the renovate Job pods, e.g. to run them on a dedicated infra node pool.
+	// +kubebuilder:validation:Optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Tolerations of the renovate Job pods.
+	// +kubebuilder:validation:Optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// Affinity of the renovate Job pods.
+	// +kubebuilder:validation:Optional
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+
+	// Priority class of the renovate Job pods.
+	// Overrides RENOVATE_PRIORITY_CLASS_NAME environment variable.
+	// +kubebuilder:validation:Optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+
+	// Service account of the renovate Job pods. Defaults to the service account deployed together with the operator,
+	// which has no permissions and can be patched with the imagePullSecrets of a private renovate image registry.
+	// In 'TaskRun' execution mode the default service account of the Tekton configuration is used if not set.
+	// Overrides RENOVATE_SERVICE_ACCOUNT environment variable.
+	// +kubebuilder:validation:Optional
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+
+	// Secrets used to pull the renovate image, in addition to the imagePullSecrets of the service account.
+	// The Secrets must exist in the namespace the renovate Jobs or TaskRuns run in.
+	// +kubebuilder:validation:Optional
+	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+
+	// Maximum number of renovate Jobs running at the same time, 0 means no limit.
+	// Jobs over the limit are queued and created once the earlier Jobs finish.
+	// Overrides RENOVATE_MAX_PARALLEL_JOBS environment variable.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	MaxParallelJobs *int `json:"maxParallelJobs,omitempty"`
+
+	// HTTP proxy of the renovate container. Set values override HTTP_PROXY, HTTPS_PROXY and NO_PROXY
+	// environment variables of the operator, other values are kept.
+	// +kubebuilder:validation:Optional
+	Proxy *RenovateProxy `json:"proxy,omitempty"`
+
+	// Name of the ConfigMap with additional trusted CA certificates under 'ca-bundle.crt' key, e.g. private CAs
+	// of self-hosted git servers and registries. The ConfigMap must exist in the namespace of the renovate pods.
+	// On OpenShift, the ConfigMap can be filled by 'config.openshift.io/inject-trusted-cabundle' label.
+	// Overrides RENOVATE_TRUSTED_CA_CONFIGMAP environment variable.
+	// +kubebuilder:validation:Optional
+	TrustedCAConfigMapName string `json:"trustedCAConfigMapName,omitempty"`
+
+	// Name of the ConfigMap with the cosign public key under 'cosign.pub' key used to verify the renovate image signature.
+	// If set, the renovate image must be referenced by digest and the renovate pods verify its signature
+	// before renovate gets access to the tokens. The ConfigMap must exist in the namespace of the renovate pods.
+	// Overrides RENOVATE_COSIGN_KEY_CONFIGMAP environment variable.
+	// +kubebuilder:validation:Optional
+	CosignKeyConfigMapName string `json:"cosignKeyConfigMapName,omitempty"`
+
+	// Name of the PersistentVolumeClaim renovate logs are written to, one '<job name>.log' file per renovate job,
+	// or '<job name>-<index>.log' per pod of parallel Jobs, so the logs are available after the pods are deleted. The claim must exist in the namespace of the renovate pods
+	// and support ReadWriteMany access mode if more renovate jobs run in parallel. Old logs are not deleted.
+	// Overrides RENOVATE_LOGS_PVC environment variable.
+	// +kubebuilder:validation:Optional
+	LogsVolumeClaimName string `json:"logsVolumeClaimName,omitempty"`
+
+	// Kind of the object renovate runs in, either 'Job' or 'TaskRun'.
+	// TaskRuns show up in the Tekton observability stack, e.g. Tekton Results, Chains and dashboards.
+	// Overrides RENOVATE_EXECUTION_MODE environment variable, defaults to 'Job'.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=Job;TaskRun
+	ExecutionMode string `json:"executionMode,omitempty"`
+
+	// Namespace of the renovate TaskRuns. Not used for Jobs, which always run in the build-service namespace.
+	// Overrides RENOVATE_TASKRUN_NAMESPACE environment variable, defaults to the build-service namespace.
+	// +kubebuilder:validation:Optional
+	TaskRunNamespace string `json:"taskRunNamespace,omitempty"`
+
+	// Run the renovate TaskRuns in the namespaces of the renovated Components, so they are subject to the tenant quotas.
+	// Repositories shared by Components of several namespaces are renovated in the first namespace by name.
+	// GitHub App tokens are scoped to the repositories the tenant owns, see TenantRepositoryOwners, and the host rule Secrets
+	// are read from the tenant namespace. The trusted CA and cosign key ConfigMaps and the logs PersistentVolumeClaim, if configured,
+	// must exist in the tenant namespaces. Only supported with 'TaskRun' execution mode, Jobs always run in the build-service namespace.
+	// Overrides RENOVATE_TENANT_NAMESPACES environment variable.
+	// +kubebuilder:validation:Optional
+	RunInTenantNamespaces *bool `json:"runInTenantNamespaces,omitempty"`
+
+	// GitHub owners, i.e. organizations or users, of the repositories the given namespaces are proven to own.
+	// The GitHub App token of the TaskRun is stored in a Secret the tenant can read, so the repositories are renovated
+	// in the tenant namespace only if the namespace owns them. Repositories of other owners are renovated
+	// in the TaskRun namespace. Used only together with RunInTenantNamespaces.
+	// +kubebuilder:validation:Optional
+	TenantRepositoryOwners []RenovateTenantRepositoryOwners `json:"tenantRepositoryOwners,omitempty"`
+}
+
+// RenovateTenantRepositoryOwners defines GitHub owners of the repositories owned by the namespace.
+type RenovateTenantRepositoryOwners struct {
+	// Namespace of the Components.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Namespace string `json:"namespace"`
+
+	// GitHub organizations or users whose repositories are owned by the namespace, e.g. 'my-org'.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	Owners []string `json:"owners"`
+}
+
+// RenovateTemplates defines templates of renovate commits and pull requests.
+// The templates may use renovate handlebars variables, see https://docs.renovatebot.com/templates/
+type RenovateTemplates struct {
+	// Prefix of the commit message and the pull request title, e.g. 'chore(deps):'.
+	// +kubebuilder:validation:Optional
+	CommitMessagePrefix string `json:"commitMessagePrefix,omitempty"`
+
+	// Topic of the commit message and the pull request title. Defaults to 'RHTAP references'.
+	// +kubebuilder:validation:Optional
+	CommitMessageTopic string `json:"commitMessageTopic,omitempty"`
+
+	// Title of the pull request, overrides the title composed from the commit message.
+	// +kubebuilder:validation:Optional
+	PRTitle string `json:"prTitle,omitempty"`
+
+	// Whether the commit messages follow the conventional commits format, e.g. 'chore(deps): update ...'.
+	// 'auto' detects the format from the repository history. Defaults to 'enabled'.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=auto;enabled;disabled
+	SemanticCommits string `json:"semanticCommits,omitempty"`
+
+	// Type of the semantic commits, e.g. 'build'. Defaults to the renovate default 'chore'.
+	// +kubebuilder:validation:Optional
+	SemanticCommitType string `json:"semanticCommitType,omitempty"`
+
+	// Scope of the semantic commits, e.g. 'tekton'. Defaults to the renovate default 'deps'.
+	// +kubebuilder:validation:Optional
+	SemanticCommitScope string `json:"semanticCommitScope,omitempty"`
+}
+
+// RenovatePostUpgradeTasks defines commands renovate runs in the repository after updating the references,
+// see https://docs.renovatebot.com/configuration-options/#postupgradetasks
+type RenovatePostUpgradeTasks struct {
+	// Commands to run, e.g. to re-run a formatter or regenerate a kustomization. The commands may use renovate templates.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	Commands []string `json:"commands"`
+
+	// Glob patterns of the files changed by the commands which are committed. Defaults to all files.
+	// +kubebuilder:validation:Optional
+	FileFilters []string `json:"fileFilters,omitempty"`
+
+	// Whether the commands run after each update or once per branch.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=update;branch
+	ExecutionMode string `json:"executionMode,omitempty"`
+
+	// Regular expressions of the commands renovate is allowed to run, other commands are refused.
+	// Defaults to the exact commands.
+	// +kubebuilder:validation:Optional
+	AllowedCommands []string `json:"allowedCommands,omitempty"`
+}
+
+// RenovateManager defines a renovate manager enabled in addition to the tekton manager.
+type RenovateManager struct {
+	// Name of the renovate manager, e.g. 'dockerfile' or 'gomod'.
+	// See https://docs.renovatebot.com/modules/manager/
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+
+	// Regular expressions of the files handled by the manager. Defaults to the manager defaults.
+	// +kubebuilder:validation:Optional
+	FileMatch []string `json:"fileMatch,omitempty"`
+
+	// Paths where the manager looks for the files, e.g. 'docker/**'.
+	// +kubebuilder:validation:Optional
+	IncludePaths []string `json:"includePaths,omitempty"`
+}
+
+// RenovateHostRule defines credentials renovate uses to access a host, e.g. a private task bundles registry.
+type RenovateHostRule struct {
+	// Host the credentials are used for, e.g. 'registry.internal' or 'quay.io/my-org'.
+	// +kubebuilder:validation:Required
+	MatchHost string `json:"matchHost"`
+
+	// Type of the host, defaults to 'docker'.
+	// See https://docs.renovatebot.com/configuration-options/#hosttype
+	// +kubebuilder:validation:Optional
+	HostType string `json:"hostType,omitempty"`
+
+	// Name of the Secret in the build-service namespace with 'username' and 'password' keys.
+	// +kubebuilder:validation:Required
+	SecretName string `json:"secretName"`
+}
+
+// RenovateGitAuthor is the author of renovate commits, see https://docs.renovatebot.com/configuration-options/#gitauthor
+type RenovateGitAuthor struct {
+	// Name of the commit author.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Email of the commit author, it must belong to the account of the credentials if the git server enforces verified authorship.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Email string `json:"email"`
+}
+
+// RenovateNamespaceGitAuthor is the author of renovate commits in the repositories of the Components of a namespace.
+type RenovateNamespaceGitAuthor struct {
+	// Namespace of the Components.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Namespace string `json:"namespace"`
+
+	RenovateGitAuthor `json:",inline"`
+}
+
+// RenovateTektonConfigSpec defines the desired configuration of renovate Jobs which update Tekton references in Component repositories.
+// Unset fields fall back to the environment variables of the Build Service and then to the built-in defaults.
+type RenovateTektonConfigSpec struct {
+	// Renovate image to run, e.g. 'quay.io/redhat-appstudio/renovate:v37.74.1'.
+	// Overrides RENOVATE_IMAGE environment variable.
+	// +kubebuilder:validation:Optional
+	Image string `json:"image,omitempty"`
+
+	// Node architecture of the renovate pods, e.g. 'amd64' or 'arm64', on clusters with mixed architectures.
+	// The pods are scheduled only on nodes with the architecture.
+	// Overrides RENOVATE_ARCHITECTURE environment variable.
+	// +kubebuilder:validation:Optional
+	Architecture string `json:"architecture,omitempty"`
+
+	// Renovate images per node architecture, e.g. 'arm64: quay.io/my-org/renovate:v37-arm64'.
+	// The image of the selected architecture is used instead of Image, if set.
+	// +kubebuilder:validation:Optional
+	ArchitectureImages map[string]string `json:"architectureImages,omitempty"`
+
+	// Regular expression to match Tekton references to update, e.g. '^quay.io/redhat-appstudio-tekton-catalog/'.
+	// Overrides RENOVATE_PATTERN environment variable.
+	// +kubebuilder:validation:Optional
+	MatchPattern string `json:"matchPattern,omitempty"`
+
+	// Regular expressions to match Tekton references to update, e.g. the catalog and its internal mirror.
+	// Takes precedence over MatchPattern.
+	// Overrides RENOVATE_PATTERN environment variable.
+	// +kubebuilder:validation:Optional
+	MatchPatterns []string `json:"matchPatterns,omitempty"`
+
+	// Number of renovate tasks, i.e. GitHub App installations or sets of repositories with the same credentials, processed by one Job.
+	// Overrides RENOVATE_INSTALLATIONS_PER_JOB environment variable.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=99
+	InstallationsPerJob int `json:"installationsPerJob,omitempty"`
+
+	// Maximum number of repositories processed by one Job. If set, the tasks with more repositories are split
+	// into several Jobs and the tasks are grouped by their repository count, so the Jobs get similar amounts of work.
+	// InstallationsPerJob still limits the number of tasks per Job.
+	// Overrides RENOVATE_REPOSITORIES_PER_JOB environment variable, by default the tasks are not split.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	RepositoriesPerJob int `json:"repositoriesPerJob,omitempty"`
+
+	// Renovate schedule, which limits the time when renovate is allowed to create branches and pull requests,
+	// e.g. 'after 10pm and before 5am every weekday'.
+	// See https://docs.renovatebot.com/configuration-options/#schedule
+	// +kubebuilder:validation:Optional
+	Schedule []string `json:"schedule,omitempty"`
+
+	// Enables automerge of Tekton reference updates once the required checks pass,
+	// using the platform native automerge when possible.
+	// Components can override it by build.appstudio.openshift.io/renovate-automerge annotation.
+	// Overrides RENOVATE_AUTOMERGE environment variable.
+	// +kubebuilder:validation:Optional
+	Automerge *bool `json:"automerge,omitempty"`
+
+	// Regular expressions of full names of repositories excluded from renovate updates even if they have Components,
+	// e.g. '^my-org/archived-.*' for archived or migration-frozen repositories.
+	// Overrides RENOVATE_EXCLUDE_REPOSITORIES environment variable.
+	// +kubebuilder:validation:Optional
+	ExcludeRepositories []string `json:"excludeRepositories,omitempty"`
+
+	// Namespaces whose Components get renovate updates. All namespaces are included if empty.
+	// Overrides RENOVATE_INCLUDE_NAMESPACES environment variable.
+	// +kubebuilder:validation:Optional
+	IncludeNamespaces []string `json:"includeNamespaces,omitempty"`
+
+	// Namespaces whose Components don't get renovate updates, e.g. staging tenants.
+	// Takes precedence over IncludeNamespaces.
+	// Overrides RENOVATE_EXCLUDE_NAMESPACES environment variable.
+	// +kubebuilder:validation:Optional
+	ExcludeNamespaces []string `json:"excludeNamespaces,omitempty"`
+
+	// Groups all updates, including the updates of the additional managers and major updates,
+	// into a single pull request per repository and base branch.
+	// Overrides RENOVATE_GROUP_ALL_UPDATES environment variable.
+	// +kubebuilder:validation:Optional
+	GroupAllUpdates *bool `json:"groupAllUpdates,omitempty"`
+
+	// Converts Tekton references with floating tags to pinned digests and keeps the digests updated.
+	// Overrides RENOVATE_PIN_DIGESTS environment variable.
+	// +kubebuilder:validation:Optional
+	PinDigests *bool `json:"pinDigests,omitempty"`
+
+	// Maximum number of pull requests renovate creates per hour in a repository, 0 means no limit.
+	// Lower values spread the pull requests of large sweeps over time, so they don't trigger GitHub abuse detection.
+	// Overrides RENOVATE_PR_HOURLY_LIMIT environment variable, defaults to the renovate default.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	PRHourlyLimit *int `json:"prHourlyLimit,omitempty"`
+
+	// Maximum number of open renovate pull requests in a repository, 0 means no limit.
+	// Overrides RENOVATE_PR_CONCURRENT_LIMIT environment variable, defaults to the renovate default.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	PRConcurrentLimit *int `json:"prConcurrentLimit,omitempty"`
+
+	// Label which stops renovate from updating a pull request, e.g. when a developer pushed changes to its branch
+	// which would be overwritten by the next sweep. The pull request footer tells developers about the label.
+	// Overrides RENOVATE_STOP_UPDATING_LABEL environment variable, defaults to 'stop-updating'.
+	// +kubebuilder:validation:Optional
+	StopUpdatingLabel string `json:"stopUpdatingLabel,omitempty"`
+
+	// When renovate rebases its open pull requests, see https://docs.renovatebot.com/configuration-options/#rebasewhen
+	// The default 'behind-base-branch' keeps the pull requests up to date with the base branch, so required checks don't fail on stale branches.
+	// Overrides RENOVATE_REBASE_WHEN environment variable.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=auto;never;conflicted;behind-base-branch;automerging
+	RebaseWhen string `json:"rebaseWhen,omitempty"`
+
+	// Author of the renovate commits, e.g. a service account of an enterprise which enforces verified commit authorship.
+	// Overrides RENOVATE_GIT_AUTHOR environment variable in 'Name <email>' form,
+	// defaults to the bot of the GitHub App or the username of the credentials.
+	// +kubebuilder:validation:Optional
+	GitAuthor *RenovateGitAuthor `json:"gitAuthor,omitempty"`
+
+	// Authors of the renovate commits in the repositories of the Components of the given namespaces, take precedence over GitAuthor.
+	// Repositories shared by Components of several namespaces get the author of the first Component's namespace with one.
+	// +kubebuilder:validation:Optional
+	NamespaceGitAuthors []RenovateNamespaceGitAuthor `json:"namespaceGitAuthors,omitempty"`
+
+	// Prefix of the renovate branches, e.g. 'konflux/' if branch protection or automation keys off the prefix.
+	// The branch of the Tekton references is '<prefix>references/<base branch>' if set, 'konflux/references/<base branch>' otherwise.
+	// Overrides RENOVATE_BRANCH_PREFIX environment variable, defaults to the renovate default 'renovate/'.
+	// +kubebuilder:validation:Optional
+	BranchPrefix string `json:"branchPrefix,omitempty"`
+
+	// Deletes the renovate branches which are no longer needed, e.g. after their pull requests were closed,
+	// the base branch was removed from the Components or the match pattern changed. Branches with commits
+	// of other authors are kept. Covers the branches with the prefix and the Tekton references branches.
+	// Not applied to repositories with Components which are not renovated, e.g. opted out or of excluded namespaces,
+	// since their base branches would be considered stale.
+	// Overrides RENOVATE_PRUNE_STALE_BRANCHES environment variable, defaults to true.
+	// +kubebuilder:validation:Optional
+	PruneStaleBranches *bool `json:"pruneStaleBranches,omitempty"`
+
+	// Runs renovate with '--dry-run=full', so it only logs the branches and pull requests it would create,
+	// e.g. to preview the updates of a new match pattern. The renovate jobs get the
+	// build.appstudio.openshift.io/renovate-dry-run label. Overrides RENOVATE_DRY_RUN environment variable.
+	// +kubebuilder:validation:Optional
+	DryRun *bool `json:"dryRun,omitempty"`
+
+	// Paths where renovate looks for Tekton PipelineRuns, e.g. 'ci/tekton/**'. Defaults to '.tekton/**'.
+	// Overrides RENOVATE_INCLUDE_PATHS environment variable.
+	// +kubebuilder:validation:Optional
+	IncludePaths []string `json:"includePaths,omitempty"`
+
+	// Credentials of the hosts renovate needs to access, e.g. to look up digests in private registries.
+	// The credentials are passed to the renovate jobs in a Secret, not in the renovate config.
+	// +kubebuilder:validation:Optional
+	HostRules []RenovateHostRule `json:"hostRules,omitempty"`
+
+	// Renovate managers enabled in addition to the tekton manager, e.g. to update Dockerfile base images.
+	// +kubebuilder:validation:Optional
+	Managers []RenovateManager `json:"managers,omitempty"`
+
+	// Templates of renovate commits and pull requests.
+	// +kubebuilder:validation:Optional
+	Templates RenovateTemplates `json:"templates,omitempty"`
+
+	// Commands run after the references are updated, their changes are committed together with the updates.
+	// +kubebuilder:validation:Optional
+	PostUpgradeTasks *RenovatePostUpgradeTasks `json:"postUpgradeTasks,omitempty"`
+
+	// Name of the ConfigMap in the build-service namespace with a renovate config under 'config.json' key,
+	// which is merged into the config generated for every repository set, e.g. to add labels, reviewers or package rules.
+	// Objects are merged recursively, package rules are appended to the generated ones and other values are replaced.
+	// Overrides RENOVATE_CONFIG_OVERLAY_CONFIGMAP environment variable.
+	// +kubebuilder:validation:Optional
+	ConfigOverlayConfigMapName string `json:"configOverlayConfigMapName,omitempty"`
+
+	// Settings of the renovate Jobs.
+	// +kubebuilder:validation:Optional
+	JobSettings RenovateJobSettings `json:"jobSettings,omitempty"`
+
+	// Interval between periodic renovate sweeps over all Components, e.g. '1h' or '24h'.
+	// Values shorter than 10 minutes are ignored. Not used if SweepSchedule is set.
+	// Overrides RENOVATE_SWEEP_INTERVAL environment variable, defaults to 6 hours.
+	// +kubebuilder:validation:Optional
+	SweepInterval *metav1.Duration `json:"sweepInterval,omitempty"`
+
+	// Cron schedule of renovate sweeps over all Components, e.g. '0 2 * * *'.
+	// If set, Build Service manages a CronJob which triggers the sweeps and the periodic sweeps are disabled.
+	// See https://kubernetes.io/docs/concepts/workloads/controllers/cron-jobs/#schedule-syntax
+	// +kubebuilder:validation:Optional
+	SweepSchedule string `json:"sweepSchedule,omitempty"`
+
+	// Suspends renovate sweeps. Sweeps requested on demand are still executed.
+	// +kubebuilder:validation:Optional
+	SuspendSweeps bool `json:"suspendSweeps,omitempty"`
+}
+
+const (
+	// Condition reporting the outcome of the renovate Jobs of the periodic and scheduled sweeps.
+	// It turns False when a sweep Job fails and True again when a Job created after that succeeds.
+	RenovateSweepSucceededConditionType = "RenovateSweepSucceeded"
+)
+
+// RenovateTektonConfigStatus defines the observed state of RenovateTektonConfig
+type RenovateTektonConfigStatus struct {
+	// Conditions of the renovate sweeps.
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:scope=Cluster
+//+kubebuilder:subresource:status
+
+// RenovateTektonConfig is the Schema for the RenovateTektonConfigs API
+type RenovateTektonConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RenovateTektonConfigSpec   `json:"spec,omitempty"`
+	Status RenovateTektonConfigStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// RenovateTektonConfigList contains a list of RenovateTektonConfig
+type RenovateTektonConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RenovateTektonConfig `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&RenovateTektonConfig{}, &RenovateTektonConfigList{})
+}
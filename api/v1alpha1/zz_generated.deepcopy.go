@@ -22,6 +22,8 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -105,6 +107,239 @@ func (in *BuildPipelineSelectorSpec) DeepCopy() *BuildPipelineSelectorSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BuildServiceConfig) DeepCopyInto(out *BuildServiceConfig) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BuildServiceConfig.
+func (in *BuildServiceConfig) DeepCopy() *BuildServiceConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(BuildServiceConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BuildServiceConfig) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BuildServiceConfigList) DeepCopyInto(out *BuildServiceConfigList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]BuildServiceConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BuildServiceConfigList.
+func (in *BuildServiceConfigList) DeepCopy() *BuildServiceConfigList {
+	if in == nil {
+		return nil
+	}
+	out := new(BuildServiceConfigList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BuildServiceConfigList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BuildServiceConfigSpec) DeepCopyInto(out *BuildServiceConfigSpec) {
+	*out = *in
+	if in.Renovate != nil {
+		in, out := &in.Renovate, &out.Renovate
+		*out = new(BuildServiceRenovateConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PipelinesAsCode != nil {
+		in, out := &in.PipelinesAsCode, &out.PipelinesAsCode
+		*out = new(BuildServicePipelinesAsCodeConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PipelineRunRetention != nil {
+		in, out := &in.PipelineRunRetention, &out.PipelineRunRetention
+		*out = new(BuildServicePipelineRunRetentionConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ImageRegistrySecretRotationPeriod != nil {
+		in, out := &in.ImageRegistrySecretRotationPeriod, &out.ImageRegistrySecretRotationPeriod
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.FeatureGates != nil {
+		in, out := &in.FeatureGates, &out.FeatureGates
+		*out = make(map[string]bool, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BuildServiceConfigSpec.
+func (in *BuildServiceConfigSpec) DeepCopy() *BuildServiceConfigSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BuildServiceConfigSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BuildServiceConfigStatus) DeepCopyInto(out *BuildServiceConfigStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BuildServiceConfigStatus.
+func (in *BuildServiceConfigStatus) DeepCopy() *BuildServiceConfigStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BuildServiceConfigStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BuildServicePipelineRunRetentionConfig) DeepCopyInto(out *BuildServicePipelineRunRetentionConfig) {
+	*out = *in
+	if in.KeepSucceeded != nil {
+		in, out := &in.KeepSucceeded, &out.KeepSucceeded
+		*out = new(int32)
+		**out = **in
+	}
+	if in.KeepFailed != nil {
+		in, out := &in.KeepFailed, &out.KeepFailed
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxAge != nil {
+		in, out := &in.MaxAge, &out.MaxAge
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BuildServicePipelineRunRetentionConfig.
+func (in *BuildServicePipelineRunRetentionConfig) DeepCopy() *BuildServicePipelineRunRetentionConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(BuildServicePipelineRunRetentionConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BuildServicePipelinesAsCodeConfig) DeepCopyInto(out *BuildServicePipelinesAsCodeConfig) {
+	*out = *in
+	if in.WebhookInsecureSSL != nil {
+		in, out := &in.WebhookInsecureSSL, &out.WebhookInsecureSSL
+		*out = new(bool)
+		**out = **in
+	}
+	if in.WebhookSecretRotationPeriod != nil {
+		in, out := &in.WebhookSecretRotationPeriod, &out.WebhookSecretRotationPeriod
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.DirectPushAllowed != nil {
+		in, out := &in.DirectPushAllowed, &out.DirectPushAllowed
+		*out = new(bool)
+		**out = **in
+	}
+	if in.GiteaHosts != nil {
+		in, out := &in.GiteaHosts, &out.GiteaHosts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BuildServicePipelinesAsCodeConfig.
+func (in *BuildServicePipelinesAsCodeConfig) DeepCopy() *BuildServicePipelinesAsCodeConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(BuildServicePipelinesAsCodeConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BuildServiceRenovateConfig) DeepCopyInto(out *BuildServiceRenovateConfig) {
+	*out = *in
+	if in.MatchPatterns != nil {
+		in, out := &in.MatchPatterns, &out.MatchPatterns
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.InstallationsPerJob != nil {
+		in, out := &in.InstallationsPerJob, &out.InstallationsPerJob
+		*out = new(int32)
+		**out = **in
+	}
+	if in.RepositoriesPerJob != nil {
+		in, out := &in.RepositoriesPerJob, &out.RepositoriesPerJob
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxParallelJobs != nil {
+		in, out := &in.MaxParallelJobs, &out.MaxParallelJobs
+		*out = new(int32)
+		**out = **in
+	}
+	if in.SweepInterval != nil {
+		in, out := &in.SweepInterval, &out.SweepInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.TriggeredSweepMinInterval != nil {
+		in, out := &in.TriggeredSweepMinInterval, &out.TriggeredSweepMinInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BuildServiceRenovateConfig.
+func (in *BuildServiceRenovateConfig) DeepCopy() *BuildServiceRenovateConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(BuildServiceRenovateConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PipelineParam) DeepCopyInto(out *PipelineParam) {
 	*out = *in
@@ -142,6 +377,599 @@ func (in *PipelineSelector) DeepCopy() *PipelineSelector {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RenovateGitAuthor) DeepCopyInto(out *RenovateGitAuthor) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RenovateGitAuthor.
+func (in *RenovateGitAuthor) DeepCopy() *RenovateGitAuthor {
+	if in == nil {
+		return nil
+	}
+	out := new(RenovateGitAuthor)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RenovateHostRule) DeepCopyInto(out *RenovateHostRule) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RenovateHostRule.
+func (in *RenovateHostRule) DeepCopy() *RenovateHostRule {
+	if in == nil {
+		return nil
+	}
+	out := new(RenovateHostRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RenovateJobSettings) DeepCopyInto(out *RenovateJobSettings) {
+	*out = *in
+	if in.TTLSecondsAfterFinished != nil {
+		in, out := &in.TTLSecondsAfterFinished, &out.TTLSecondsAfterFinished
+		*out = new(int32)
+		**out = **in
+	}
+	if in.BackoffLimit != nil {
+		in, out := &in.BackoffLimit, &out.BackoffLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.RetryLimit != nil {
+		in, out := &in.RetryLimit, &out.RetryLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.RetryBackoff != nil {
+		in, out := &in.RetryBackoff, &out.RetryBackoff
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Parallelism != nil {
+		in, out := &in.Parallelism, &out.Parallelism
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(corev1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ImagePullSecrets != nil {
+		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
+		*out = make([]corev1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.MaxParallelJobs != nil {
+		in, out := &in.MaxParallelJobs, &out.MaxParallelJobs
+		*out = new(int)
+		**out = **in
+	}
+	if in.Proxy != nil {
+		in, out := &in.Proxy, &out.Proxy
+		*out = new(RenovateProxy)
+		**out = **in
+	}
+	if in.RunInTenantNamespaces != nil {
+		in, out := &in.RunInTenantNamespaces, &out.RunInTenantNamespaces
+		*out = new(bool)
+		**out = **in
+	}
+	if in.TenantRepositoryOwners != nil {
+		in, out := &in.TenantRepositoryOwners, &out.TenantRepositoryOwners
+		*out = make([]RenovateTenantRepositoryOwners, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RenovateJobSettings.
+func (in *RenovateJobSettings) DeepCopy() *RenovateJobSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(RenovateJobSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RenovateManager) DeepCopyInto(out *RenovateManager) {
+	*out = *in
+	if in.FileMatch != nil {
+		in, out := &in.FileMatch, &out.FileMatch
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.IncludePaths != nil {
+		in, out := &in.IncludePaths, &out.IncludePaths
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RenovateManager.
+func (in *RenovateManager) DeepCopy() *RenovateManager {
+	if in == nil {
+		return nil
+	}
+	out := new(RenovateManager)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RenovateNamespaceGitAuthor) DeepCopyInto(out *RenovateNamespaceGitAuthor) {
+	*out = *in
+	out.RenovateGitAuthor = in.RenovateGitAuthor
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RenovateNamespaceGitAuthor.
+func (in *RenovateNamespaceGitAuthor) DeepCopy() *RenovateNamespaceGitAuthor {
+	if in == nil {
+		return nil
+	}
+	out := new(RenovateNamespaceGitAuthor)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RenovatePostUpgradeTasks) DeepCopyInto(out *RenovatePostUpgradeTasks) {
+	*out = *in
+	if in.Commands != nil {
+		in, out := &in.Commands, &out.Commands
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.FileFilters != nil {
+		in, out := &in.FileFilters, &out.FileFilters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowedCommands != nil {
+		in, out := &in.AllowedCommands, &out.AllowedCommands
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RenovatePostUpgradeTasks.
+func (in *RenovatePostUpgradeTasks) DeepCopy() *RenovatePostUpgradeTasks {
+	if in == nil {
+		return nil
+	}
+	out := new(RenovatePostUpgradeTasks)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RenovateProxy) DeepCopyInto(out *RenovateProxy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RenovateProxy.
+func (in *RenovateProxy) DeepCopy() *RenovateProxy {
+	if in == nil {
+		return nil
+	}
+	out := new(RenovateProxy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RenovateRun) DeepCopyInto(out *RenovateRun) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RenovateRun.
+func (in *RenovateRun) DeepCopy() *RenovateRun {
+	if in == nil {
+		return nil
+	}
+	out := new(RenovateRun)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RenovateRun) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RenovateRunList) DeepCopyInto(out *RenovateRunList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]RenovateRun, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RenovateRunList.
+func (in *RenovateRunList) DeepCopy() *RenovateRunList {
+	if in == nil {
+		return nil
+	}
+	out := new(RenovateRunList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RenovateRunList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RenovateRunPullRequest) DeepCopyInto(out *RenovateRunPullRequest) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RenovateRunPullRequest.
+func (in *RenovateRunPullRequest) DeepCopy() *RenovateRunPullRequest {
+	if in == nil {
+		return nil
+	}
+	out := new(RenovateRunPullRequest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RenovateRunRepositoryStatus) DeepCopyInto(out *RenovateRunRepositoryStatus) {
+	*out = *in
+	if in.PullRequests != nil {
+		in, out := &in.PullRequests, &out.PullRequests
+		*out = make([]RenovateRunPullRequest, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RenovateRunRepositoryStatus.
+func (in *RenovateRunRepositoryStatus) DeepCopy() *RenovateRunRepositoryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RenovateRunRepositoryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RenovateRunSpec) DeepCopyInto(out *RenovateRunSpec) {
+	*out = *in
+	if in.Repositories != nil {
+		in, out := &in.Repositories, &out.Repositories
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RenovateRunSpec.
+func (in *RenovateRunSpec) DeepCopy() *RenovateRunSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RenovateRunSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RenovateRunStatus) DeepCopyInto(out *RenovateRunStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Jobs != nil {
+		in, out := &in.Jobs, &out.Jobs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Repositories != nil {
+		in, out := &in.Repositories, &out.Repositories
+		*out = make([]RenovateRunRepositoryStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RenovateRunStatus.
+func (in *RenovateRunStatus) DeepCopy() *RenovateRunStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RenovateRunStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RenovateTektonConfig) DeepCopyInto(out *RenovateTektonConfig) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RenovateTektonConfig.
+func (in *RenovateTektonConfig) DeepCopy() *RenovateTektonConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(RenovateTektonConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RenovateTektonConfig) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RenovateTektonConfigList) DeepCopyInto(out *RenovateTektonConfigList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]RenovateTektonConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RenovateTektonConfigList.
+func (in *RenovateTektonConfigList) DeepCopy() *RenovateTektonConfigList {
+	if in == nil {
+		return nil
+	}
+	out := new(RenovateTektonConfigList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RenovateTektonConfigList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RenovateTektonConfigSpec) DeepCopyInto(out *RenovateTektonConfigSpec) {
+	*out = *in
+	if in.ArchitectureImages != nil {
+		in, out := &in.ArchitectureImages, &out.ArchitectureImages
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.MatchPatterns != nil {
+		in, out := &in.MatchPatterns, &out.MatchPatterns
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Schedule != nil {
+		in, out := &in.Schedule, &out.Schedule
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Automerge != nil {
+		in, out := &in.Automerge, &out.Automerge
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ExcludeRepositories != nil {
+		in, out := &in.ExcludeRepositories, &out.ExcludeRepositories
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.IncludeNamespaces != nil {
+		in, out := &in.IncludeNamespaces, &out.IncludeNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExcludeNamespaces != nil {
+		in, out := &in.ExcludeNamespaces, &out.ExcludeNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.GroupAllUpdates != nil {
+		in, out := &in.GroupAllUpdates, &out.GroupAllUpdates
+		*out = new(bool)
+		**out = **in
+	}
+	if in.PinDigests != nil {
+		in, out := &in.PinDigests, &out.PinDigests
+		*out = new(bool)
+		**out = **in
+	}
+	if in.PRHourlyLimit != nil {
+		in, out := &in.PRHourlyLimit, &out.PRHourlyLimit
+		*out = new(int)
+		**out = **in
+	}
+	if in.PRConcurrentLimit != nil {
+		in, out := &in.PRConcurrentLimit, &out.PRConcurrentLimit
+		*out = new(int)
+		**out = **in
+	}
+	if in.GitAuthor != nil {
+		in, out := &in.GitAuthor, &out.GitAuthor
+		*out = new(RenovateGitAuthor)
+		**out = **in
+	}
+	if in.NamespaceGitAuthors != nil {
+		in, out := &in.NamespaceGitAuthors, &out.NamespaceGitAuthors
+		*out = make([]RenovateNamespaceGitAuthor, len(*in))
+		copy(*out, *in)
+	}
+	if in.PruneStaleBranches != nil {
+		in, out := &in.PruneStaleBranches, &out.PruneStaleBranches
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DryRun != nil {
+		in, out := &in.DryRun, &out.DryRun
+		*out = new(bool)
+		**out = **in
+	}
+	if in.IncludePaths != nil {
+		in, out := &in.IncludePaths, &out.IncludePaths
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.HostRules != nil {
+		in, out := &in.HostRules, &out.HostRules
+		*out = make([]RenovateHostRule, len(*in))
+		copy(*out, *in)
+	}
+	if in.Managers != nil {
+		in, out := &in.Managers, &out.Managers
+		*out = make([]RenovateManager, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	out.Templates = in.Templates
+	if in.PostUpgradeTasks != nil {
+		in, out := &in.PostUpgradeTasks, &out.PostUpgradeTasks
+		*out = new(RenovatePostUpgradeTasks)
+		(*in).DeepCopyInto(*out)
+	}
+	in.JobSettings.DeepCopyInto(&out.JobSettings)
+	if in.SweepInterval != nil {
+		in, out := &in.SweepInterval, &out.SweepInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RenovateTektonConfigSpec.
+func (in *RenovateTektonConfigSpec) DeepCopy() *RenovateTektonConfigSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RenovateTektonConfigSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RenovateTektonConfigStatus) DeepCopyInto(out *RenovateTektonConfigStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RenovateTektonConfigStatus.
+func (in *RenovateTektonConfigStatus) DeepCopy() *RenovateTektonConfigStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RenovateTektonConfigStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RenovateTemplates) DeepCopyInto(out *RenovateTemplates) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RenovateTemplates.
+func (in *RenovateTemplates) DeepCopy() *RenovateTemplates {
+	if in == nil {
+		return nil
+	}
+	out := new(RenovateTemplates)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RenovateTenantRepositoryOwners) DeepCopyInto(out *RenovateTenantRepositoryOwners) {
+	*out = *in
+	if in.Owners != nil {
+		in, out := &in.Owners, &out.Owners
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RenovateTenantRepositoryOwners.
+func (in *RenovateTenantRepositoryOwners) DeepCopy() *RenovateTenantRepositoryOwners {
+	if in == nil {
+		return nil
+	}
+	out := new(RenovateTenantRepositoryOwners)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WhenCondition) DeepCopyInto(out *WhenCondition) {
 	*out = *in
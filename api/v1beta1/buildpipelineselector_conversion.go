@@ -0,0 +1,332 @@
+/*
+Copyright 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	tektonapi "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"k8s.io/utils/strings/slices"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/konflux-ci/build-service/api/v1alpha1"
+)
+
+// Tekton resolvers used by v1alpha1 pipeline references.
+const (
+	bundlesResolverName = "bundles"
+	gitResolverName     = "git"
+)
+
+// PipelineRefParamsAnnotationName holds v1alpha1 resolver params which aren't modeled by v1beta1 bundle and git references,
+// e.g. 'serviceAccount' or 'kind' other than 'pipeline', so that they survive v1alpha1 -> v1beta1 -> v1alpha1 conversion.
+// The value is JSON object with the resolver and its params keyed by the index of the selector item.
+const PipelineRefParamsAnnotationName = "build.appstudio.openshift.io/v1alpha1-pipeline-ref-params"
+
+// pipelineRefParams are the resolver params of a selector item stored in PipelineRefParamsAnnotationName annotation.
+// The params are restored only if the item still uses the same resolver.
+type pipelineRefParams struct {
+	Resolver string            `json:"resolver"`
+	Params   []tektonapi.Param `json:"params"`
+}
+
+// ConvertTo converts this BuildPipelineSelector to the hub (v1alpha1) version.
+func (src *BuildPipelineSelector) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1alpha1.BuildPipelineSelector)
+
+	extraParams, err := getExtraPipelineRefParams(src.Annotations)
+	if err != nil {
+		return fmt.Errorf("failed to read %s annotation of BuildPipelineSelector %s/%s: %w", PipelineRefParamsAnnotationName, src.Namespace, src.Name, err)
+	}
+
+	dst.ObjectMeta = src.ObjectMeta
+	if _, exists := src.Annotations[PipelineRefParamsAnnotationName]; exists {
+		dst.Annotations = make(map[string]string, len(src.Annotations)-1)
+		for key, value := range src.Annotations {
+			if key != PipelineRefParamsAnnotationName {
+				dst.Annotations[key] = value
+			}
+		}
+		if len(dst.Annotations) == 0 {
+			dst.Annotations = nil
+		}
+	}
+	dst.Spec.Mode = v1alpha1.BuildPipelineSelectorMode(src.Spec.Mode)
+	dst.Spec.Selectors = nil
+	for i, srcSelector := range src.Spec.Selectors {
+		var storedParams *pipelineRefParams
+		if selectorParams, exists := extraParams[strconv.Itoa(i)]; exists {
+			storedParams = &selectorParams
+		}
+		pipelineRef, err := convertPipelineRefToHub(srcSelector.PipelineRef, storedParams)
+		if err != nil {
+			return fmt.Errorf("failed to convert %q selector of BuildPipelineSelector %s/%s: %w", srcSelector.Name, src.Namespace, src.Name, err)
+		}
+		dstSelector := v1alpha1.PipelineSelector{
+			Name:        srcSelector.Name,
+			PipelineRef: pipelineRef,
+			WhenConditions: v1alpha1.WhenCondition{
+				Language:           joinValues(srcSelector.Match.Languages),
+				ProjectType:        joinValues(srcSelector.Match.ProjectTypes),
+				DockerfileRequired: srcSelector.Match.DockerfileRequired,
+				ProjectFiles:       joinValues(srcSelector.Match.ProjectFiles),
+				ComponentName:      joinValues(srcSelector.Match.ComponentNames),
+				Annotations:        joinValuesMap(srcSelector.Match.Annotations),
+				Labels:             joinValuesMap(srcSelector.Match.Labels),
+			},
+			Priority: srcSelector.Priority,
+		}
+		for _, param := range srcSelector.PipelineParams {
+			dstSelector.PipelineParams = append(dstSelector.PipelineParams, v1alpha1.PipelineParam{Name: param.Name, Value: param.Value})
+		}
+		dst.Spec.Selectors = append(dst.Spec.Selectors, dstSelector)
+	}
+
+	return nil
+}
+
+// ConvertFrom converts from the hub (v1alpha1) version to this version.
+func (dst *BuildPipelineSelector) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1alpha1.BuildPipelineSelector)
+
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec.Mode = BuildPipelineSelectorMode(src.Spec.Mode)
+	dst.Spec.Selectors = nil
+	extraParams := map[string]pipelineRefParams{}
+	for i, srcSelector := range src.Spec.Selectors {
+		pipelineRef, selectorParams := convertPipelineRefFromHub(srcSelector.PipelineRef)
+		if selectorParams != nil {
+			extraParams[strconv.Itoa(i)] = *selectorParams
+		}
+		dstSelector := PipelineSelector{
+			Name:        srcSelector.Name,
+			PipelineRef: pipelineRef,
+			Match: MatchCriteria{
+				Languages:          splitValues(srcSelector.WhenConditions.Language),
+				ProjectTypes:       splitValues(srcSelector.WhenConditions.ProjectType),
+				DockerfileRequired: srcSelector.WhenConditions.DockerfileRequired,
+				ProjectFiles:       splitValues(srcSelector.WhenConditions.ProjectFiles),
+				ComponentNames:     splitValues(srcSelector.WhenConditions.ComponentName),
+				Annotations:        splitValuesMap(srcSelector.WhenConditions.Annotations),
+				Labels:             splitValuesMap(srcSelector.WhenConditions.Labels),
+			},
+			Priority: srcSelector.Priority,
+		}
+		for _, param := range srcSelector.PipelineParams {
+			dstSelector.PipelineParams = append(dstSelector.PipelineParams, PipelineParam{Name: param.Name, Value: param.Value})
+		}
+		dst.Spec.Selectors = append(dst.Spec.Selectors, dstSelector)
+	}
+
+	if len(extraParams) > 0 {
+		extraParamsJson, err := json.Marshal(extraParams)
+		if err != nil {
+			return fmt.Errorf("failed to store resolver params of BuildPipelineSelector %s/%s: %w", src.Namespace, src.Name, err)
+		}
+		// The annotations map is shared with the hub object
+		dst.Annotations = make(map[string]string, len(src.Annotations)+1)
+		for key, value := range src.Annotations {
+			dst.Annotations[key] = value
+		}
+		dst.Annotations[PipelineRefParamsAnnotationName] = string(extraParamsJson)
+	}
+
+	return nil
+}
+
+// getExtraPipelineRefParams reads the resolver params stored by the conversion from v1alpha1, keyed by the selector index.
+func getExtraPipelineRefParams(annotations map[string]string) (map[string]pipelineRefParams, error) {
+	extraParams := map[string]pipelineRefParams{}
+	if value, exists := annotations[PipelineRefParamsAnnotationName]; exists {
+		if err := json.Unmarshal([]byte(value), &extraParams); err != nil {
+			return nil, err
+		}
+	}
+	return extraParams, nil
+}
+
+// convertPipelineRefToHub converts the reference into Tekton resolver reference.
+// The params stored by the conversion from v1alpha1 replace the default 'kind' param of the bundles resolver
+// and are appended to the params of the reference, if the reference still uses the same resolver.
+func convertPipelineRefToHub(pipelineRef PipelineRef, storedParams *pipelineRefParams) (tektonapi.PipelineRef, error) {
+	stringParam := func(name, value string) tektonapi.Param {
+		return tektonapi.Param{Name: name, Value: *tektonapi.NewStructuredValues(value)}
+	}
+	storedParamsOf := func(resolver string) ([]tektonapi.Param, bool) {
+		if storedParams == nil || storedParams.Resolver != resolver {
+			return nil, false
+		}
+		return storedParams.Params, true
+	}
+
+	switch {
+	case pipelineRef.Bundle != nil:
+		bundle := pipelineRef.Bundle.Repository
+		if pipelineRef.Bundle.Tag != "" {
+			bundle += ":" + pipelineRef.Bundle.Tag
+		}
+		if pipelineRef.Bundle.Digest != "" {
+			bundle += "@" + pipelineRef.Bundle.Digest
+		}
+		params := []tektonapi.Param{
+			stringParam("name", pipelineRef.Bundle.Name),
+			stringParam("bundle", bundle),
+		}
+		extraParams, stored := storedParamsOf(bundlesResolverName)
+		if !stored {
+			extraParams = []tektonapi.Param{stringParam("kind", "pipeline")}
+		}
+		return tektonapi.PipelineRef{
+			ResolverRef: tektonapi.ResolverRef{
+				Resolver: bundlesResolverName,
+				Params:   append(params, extraParams...),
+			},
+		}, nil
+	case pipelineRef.Git != nil:
+		extraParams, _ := storedParamsOf(gitResolverName)
+		params := []tektonapi.Param{
+			stringParam("url", pipelineRef.Git.URL),
+			stringParam("revision", pipelineRef.Git.Revision),
+			stringParam("pathInRepo", pipelineRef.Git.PathInRepo),
+		}
+		return tektonapi.PipelineRef{
+			ResolverRef: tektonapi.ResolverRef{
+				Resolver: gitResolverName,
+				Params:   append(params, extraParams...),
+			},
+		}, nil
+	case pipelineRef.Raw != nil:
+		return *pipelineRef.Raw.DeepCopy(), nil
+	}
+	return tektonapi.PipelineRef{}, fmt.Errorf("pipeline reference is empty")
+}
+
+// convertPipelineRefFromHub converts Tekton resolver reference into bundle or git reference
+// and returns the resolver params to store if the reference has params other than the modeled ones.
+// References which can't be expressed as bundle or git reference, e.g. other resolvers
+// or plain pipeline name, are kept as raw Tekton reference.
+func convertPipelineRefFromHub(pipelineRef tektonapi.PipelineRef) (PipelineRef, *pipelineRefParams) {
+	params := map[string]string{}
+	for _, param := range pipelineRef.Params {
+		if param.Value.Type != tektonapi.ParamTypeString {
+			return PipelineRef{Raw: pipelineRef.DeepCopy()}, nil
+		}
+		params[param.Name] = param.Value.StringVal
+	}
+	// Returns the params other than the modeled ones, nil if they are the defaults
+	getStoredParams := func(modeledParams []string, defaultParams []tektonapi.Param) *pipelineRefParams {
+		extraParams := []tektonapi.Param{}
+		for _, param := range pipelineRef.Params {
+			if !slices.Contains(modeledParams, param.Name) {
+				extraParams = append(extraParams, param)
+			}
+		}
+		if reflect.DeepEqual(extraParams, defaultParams) {
+			return nil
+		}
+		return &pipelineRefParams{Resolver: string(pipelineRef.Resolver), Params: extraParams}
+	}
+
+	if pipelineRef.Name != "" || pipelineRef.APIVersion != "" {
+		return PipelineRef{Raw: pipelineRef.DeepCopy()}, nil
+	}
+	switch pipelineRef.Resolver {
+	case bundlesResolverName:
+		if params["name"] == "" || params["bundle"] == "" {
+			break
+		}
+		repository, tag, digest := parseBundleReference(params["bundle"])
+		return PipelineRef{
+			Bundle: &BundlePipelineRef{
+				Name:       params["name"],
+				Repository: repository,
+				Tag:        tag,
+				Digest:     digest,
+			},
+		}, getStoredParams([]string{"name", "bundle"},
+			[]tektonapi.Param{{Name: "kind", Value: *tektonapi.NewStructuredValues("pipeline")}})
+	case gitResolverName:
+		if params["url"] == "" || params["revision"] == "" || params["pathInRepo"] == "" {
+			break
+		}
+		return PipelineRef{
+			Git: &GitPipelineRef{
+				URL:        params["url"],
+				Revision:   params["revision"],
+				PathInRepo: params["pathInRepo"],
+			},
+		}, getStoredParams([]string{"url", "revision", "pathInRepo"}, []tektonapi.Param{})
+	}
+	return PipelineRef{Raw: pipelineRef.DeepCopy()}, nil
+}
+
+// parseBundleReference splits image reference, e.g. 'quay.io/org/bundle:tag@sha256:...', into repository, tag and digest.
+func parseBundleReference(bundle string) (string, string, string) {
+	repository, digest, _ := strings.Cut(bundle, "@")
+	tag := ""
+	// Port of the registry host may contain ':' as well
+	if tagIndex := strings.LastIndex(repository, ":"); tagIndex > strings.LastIndex(repository, "/") {
+		tag = repository[tagIndex+1:]
+		repository = repository[:tagIndex]
+	}
+	return repository, tag, digest
+}
+
+// splitValues converts v1alpha1 comma separated values into list.
+func splitValues(values string) []string {
+	if strings.TrimSpace(values) == "" {
+		return nil
+	}
+	var result []string
+	for _, value := range strings.Split(values, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+// joinValues converts list of values into v1alpha1 comma separated values.
+func joinValues(values []string) string {
+	return strings.Join(values, ",")
+}
+
+func splitValuesMap(values map[string]string) map[string][]string {
+	if values == nil {
+		return nil
+	}
+	result := make(map[string][]string, len(values))
+	for key, value := range values {
+		result[key] = splitValues(value)
+	}
+	return result
+}
+
+func joinValuesMap(values map[string][]string) map[string]string {
+	if values == nil {
+		return nil
+	}
+	result := make(map[string]string, len(values))
+	for key, value := range values {
+		result[key] = joinValues(value)
+	}
+	return result
+}
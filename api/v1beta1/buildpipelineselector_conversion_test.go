@@ -0,0 +1,275 @@
+/*
+Copyright 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"reflect"
+	"testing"
+
+	tektonapi "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/konflux-ci/build-service/api/v1alpha1"
+)
+
+const testDigest = "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+func TestBuildPipelineSelectorConversionRoundTrip(t *testing.T) {
+	dockerfileRequired := true
+	selector := &BuildPipelineSelector{
+		ObjectMeta: metav1.ObjectMeta{Name: "build-pipeline-selector", Namespace: "test"},
+		Spec: BuildPipelineSelectorSpec{
+			Mode: BuildPipelineSelectorModeOverride,
+			Selectors: []PipelineSelector{
+				{
+					Name: "docker",
+					PipelineRef: PipelineRef{Bundle: &BundlePipelineRef{
+						Name:       "docker-build",
+						Repository: "registry.io:5000/org/pipeline-docker-build",
+						Tag:        "latest",
+						Digest:     testDigest,
+					}},
+					PipelineParams: []PipelineParam{{Name: "engine", Value: "buildah"}},
+					Match: MatchCriteria{
+						Languages:          []string{"java", "go"},
+						DockerfileRequired: &dockerfileRequired,
+						ProjectFiles:       []string{"pom.xml", "build.gradle"},
+						Annotations:        map[string][]string{"builder": {"gradle", "maven"}},
+						Labels:             map[string][]string{"team": {"a"}},
+					},
+					Priority: 10,
+				},
+				{
+					Name: "git",
+					PipelineRef: PipelineRef{Git: &GitPipelineRef{
+						URL:        "https://github.com/org/pipelines",
+						Revision:   "main",
+						PathInRepo: "pipelines/build.yaml",
+					}},
+					Match: MatchCriteria{ComponentNames: []string{"my-component"}},
+				},
+			},
+		},
+	}
+
+	hub := &v1alpha1.BuildPipelineSelector{}
+	if err := selector.ConvertTo(hub); err != nil {
+		t.Fatalf("failed to convert to v1alpha1: %v", err)
+	}
+
+	hubSelector := hub.Spec.Selectors[0]
+	if hubSelector.WhenConditions.Language != "java,go" || hubSelector.WhenConditions.ProjectFiles != "pom.xml,build.gradle" {
+		t.Errorf("unexpected v1alpha1 when conditions: %+v", hubSelector.WhenConditions)
+	}
+	if hubSelector.WhenConditions.Annotations["builder"] != "gradle,maven" {
+		t.Errorf("unexpected v1alpha1 annotations condition: %v", hubSelector.WhenConditions.Annotations)
+	}
+	if hubSelector.PipelineRef.Resolver != "bundles" {
+		t.Errorf("expected bundles resolver, got %q", hubSelector.PipelineRef.Resolver)
+	}
+	for _, param := range hubSelector.PipelineRef.Params {
+		if param.Name == "bundle" && param.Value.StringVal != "registry.io:5000/org/pipeline-docker-build:latest@"+testDigest {
+			t.Errorf("unexpected bundle param: %s", param.Value.StringVal)
+		}
+	}
+	if hub.Spec.Selectors[1].PipelineRef.Resolver != "git" {
+		t.Errorf("expected git resolver, got %q", hub.Spec.Selectors[1].PipelineRef.Resolver)
+	}
+
+	converted := &BuildPipelineSelector{}
+	if err := converted.ConvertFrom(hub); err != nil {
+		t.Fatalf("failed to convert from v1alpha1: %v", err)
+	}
+	if !reflect.DeepEqual(selector, converted) {
+		t.Errorf("round trip conversion changed the object:\n got: %+v\nwant: %+v", converted.Spec, selector.Spec)
+	}
+}
+
+func TestBuildPipelineSelectorConvertFrom(t *testing.T) {
+	tests := []struct {
+		name        string
+		pipelineRef tektonapi.PipelineRef
+		when        v1alpha1.WhenCondition
+		want        PipelineSelector
+	}{
+		{
+			name: "should convert bundle reference without digest and trim comma separated values",
+			pipelineRef: tektonapi.PipelineRef{
+				ResolverRef: tektonapi.ResolverRef{
+					Resolver: "bundles",
+					Params: []tektonapi.Param{
+						{Name: "name", Value: *tektonapi.NewStructuredValues("docker-build")},
+						{Name: "bundle", Value: *tektonapi.NewStructuredValues("quay.io/org/bundle:v1")},
+						{Name: "kind", Value: *tektonapi.NewStructuredValues("pipeline")},
+					},
+				},
+			},
+			when: v1alpha1.WhenCondition{Language: "nodejs, node", Labels: map[string]string{"team": "a, b"}},
+			want: PipelineSelector{
+				PipelineRef: PipelineRef{Bundle: &BundlePipelineRef{Name: "docker-build", Repository: "quay.io/org/bundle", Tag: "v1"}},
+				Match:       MatchCriteria{Languages: []string{"nodejs", "node"}, Labels: map[string][]string{"team": {"a", "b"}}},
+			},
+		},
+		{
+			name: "should convert bundle reference with digest only",
+			pipelineRef: tektonapi.PipelineRef{
+				ResolverRef: tektonapi.ResolverRef{
+					Resolver: "bundles",
+					Params: []tektonapi.Param{
+						{Name: "name", Value: *tektonapi.NewStructuredValues("docker-build")},
+						{Name: "bundle", Value: *tektonapi.NewStructuredValues("quay.io/org/bundle@" + testDigest)},
+					},
+				},
+			},
+			want: PipelineSelector{
+				PipelineRef: PipelineRef{Bundle: &BundlePipelineRef{Name: "docker-build", Repository: "quay.io/org/bundle", Digest: testDigest}},
+			},
+		},
+		{
+			name:        "should keep reference with other resolver as raw reference",
+			pipelineRef: tektonapi.PipelineRef{ResolverRef: tektonapi.ResolverRef{Resolver: "cluster"}},
+			want: PipelineSelector{
+				PipelineRef: PipelineRef{Raw: &tektonapi.PipelineRef{ResolverRef: tektonapi.ResolverRef{Resolver: "cluster"}}},
+			},
+		},
+		{
+			name:        "should keep plain pipeline name as raw reference",
+			pipelineRef: tektonapi.PipelineRef{Name: "docker-build"},
+			want: PipelineSelector{
+				PipelineRef: PipelineRef{Raw: &tektonapi.PipelineRef{Name: "docker-build"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hub := &v1alpha1.BuildPipelineSelector{
+				Spec: v1alpha1.BuildPipelineSelectorSpec{
+					Selectors: []v1alpha1.PipelineSelector{{PipelineRef: tt.pipelineRef, WhenConditions: tt.when}},
+				},
+			}
+			selector := &BuildPipelineSelector{}
+			if err := selector.ConvertFrom(hub); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(selector.Spec.Selectors[0], tt.want) {
+				t.Errorf("got %+v, want %+v", selector.Spec.Selectors[0], tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildPipelineSelectorHubConversionRoundTrip(t *testing.T) {
+	stringParam := func(name, value string) tektonapi.Param {
+		return tektonapi.Param{Name: name, Value: *tektonapi.NewStructuredValues(value)}
+	}
+	resolverRef := func(resolver string, params ...tektonapi.Param) tektonapi.PipelineRef {
+		return tektonapi.PipelineRef{ResolverRef: tektonapi.ResolverRef{Resolver: tektonapi.ResolverName(resolver), Params: params}}
+	}
+
+	tests := []struct {
+		name            string
+		pipelineRefs    []tektonapi.PipelineRef
+		wantAnnotations bool
+	}{
+		{
+			name: "should keep bundle and git references with default params",
+			pipelineRefs: []tektonapi.PipelineRef{
+				resolverRef("bundles", stringParam("name", "docker-build"), stringParam("bundle", "quay.io/org/bundle:v1"), stringParam("kind", "pipeline")),
+				resolverRef("git", stringParam("url", "https://github.com/org/pipelines"), stringParam("revision", "main"), stringParam("pathInRepo", "build.yaml")),
+			},
+		},
+		{
+			name: "should keep plain pipeline name and other resolvers",
+			pipelineRefs: []tektonapi.PipelineRef{
+				{Name: "docker-build"},
+				resolverRef("cluster", stringParam("kind", "pipeline"), stringParam("name", "docker-build"), stringParam("namespace", "pipelines")),
+				resolverRef("hub", stringParam("catalog", "tekton"), stringParam("name", "buildah"), stringParam("version", "0.1")),
+				{ResolverRef: tektonapi.ResolverRef{Resolver: "bundles", Params: []tektonapi.Param{
+					{Name: "name", Value: *tektonapi.NewStructuredValues("docker-build")},
+					{Name: "bundle", Value: *tektonapi.NewStructuredValues("quay.io/org/bundle:v1")},
+					{Name: "tags", Value: *tektonapi.NewStructuredValues("a", "b")},
+				}}},
+			},
+		},
+		{
+			name: "should keep resolver params not modeled by bundle and git references",
+			pipelineRefs: []tektonapi.PipelineRef{
+				resolverRef("bundles", stringParam("name", "docker-build"), stringParam("bundle", "quay.io/org/bundle:v1"), stringParam("kind", "pipeline"), stringParam("serviceAccount", "puller")),
+				resolverRef("bundles", stringParam("name", "docker-build"), stringParam("bundle", "quay.io/org/bundle:v1")),
+				resolverRef("git", stringParam("url", "https://github.com/org/pipelines"), stringParam("revision", "main"), stringParam("pathInRepo", "build.yaml"), stringParam("token", "git-token")),
+			},
+			wantAnnotations: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hub := &v1alpha1.BuildPipelineSelector{
+				ObjectMeta: metav1.ObjectMeta{Name: "build-pipeline-selector", Namespace: "test", Annotations: map[string]string{"owner": "team"}},
+			}
+			for _, pipelineRef := range tt.pipelineRefs {
+				hub.Spec.Selectors = append(hub.Spec.Selectors, v1alpha1.PipelineSelector{PipelineRef: pipelineRef})
+			}
+			original := hub.DeepCopy()
+
+			selector := &BuildPipelineSelector{}
+			if err := selector.ConvertFrom(hub); err != nil {
+				t.Fatalf("failed to convert from v1alpha1: %v", err)
+			}
+			if _, exists := selector.Annotations[PipelineRefParamsAnnotationName]; exists != tt.wantAnnotations {
+				t.Errorf("expected %s annotation: %v, got annotations: %v", PipelineRefParamsAnnotationName, tt.wantAnnotations, selector.Annotations)
+			}
+			if !reflect.DeepEqual(hub, original) {
+				t.Errorf("conversion from v1alpha1 changed the hub object")
+			}
+
+			converted := &v1alpha1.BuildPipelineSelector{}
+			if err := selector.ConvertTo(converted); err != nil {
+				t.Fatalf("failed to convert to v1alpha1: %v", err)
+			}
+			if !reflect.DeepEqual(converted, original) {
+				t.Errorf("round trip conversion changed the object:\n got: %+v\nwant: %+v", converted, original)
+			}
+		})
+	}
+}
+
+func TestBuildPipelineSelectorConvertToIgnoresParamsOfOtherResolver(t *testing.T) {
+	selector := &BuildPipelineSelector{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "build-pipeline-selector",
+			Annotations: map[string]string{PipelineRefParamsAnnotationName: `{"0":{"resolver":"bundles","params":[{"name":"serviceAccount","value":"puller"}]}}`},
+		},
+		Spec: BuildPipelineSelectorSpec{
+			Selectors: []PipelineSelector{
+				{PipelineRef: PipelineRef{Git: &GitPipelineRef{URL: "https://github.com/org/pipelines", Revision: "main", PathInRepo: "build.yaml"}}},
+			},
+		},
+	}
+
+	hub := &v1alpha1.BuildPipelineSelector{}
+	if err := selector.ConvertTo(hub); err != nil {
+		t.Fatalf("failed to convert to v1alpha1: %v", err)
+	}
+	if hub.Annotations != nil {
+		t.Errorf("expected no annotations, got %v", hub.Annotations)
+	}
+	if params := hub.Spec.Selectors[0].PipelineRef.Params; len(params) != 3 {
+		t.Errorf("expected only git resolver params, got %v", params)
+	}
+}
@@ -0,0 +1,220 @@
+/*
+Copyright 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	tektonapi "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MatchCriteria defines requirements when specified build pipeline must be used.
+// All criteria are connected via AND, whereas values within any criterion connected via OR.
+// Example:
+//
+//	languages: [java]
+//	projectTypes: [spring, quarkus]
+//	projectFiles: [pom.xml, build.gradle]
+//	annotations:
+//	   builder: [gradle, maven]
+//
+// which means that language is 'java' AND (project type is 'spring' OR 'quarkus') AND
+// ('pom.xml' OR 'build.gradle' file is present in the component sources) AND
+// annotation 'builder' is present with value 'gradle' OR 'maven'.
+type MatchCriteria struct {
+	// Defines component languages to match, e.g. 'java'.
+	// The value to compare with is taken from devfile.metadata.language field.
+	// +kubebuilder:validation:Optional
+	// +listType=atomic
+	Languages []string `json:"languages,omitempty"`
+
+	// Defines types of project of the component to match, e.g. 'quarkus'.
+	// The value to compare with is taken from devfile.metadata.projectType field.
+	// +kubebuilder:validation:Optional
+	// +listType=atomic
+	ProjectTypes []string `json:"projectTypes,omitempty"`
+
+	// Defines if a Dockerfile should be present in the component.
+	// Note, unset (nil) value is not the same as false (unset means skip the dockerfile check).
+	// The value to compare with is taken from devfile components of image type.
+	// +kubebuilder:validation:Optional
+	DockerfileRequired *bool `json:"dockerfileRequired,omitempty"`
+
+	// Defines files which presence identifies the project type, e.g. 'package.json' or 'go.mod'.
+	// The criterion is met if any of the files is present in the component context directory of the git repository.
+	// +kubebuilder:validation:Optional
+	// +listType=atomic
+	ProjectFiles []string `json:"projectFiles,omitempty"`
+
+	// Defines allowed component names to match, e.g. 'my-component'.
+	// The value to compare with is taken from component.metadata.name field.
+	// +kubebuilder:validation:Optional
+	// +listType=atomic
+	ComponentNames []string `json:"componentNames,omitempty"`
+
+	// Defines annotations to match, each annotation with the list of allowed values.
+	// The values to compare with are taken from component.metadata.annotations field.
+	// +kubebuilder:validation:Optional
+	Annotations map[string][]string `json:"annotations,omitempty"`
+
+	// Defines labels to match, each label with the list of allowed values.
+	// The values to compare with are taken from component.metadata.labels field.
+	// +kubebuilder:validation:Optional
+	Labels map[string][]string `json:"labels,omitempty"`
+}
+
+// BundlePipelineRef references a build pipeline in a Tekton bundle.
+type BundlePipelineRef struct {
+	// Name of the pipeline in the bundle, e.g. 'docker-build'.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Image repository of the bundle without tag and digest, e.g. 'quay.io/konflux-ci/pipeline-docker-build'.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Repository string `json:"repository"`
+
+	// Tag of the bundle image, e.g. 'latest'. Optional if the digest is set.
+	// +kubebuilder:validation:Optional
+	Tag string `json:"tag,omitempty"`
+
+	// Digest of the bundle image, e.g. 'sha256:0123...'. Pins the build pipeline to the given bundle content.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=`^sha256:[a-f0-9]{64}$`
+	Digest string `json:"digest,omitempty"`
+}
+
+// GitPipelineRef references a build pipeline definition in a git repository.
+type GitPipelineRef struct {
+	// URL of the git repository with the pipeline definition.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	URL string `json:"url"`
+
+	// Revision of the git repository, e.g. branch, tag or commit sha.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Revision string `json:"revision"`
+
+	// Path to the pipeline definition in the git repository, e.g. 'pipelines/docker-build.yaml'.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	PathInRepo string `json:"pathInRepo"`
+}
+
+// PipelineRef references the build pipeline definition. Exactly one of the references must be set.
+// +kubebuilder:validation:MinProperties=1
+// +kubebuilder:validation:MaxProperties=1
+type PipelineRef struct {
+	// Build pipeline from a Tekton bundle, resolved by the Tekton bundles resolver.
+	// +kubebuilder:validation:Optional
+	Bundle *BundlePipelineRef `json:"bundle,omitempty"`
+
+	// Build pipeline from a git repository, resolved by the Tekton git resolver.
+	// +kubebuilder:validation:Optional
+	Git *GitPipelineRef `json:"git,omitempty"`
+
+	// Tekton pipeline reference used as is, e.g. with the cluster or hub Tekton resolver.
+	// Holds the v1alpha1 references which can't be expressed as bundle or git reference.
+	// +kubebuilder:validation:Optional
+	Raw *tektonapi.PipelineRef `json:"raw,omitempty"`
+}
+
+// PipelineParam is a type to describe pipeline parameters.
+type PipelineParam struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// PipelineSelector defines allowed build pipeline and criteria when it should be used.
+type PipelineSelector struct {
+	// Name of the selector item. Optional.
+	// +kubebuilder:validation:Optional
+	Name string `json:"name,omitempty"`
+
+	// Build Pipeline to use if the selector criteria are met.
+	// +kubebuilder:validation:Required
+	PipelineRef PipelineRef `json:"pipelineRef"`
+
+	// Extra arguments to add to the specified pipeline run.
+	// +kubebuilder:validation:Optional
+	// +listType=atomic
+	PipelineParams []PipelineParam `json:"pipelineParams,omitempty"`
+
+	// Defines the selector criteria when given build pipeline should be used.
+	// All criteria are connected via AND, whereas values within any criterion connected via OR.
+	// If the section is omitted, then the criteria are considered met (usually used for fallback item).
+	// +kubebuilder:validation:Optional
+	Match MatchCriteria `json:"match,omitempty"`
+
+	// Priority of the selector item, 0 if omitted.
+	// If several items match the component, the one with the highest priority is used.
+	// Items with the same priority are evaluated in the order they are listed.
+	// +kubebuilder:validation:Optional
+	Priority int32 `json:"priority,omitempty"`
+}
+
+// BuildPipelineSelectorMode describes how the BuildPipelineSelector relates to the less specific ones.
+type BuildPipelineSelectorMode string
+
+const (
+	// The less specific BuildPipelineSelectors are evaluated if no item of the BuildPipelineSelector matches the component.
+	BuildPipelineSelectorModeExtend BuildPipelineSelectorMode = "Extend"
+	// The less specific BuildPipelineSelectors are never evaluated, the BuildPipelineSelector replaces them.
+	BuildPipelineSelectorModeOverride BuildPipelineSelectorMode = "Override"
+)
+
+// BuildPipelineSelectorSpec defines the desired state of BuildPipelineSelector
+type BuildPipelineSelectorSpec struct {
+	// Defines chain of pipeline selectors.
+	// The matching item with the highest priority is used, the first one of them if several items have the same priority.
+	// +kubebuilder:validation:Required
+	Selectors []PipelineSelector `json:"selectors"`
+
+	// Defines how the selector relates to the less specific ones. BuildPipelineSelectors are evaluated from the most specific:
+	// the one named after the Application, the 'build-pipeline-selector' one in the namespace of the component
+	// and the global 'build-pipeline-selector' one in the build-service namespace.
+	// 'Extend' falls back to the less specific selectors if no item matches the component.
+	// 'Override' uses only this selector, so a namespace can replace the global default pipeline without falling back to it.
+	// Defaults to 'Extend'.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=Extend;Override
+	Mode BuildPipelineSelectorMode `json:"mode,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// BuildPipelineSelector is the Schema for the BuildPipelineSelectors API
+type BuildPipelineSelector struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec BuildPipelineSelectorSpec `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// BuildPipelineSelectorList contains a list of BuildPipelineSelector
+type BuildPipelineSelectorList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []BuildPipelineSelector `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&BuildPipelineSelector{}, &BuildPipelineSelectorList{})
+}
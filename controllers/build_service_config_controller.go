@@ -0,0 +1,267 @@
+/*
+Copyright 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	buildappstudiov1alpha1 "github.com/konflux-ci/build-service/api/v1alpha1"
+	. "github.com/konflux-ci/build-service/pkg/common"
+	"github.com/konflux-ci/build-service/pkg/featuregates"
+	"github.com/konflux-ci/build-service/pkg/git/github"
+	gp "github.com/konflux-ci/build-service/pkg/git/gitprovider"
+	l "github.com/konflux-ci/build-service/pkg/logs"
+	"github.com/konflux-ci/build-service/pkg/renovate"
+)
+
+// BuildServiceConfigReconciler applies the BuildServiceConfig object as the operator configuration,
+// which takes precedence over the environment variables and the feature gates flag of the Build Service.
+// The controllers read the configuration when they need it, so the changes are applied without restart.
+type BuildServiceConfigReconciler struct {
+	Client        client.Client
+	Scheme        *runtime.Scheme
+	EventRecorder record.EventRecorder
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *BuildServiceConfigReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&buildappstudiov1alpha1.BuildServiceConfig{}, builder.WithPredicates(predicate.NewPredicateFuncs(func(object client.Object) bool {
+			return object.GetName() == buildappstudiov1alpha1.BuildServiceConfigName
+		}), predicate.GenerationChangedPredicate{})).
+		Complete(r)
+}
+
+// +kubebuilder:rbac:groups=appstudio.redhat.com,resources=buildserviceconfigs,verbs=get;list;watch
+// +kubebuilder:rbac:groups=appstudio.redhat.com,resources=buildserviceconfigs/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+func (r *BuildServiceConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := ctrllog.FromContext(ctx).WithName("BuildServiceConfig")
+
+	buildServiceConfig := &buildappstudiov1alpha1.BuildServiceConfig{}
+	if err := r.Client.Get(ctx, req.NamespacedName, buildServiceConfig); err != nil {
+		if errors.IsNotFound(err) {
+			SetConfigOverrides(nil)
+			featuregates.SetOverrides(nil)
+			log.Info("BuildServiceConfig removed, using environment variables and feature gates flag")
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "failed to get BuildServiceConfig", l.Action, l.ActionView)
+		return ctrl.Result{}, err
+	}
+	if !buildServiceConfig.DeletionTimestamp.IsZero() {
+		SetConfigOverrides(nil)
+		featuregates.SetOverrides(nil)
+		return ctrl.Result{}, nil
+	}
+
+	condition := metav1.Condition{
+		Type:               buildappstudiov1alpha1.BuildServiceConfigAppliedConditionType,
+		ObservedGeneration: buildServiceConfig.Generation,
+	}
+	overrides, err := getBuildServiceConfigOverrides(buildServiceConfig.Spec)
+	if err != nil {
+		// Keep the previously applied configuration, the object must be fixed
+		log.Error(err, "BuildServiceConfig is invalid")
+		r.EventRecorder.Event(buildServiceConfig, "Warning", "InvalidBuildServiceConfig", err.Error())
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "InvalidConfiguration"
+		condition.Message = err.Error()
+	} else {
+		SetConfigOverrides(overrides)
+		featuregates.SetOverrides(buildServiceConfig.Spec.FeatureGates)
+		log.Info("BuildServiceConfig applied", "Options", len(overrides))
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "ConfigurationApplied"
+		condition.Message = "configuration is applied"
+	}
+
+	meta.SetStatusCondition(&buildServiceConfig.Status.Conditions, condition)
+	if err := r.Client.Status().Update(ctx, buildServiceConfig); err != nil {
+		log.Error(err, "failed to update BuildServiceConfig status", l.Action, l.ActionUpdate)
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// LoadBuildServiceConfig applies the BuildServiceConfig on the operator start,
+// so the controllers don't use the environment variables until the BuildServiceConfig is reconciled.
+// Invalid configuration is left to the reconciler to report.
+func LoadBuildServiceConfig(ctx context.Context, reader client.Reader) error {
+	buildServiceConfig := &buildappstudiov1alpha1.BuildServiceConfig{}
+	if err := reader.Get(ctx, types.NamespacedName{Name: buildappstudiov1alpha1.BuildServiceConfigName}, buildServiceConfig); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	overrides, err := getBuildServiceConfigOverrides(buildServiceConfig.Spec)
+	if err != nil {
+		return nil
+	}
+	SetConfigOverrides(overrides)
+	featuregates.SetOverrides(buildServiceConfig.Spec.FeatureGates)
+	return nil
+}
+
+// getBuildServiceConfigOverrides validates the configuration and returns the set values
+// by the names of the environment variables they override.
+func getBuildServiceConfigOverrides(spec buildappstudiov1alpha1.BuildServiceConfigSpec) (map[string]string, error) {
+	overrides := map[string]string{}
+	setString := func(envName, value string) {
+		if value != "" {
+			overrides[envName] = value
+		}
+	}
+	setInt := func(envName string, value *int32) {
+		if value != nil {
+			overrides[envName] = strconv.Itoa(int(*value))
+		}
+	}
+	setBool := func(envName string, value *bool) {
+		if value != nil {
+			overrides[envName] = strconv.FormatBool(*value)
+		}
+	}
+	setDuration := func(envName string, value *metav1.Duration) error {
+		if value == nil {
+			return nil
+		}
+		if value.Duration < 0 {
+			return fmt.Errorf("%s must not be negative: %s", envName, value.Duration)
+		}
+		overrides[envName] = value.Duration.String()
+		return nil
+	}
+	setList := func(envName string, values []string) error {
+		for _, value := range values {
+			if strings.TrimSpace(value) == "" || strings.Contains(value, ",") {
+				return fmt.Errorf("%s items must be non-empty and must not contain commas: %q", envName, value)
+			}
+		}
+		if len(values) > 0 {
+			overrides[envName] = strings.Join(values, ",")
+		}
+		return nil
+	}
+	setURL := func(envName, value string) error {
+		if value == "" {
+			return nil
+		}
+		if parsedURL, err := url.Parse(value); err != nil || (parsedURL.Scheme != "http" && parsedURL.Scheme != "https") || parsedURL.Host == "" {
+			return fmt.Errorf("%s must be an absolute http or https url: %q", envName, value)
+		}
+		overrides[envName] = value
+		return nil
+	}
+	setTemplate := func(envName, value string) error {
+		if value == "" {
+			return nil
+		}
+		if _, err := template.New(envName).Parse(value); err != nil {
+			return fmt.Errorf("%s template is invalid: %w", envName, err)
+		}
+		overrides[envName] = value
+		return nil
+	}
+
+	if renovateConfig := spec.Renovate; renovateConfig != nil {
+		setString(renovate.RenovateImageEnvName, renovateConfig.Image)
+		if err := setList(renovate.RenovateMatchPatternEnvName, renovateConfig.MatchPatterns); err != nil {
+			return nil, err
+		}
+		setInt(renovate.InstallationsPerJobEnvName, renovateConfig.InstallationsPerJob)
+		setInt(renovate.RepositoriesPerJobEnvName, renovateConfig.RepositoriesPerJob)
+		setInt(renovate.MaxParallelJobsEnvName, renovateConfig.MaxParallelJobs)
+		setString(renovate.ExecutionModeEnvName, renovateConfig.ExecutionMode)
+		if renovateConfig.SweepInterval != nil && renovateConfig.SweepInterval.Duration < renovate.MinSweepInterval {
+			return nil, fmt.Errorf("%s must be at least %s: %s", renovate.SweepIntervalEnvName, renovate.MinSweepInterval, renovateConfig.SweepInterval.Duration)
+		}
+		if err := setDuration(renovate.SweepIntervalEnvName, renovateConfig.SweepInterval); err != nil {
+			return nil, err
+		}
+		if err := setDuration(RenovateTriggeredSweepMinIntervalEnvName, renovateConfig.TriggeredSweepMinInterval); err != nil {
+			return nil, err
+		}
+		setString(RenovateSweepImageEnvName, renovateConfig.SweepTriggerImage)
+		setString(renovate.CosignImageEnvName, renovateConfig.CosignImage)
+	}
+
+	if pacConfig := spec.PipelinesAsCode; pacConfig != nil {
+		if err := setURL(pipelinesAsCodeRouteEnvVar, pacConfig.WebhookURL); err != nil {
+			return nil, err
+		}
+		setBool(gp.PipelinesAsCodeWebhhokInsecureSslEnvVar, pacConfig.WebhookInsecureSSL)
+		if err := setDuration(PaCWebhookSecretRotationPeriodEnvName, pacConfig.WebhookSecretRotationPeriod); err != nil {
+			return nil, err
+		}
+		setString(PaCProvisionModeEnvVar, pacConfig.ProvisionMode)
+		setBool(PaCDirectPushAllowedEnvVar, pacConfig.DirectPushAllowed)
+		setString(PipelineRunOnPRExpirationEnvVar, pacConfig.PullRequestImageExpiration)
+		if err := setList(GiteaHostsEnvVar, pacConfig.GiteaHosts); err != nil {
+			return nil, err
+		}
+		if err := setURL(github.GithubEnterpriseUrlEnvName, pacConfig.GithubEnterpriseURL); err != nil {
+			return nil, err
+		}
+		if err := setTemplate(PaCMergeRequestBranchTemplateEnvVar, pacConfig.MergeRequestBranchTemplate); err != nil {
+			return nil, err
+		}
+		if err := setTemplate(PaCMergeRequestCommitMessageTemplateEnvVar, pacConfig.MergeRequestCommitMessageTemplate); err != nil {
+			return nil, err
+		}
+		if err := setTemplate(PaCMergeRequestDescriptionTemplateEnvVar, pacConfig.MergeRequestDescriptionTemplate); err != nil {
+			return nil, err
+		}
+	}
+
+	if retentionConfig := spec.PipelineRunRetention; retentionConfig != nil {
+		setInt(PipelineRunRetentionSucceededEnvName, retentionConfig.KeepSucceeded)
+		setInt(PipelineRunRetentionFailedEnvName, retentionConfig.KeepFailed)
+		if err := setDuration(PipelineRunRetentionMaxAgeEnvName, retentionConfig.MaxAge); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := setDuration(ImageRegistrySecretRotationPeriodEnvName, spec.ImageRegistrySecretRotationPeriod); err != nil {
+		return nil, err
+	}
+
+	if err := featuregates.Validate(spec.FeatureGates); err != nil {
+		return nil, err
+	}
+
+	return overrides, nil
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+
+	buildappstudiov1alpha1 "github.com/konflux-ci/build-service/api/v1alpha1"
+	. "github.com/konflux-ci/build-service/pkg/common"
+)
+
+var _ = Describe("BuildServiceConfig controller", func() {
+
+	var (
+		buildServiceConfigKey = types.NamespacedName{Name: buildappstudiov1alpha1.BuildServiceConfigName}
+	)
+
+	getAppliedCondition := func() *metav1.Condition {
+		buildServiceConfig := &buildappstudiov1alpha1.BuildServiceConfig{}
+		if err := k8sClient.Get(ctx, buildServiceConfigKey, buildServiceConfig); err != nil {
+			return nil
+		}
+		return meta.FindStatusCondition(buildServiceConfig.Status.Conditions, buildappstudiov1alpha1.BuildServiceConfigAppliedConditionType)
+	}
+
+	Context("Test operator configuration", func() {
+
+		_ = AfterEach(func() {
+			buildServiceConfig := &buildappstudiov1alpha1.BuildServiceConfig{ObjectMeta: metav1.ObjectMeta{Name: buildServiceConfigKey.Name}}
+			if err := k8sClient.Delete(ctx, buildServiceConfig); err != nil && !errors.IsNotFound(err) {
+				Fail(err.Error())
+			}
+			Eventually(func() string {
+				return GetConfigValue(PipelineRunRetentionSucceededEnvName)
+			}, timeout, interval).Should(BeEmpty())
+		})
+
+		It("should apply configuration and reconfigure on change", func() {
+			buildServiceConfig := &buildappstudiov1alpha1.BuildServiceConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: buildServiceConfigKey.Name},
+				Spec: buildappstudiov1alpha1.BuildServiceConfigSpec{
+					PipelineRunRetention: &buildappstudiov1alpha1.BuildServicePipelineRunRetentionConfig{KeepSucceeded: ptr.To(int32(3))},
+				},
+			}
+			Expect(k8sClient.Create(ctx, buildServiceConfig)).To(Succeed())
+
+			Eventually(func() string {
+				return GetConfigValue(PipelineRunRetentionSucceededEnvName)
+			}, timeout, interval).Should(Equal("3"))
+			Eventually(func() metav1.ConditionStatus {
+				if condition := getAppliedCondition(); condition != nil {
+					return condition.Status
+				}
+				return ""
+			}, timeout, interval).Should(Equal(metav1.ConditionTrue))
+
+			Expect(k8sClient.Get(ctx, buildServiceConfigKey, buildServiceConfig)).To(Succeed())
+			buildServiceConfig.Spec.PipelineRunRetention.KeepSucceeded = ptr.To(int32(5))
+			Expect(k8sClient.Update(ctx, buildServiceConfig)).To(Succeed())
+
+			Eventually(func() string {
+				return GetConfigValue(PipelineRunRetentionSucceededEnvName)
+			}, timeout, interval).Should(Equal("5"))
+		})
+
+		It("should report invalid configuration and keep the applied one", func() {
+			buildServiceConfig := &buildappstudiov1alpha1.BuildServiceConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: buildServiceConfigKey.Name},
+				Spec: buildappstudiov1alpha1.BuildServiceConfigSpec{
+					PipelineRunRetention: &buildappstudiov1alpha1.BuildServicePipelineRunRetentionConfig{KeepSucceeded: ptr.To(int32(3))},
+				},
+			}
+			Expect(k8sClient.Create(ctx, buildServiceConfig)).To(Succeed())
+			Eventually(func() string {
+				return GetConfigValue(PipelineRunRetentionSucceededEnvName)
+			}, timeout, interval).Should(Equal("3"))
+
+			Expect(k8sClient.Get(ctx, buildServiceConfigKey, buildServiceConfig)).To(Succeed())
+			buildServiceConfig.Spec.PipelineRunRetention.KeepSucceeded = ptr.To(int32(5))
+			buildServiceConfig.Spec.ImageRegistrySecretRotationPeriod = &metav1.Duration{Duration: -time.Hour}
+			Expect(k8sClient.Update(ctx, buildServiceConfig)).To(Succeed())
+
+			Eventually(func() string {
+				if condition := getAppliedCondition(); condition != nil && condition.Status == metav1.ConditionFalse {
+					return condition.Reason
+				}
+				return ""
+			}, timeout, interval).Should(Equal("InvalidConfiguration"))
+			Expect(GetConfigValue(PipelineRunRetentionSucceededEnvName)).To(Equal("3"))
+		})
+	})
+})
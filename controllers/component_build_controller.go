@@ -20,6 +20,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"reflect"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
@@ -32,9 +33,11 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
+	pacv1alpha1 "github.com/openshift-pipelines/pipelines-as-code/pkg/apis/pipelinesascode/v1alpha1"
 	appstudiov1alpha1 "github.com/redhat-appstudio/application-api/api/v1alpha1"
 
 	"github.com/konflux-ci/build-service/pkg/boerrors"
@@ -45,11 +48,12 @@ import (
 )
 
 const (
-	BuildRequestAnnotationName                    = "build.appstudio.openshift.io/request"
-	BuildRequestTriggerSimpleBuildAnnotationValue = "trigger-simple-build"
-	BuildRequestTriggerPaCBuildAnnotationValue    = "trigger-pac-build"
-	BuildRequestConfigurePaCAnnotationValue       = "configure-pac"
-	BuildRequestUnconfigurePaCAnnotationValue     = "unconfigure-pac"
+	BuildRequestAnnotationName                     = "build.appstudio.openshift.io/request"
+	BuildRequestTriggerSimpleBuildAnnotationValue  = "trigger-simple-build"
+	BuildRequestTriggerPaCBuildAnnotationValue     = "trigger-pac-build"
+	BuildRequestConfigurePaCAnnotationValue        = "configure-pac"
+	BuildRequestUnconfigurePaCAnnotationValue      = "unconfigure-pac"
+	BuildRequestRotateWebhookSecretAnnotationValue = "rotate-webhook-secret"
 
 	BuildStatusAnnotationName = "build.appstudio.openshift.io/status"
 
@@ -72,11 +76,23 @@ const (
 	buildPipelineSelectorResourceName  = "build-pipeline-selector"
 	defaultBuildPipelineAnnotation     = "build.appstudio.openshift.io/pipeline"
 	buildPipelineConfigMapResourceName = "build-pipeline-config"
+	// Defaults for the generated build PipelineRuns, global in build-service namespace or per tenant namespace.
+	pipelineRunPolicyConfigMapName = "build-pipelinerun-policy"
+	pipelineRunPolicyConfigMapKey  = "policy.yaml"
+
+	// Requests hermetic build of the Component, i.e. build without network access.
+	// Translated into 'hermetic' parameter of the build PipelineRuns.
+	HermeticBuildAnnotationName = "build.appstudio.openshift.io/hermetic"
+	// Dependencies to prefetch for hermetic build, e.g. 'gomod' or '[{"type": "pip", "path": "."}]'.
+	// Translated into 'prefetch-input' parameter of the build PipelineRuns.
+	PrefetchInputAnnotationName = "build.appstudio.openshift.io/prefetch-input"
 )
 
 type BuildStatus struct {
 	Simple *SimpleBuildStatus `json:"simple,omitempty"`
 	PaC    *PaCBuildStatus    `json:"pac,omitempty"`
+	// Shows the latest push or simple build PipelineRun of the Component.
+	LastBuild *LastBuildStatus `json:"last-build,omitempty"`
 	// Shows build methods agnostic messages, e.g. invalid build request.
 	Message string `json:"message,omitempty"`
 }
@@ -102,10 +118,38 @@ type PaCBuildStatus struct {
 	MergeUrl string `json:"merge-url,omitempty"`
 	// Time of the last successful PaC configuration in RFC1123 format
 	ConfigurationTime string `json:"configuration-time,omitempty"`
+	// Time of the last successful push pipeline rerun request in RFC1123 format
+	LastBuildTriggerTime string `json:"last-build-trigger-time,omitempty"`
+	// Time of the last successful webhook secret rotation in RFC1123 format
+	WebhookSecretRotationTime string `json:"webhook-secret-rotation-time,omitempty"`
 
+	PaCRepositoryMode
 	ErrorInfo
 }
 
+// Shows how Pipelines as Code is notified about the Component repository events.
+// Decided on PaC provision, so it's not needed to resolve the PaC secret to check the PaC repository object.
+type PaCRepositoryMode struct {
+	// Shows that PaC is notified via webhook instead of git application.
+	Webhook bool `json:"webhook,omitempty"`
+	// Shows that the PaC GitHub application can't access the Component repository
+	// and the tenant webhook fallback secret is used instead.
+	WebhookFallback bool `json:"webhook-fallback,omitempty"`
+}
+
+type LastBuildStatus struct {
+	PipelineRunName string `json:"pipelinerun-name,omitempty"`
+	// Result of the PipelineRun.
+	// Values are: Running, Succeeded, Failed.
+	Result string `json:"result,omitempty"`
+	// Reason of the PipelineRun failure, e.g. Cancelled.
+	Reason string `json:"reason,omitempty"`
+	// Creation time of the PipelineRun in RFC1123 format
+	StartTime string `json:"start-time,omitempty"`
+	// Completion time of the PipelineRun in RFC1123 format
+	CompletionTime string `json:"completion-time,omitempty"`
+}
+
 // ComponentBuildReconciler watches AppStudio Component objects in order to
 // provision Pipelines as Code configuration for the Component or
 // submit initial builds and dependent resources if PaC is not configured.
@@ -125,7 +169,8 @@ func (r *ComponentBuildReconciler) SetupWithManager(mgr ctrl.Manager) error {
 				return true
 			},
 			UpdateFunc: func(e event.UpdateEvent) bool {
-				return true
+				// Retries of PaC clean up are scheduled with backoff, the reported progress must not trigger them
+				return !isOnlyPaCCleanupConditionChanged(e.ObjectOld, e.ObjectNew)
 			},
 			DeleteFunc: func(e event.DeleteEvent) bool {
 				return false
@@ -134,6 +179,29 @@ func (r *ComponentBuildReconciler) SetupWithManager(mgr ctrl.Manager) error {
 				return false
 			},
 		})).
+		// Detect changes of PaC repository objects in order to repair them.
+		// Status of the repository is updated by Pipelines as Code on each build, ignore it.
+		Watches(&pacv1alpha1.Repository{}, handler.EnqueueRequestForOwner(mgr.GetScheme(), mgr.GetRESTMapper(), &appstudiov1alpha1.Component{}),
+			builder.WithPredicates(predicate.Funcs{
+				CreateFunc: func(e event.CreateEvent) bool {
+					return false
+				},
+				UpdateFunc: func(e event.UpdateEvent) bool {
+					oldRepository, oldOk := e.ObjectOld.(*pacv1alpha1.Repository)
+					newRepository, newOk := e.ObjectNew.(*pacv1alpha1.Repository)
+					if !oldOk || !newOk {
+						return false
+					}
+					return !reflect.DeepEqual(oldRepository.Spec, newRepository.Spec) ||
+						!reflect.DeepEqual(oldRepository.OwnerReferences, newRepository.OwnerReferences)
+				},
+				DeleteFunc: func(e event.DeleteEvent) bool {
+					return true
+				},
+				GenericFunc: func(e event.GenericEvent) bool {
+					return false
+				},
+			})).
 		Complete(r)
 }
 
@@ -152,7 +220,7 @@ func updateMetricsTimes(componentIdForMetrics string, requestedAction string, re
 }
 
 //+kubebuilder:rbac:groups=appstudio.redhat.com,resources=components,verbs=get;list;watch;update;patch
-//+kubebuilder:rbac:groups=appstudio.redhat.com,resources=components/status,verbs=get;list;watch
+//+kubebuilder:rbac:groups=appstudio.redhat.com,resources=components/status,verbs=get;list;watch;update;patch
 //+kubebuilder:rbac:groups=appstudio.redhat.com,resources=buildpipelineselectors,verbs=get;list;watch;create;update;patch
 //+kubebuilder:rbac:groups=appstudio.redhat.com,resources=releaseplanadmissions,verbs=get;list;watch
 //+kubebuilder:rbac:groups=tekton.dev,resources=pipelineruns,verbs=create
@@ -185,6 +253,9 @@ func (r *ComponentBuildReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 
 	if getContainerImageRepositoryForComponent(&component) == "" {
 		// Container image must be set. It's not possible to proceed without it.
+		if err := r.requestImageRepository(ctx, &component); err != nil {
+			return ctrl.Result{}, err
+		}
 		log.Info("Waiting for ContainerImage to be set")
 		return ctrl.Result{}, nil
 	}
@@ -214,7 +285,7 @@ func (r *ComponentBuildReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 			}
 			if err == nil { // If pipeline service account found, unlink the secret from it
 				if _, generatedImageRepoSecretName, err := getComponentImageRepoAndSecretNameFromImageAnnotation(&component); err == nil {
-					if _, err := r.unlinkSecretFromServiceAccount(ctx, generatedImageRepoSecretName, pipelineSA.Name, pipelineSA.Namespace); err != nil {
+					if _, err := unlinkSecretFromServiceAccount(ctx, r.Client, generatedImageRepoSecretName, pipelineSA.Name, pipelineSA.Namespace); err != nil {
 						return ctrl.Result{}, err
 					}
 				}
@@ -235,18 +306,9 @@ func (r *ComponentBuildReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		}
 
 		if controllerutil.ContainsFinalizer(&component, PaCProvisionFinalizer) {
-			// In order to not to block the deletion of the Component,
-			// delete finalizer unconditionally and then try to do clean up ignoring errors.
-
-			// Delete Pipelines as Code provision finalizer
-			controllerutil.RemoveFinalizer(&component, PaCProvisionFinalizer)
-			if err := r.Client.Update(ctx, &component); err != nil {
-				return ctrl.Result{}, err
-			}
-			log.Info("PaC finalizer removed", l.Action, l.ActionDelete)
-
-			// Try to clean up Pipelines as Code configuration
-			_, _ = r.UndoPaCProvisionForComponent(ctx, &component)
+			// Clean up Pipelines as Code configuration retrying on failures.
+			// The finalizer is removed after limited number of attempts in order to not to block the deletion of the Component.
+			return r.cleanupPaCForDeletedComponent(ctx, &component)
 		}
 
 		return ctrl.Result{}, nil
@@ -288,8 +350,8 @@ func (r *ComponentBuildReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 			return ctrl.Result{}, nil
 		}
 	} else {
-		buildPipelineName, buildPipelineBundle, _ := getPipelineNameAndBundle(pipelineRef)
-		log.Info(fmt.Sprintf("Will use default pipeline from annotation %s : name: %s; bundle: %s", defaultBuildPipelineAnnotation, buildPipelineName, buildPipelineBundle))
+		buildPipelineDescription, _ := describePipelineRef(pipelineRef)
+		log.Info(fmt.Sprintf("Will use default pipeline from annotation %s : %s", defaultBuildPipelineAnnotation, buildPipelineDescription))
 	}
 
 	// Ensure pipeline service account exists
@@ -307,7 +369,7 @@ func (r *ComponentBuildReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 
 		// Check if the generated image is used
 		if imageRepoGenerated != "" && (component.Spec.ContainerImage == "" || imageRepoGenerated == getContainerImageRepository(component.Spec.ContainerImage)) {
-			_, err = r.linkSecretToServiceAccount(ctx, imageRepoSecretName, pipelineSA.Name, pipelineSA.Namespace, true)
+			_, err = linkSecretToServiceAccount(ctx, r.Client, imageRepoSecretName, pipelineSA.Name, pipelineSA.Namespace, true)
 			if err != nil {
 				return ctrl.Result{}, err
 			}
@@ -332,12 +394,72 @@ func (r *ComponentBuildReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 	requestedAction, requestedActionExists := component.Annotations[BuildRequestAnnotationName]
 	if !requestedActionExists {
 		if _, statusExists := component.Annotations[BuildStatusAnnotationName]; statusExists {
-			// Nothing to do
-			return ctrl.Result{}, nil
+			buildStatus := readBuildStatus(&component)
+			if buildStatus.PaC != nil && buildStatus.PaC.State == "enabled" {
+				// Builds silently stop if PaC repository object is modified or deleted.
+				// The repair resolves the PaC secret, so it's done only if the repository object doesn't match
+				// the PaC repository mode recorded on provision or to check whether the broken PaC secret has been fixed.
+				repairNeeded := isPaCSecretError(buildStatus.PaC.ErrId)
+				if !repairNeeded {
+					if repairNeeded, err = r.isPaCRepositoryRepairNeeded(ctx, &component, buildStatus.PaC.PaCRepositoryMode); err != nil {
+						return ctrl.Result{}, err
+					}
+				}
+				if repairNeeded {
+					repairMessage, webhookUsed, err := r.repairPaCRepository(ctx, &component)
+					if err != nil {
+						if boErr, ok := err.(*boerrors.BuildOpError); ok && boErr.IsPersistent() {
+							log.Error(err, "failed to repair PaC repository of the Component")
+							// Show broken PaC secret in the build status, so it's possible to act on it
+							errorInfo := ErrorInfo{ErrId: boErr.GetErrorId(), ErrMessage: boErr.ShortError()}
+							if isPaCSecretError(errorInfo.ErrId) && buildStatus.PaC.ErrorInfo != errorInfo {
+								buildStatus.PaC.ErrorInfo = errorInfo
+								writeBuildStatus(&component, buildStatus)
+								if err := r.Client.Update(ctx, &component); err != nil {
+									log.Error(err, "failed to update build status of the Component", l.Action, l.ActionUpdate)
+									return ctrl.Result{}, err
+								}
+							}
+							return ctrl.Result{}, nil
+						}
+						return ctrl.Result{}, err
+					}
+					if repairMessage != "" {
+						r.EventRecorder.Event(&component, "Warning", "PaCRepositoryRepaired", repairMessage)
+					}
+					if isPaCSecretError(buildStatus.PaC.ErrId) || webhookUsed != buildStatus.PaC.Webhook {
+						// The PaC secret has been fixed or the Component was provisioned before the PaC repository mode was recorded
+						buildStatus.PaC.ErrorInfo = ErrorInfo{}
+						buildStatus.PaC.Webhook = webhookUsed
+						writeBuildStatus(&component, buildStatus)
+						if err := r.Client.Update(ctx, &component); err != nil {
+							log.Error(err, "failed to update build status of the Component", l.Action, l.ActionUpdate)
+							return ctrl.Result{}, err
+						}
+						return ctrl.Result{}, nil
+					}
+				}
+
+				if rotationPeriod := getPaCWebhookSecretRotationPeriod(); rotationPeriod > 0 {
+					// Periodic rotation is suspended while the build status shows an error
+					if buildStatus.PaC.Webhook && buildStatus.PaC.ErrId == 0 {
+						if rotationDueIn := getPaCWebhookSecretRotationDueIn(buildStatus.PaC, rotationPeriod, time.Now()); rotationDueIn > 0 {
+							return ctrl.Result{RequeueAfter: rotationDueIn}, nil
+						}
+						log.Info("webhook secret rotation period elapsed, requesting the rotation")
+						requestedAction = BuildRequestRotateWebhookSecretAnnotationValue
+					}
+				}
+			}
+			if requestedAction == "" {
+				// Nothing to do
+				return ctrl.Result{}, nil
+			}
+		} else {
+			// Automatically build component after creation
+			log.Info("automatically requesting initial build for the new component")
+			requestedAction = BuildRequestTriggerSimpleBuildAnnotationValue
 		}
-		// Automatically build component after creation
-		log.Info("automatically requesting initial build for the new component")
-		requestedAction = BuildRequestTriggerSimpleBuildAnnotationValue
 	}
 
 	switch requestedAction {
@@ -386,7 +508,10 @@ func (r *ComponentBuildReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		buildStatus := readBuildStatus(&component)
 		if !(buildStatus.PaC != nil && buildStatus.PaC.State == "enabled") {
 			log.Info("Can't rerun push pipeline because Pipelines as Code isn't provisioned for the Component")
-			return ctrl.Result{}, nil
+			// Do not keep the request, it cannot be fulfilled until PaC is configured
+			buildStatus.Message = "can't rerun push pipeline because Pipelines as Code isn't provisioned for the Component"
+			writeBuildStatus(&component, buildStatus)
+			break
 		}
 
 		reconcileRequired, err := r.TriggerPaCBuild(ctx, &component)
@@ -397,6 +522,7 @@ func (r *ComponentBuildReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 				buildStatus := readBuildStatus(&component)
 				buildStatus.PaC.ErrId = boErr.GetErrorId()
 				buildStatus.PaC.ErrMessage = boErr.ShortError()
+				buildStatus.Message = "done"
 				writeBuildStatus(&component, buildStatus)
 			} else {
 				// transient error, retry
@@ -408,6 +534,12 @@ func (r *ComponentBuildReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 				return ctrl.Result{Requeue: true}, nil
 			}
 			bometrics.PushPipelineRebuildTriggerTimeMetric.Observe(time.Since(bometrics.ComponentTimesForMetrics[componentIdForMetrics].StartTimestamp).Seconds())
+
+			buildStatus := readBuildStatus(&component)
+			buildStatus.PaC.ErrorInfo = ErrorInfo{}
+			buildStatus.PaC.LastBuildTriggerTime = time.Now().Format(time.RFC1123)
+			buildStatus.Message = "done"
+			writeBuildStatus(&component, buildStatus)
 		}
 
 	case BuildRequestConfigurePaCAnnotationValue:
@@ -419,12 +551,35 @@ func (r *ComponentBuildReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		}()
 
 		pacBuildStatus := &PaCBuildStatus{}
-		if mergeUrl, err := r.ProvisionPaCForComponent(ctx, &component); err != nil {
+		var simpleBuildStatus *SimpleBuildStatus
+		if mergeUrl, repositoryMode, err := r.ProvisionPaCForComponent(ctx, &component); err != nil {
 			if boErr, ok := err.(*boerrors.BuildOpError); ok && boErr.IsPersistent() {
 				log.Error(err, "Pipelines as Code provision for the Component failed")
 				pacBuildStatus.State = "error"
 				pacBuildStatus.ErrId = boErr.GetErrorId()
 				pacBuildStatus.ErrMessage = boErr.ShortError()
+
+				// Do not leave new Component without any build if PaC cannot be configured for its repository
+				if initialBuild && isPaCUnavailableForRepository(boErr) {
+					log.Info("falling back to simple build for the new Component")
+					simpleBuildStatus = &SimpleBuildStatus{}
+					if err := r.SubmitNewBuild(ctx, &component); err != nil {
+						if boErr, ok := err.(*boerrors.BuildOpError); ok && boErr.IsPersistent() {
+							log.Error(err, "simple build submition for the Component failed")
+							simpleBuildStatus.ErrId = boErr.GetErrorId()
+							simpleBuildStatus.ErrMessage = boErr.ShortError()
+						} else {
+							// transient error, retry
+							log.Error(err, "simple build submition transient error")
+							return ctrl.Result{}, err
+						}
+					} else {
+						simpleBuildStatus.BuildStartTime = time.Now().Format(time.RFC1123)
+						r.EventRecorder.Event(&component, "Normal", "SimpleBuildFallback",
+							"Pipelines as Code cannot be configured for the Component repository, submitted simple build instead")
+						bometrics.ComponentOnboardingTimeMetric.Observe(time.Since(bometrics.ComponentTimesForMetrics[componentIdForMetrics].StartTimestamp).Seconds())
+					}
+				}
 			} else {
 				// transient error, retry
 				log.Error(err, "Pipelines as Code provision transient error")
@@ -434,6 +589,7 @@ func (r *ComponentBuildReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 			pacBuildStatus.State = "enabled"
 			pacBuildStatus.MergeUrl = mergeUrl
 			pacBuildStatus.ConfigurationTime = time.Now().Format(time.RFC1123)
+			pacBuildStatus.PaCRepositoryMode = repositoryMode
 			log.Info("Pipelines as Code provision for the Component finished successfully")
 
 			// initial PaC provision upon component creation
@@ -464,6 +620,9 @@ func (r *ComponentBuildReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		// Update build status annotation
 		buildStatus := readBuildStatus(&component)
 		buildStatus.PaC = pacBuildStatus
+		if simpleBuildStatus != nil {
+			buildStatus.Simple = simpleBuildStatus
+		}
 		buildStatus.Message = "done"
 		writeBuildStatus(&component, buildStatus)
 
@@ -521,6 +680,43 @@ func (r *ComponentBuildReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		buildStatus.Message = "done"
 		writeBuildStatus(&component, buildStatus)
 
+	case BuildRequestRotateWebhookSecretAnnotationValue:
+		updateMetricsTimes(componentIdForMetrics, requestedAction, reconcileStartTime)
+
+		buildStatus := readBuildStatus(&component)
+		if !(buildStatus.PaC != nil && buildStatus.PaC.State == "enabled") {
+			log.Info("Can't rotate webhook secret because Pipelines as Code isn't provisioned for the Component")
+			buildStatus.Message = "can't rotate webhook secret because Pipelines as Code isn't provisioned for the Component"
+			writeBuildStatus(&component, buildStatus)
+			break
+		}
+
+		rotated, err := r.RotatePaCWebhookSecret(ctx, &component)
+		if err != nil {
+			if boErr, ok := err.(*boerrors.BuildOpError); ok && boErr.IsPersistent() {
+				log.Error(err, "Failed to rotate webhook secret for the Component")
+				buildStatus.PaC.ErrId = boErr.GetErrorId()
+				buildStatus.PaC.ErrMessage = boErr.ShortError()
+				buildStatus.Message = "done"
+				writeBuildStatus(&component, buildStatus)
+			} else {
+				// transient error, retry
+				log.Error(err, "Failed to rotate webhook secret for the Component with transient error")
+				return ctrl.Result{}, err
+			}
+		} else if !rotated {
+			log.Info("Webhook secret rotation is not applicable, the Component uses Pipelines as Code application")
+			buildStatus.Message = "can't rotate webhook secret because the Component uses Pipelines as Code application"
+			writeBuildStatus(&component, buildStatus)
+		} else {
+			r.EventRecorder.Event(&component, "Normal", "PaCWebhookSecretRotated", "Pipelines as Code webhook secret rotated")
+
+			buildStatus.PaC.ErrorInfo = ErrorInfo{}
+			buildStatus.PaC.WebhookSecretRotationTime = time.Now().Format(time.RFC1123)
+			buildStatus.Message = "done"
+			writeBuildStatus(&component, buildStatus)
+		}
+
 	default:
 		if requestedAction == "" {
 			// Do not show error for empty annotation, consider it as noop.
@@ -547,6 +743,11 @@ func (r *ComponentBuildReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 	return ctrl.Result{}, nil
 }
 
+// isPaCSecretError checks whether the error is caused by missing or malformed Pipelines as Code secret.
+func isPaCSecretError(errId int) bool {
+	return errId == int(boerrors.EPaCSecretNotFound) || errId == int(boerrors.EPaCSecretInvalid)
+}
+
 func (r *ComponentBuildReconciler) WaitForCacheUpdate(ctx context.Context, namespace types.NamespacedName, component *appstudiov1alpha1.Component) {
 	log := ctrllog.FromContext(ctx)
 
@@ -583,6 +784,25 @@ func (r *ComponentBuildReconciler) WaitForCacheUpdate(ctx context.Context, names
 	}
 }
 
+// isPaCUnavailableForRepository checks whether the PaC provision error means that PaC cannot be used
+// for the Component git repository at all, e.g. the application isn't installed or access is not sufficient,
+// unlike configuration errors of build-service or the Component itself.
+func isPaCUnavailableForRepository(boErr *boerrors.BuildOpError) bool {
+	switch boErr.GetErrorId() {
+	case int(boerrors.EGitHubAppNotInstalled),
+		int(boerrors.EGitHubTokenUnauthorized),
+		int(boerrors.EGitHubNoResourceToOperateOn),
+		int(boerrors.EGitLabTokenUnauthorized),
+		int(boerrors.EGitLabTokenInsufficientScope),
+		int(boerrors.EBitbucketTokenUnauthorized),
+		int(boerrors.EBitbucketTokenInsufficientScope),
+		int(boerrors.EGiteaTokenUnauthorized),
+		int(boerrors.EGiteaTokenInsufficientScope):
+		return true
+	}
+	return false
+}
+
 func readBuildStatus(component *appstudiov1alpha1.Component) *BuildStatus {
 	if component.Annotations == nil {
 		return &BuildStatus{}
@@ -23,22 +23,29 @@ import (
 	"net/url"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 
+	"github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
 	buildappstudiov1alpha1 "github.com/konflux-ci/build-service/api/v1alpha1"
 	"github.com/konflux-ci/build-service/pkg/boerrors"
 	. "github.com/konflux-ci/build-service/pkg/common"
+	"github.com/konflux-ci/build-service/pkg/git"
+	gp "github.com/konflux-ci/build-service/pkg/git/gitprovider"
 	l "github.com/konflux-ci/build-service/pkg/logs"
 	pipelineselector "github.com/konflux-ci/build-service/pkg/pipeline-selector"
 	appstudiov1alpha1 "github.com/redhat-appstudio/application-api/api/v1alpha1"
 	devfile "github.com/redhat-appstudio/application-service/cdq-analysis/pkg"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/pod"
 	tektonapi "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/yaml"
 )
 
 type BuildPipeline struct {
@@ -51,7 +58,7 @@ var DevfileSearchForDockerfile = devfile.SearchForDockerfile
 
 // getGitProvider returns git provider name based on the repository url, e.g. github, gitlab, etc or git-privider annotation
 func getGitProvider(component appstudiov1alpha1.Component) (string, error) {
-	allowedGitProviders := map[string]bool{"github": true, "gitlab": true, "bitbucket": true}
+	allowedGitProviders := map[string]bool{"github": true, "gitlab": true, "bitbucket": true, "gitea": true, "azure": true}
 	gitProvider := ""
 
 	if component.Spec.Source.GitSource == nil {
@@ -60,18 +67,24 @@ func getGitProvider(component appstudiov1alpha1.Component) (string, error) {
 	}
 	sourceUrl := component.Spec.Source.GitSource.URL
 
+	var host string
 	if strings.HasPrefix(sourceUrl, "git@") {
 		// git@github.com:redhat-appstudio/application-service.git
 		sourceUrl = strings.TrimPrefix(sourceUrl, "git@")
-		host := strings.Split(sourceUrl, ":")[0]
+		host = strings.Split(sourceUrl, ":")[0]
 		gitProvider = strings.Split(host, ".")[0]
+		if host == git.AzureDevOpsSshHost {
+			// git@ssh.dev.azure.com:v3/organization/project/repository
+			gitProvider = "azure"
+		}
 	} else {
 		// https://github.com/redhat-appstudio/application-service
 		u, err := url.Parse(sourceUrl)
 		if err != nil {
 			return "", err
 		}
-		uParts := strings.Split(u.Hostname(), ".")
+		host = u.Hostname()
+		uParts := strings.Split(host, ".")
 		if len(uParts) == 1 {
 			gitProvider = uParts[0]
 		} else {
@@ -79,6 +92,10 @@ func getGitProvider(component appstudiov1alpha1.Component) (string, error) {
 		}
 	}
 
+	if isGiteaHost(host) {
+		return "gitea", nil
+	}
+
 	var err error
 	if !allowedGitProviders[gitProvider] {
 		// Self-hosted git provider, check for git-provider annotation on the component
@@ -97,6 +114,19 @@ func getGitProvider(component appstudiov1alpha1.Component) (string, error) {
 	return gitProvider, err
 }
 
+// isGiteaHost checks whether the given host is one of the Gitea (or Forgejo) instances configured via GITEA_HOSTS environment variable.
+func isGiteaHost(host string) bool {
+	if host == "" {
+		return false
+	}
+	for _, giteaHost := range strings.Split(GetConfigValue(GiteaHostsEnvVar), ",") {
+		if strings.TrimSpace(giteaHost) == host {
+			return true
+		}
+	}
+	return false
+}
+
 // GetBuildPipelineFromComponentAnnotation parses pipeline annotation on component and returns build pipeline
 func GetBuildPipelineFromComponentAnnotation(component *appstudiov1alpha1.Component) (*tektonapi.PipelineRef, error) {
 	buildPipeline, err := readBuildPipelineAnnotation(component)
@@ -121,9 +151,9 @@ func GetBuildPipelineFromComponentAnnotation(component *appstudiov1alpha1.Compon
 }
 
 // GetPipelineForComponent searches for the build pipeline to use on the component.
-func (r *ComponentBuildReconciler) GetPipelineForComponent(ctx context.Context, component *appstudiov1alpha1.Component) (*tektonapi.PipelineRef, []tektonapi.Param, error) {
+// The git client is used to detect the project files the selectors match on, in the given branch of the component repository.
+func (r *ComponentBuildReconciler) GetPipelineForComponent(ctx context.Context, component *appstudiov1alpha1.Component, gitClient gp.GitProviderClient, branch string) (*tektonapi.PipelineRef, []tektonapi.Param, error) {
 	var pipelineSelectors []buildappstudiov1alpha1.BuildPipelineSelector
-	pipelineSelector := &buildappstudiov1alpha1.BuildPipelineSelector{}
 
 	pipelineSelectorKeys := []types.NamespacedName{
 		// First try specific config for the application
@@ -135,18 +165,23 @@ func (r *ComponentBuildReconciler) GetPipelineForComponent(ctx context.Context,
 	}
 
 	for _, pipelineSelectorKey := range pipelineSelectorKeys {
+		pipelineSelector := &buildappstudiov1alpha1.BuildPipelineSelector{}
 		if err := r.Client.Get(ctx, pipelineSelectorKey, pipelineSelector); err != nil {
 			if !errors.IsNotFound(err) {
 				return nil, nil, err
 			}
 			// The config is not found, try the next one in the hierarchy
-		} else {
-			pipelineSelectors = append(pipelineSelectors, *pipelineSelector)
+			continue
+		}
+		pipelineSelectors = append(pipelineSelectors, *pipelineSelector)
+		if pipelineSelector.Spec.Mode == buildappstudiov1alpha1.BuildPipelineSelectorModeOverride {
+			// The less specific configs are replaced by this one
+			break
 		}
 	}
 
 	if len(pipelineSelectors) > 0 {
-		pipelineRef, pipelineParams, err := pipelineselector.SelectPipelineForComponent(component, pipelineSelectors)
+		pipelineRef, pipelineParams, err := pipelineselector.SelectPipelineForComponent(component, pipelineSelectors, getProjectFileChecker(component, gitClient, branch))
 		if err != nil {
 			return nil, nil, err
 		}
@@ -159,6 +194,96 @@ func (r *ComponentBuildReconciler) GetPipelineForComponent(ctx context.Context,
 	return nil, nil, boerrors.NewBuildOpError(boerrors.EBuildPipelineSelectorNotDefined, nil)
 }
 
+// PipelineRunPolicy defines defaults which are injected into the build PipelineRuns generated by build-service.
+type PipelineRunPolicy struct {
+	// Timeouts of the PipelineRun, its tasks and finally tasks.
+	Timeouts *tektonapi.TimeoutFields `json:"timeouts,omitempty"`
+	// Compute resources and pod templates of the pipeline tasks, matched by the pipeline task name.
+	TaskRunSpecs []tektonapi.PipelineTaskRunSpec `json:"taskRunSpecs,omitempty"`
+	// Pod template of all the TaskRuns of the PipelineRun.
+	PodTemplate *pod.PodTemplate `json:"podTemplate,omitempty"`
+}
+
+// getPipelineRunPolicy reads the PipelineRun policy which applies to the Component.
+// The policy in the Component namespace overrides the global one from build-service namespace field by field,
+// so heavy builds can get more resources without repeating the whole policy.
+// Returns nil if no policy is defined.
+func (r *ComponentBuildReconciler) getPipelineRunPolicy(ctx context.Context, component *appstudiov1alpha1.Component) (*PipelineRunPolicy, error) {
+	var policy *PipelineRunPolicy
+	for _, namespace := range []string{BuildServiceNamespaceName, component.Namespace} {
+		configMap := &corev1.ConfigMap{}
+		if err := r.Client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: pipelineRunPolicyConfigMapName}, configMap); err != nil {
+			if !errors.IsNotFound(err) {
+				return nil, err
+			}
+			continue
+		}
+		namespacePolicy := &PipelineRunPolicy{}
+		if err := yaml.UnmarshalStrict([]byte(configMap.Data[pipelineRunPolicyConfigMapKey]), namespacePolicy); err != nil {
+			return nil, boerrors.NewBuildOpError(boerrors.EInvalidPipelineRunPolicy,
+				fmt.Errorf("failed to parse PipelineRun policy from %s ConfigMap in %s namespace: %w", pipelineRunPolicyConfigMapName, namespace, err))
+		}
+		policy = mergePipelineRunPolicies(policy, namespacePolicy)
+	}
+	return policy, nil
+}
+
+// mergePipelineRunPolicies returns the base policy with the fields defined in the override replaced.
+// Task run specs are merged by the pipeline task name.
+func mergePipelineRunPolicies(base, override *PipelineRunPolicy) *PipelineRunPolicy {
+	if base == nil {
+		return override
+	}
+	merged := *base
+	if override.Timeouts != nil {
+		merged.Timeouts = override.Timeouts
+	}
+	if override.PodTemplate != nil {
+		merged.PodTemplate = override.PodTemplate
+	}
+	merged.TaskRunSpecs = mergeTaskRunSpecs(merged.TaskRunSpecs, override.TaskRunSpecs)
+	return &merged
+}
+
+// mergeTaskRunSpecs returns the existing task run specs with the additional ones added or replacing the existing
+// for the same pipeline task.
+func mergeTaskRunSpecs(existingSpecs, additionalSpecs []tektonapi.PipelineTaskRunSpec) []tektonapi.PipelineTaskRunSpec {
+	var merged []tektonapi.PipelineTaskRunSpec
+	for _, existingSpec := range existingSpecs {
+		overridden := false
+		for _, additionalSpec := range additionalSpecs {
+			if additionalSpec.PipelineTaskName == existingSpec.PipelineTaskName {
+				overridden = true
+				break
+			}
+		}
+		if !overridden {
+			merged = append(merged, existingSpec)
+		}
+	}
+	return append(merged, additionalSpecs...)
+}
+
+// applyPipelineRunPolicy injects the policy into the build PipelineRun.
+func applyPipelineRunPolicy(pipelineRun *tektonapi.PipelineRun, policy *PipelineRunPolicy) {
+	if policy == nil {
+		return
+	}
+	if policy.Timeouts != nil {
+		pipelineRun.Spec.Timeouts = policy.Timeouts.DeepCopy()
+	}
+	if len(policy.TaskRunSpecs) > 0 {
+		taskRunSpecs := make([]tektonapi.PipelineTaskRunSpec, 0, len(policy.TaskRunSpecs))
+		for _, taskRunSpec := range policy.TaskRunSpecs {
+			taskRunSpecs = append(taskRunSpecs, *taskRunSpec.DeepCopy())
+		}
+		pipelineRun.Spec.TaskRunSpecs = mergeTaskRunSpecs(pipelineRun.Spec.TaskRunSpecs, taskRunSpecs)
+	}
+	if policy.PodTemplate != nil {
+		pipelineRun.Spec.TaskRunTemplate.PodTemplate = policy.PodTemplate.DeepCopy()
+	}
+}
+
 func (r *ComponentBuildReconciler) ensurePipelineServiceAccount(ctx context.Context, namespace string) (*corev1.ServiceAccount, error) {
 	log := ctrllog.FromContext(ctx)
 
@@ -185,7 +310,7 @@ func (r *ComponentBuildReconciler) ensurePipelineServiceAccount(ctx context.Cont
 	return pipelinesServiceAccount, nil
 }
 
-func (r *ComponentBuildReconciler) linkSecretToServiceAccount(ctx context.Context, secretName, serviceAccountName, namespace string, isPullSecret bool) (bool, error) {
+func linkSecretToServiceAccount(ctx context.Context, c client.Client, secretName, serviceAccountName, namespace string, isPullSecret bool) (bool, error) {
 	log := ctrllog.FromContext(ctx)
 
 	if secretName == "" {
@@ -194,7 +319,7 @@ func (r *ComponentBuildReconciler) linkSecretToServiceAccount(ctx context.Contex
 	}
 
 	serviceAccount := &corev1.ServiceAccount{}
-	err := r.Client.Get(ctx, types.NamespacedName{Name: serviceAccountName, Namespace: namespace}, serviceAccount)
+	err := c.Get(ctx, types.NamespacedName{Name: serviceAccountName, Namespace: namespace}, serviceAccount)
 	if err != nil {
 		if errors.IsNotFound(err) {
 			return false, nil
@@ -235,7 +360,7 @@ func (r *ComponentBuildReconciler) linkSecretToServiceAccount(ctx context.Contex
 
 	// Update service account if needed
 	if isNewSecretLinked {
-		err := r.Client.Update(ctx, serviceAccount)
+		err := c.Update(ctx, serviceAccount)
 		if err != nil {
 			log.Error(err, fmt.Sprintf("Unable to update service account %s", serviceAccount.Name), l.Action, l.ActionUpdate)
 			return false, err
@@ -248,11 +373,11 @@ func (r *ComponentBuildReconciler) linkSecretToServiceAccount(ctx context.Contex
 
 // unlinkSecretFromServiceAccount ensures that the given secret is not linked with the provided service account.
 // Returns true if the secret was unlinked, false if the link didn't exist.
-func (r *ComponentBuildReconciler) unlinkSecretFromServiceAccount(ctx context.Context, secretNameToRemove, serviceAccountName, namespace string) (bool, error) {
+func unlinkSecretFromServiceAccount(ctx context.Context, c client.Client, secretNameToRemove, serviceAccountName, namespace string) (bool, error) {
 	log := ctrllog.FromContext(ctx)
 
 	serviceAccount := &corev1.ServiceAccount{}
-	err := r.Client.Get(ctx, types.NamespacedName{Name: serviceAccountName, Namespace: namespace}, serviceAccount)
+	err := c.Get(ctx, types.NamespacedName{Name: serviceAccountName, Namespace: namespace}, serviceAccount)
 	if err != nil {
 		if errors.IsNotFound(err) {
 			return false, nil
@@ -290,7 +415,7 @@ func (r *ComponentBuildReconciler) unlinkSecretFromServiceAccount(ctx context.Co
 	}
 
 	if isSecretUnlinked {
-		if err := r.Client.Update(ctx, serviceAccount); err != nil {
+		if err := c.Update(ctx, serviceAccount); err != nil {
 			log.Error(err, fmt.Sprintf("Unable to update pipeline service account %v", serviceAccount), l.Action, l.ActionUpdate)
 			return false, err
 		}
@@ -310,6 +435,39 @@ func getContainerImageRepositoryForComponent(component *appstudiov1alpha1.Compon
 	return ""
 }
 
+// Visibility of the image repository requested for a Component without ContainerImage.
+const defaultImageRepositoryVisibility = "public"
+
+// requestImageRepository asks image controller to provision image repository for the Component
+// which is onboarded without ContainerImage.
+// Image controller sets image.redhat.com/image annotation with the repository and the secret to push into it,
+// which triggers a new reconcile of the Component.
+// Nothing is done if the repository has been already requested or the request has been opted out.
+func (r *ComponentBuildReconciler) requestImageRepository(ctx context.Context, component *appstudiov1alpha1.Component) error {
+	log := ctrllog.FromContext(ctx)
+
+	if !component.DeletionTimestamp.IsZero() || component.Spec.Source.GitSource == nil || component.Spec.Source.GitSource.URL == "" {
+		return nil
+	}
+	if _, exists := component.Annotations[ImageRepoAnnotationName]; exists {
+		return nil
+	}
+	if _, exists := component.Annotations[ImageRepoGenerateAnnotationName]; exists {
+		return nil
+	}
+
+	if component.Annotations == nil {
+		component.Annotations = make(map[string]string)
+	}
+	component.Annotations[ImageRepoGenerateAnnotationName] = fmt.Sprintf(`{"visibility":"%s"}`, defaultImageRepositoryVisibility)
+	if err := r.Client.Update(ctx, component); err != nil {
+		log.Error(err, "failed to request image repository for the Component", l.Action, l.ActionUpdate)
+		return err
+	}
+	log.Info("Requested image repository for the Component", l.Action, l.ActionUpdate)
+	return nil
+}
+
 // getContainerImageRepository removes tag or SHA has from container image reference
 func getContainerImageRepository(image string) string {
 	if strings.Contains(image, "@") {
@@ -358,6 +516,53 @@ func mergeAndSortTektonParams(existedParams, additionalParams []tektonapi.Param)
 	return params
 }
 
+// getComponentAnnotationParams returns build pipeline params requested via the Component annotations.
+// The params take precedence over the ones defined in the build pipeline selector,
+// so that every regeneration of the PipelineRun definitions keeps them in sync with the Component.
+func getComponentAnnotationParams(component *appstudiov1alpha1.Component) []tektonapi.Param {
+	var params []tektonapi.Param
+	if value, exists := component.Annotations[HermeticBuildAnnotationName]; exists {
+		if hermetic, err := strconv.ParseBool(value); err == nil {
+			params = append(params, tektonapi.Param{Name: "hermetic", Value: tektonapi.ParamValue{Type: "string", StringVal: strconv.FormatBool(hermetic)}})
+		}
+	}
+	if value := strings.TrimSpace(component.Annotations[PrefetchInputAnnotationName]); value != "" {
+		// Structured input must be valid json, otherwise prefetch fails in the middle of the build
+		isStructured := strings.HasPrefix(value, "{") || strings.HasPrefix(value, "[")
+		if !isStructured || json.Valid([]byte(value)) {
+			params = append(params, tektonapi.Param{Name: "prefetch-input", Value: tektonapi.ParamValue{Type: "string", StringVal: value}})
+		}
+	}
+	return params
+}
+
+// applyPipelineParamDefaults fills in default values of the params declared by the build pipeline
+// which are not set in the PipelineRun, so the PipelineRun definitions list all the pipeline params explicitly.
+// Returns names of the declared params without default value which are not set in the PipelineRun.
+func applyPipelineParamDefaults(pipelineRun *tektonapi.PipelineRun, pipelineParamSpecs []tektonapi.ParamSpec) []string {
+	setParams := make(map[string]bool)
+	for _, param := range pipelineRun.Spec.Params {
+		setParams[param.Name] = true
+	}
+
+	var defaultParams []tektonapi.Param
+	var missingParams []string
+	for _, paramSpec := range pipelineParamSpecs {
+		if setParams[paramSpec.Name] {
+			continue
+		}
+		if paramSpec.Default == nil {
+			missingParams = append(missingParams, paramSpec.Name)
+			continue
+		}
+		defaultParams = append(defaultParams, tektonapi.Param{Name: paramSpec.Name, Value: *paramSpec.Default.DeepCopy()})
+	}
+	if len(defaultParams) > 0 {
+		pipelineRun.Spec.Params = mergeAndSortTektonParams(pipelineRun.Spec.Params, defaultParams)
+	}
+	return missingParams
+}
+
 func generateVolumeClaimTemplate() *corev1.PersistentVolumeClaim {
 	return &corev1.PersistentVolumeClaim{
 		Spec: corev1.PersistentVolumeClaimSpec{
@@ -373,6 +578,25 @@ func generateVolumeClaimTemplate() *corev1.PersistentVolumeClaim {
 	}
 }
 
+// getDockerfileBuildContext returns build context declared in the devfile, if any.
+func getDockerfileBuildContext(dockerfile *v1alpha2.DockerfileImage) string {
+	if dockerfile == nil {
+		return ""
+	}
+	return dockerfile.BuildContext
+}
+
+// getComponentContextDir returns directory of the git repository the Component is built from, with trailing slash.
+// The directory is combined from the Component git source context and build context declared in the devfile.
+// Returns empty string if the Component occupies the whole git repository.
+func getComponentContextDir(component *appstudiov1alpha1.Component, dockerfile *v1alpha2.DockerfileImage) string {
+	contextDir := getPathContext(component.Spec.Source.GitSource.Context, getDockerfileBuildContext(dockerfile))
+	if contextDir == "" || contextDir == "." {
+		return ""
+	}
+	return contextDir + "/"
+}
+
 func getPathContext(gitContext, dockerfileContext string) string {
 	if gitContext == "" && dockerfileContext == "" {
 		return ""
@@ -384,8 +608,54 @@ func getPathContext(gitContext, dockerfileContext string) string {
 	return path
 }
 
+// Well known Dockerfile locations relative to the Component context directory, in order of precedence.
+var dockerfileCandidates = []string{"Dockerfile", "Containerfile", "docker/Dockerfile", "docker/Containerfile"}
+
+const defaultDockerfilePath = "Dockerfile"
+
+// detectDockerfilePath returns Dockerfile location of the Component.
+// Dockerfile URL set in the Component git source takes precedence,
+// otherwise well known locations are checked in the Component context directory of the given branch.
+// Returns path relative to the context directory or the default "Dockerfile" if nothing is found.
+func detectDockerfilePath(component *appstudiov1alpha1.Component, gitClient gp.GitProviderClient, branch string) (string, error) {
+	gitSource := component.Spec.Source.GitSource
+	if gitSource.DockerfileURL != "" {
+		return gitSource.DockerfileURL, nil
+	}
+
+	for _, candidate := range dockerfileCandidates {
+		filePath := strings.TrimPrefix(filepath.Join(gitSource.Context, candidate), string(filepath.Separator))
+		exists, err := gitClient.IsFileExist(gitSource.URL, branch, filePath)
+		if err != nil {
+			return "", err
+		}
+		if exists {
+			return candidate, nil
+		}
+	}
+	return defaultDockerfilePath, nil
+}
+
+// getProjectFileChecker returns function which checks presence of the given file in the component context directory.
+func getProjectFileChecker(component *appstudiov1alpha1.Component, gitClient gp.GitProviderClient, branch string) pipelineselector.ProjectFileChecker {
+	if gitClient == nil {
+		return nil
+	}
+	gitSource := component.Spec.Source.GitSource
+	return func(fileName string) (bool, error) {
+		filePath := strings.TrimPrefix(filepath.Join(gitSource.Context, fileName), string(filepath.Separator))
+		return gitClient.IsFileExist(gitSource.URL, branch, filePath)
+	}
+}
+
+// Supported Tekton resolvers of the build pipeline reference.
+const (
+	bundlesResolverName = "bundles"
+	gitResolverName     = "git"
+)
+
 func getPipelineNameAndBundle(pipelineRef *tektonapi.PipelineRef) (string, string, error) {
-	if pipelineRef.Resolver != "" && pipelineRef.Resolver != "bundles" {
+	if pipelineRef.Resolver != "" && pipelineRef.Resolver != bundlesResolverName {
 		return "", "", boerrors.NewBuildOpError(
 			boerrors.EUnsupportedPipelineRef,
 			fmt.Errorf("unsupported Tekton resolver %q", pipelineRef.Resolver),
@@ -414,6 +684,55 @@ func getPipelineNameAndBundle(pipelineRef *tektonapi.PipelineRef) (string, strin
 	return name, bundle, nil
 }
 
+// getPipelineGitSource returns git repository url, revision and path to the pipeline definition
+// of the pipelineRef which uses the Tekton git resolver.
+func getPipelineGitSource(pipelineRef *tektonapi.PipelineRef) (string, string, string, error) {
+	if pipelineRef.Resolver != gitResolverName {
+		return "", "", "", boerrors.NewBuildOpError(
+			boerrors.EUnsupportedPipelineRef,
+			fmt.Errorf("unsupported Tekton resolver %q", pipelineRef.Resolver),
+		)
+	}
+
+	var url, revision, pathInRepo string
+	for _, param := range pipelineRef.Params {
+		switch param.Name {
+		case "url":
+			url = param.Value.StringVal
+		case "revision":
+			revision = param.Value.StringVal
+		case "pathInRepo":
+			pathInRepo = param.Value.StringVal
+		}
+	}
+
+	if url == "" || revision == "" || pathInRepo == "" {
+		return "", "", "", boerrors.NewBuildOpError(
+			boerrors.EMissingParamsForGitResolver,
+			fmt.Errorf("missing url, revision or pathInRepo in pipelineRef: url=%s revision=%s pathInRepo=%s", url, revision, pathInRepo),
+		)
+	}
+
+	return url, revision, pathInRepo, nil
+}
+
+// describePipelineRef validates the pipelineRef and returns its human readable description for logs.
+func describePipelineRef(pipelineRef *tektonapi.PipelineRef) (string, error) {
+	if pipelineRef.Resolver == gitResolverName {
+		url, revision, pathInRepo, err := getPipelineGitSource(pipelineRef)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s pipeline from %s git repository at %s revision", pathInRepo, url, revision), nil
+	}
+
+	name, bundle, err := getPipelineNameAndBundle(pipelineRef)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s pipeline from %s bundle", name, bundle), nil
+}
+
 func readBuildPipelineAnnotation(component *appstudiov1alpha1.Component) (*BuildPipeline, error) {
 	if component.Annotations == nil {
 		return &BuildPipeline{}, nil
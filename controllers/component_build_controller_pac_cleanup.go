@@ -0,0 +1,210 @@
+/*
+Copyright 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	appstudiov1alpha1 "github.com/redhat-appstudio/application-api/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/konflux-ci/build-service/pkg/boerrors"
+	l "github.com/konflux-ci/build-service/pkg/logs"
+)
+
+const (
+	// Condition of the deleted Component which shows progress of Pipelines as Code configuration clean up
+	PaCCleanupConditionType = "PipelinesAsCodeCleanup"
+
+	PaCCleanupRetryingReason  = "Retrying"
+	PaCCleanupFailedReason    = "Failed"
+	PaCCleanupSucceededReason = "Succeeded"
+
+	// Number of clean up attempts before the finalizer is removed regardless of the result
+	PaCCleanupMaxAttempts = 5
+)
+
+// Delay before the second clean up attempt, doubled for each next attempt.
+// That way it can be changed in tests.
+var PaCCleanupRetryDelay = 10 * time.Second
+
+// Message of the retrying clean up condition, starts with the number of the failed attempt.
+const pacCleanupRetryingMessageFormat = "Attempt %d of %d failed, retrying in %s: %s"
+
+// cleanupPaCForDeletedComponent removes Pipelines as Code configuration of the Component which is being deleted:
+// deletes the webhook, closes still open onboarding pull request (or creates the one which removes .tekton definitions)
+// and deletes the PaC repository object if no other Component uses it.
+// Transient failures, e.g. git provider API flakes, are retried with exponential backoff.
+// The progress is reported via PipelinesAsCodeCleanup condition of the Component, which also holds the number
+// of failed attempts, so the limit of attempts holds across operator restarts.
+// PaC provision finalizer is removed when the clean up is done or all attempts are exhausted,
+// so the Component deletion is never blocked forever.
+func (r *ComponentBuildReconciler) cleanupPaCForDeletedComponent(ctx context.Context, component *appstudiov1alpha1.Component) (ctrl.Result, error) {
+	log := ctrllog.FromContext(ctx)
+
+	attempt := getPaCCleanupFailedAttempts(component) + 1
+
+	condition := metav1.Condition{Type: PaCCleanupConditionType}
+	err := r.cleanupPaCForComponent(ctx, component)
+	if err != nil {
+		boErr, isBuildOpError := err.(*boerrors.BuildOpError)
+		isPersistent := isBuildOpError && boErr.IsPersistent()
+		if !isPersistent && attempt < PaCCleanupMaxAttempts {
+			delay := PaCCleanupRetryDelay * time.Duration(1<<(attempt-1))
+			log.Error(err, "Pipelines as Code clean up for the deleted Component failed, retrying", "attempt", attempt, "retryAfter", delay.String())
+
+			condition.Status = metav1.ConditionFalse
+			condition.Reason = PaCCleanupRetryingReason
+			condition.Message = fmt.Sprintf(pacCleanupRetryingMessageFormat, attempt, PaCCleanupMaxAttempts, delay.String(), err.Error())
+			setComponentCondition(ctx, r.Client, component, condition)
+			// Updates of the condition don't trigger reconcile, the next attempt is scheduled by the workqueue
+			return ctrl.Result{RequeueAfter: delay}, nil
+		}
+
+		log.Error(err, "Pipelines as Code clean up for the deleted Component failed, giving up", "attempt", attempt, l.Audit, "true")
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = PaCCleanupFailedReason
+		condition.Message = fmt.Sprintf("Clean up failed after %d attempt(s): %s", attempt, err.Error())
+	} else {
+		log.Info("Pipelines as Code clean up for the deleted Component finished successfully", "attempt", attempt)
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = PaCCleanupSucceededReason
+		condition.Message = "Pipelines as Code configuration has been cleaned up"
+	}
+	setComponentCondition(ctx, r.Client, component, condition)
+
+	controllerutil.RemoveFinalizer(component, PaCProvisionFinalizer)
+	if err := r.Client.Update(ctx, component); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	log.Info("PaC finalizer removed", l.Action, l.ActionDelete)
+
+	return ctrl.Result{}, nil
+}
+
+// getPaCCleanupFailedAttempts returns the number of failed clean up attempts recorded in the retrying clean up condition.
+func getPaCCleanupFailedAttempts(component *appstudiov1alpha1.Component) int {
+	condition := meta.FindStatusCondition(component.Status.Conditions, PaCCleanupConditionType)
+	if condition == nil || condition.Reason != PaCCleanupRetryingReason {
+		return 0
+	}
+	attempts := 0
+	if _, err := fmt.Sscanf(condition.Message, "Attempt %d of", &attempts); err != nil {
+		return 0
+	}
+	return attempts
+}
+
+// isOnlyPaCCleanupConditionChanged checks whether the Component update changes nothing but the clean up condition,
+// i.e. it is the progress reported by the controller itself.
+func isOnlyPaCCleanupConditionChanged(oldObject, newObject client.Object) bool {
+	oldComponent, okOld := oldObject.(*appstudiov1alpha1.Component)
+	newComponent, okNew := newObject.(*appstudiov1alpha1.Component)
+	if !okOld || !okNew {
+		return false
+	}
+	oldCondition := meta.FindStatusCondition(oldComponent.Status.Conditions, PaCCleanupConditionType)
+	newCondition := meta.FindStatusCondition(newComponent.Status.Conditions, PaCCleanupConditionType)
+	if newCondition == nil || reflect.DeepEqual(oldCondition, newCondition) {
+		return false
+	}
+
+	withoutCondition := func(component *appstudiov1alpha1.Component) *appstudiov1alpha1.Component {
+		component = component.DeepCopy()
+		component.ResourceVersion = ""
+		component.ManagedFields = nil
+		meta.RemoveStatusCondition(&component.Status.Conditions, PaCCleanupConditionType)
+		if len(component.Status.Conditions) == 0 {
+			component.Status.Conditions = nil
+		}
+		return component
+	}
+	return reflect.DeepEqual(withoutCondition(oldComponent), withoutCondition(newComponent))
+}
+
+// cleanupPaCForComponent undoes Pipelines as Code provision and deletes PaC repository object of the Component.
+func (r *ComponentBuildReconciler) cleanupPaCForComponent(ctx context.Context, component *appstudiov1alpha1.Component) error {
+	if _, err := r.UndoPaCProvisionForComponent(ctx, component); err != nil {
+		return err
+	}
+	return r.deletePaCRepositoryOfComponent(ctx, component)
+}
+
+// deletePaCRepositoryOfComponent deletes PaC repository object of the Component.
+// If the repository is shared with other Components, only the owner reference to the Component is removed.
+func (r *ComponentBuildReconciler) deletePaCRepositoryOfComponent(ctx context.Context, component *appstudiov1alpha1.Component) error {
+	log := ctrllog.FromContext(ctx)
+
+	repository, err := r.findPaCRepositoryForComponent(ctx, component)
+	if err != nil {
+		return err
+	}
+	if repository == nil || !hasOwnerReference(repository, component) {
+		return nil
+	}
+
+	if len(repository.OwnerReferences) > 1 {
+		ownerReferences := []metav1.OwnerReference{}
+		for _, ownerReference := range repository.OwnerReferences {
+			if ownerReference.UID != component.UID {
+				ownerReferences = append(ownerReferences, ownerReference)
+			}
+		}
+		repository.OwnerReferences = ownerReferences
+		if err := r.Client.Update(ctx, repository); err != nil {
+			log.Error(err, "failed to remove Component owner reference from PaC repository", "PaCRepositoryName", repository.Name, l.Action, l.ActionUpdate)
+			return err
+		}
+		log.Info("Removed Component from owners of the shared PaC repository", "PaCRepositoryName", repository.Name, l.Action, l.ActionUpdate)
+		return nil
+	}
+
+	if err := r.Client.Delete(ctx, repository); err != nil && !errors.IsNotFound(err) {
+		log.Error(err, "failed to delete PaC repository", "PaCRepositoryName", repository.Name, l.Action, l.ActionDelete)
+		return err
+	}
+	log.Info("PaC repository deleted", "PaCRepositoryName", repository.Name, l.Action, l.ActionDelete)
+	return nil
+}
+
+// setComponentCondition sets the condition in the Component status.
+// Failure to report the condition is only logged, it must not block the Component deletion or other processing.
+func setComponentCondition(ctx context.Context, c client.Client, component *appstudiov1alpha1.Component, condition metav1.Condition) {
+	log := ctrllog.FromContext(ctx)
+
+	meta.SetStatusCondition(&component.Status.Conditions, condition)
+	if err := c.Status().Update(ctx, component); err != nil {
+		log.Error(err, "failed to update Component condition", "condition", condition.Type, l.Action, l.ActionUpdate)
+		// Get the latest version to not to fail with conflict on the next update
+		if err := c.Get(ctx, types.NamespacedName{Name: component.Name, Namespace: component.Namespace}, component); err != nil {
+			log.Error(err, "failed to get Component", l.Action, l.ActionView)
+		}
+	}
+}
@@ -24,6 +24,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/devfile/api/v2/pkg/apis/workspaces/v1alpha2"
 	appstudiov1alpha1 "github.com/redhat-appstudio/application-api/api/v1alpha1"
 	tektonapi "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -35,6 +36,7 @@ import (
 
 	"github.com/konflux-ci/build-service/pkg/boerrors"
 	. "github.com/konflux-ci/build-service/pkg/common"
+	gp "github.com/konflux-ci/build-service/pkg/git/gitprovider"
 	"github.com/konflux-ci/build-service/pkg/git/gitproviderfactory"
 	l "github.com/konflux-ci/build-service/pkg/logs"
 )
@@ -50,48 +52,64 @@ func (r *ComponentBuildReconciler) SubmitNewBuild(ctx context.Context, component
 			fmt.Errorf("Git repository URL can't use insecure HTTP: %s", component.Spec.Source.GitSource.URL))
 	}
 
-	var pipelineName string
-	var pipelineBundle string
 	var additionalPipelineParams []tektonapi.Param
 	var pipelineRef *tektonapi.PipelineRef
 	var err error
 
+	gitProvider, err := getGitProvider(*component)
+	if err != nil {
+		// There is no point to continue if git provider is not known
+		log.Error(err, "error detecting git provider")
+		return boerrors.NewBuildOpError(boerrors.EUnknownGitProvider, err)
+	}
+	pacSecret, err := r.lookupPaCSecret(ctx, component, gitProvider)
+	if err != nil {
+		log.Error(err, "secret cannot be found for the component")
+		return boerrors.NewBuildOpError(boerrors.EPaCSecretNotFound, err)
+	}
+
+	gitClient, err := gitproviderfactory.CreateGitClient(gitproviderfactory.GitClientConfig{
+		PacSecretData:             pacSecret.Data,
+		GitProvider:               gitProvider,
+		RepoUrl:                   component.Spec.Source.GitSource.URL,
+		IsAppInstallationExpected: false,
+	})
+	if err != nil {
+		log.Error(err, "failed to instantiate git client")
+		return err
+	}
+
 	// no need to check error because it would fail already in Reconcile
 	pipelineRef, _ = GetBuildPipelineFromComponentAnnotation(component)
 	if pipelineRef == nil {
-		pipelineRef, additionalPipelineParams, err = r.GetPipelineForComponent(ctx, component)
+		pipelineRef, additionalPipelineParams, err = r.GetPipelineForComponent(ctx, component, gitClient, component.Spec.Source.GitSource.Revision)
 		if err != nil {
 			return err
 		}
 	}
 
-	pipelineName, pipelineBundle, err = getPipelineNameAndBundle(pipelineRef)
+	pipelineDescription, err := describePipelineRef(pipelineRef)
 	if err != nil {
 		return err
 	}
 
-	gitProvider, err := getGitProvider(*component)
-	if err != nil {
-		// There is no point to continue if git provider is not known
-		log.Error(err, "error detecting git provider")
-		return boerrors.NewBuildOpError(boerrors.EUnknownGitProvider, err)
-	}
-	pacSecret, err := r.lookupPaCSecret(ctx, component, gitProvider)
+	buildGitInfo, err := r.getBuildGitInfo(ctx, component, gitClient)
 	if err != nil {
-		log.Error(err, "secret cannot be found for the component")
-		return boerrors.NewBuildOpError(boerrors.EPaCSecretNotFound, err)
+		return err
 	}
 
-	buildGitInfo, err := r.getBuildGitInfo(ctx, component, gitProvider, pacSecret.Data)
+	buildPipelineRun, err := generatePipelineRunForComponent(component, pipelineRef, additionalPipelineParams, buildGitInfo)
 	if err != nil {
+		log.Error(err, fmt.Sprintf("Failed to generate PipelineRun to build %s component in %s namespace", component.Name, component.Namespace))
 		return err
 	}
 
-	buildPipelineRun, err := generatePipelineRunForComponent(component, pipelineRef, additionalPipelineParams, buildGitInfo)
+	pipelineRunPolicy, err := r.getPipelineRunPolicy(ctx, component)
 	if err != nil {
-		log.Error(err, fmt.Sprintf("Failed to generate PipelineRun to build %s component in %s namespace", component.Name, component.Namespace))
+		log.Error(err, "failed to read PipelineRun policy", l.Action, l.ActionView)
 		return err
 	}
+	applyPipelineRunPolicy(buildPipelineRun, pipelineRunPolicy)
 
 	err = controllerutil.SetOwnerReference(component, buildPipelineRun, r.Scheme)
 	if err != nil {
@@ -104,8 +122,8 @@ func (r *ComponentBuildReconciler) SubmitNewBuild(ctx context.Context, component
 		return err
 	}
 
-	log.Info(fmt.Sprintf("Build pipeline %s created for component %s in %s namespace using %s pipeline from %s bundle",
-		buildPipelineRun.Name, component.Name, component.Namespace, pipelineName, pipelineBundle),
+	log.Info(fmt.Sprintf("Build pipeline %s created for component %s in %s namespace using %s",
+		buildPipelineRun.Name, component.Name, component.Namespace, pipelineDescription),
 		l.Action, l.ActionAdd, l.Audit, "true")
 
 	return nil
@@ -120,25 +138,18 @@ type buildGitInfo struct {
 	// These fields are optional for the build and are shown on UI only.
 	gitSourceSha              string
 	browseRepositoryAtShaLink string
+
+	// dockerfilePath contains Dockerfile location detected in the component git repository.
+	// Used only if the devfile doesn't declare Dockerfile.
+	dockerfilePath string
 }
 
 // getBuildGitInfo find out git source information the build is done from.
-func (r *ComponentBuildReconciler) getBuildGitInfo(ctx context.Context, component *appstudiov1alpha1.Component, gitProvider string, pacConfig map[string][]byte) (*buildGitInfo, error) {
+func (r *ComponentBuildReconciler) getBuildGitInfo(ctx context.Context, component *appstudiov1alpha1.Component, gitClient gp.GitProviderClient) (*buildGitInfo, error) {
 	log := ctrllog.FromContext(ctx).WithName("getBuildGitInfo")
 
 	repoUrl := component.Spec.Source.GitSource.URL
 
-	gitClient, err := gitproviderfactory.CreateGitClient(gitproviderfactory.GitClientConfig{
-		PacSecretData:             pacConfig,
-		GitProvider:               gitProvider,
-		RepoUrl:                   repoUrl,
-		IsAppInstallationExpected: false,
-	})
-	if err != nil {
-		log.Error(err, "failed to instantiate git client")
-		return nil, err
-	}
-
 	var gitSecretName string
 	isPublic, err := gitClient.IsRepositoryPublic(repoUrl)
 	if err != nil {
@@ -190,12 +201,20 @@ func (r *ComponentBuildReconciler) getBuildGitInfo(ctx context.Context, componen
 		browseRepositoryAtShaLink = gitClient.GetBrowseRepositoryAtShaLink(repoUrl, gitSourceSha)
 	}
 
+	dockerfilePath, err := detectDockerfilePath(component, gitClient, revision)
+	if err != nil {
+		log.Error(err, "failed to detect Dockerfile location, continue with the default one")
+		dockerfilePath = defaultDockerfilePath
+	}
+
 	return &buildGitInfo{
 		isPublic:      isPublic,
 		gitSecretName: gitSecretName,
 
 		gitSourceSha:              gitSourceSha,
 		browseRepositoryAtShaLink: browseRepositoryAtShaLink,
+
+		dockerfilePath: dockerfilePath,
 	}, nil
 }
 
@@ -207,13 +226,22 @@ func generatePipelineRunForComponent(component *appstudiov1alpha1.Component, pip
 		gitBranch = component.Spec.Source.GitSource.Revision
 	}
 
-	pipelineName, pipelineBundle, err := getPipelineNameAndBundle(pipelineRef)
-	if err != nil {
-		return nil, err
-	}
-	annotations := map[string]string{
-		"build.appstudio.redhat.com/pipeline_name": pipelineName,
-		"build.appstudio.redhat.com/bundle":        pipelineBundle,
+	annotations := map[string]string{}
+	if pipelineRef.Resolver == gitResolverName {
+		pipelineGitUrl, pipelineGitRevision, pipelinePath, err := getPipelineGitSource(pipelineRef)
+		if err != nil {
+			return nil, err
+		}
+		annotations["build.appstudio.redhat.com/pipeline_git_url"] = pipelineGitUrl
+		annotations["build.appstudio.redhat.com/pipeline_git_revision"] = pipelineGitRevision
+		annotations["build.appstudio.redhat.com/pipeline_path"] = pipelinePath
+	} else {
+		pipelineName, pipelineBundle, err := getPipelineNameAndBundle(pipelineRef)
+		if err != nil {
+			return nil, err
+		}
+		annotations["build.appstudio.redhat.com/pipeline_name"] = pipelineName
+		annotations["build.appstudio.redhat.com/bundle"] = pipelineBundle
 	}
 	if gitBranch != "" {
 		annotations[gitTargetBranchAnnotationName] = gitBranch
@@ -242,29 +270,27 @@ func generatePipelineRunForComponent(component *appstudiov1alpha1.Component, pip
 
 	// no need to check error because it would fail already in Reconcile
 	pipelineAnnotationUsed, _ := GetBuildPipelineFromComponentAnnotation(component)
+	var dockerFile *v1alpha2.DockerfileImage
 	if pipelineAnnotationUsed == nil {
-		dockerFile, err := DevfileSearchForDockerfile([]byte(component.Status.Devfile))
+		var err error
+		dockerFile, err = DevfileSearchForDockerfile([]byte(component.Status.Devfile))
 		if err != nil {
 			return nil, boerrors.NewBuildOpError(boerrors.EInvalidDevfile, err)
 		}
-		if dockerFile != nil {
-			if dockerFile.Uri != "" {
-				params = append(params, tektonapi.Param{Name: "dockerfile", Value: tektonapi.ParamValue{Type: "string", StringVal: dockerFile.Uri}})
-			}
-			pathContext := getPathContext(component.Spec.Source.GitSource.Context, dockerFile.BuildContext)
-			if pathContext != "" {
-				params = append(params, tektonapi.Param{Name: "path-context", Value: tektonapi.ParamValue{Type: "string", StringVal: pathContext}})
-			}
-		}
-	} else {
-		params = append(params, tektonapi.Param{Name: "dockerfile", Value: tektonapi.ParamValue{Type: "string", StringVal: "Dockerfile"}})
-		pathContext := getPathContext(component.Spec.Source.GitSource.Context, "")
-		if pathContext != "" {
-			params = append(params, tektonapi.Param{Name: "path-context", Value: tektonapi.ParamValue{Type: "string", StringVal: pathContext}})
-		}
+	}
+	if dockerFile != nil && dockerFile.Uri != "" {
+		params = append(params, tektonapi.Param{Name: "dockerfile", Value: tektonapi.ParamValue{Type: "string", StringVal: dockerFile.Uri}})
+	} else if pRunGitInfo.dockerfilePath != "" {
+		params = append(params, tektonapi.Param{Name: "dockerfile", Value: tektonapi.ParamValue{Type: "string", StringVal: pRunGitInfo.dockerfilePath}})
+	} else if pipelineAnnotationUsed != nil {
+		params = append(params, tektonapi.Param{Name: "dockerfile", Value: tektonapi.ParamValue{Type: "string", StringVal: defaultDockerfilePath}})
+	}
+	if pathContext := getPathContext(component.Spec.Source.GitSource.Context, getDockerfileBuildContext(dockerFile)); pathContext != "" {
+		params = append(params, tektonapi.Param{Name: "path-context", Value: tektonapi.ParamValue{Type: "string", StringVal: pathContext}})
 	}
 
 	params = mergeAndSortTektonParams(params, additionalPipelineParams)
+	params = mergeAndSortTektonParams(params, getComponentAnnotationParams(component))
 
 	pipelineRun := &tektonapi.PipelineRun{
 		TypeMeta: metav1.TypeMeta{
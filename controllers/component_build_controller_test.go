@@ -21,6 +21,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"os"
 	"strings"
 	"time"
 
@@ -222,6 +223,7 @@ var _ = Describe("Component initial build controller", func() {
 		})
 
 		_ = AfterEach(func() {
+			deleteComponentPipelineRuns(resourcePacPrepKey)
 			deleteComponent(resourcePacPrepKey)
 			deletePaCRepository(resourcePacPrepKey)
 
@@ -278,6 +280,34 @@ var _ = Describe("Component initial build controller", func() {
 			expectPacBuildStatus(resourcePacPrepKey, "enabled", 0, "", mergeUrl)
 		})
 
+		It("should repair modified and re-create deleted PaC repository", func() {
+			createComponentAndProcessBuildRequest(resourcePacPrepKey, BuildRequestConfigurePaCAnnotationValue)
+			pacRepo := waitPaCRepositoryCreated(resourcePacPrepKey)
+			expectPacBuildStatus(resourcePacPrepKey, "enabled", 0, "", "https://githost.com/mr/1234")
+			expectedUrl := pacRepo.Spec.URL
+
+			pacRepo.Spec.URL = "https://github.com/user/another-repository"
+			Expect(k8sClient.Update(ctx, pacRepo)).To(Succeed())
+			Eventually(func() bool {
+				pacRepo := &pacv1alpha1.Repository{}
+				if err := k8sClient.Get(ctx, resourcePacPrepKey, pacRepo); err != nil {
+					return false
+				}
+				return pacRepo.Spec.URL == expectedUrl
+			}, timeout, interval).Should(BeTrue())
+
+			Expect(k8sClient.Get(ctx, resourcePacPrepKey, pacRepo)).To(Succeed())
+			deletedPaCRepoUID := pacRepo.UID
+			Expect(k8sClient.Delete(ctx, pacRepo)).To(Succeed())
+			Eventually(func() bool {
+				pacRepo := &pacv1alpha1.Repository{}
+				if err := k8sClient.Get(ctx, resourcePacPrepKey, pacRepo); err != nil {
+					return false
+				}
+				return pacRepo.UID != deletedPaCRepoUID && pacRepo.Spec.URL == expectedUrl
+			}, timeout, interval).Should(BeTrue())
+		})
+
 		It("should submit PR with PaC definitions converted to Tekton v1 from a v1beta1 Pipeline", func() {
 			deleteBuildPipelineRunSelector(defaultSelectorKey)
 			createBuildPipelineRunSelector(defaultSelectorKey, v1beta1PipelineBundle, defaultPipelineName)
@@ -325,6 +355,82 @@ var _ = Describe("Component initial build controller", func() {
 			expectPacBuildStatus(resourcePacPrepKey, "error", expectError.GetErrorId(), expectError.ShortError(), "")
 		})
 
+		It("should fall back to simple build if GitHub application is not installed into git repository", func() {
+			appNotInstalledErr := boerrors.NewBuildOpError(boerrors.EGitHubAppNotInstalled, nil)
+			gpf.CreateGitClient = func(gitClientConfig gpf.GitClientConfig) (gp.GitProviderClient, error) {
+				if gitClientConfig.IsAppInstallationExpected {
+					return nil, appNotInstalledErr
+				}
+				return testGitProviderClient, nil
+			}
+			EnsurePaCMergeRequestFunc = func(string, *gp.MergeRequestData) (string, error) {
+				defer GinkgoRecover()
+				Fail("Should not invoke merge request creation if GitHub application is not installed into the repository")
+				return "url", nil
+			}
+
+			createComponentAndProcessBuildRequest(resourcePacPrepKey, BuildRequestConfigurePaCAnnotationValue)
+
+			waitOneInitialPipelineRunCreated(resourcePacPrepKey)
+			expectPacBuildStatus(resourcePacPrepKey, "error", appNotInstalledErr.GetErrorId(), appNotInstalledErr.ShortError(), "")
+			expectSimpleBuildStatus(resourcePacPrepKey, 0, "", false)
+		})
+
+		It("should fall back to webhook if GitHub application is not installed into git repository and fallback secret is provided", func() {
+			gpf.CreateGitClient = func(gitClientConfig gpf.GitClientConfig) (gp.GitProviderClient, error) {
+				if gitClientConfig.IsAppInstallationExpected && IsPaCApplicationConfigured("github", gitClientConfig.PacSecretData) {
+					return nil, boerrors.NewBuildOpError(boerrors.EGitHubAppNotInstalled, nil)
+				}
+				return testGitProviderClient, nil
+			}
+			isSetupPaCWebhookInvoked := false
+			SetupPaCWebhookFunc = func(string, string, string) error {
+				isSetupPaCWebhookInvoked = true
+				return nil
+			}
+
+			fallbackSecretKey := types.NamespacedName{Name: PipelinesAsCodeWebhookFallbackSecretName, Namespace: HASAppNamespace}
+			// Must not be matched as SCM secret of the repository
+			createSecret(fallbackSecretKey, map[string]string{"password": "ghp_token"})
+			defer deleteSecret(fallbackSecretKey)
+
+			createComponentAndProcessBuildRequest(resourcePacPrepKey, BuildRequestConfigurePaCAnnotationValue)
+
+			pacRepository := waitPaCRepositoryCreated(resourcePacPrepKey)
+			Expect(pacRepository.Spec.GitProvider).ToNot(BeNil())
+			Expect(pacRepository.Spec.GitProvider.Secret.Name).To(Equal(PipelinesAsCodeWebhookFallbackSecretName))
+			Expect(isSetupPaCWebhookInvoked).To(BeTrue())
+			waitSecretCreated(webhookSecretKey)
+			expectPacBuildStatus(resourcePacPrepKey, "enabled", 0, "", "https://githost.com/mr/1234")
+			Expect(readBuildStatus(getComponent(resourcePacPrepKey)).PaC.WebhookFallback).To(BeTrue())
+		})
+
+		It("should not fall back to simple build if PaC provision of existing Component fails", func() {
+			mergeUrl := "merge-url"
+			EnsurePaCMergeRequestFunc = func(string, *gp.MergeRequestData) (string, error) {
+				return mergeUrl, nil
+			}
+
+			createComponentAndProcessBuildRequest(resourcePacPrepKey, BuildRequestConfigurePaCAnnotationValue)
+			waitPaCRepositoryCreated(resourcePacPrepKey)
+			expectPacBuildStatus(resourcePacPrepKey, "enabled", 0, "", mergeUrl)
+
+			appNotInstalledErr := boerrors.NewBuildOpError(boerrors.EGitHubAppNotInstalled, nil)
+			gpf.CreateGitClient = func(gitClientConfig gpf.GitClientConfig) (gp.GitProviderClient, error) {
+				if gitClientConfig.IsAppInstallationExpected {
+					return nil, appNotInstalledErr
+				}
+				return testGitProviderClient, nil
+			}
+
+			setComponentBuildRequest(resourcePacPrepKey, BuildRequestConfigurePaCAnnotationValue)
+			waitComponentAnnotationGone(resourcePacPrepKey, BuildRequestAnnotationName)
+
+			expectPacBuildStatus(resourcePacPrepKey, "error", appNotInstalledErr.GetErrorId(), appNotInstalledErr.ShortError(), "")
+			Expect(readBuildStatus(getComponent(resourcePacPrepKey)).Simple).To(BeNil())
+			ensureNoPipelineRunsCreated(resourcePacPrepKey)
+		})
+
 		It("should fail to submit PR if unknown git provider is used", func() {
 			EnsurePaCMergeRequestFunc = func(repoUrl string, d *gp.MergeRequestData) (string, error) {
 				defer GinkgoRecover()
@@ -383,6 +489,10 @@ var _ = Describe("Component initial build controller", func() {
 			appNotInstalledErr := boerrors.NewBuildOpError(boerrors.EGitHubAppNotInstalled, nil)
 			isCreateGithubClientInvoked := false
 			gpf.CreateGitClient = func(gitClientConfig gpf.GitClientConfig) (gp.GitProviderClient, error) {
+				if !gitClientConfig.IsAppInstallationExpected {
+					// Simple build fallback
+					return testGitProviderClient, nil
+				}
 				isCreateGithubClientInvoked = true
 				return nil, appNotInstalledErr
 			}
@@ -400,6 +510,9 @@ var _ = Describe("Component initial build controller", func() {
 
 			// Ensure no more retries after permanent error
 			gpf.CreateGitClient = func(gitClientConfig gpf.GitClientConfig) (gp.GitProviderClient, error) {
+				if !gitClientConfig.IsAppInstallationExpected {
+					return testGitProviderClient, nil
+				}
 				defer GinkgoRecover()
 				Fail("Should not retry PaC provision on permanent error")
 				return nil, nil
@@ -471,6 +584,57 @@ var _ = Describe("Component initial build controller", func() {
 			}, timeout, interval).Should(BeTrue())
 		})
 
+		It("should push PaC definitions directly into base branch in direct push provision mode", func() {
+			os.Setenv(PaCDirectPushAllowedEnvVar, "true")
+			defer os.Unsetenv(PaCDirectPushAllowedEnvVar)
+			pushUrl := DefaultBrowseRepository + "1234abcd"
+
+			isPushPaCConfigurationInvoked := false
+			PushPaCConfigurationFunc = func(repoUrl string, d *gp.MergeRequestData) (string, error) {
+				isPushPaCConfigurationInvoked = true
+				Expect(repoUrl).To(Equal(SampleRepoLink + "-" + resourcePacPrepKey.Name))
+				Expect(len(d.Files)).To(Equal(2))
+				Expect(d.CommitMessage).ToNot(BeEmpty())
+				Expect(d.BranchName).To(BeEmpty())
+				Expect(d.BaseBranchName).To(Equal("main"))
+				return pushUrl, nil
+			}
+			EnsurePaCMergeRequestFunc = func(string, *gp.MergeRequestData) (string, error) {
+				defer GinkgoRecover()
+				Fail("Should not create PR in direct push provision mode")
+				return "", nil
+			}
+
+			createCustomComponentWithBuildRequest(componentConfig{
+				componentKey: resourcePacPrepKey,
+				annotations:  map[string]string{PaCProvisionModeAnnotationName: PaCProvisionModeDirectPush},
+			}, BuildRequestConfigurePaCAnnotationValue)
+			waitComponentAnnotationGone(resourcePacPrepKey, BuildRequestAnnotationName)
+
+			waitPaCRepositoryCreated(resourcePacPrepKey)
+			Eventually(func() bool {
+				return isPushPaCConfigurationInvoked
+			}, timeout, interval).Should(BeTrue())
+			expectPacBuildStatus(resourcePacPrepKey, "enabled", 0, "", pushUrl)
+		})
+
+		It("should fail to provision PaC if unknown provision mode is requested", func() {
+			EnsurePaCMergeRequestFunc = func(string, *gp.MergeRequestData) (string, error) {
+				defer GinkgoRecover()
+				Fail("Should not create PR if provision mode is invalid")
+				return "", nil
+			}
+
+			createCustomComponentWithBuildRequest(componentConfig{
+				componentKey: resourcePacPrepKey,
+				annotations:  map[string]string{PaCProvisionModeAnnotationName: "force-push"},
+			}, BuildRequestConfigurePaCAnnotationValue)
+			waitComponentAnnotationGone(resourcePacPrepKey, BuildRequestAnnotationName)
+
+			expectError := boerrors.NewBuildOpError(boerrors.EPaCProvisionModeInvalid, nil)
+			expectPacBuildStatus(resourcePacPrepKey, "error", expectError.GetErrorId(), expectError.ShortError(), "")
+		})
+
 		It("should provision PaC definitions after initial build, use simple build while PaC enabled, and be able to switch back to simple build only", func() {
 			EnsurePaCMergeRequestFunc = func(repoUrl string, d *gp.MergeRequestData) (string, error) {
 				defer GinkgoRecover()
@@ -634,6 +798,80 @@ var _ = Describe("Component initial build controller", func() {
 			Expect(webhookSecretStrings[0]).ToNot(Equal(webhookSecretStrings[1]))
 		})
 
+		It("should rotate webhook secret on request", func() {
+			var webhookSecretStrings []string
+			SetupPaCWebhookFunc = func(repoUrl string, webhookUrl string, webhookSecret string) error {
+				webhookSecretStrings = append(webhookSecretStrings, webhookSecret)
+				return nil
+			}
+
+			pacSecretData := map[string]string{"password": "ghp_token"}
+			createSCMSecret(namespacePaCSecretKey, pacSecretData, corev1.SecretTypeBasicAuth, map[string]string{})
+
+			createComponentAndProcessBuildRequest(resourcePacPrepKey, BuildRequestConfigurePaCAnnotationValue)
+			waitPaCRepositoryCreated(resourcePacPrepKey)
+			Expect(webhookSecretStrings).To(HaveLen(1))
+
+			setComponentBuildRequest(resourcePacPrepKey, BuildRequestRotateWebhookSecretAnnotationValue)
+			waitComponentAnnotationGone(resourcePacPrepKey, BuildRequestAnnotationName)
+
+			Expect(webhookSecretStrings).To(HaveLen(2))
+			Expect(webhookSecretStrings[1]).ToNot(Equal(webhookSecretStrings[0]))
+
+			webhookSecretsSecret := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, webhookSecretKey, webhookSecretsSecret)).To(Succeed())
+			component := getComponent(resourcePacPrepKey)
+			Expect(string(webhookSecretsSecret.Data[getWebhookSecretKeyForComponent(*component)])).To(Equal(webhookSecretStrings[1]))
+
+			buildStatus := readBuildStatus(component)
+			Expect(buildStatus.Message).To(Equal("done"))
+			Expect(buildStatus.PaC.Webhook).To(BeTrue())
+			Expect(buildStatus.PaC.WebhookSecretRotationTime).ToNot(BeEmpty())
+		})
+
+		It("should keep previous webhook secret if webhook update fails during rotation", func() {
+			pacSecretData := map[string]string{"password": "ghp_token"}
+			createSCMSecret(namespacePaCSecretKey, pacSecretData, corev1.SecretTypeBasicAuth, map[string]string{})
+
+			createComponentAndProcessBuildRequest(resourcePacPrepKey, BuildRequestConfigurePaCAnnotationValue)
+			waitPaCRepositoryCreated(resourcePacPrepKey)
+
+			webhookSecretsSecret := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, webhookSecretKey, webhookSecretsSecret)).To(Succeed())
+			webhookSecretDataKey := getWebhookSecretKeyForComponent(*getComponent(resourcePacPrepKey))
+			previousWebhookSecret := string(webhookSecretsSecret.Data[webhookSecretDataKey])
+
+			SetupPaCWebhookFunc = func(repoUrl string, webhookUrl string, webhookSecret string) error {
+				return boerrors.NewBuildOpError(boerrors.EGitHubTokenUnauthorized, fmt.Errorf("unauthorized"))
+			}
+			setComponentBuildRequest(resourcePacPrepKey, BuildRequestRotateWebhookSecretAnnotationValue)
+			waitComponentAnnotationGone(resourcePacPrepKey, BuildRequestAnnotationName)
+
+			Expect(k8sClient.Get(ctx, webhookSecretKey, webhookSecretsSecret)).To(Succeed())
+			Expect(string(webhookSecretsSecret.Data[webhookSecretDataKey])).To(Equal(previousWebhookSecret))
+
+			buildStatus := readBuildStatus(getComponent(resourcePacPrepKey))
+			Expect(buildStatus.PaC.ErrId).To(Equal(int(boerrors.EGitHubTokenUnauthorized)))
+			Expect(buildStatus.PaC.WebhookSecretRotationTime).To(BeEmpty())
+		})
+
+		It("should not rotate webhook secret if GitHub application is used", func() {
+			createComponentAndProcessBuildRequest(resourcePacPrepKey, BuildRequestConfigurePaCAnnotationValue)
+			waitPaCRepositoryCreated(resourcePacPrepKey)
+
+			SetupPaCWebhookFunc = func(string, string, string) error {
+				defer GinkgoRecover()
+				Fail("Should not update webhook if GitHub application is used")
+				return nil
+			}
+			setComponentBuildRequest(resourcePacPrepKey, BuildRequestRotateWebhookSecretAnnotationValue)
+			waitComponentAnnotationGone(resourcePacPrepKey, BuildRequestAnnotationName)
+
+			buildStatus := readBuildStatus(getComponent(resourcePacPrepKey))
+			Expect(buildStatus.Message).To(ContainSubstring("uses Pipelines as Code application"))
+			Expect(buildStatus.PaC.Webhook).To(BeFalse())
+		})
+
 		It("should set error in status if invalid build action requested", func() {
 			createCustomComponentWithBuildRequest(componentConfig{
 				componentKey: resourcePacPrepKey,
@@ -1150,7 +1388,13 @@ var _ = Describe("Component initial build controller", func() {
 		})
 
 		It("should not block component deletion if PaC definitions removal failed", func() {
+			defaultRetryDelay := PaCCleanupRetryDelay
+			PaCCleanupRetryDelay = 100 * time.Millisecond
+			defer func() { PaCCleanupRetryDelay = defaultRetryDelay }()
+
+			undoPaCMergeRequestInvocations := 0
 			UndoPaCMergeRequestFunc = func(string, *gp.MergeRequestData) (webUrl string, err error) {
+				undoPaCMergeRequestInvocations++
 				return "", fmt.Errorf("failed to create PR")
 			}
 			DeletePaCWebhookFunc = func(string, string) error {
@@ -1165,6 +1409,38 @@ var _ = Describe("Component initial build controller", func() {
 
 			// deleteComponent waits until the component is gone
 			deleteComponent(resourceCleanupKey)
+			Expect(undoPaCMergeRequestInvocations).To(Equal(PaCCleanupMaxAttempts))
+		})
+
+		It("should retry PaC clean up on component deletion and delete PaC repository", func() {
+			defaultRetryDelay := PaCCleanupRetryDelay
+			PaCCleanupRetryDelay = 100 * time.Millisecond
+			defer func() { PaCCleanupRetryDelay = defaultRetryDelay }()
+
+			undoPaCMergeRequestInvocations := 0
+			UndoPaCMergeRequestFunc = func(string, *gp.MergeRequestData) (webUrl string, err error) {
+				undoPaCMergeRequestInvocations++
+				if undoPaCMergeRequestInvocations == 1 {
+					return "", fmt.Errorf("git provider API is not available")
+				}
+				return "merge-url", nil
+			}
+
+			pacSecretData := map[string]string{
+				"github-application-id": "12345",
+				"github-private-key":    githubAppPrivateKey,
+			}
+			createSecret(pacSecretKey, pacSecretData)
+
+			createComponentAndProcessBuildRequest(resourceCleanupKey, BuildRequestConfigurePaCAnnotationValue)
+			waitPaCFinalizerOnComponent(resourceCleanupKey)
+			waitPaCRepositoryCreated(resourceCleanupKey)
+
+			deleteComponent(resourceCleanupKey)
+			Expect(undoPaCMergeRequestInvocations).To(Equal(2))
+			Eventually(func() bool {
+				return k8sErrors.IsNotFound(k8sClient.Get(ctx, resourceCleanupKey, &pacv1alpha1.Repository{}))
+			}, timeout, interval).Should(BeTrue())
 		})
 
 		var assertCloseUnmergedMergeRequest = func(expectedBaseBranch string, sourceBranchExists bool) {
@@ -1525,6 +1801,70 @@ var _ = Describe("Component initial build controller", func() {
 			Expect(pipelineRun.Annotations[gitRepoAtShaAnnotationName]).To(Equal(DefaultBrowseRepository + gitSourceSHA))
 		})
 
+		It("should inject PipelineRun policy into initial build", func() {
+			globalPolicyKey := types.NamespacedName{Namespace: BuildServiceNamespaceName, Name: pipelineRunPolicyConfigMapName}
+			namespacePolicyKey := types.NamespacedName{Namespace: resouceSimpleBuildKey.Namespace, Name: pipelineRunPolicyConfigMapName}
+			globalPolicy := corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: globalPolicyKey.Name, Namespace: globalPolicyKey.Namespace},
+				Data: map[string]string{pipelineRunPolicyConfigMapKey: `
+timeouts:
+  pipeline: 1h
+podTemplate:
+  nodeSelector:
+    workload: build
+`},
+			}
+			Expect(k8sClient.Create(ctx, &globalPolicy)).To(Succeed())
+			defer deleteBuildPipelineConfigMap(globalPolicyKey)
+			namespacePolicy := corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: namespacePolicyKey.Name, Namespace: namespacePolicyKey.Namespace},
+				Data: map[string]string{pipelineRunPolicyConfigMapKey: `
+timeouts:
+  pipeline: 3h
+taskRunSpecs:
+- pipelineTaskName: build-container
+  computeResources:
+    limits:
+      memory: 16Gi
+`},
+			}
+			Expect(k8sClient.Create(ctx, &namespacePolicy)).To(Succeed())
+			defer deleteBuildPipelineConfigMap(namespacePolicyKey)
+
+			setComponentDevfileModel(resouceSimpleBuildKey)
+
+			waitOneInitialPipelineRunCreated(resouceSimpleBuildKey)
+			expectSimpleBuildStatus(resouceSimpleBuildKey, 0, "", false)
+
+			pipelineRun := listComponentPipelineRuns(resouceSimpleBuildKey)[0]
+			Expect(pipelineRun.Spec.Timeouts).ToNot(BeNil())
+			Expect(pipelineRun.Spec.Timeouts.Pipeline.Duration).To(Equal(3 * time.Hour))
+			Expect(pipelineRun.Spec.TaskRunTemplate.PodTemplate).ToNot(BeNil())
+			Expect(pipelineRun.Spec.TaskRunTemplate.PodTemplate.NodeSelector).To(Equal(map[string]string{"workload": "build"}))
+			Expect(pipelineRun.Spec.TaskRunSpecs).To(HaveLen(1))
+			Expect(pipelineRun.Spec.TaskRunSpecs[0].PipelineTaskName).To(Equal("build-container"))
+			Expect(pipelineRun.Spec.TaskRunSpecs[0].ComputeResources.Limits.Memory().String()).To(Equal("16Gi"))
+		})
+
+		It("should fail to submit initial build if PipelineRun policy is invalid", func() {
+			policyKey := types.NamespacedName{Namespace: resouceSimpleBuildKey.Namespace, Name: pipelineRunPolicyConfigMapName}
+			policy := corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: policyKey.Name, Namespace: policyKey.Namespace},
+				Data:       map[string]string{pipelineRunPolicyConfigMapKey: "timeout: 1h"},
+			}
+			Expect(k8sClient.Create(ctx, &policy)).To(Succeed())
+			defer deleteBuildPipelineConfigMap(policyKey)
+
+			setComponentDevfileModel(resouceSimpleBuildKey)
+			Eventually(func() bool {
+				return readBuildStatus(getComponent(resouceSimpleBuildKey)).Simple != nil
+			}, timeout, interval).Should(BeTrue())
+
+			expectError := boerrors.NewBuildOpError(boerrors.EInvalidPipelineRunPolicy, nil)
+			expectSimpleBuildStatus(resouceSimpleBuildKey, expectError.GetErrorId(), expectError.ShortError(), true)
+			ensureNoPipelineRunsCreated(resouceSimpleBuildKey)
+		})
+
 		It("should be able to retrigger simple build", func() {
 			setComponentDevfileModel(resouceSimpleBuildKey)
 
@@ -1818,6 +2158,32 @@ var _ = Describe("Component initial build controller", func() {
 			ensureNoPipelineRunsCreated(resouceSimpleBuildKey)
 		})
 
+		It("should request image repository and submit initial build when it is provisioned", func() {
+			deleteComponent(resouceSimpleBuildKey)
+
+			component := getSampleComponentData(resouceSimpleBuildKey)
+			component.Spec.ContainerImage = ""
+
+			createComponentCustom(component)
+			setComponentDevfileModel(resouceSimpleBuildKey)
+
+			Eventually(func() bool {
+				component = getComponent(resouceSimpleBuildKey)
+				return component.Annotations[ImageRepoGenerateAnnotationName] == `{"visibility":"public"}`
+			}, timeout, interval).Should(BeTrue())
+			ensureNoPipelineRunsCreated(resouceSimpleBuildKey)
+
+			// Simulate image controller
+			component = getComponent(resouceSimpleBuildKey)
+			delete(component.Annotations, ImageRepoGenerateAnnotationName)
+			component.Annotations[ImageRepoAnnotationName] = fmt.Sprintf(`{"image":"%s","secret":""}`, ComponentContainerImage)
+			Expect(k8sClient.Update(ctx, component)).To(Succeed())
+
+			waitOneInitialPipelineRunCreated(resouceSimpleBuildKey)
+			waitComponentAnnotationGone(resouceSimpleBuildKey, BuildRequestAnnotationName)
+			expectSimpleBuildStatus(resouceSimpleBuildKey, 0, "", false)
+		})
+
 		It("should not submit initial build when request is empty)", func() {
 			deleteComponent(resouceSimpleBuildKey)
 
@@ -1927,6 +2293,58 @@ var _ = Describe("Component initial build controller", func() {
 			Expect(k8sClient.Delete(ctx, selectors)).Should(Succeed())
 		})
 
+		It("should use the build pipeline from git repository specified for application", func() {
+			selectors := &buildappstudiov1alpha1.BuildPipelineSelector{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      HASAppName,
+					Namespace: HASAppNamespace,
+				},
+				Spec: buildappstudiov1alpha1.BuildPipelineSelectorSpec{
+					Selectors: []buildappstudiov1alpha1.PipelineSelector{
+						{
+							Name: "nodejs",
+							PipelineRef: tektonapi.PipelineRef{
+								ResolverRef: tektonapi.ResolverRef{
+									Resolver: "git",
+									Params: []tektonapi.Param{
+										{Name: "url", Value: *tektonapi.NewStructuredValues("https://github.com/org/pipelines")},
+										{Name: "revision", Value: *tektonapi.NewStructuredValues("v1.0")},
+										{Name: "pathInRepo", Value: *tektonapi.NewStructuredValues("pipelines/nodejs-builder.yaml")},
+									},
+								},
+							},
+							WhenConditions: buildappstudiov1alpha1.WhenCondition{
+								Language: "nodejs",
+							},
+						},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, selectors)).To(Succeed())
+			getComponent(resourceResBundleKey)
+
+			devfile := `
+                        schemaVersion: 2.2.0
+                        metadata:
+                            name: devfile-nodejs
+                            language: nodejs
+                    `
+			setComponentDevfile(resourceResBundleKey, devfile)
+
+			waitOneInitialPipelineRunCreated(resourceResBundleKey)
+			pipelineRun := listComponentPipelineRuns(resourceResBundleKey)[0]
+
+			Expect(pipelineRun.Annotations["build.appstudio.redhat.com/pipeline_git_url"]).To(Equal("https://github.com/org/pipelines"))
+			Expect(pipelineRun.Annotations["build.appstudio.redhat.com/pipeline_git_revision"]).To(Equal("v1.0"))
+			Expect(pipelineRun.Annotations["build.appstudio.redhat.com/pipeline_path"]).To(Equal("pipelines/nodejs-builder.yaml"))
+
+			Expect(pipelineRun.Spec.PipelineRef).ToNot(BeNil())
+			Expect(pipelineRun.Spec.PipelineRef.Resolver).To(Equal(tektonapi.ResolverName("git")))
+			Expect(pipelineRun.Spec.PipelineRef.Params).To(Equal(selectors.Spec.Selectors[0].PipelineRef.Params))
+
+			Expect(k8sClient.Delete(ctx, selectors)).Should(Succeed())
+		})
+
 		It("should use the build bundle specified for the namespace", func() {
 			selectors := &buildappstudiov1alpha1.BuildPipelineSelector{
 				ObjectMeta: metav1.ObjectMeta{
@@ -2174,7 +2592,32 @@ var _ = Describe("Component initial build controller", func() {
 			assertBuildFail(false, "Build pipeline selector is not defined")
 		})
 
-		unsupportedResolverRef := tektonapi.ResolverRef{Resolver: "git"}
+		It("initial build should fail when namespace BuildPipelineSelector overrides the matching global one", func() {
+			createBuildPipelineSelector(defaultResolverRef, buildappstudiov1alpha1.WhenCondition{})
+			namespaceSelector := &buildappstudiov1alpha1.BuildPipelineSelector{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      buildPipelineSelectorResourceName,
+					Namespace: HASAppNamespace,
+				},
+				Spec: buildappstudiov1alpha1.BuildPipelineSelectorSpec{
+					Selectors: []buildappstudiov1alpha1.PipelineSelector{
+						{
+							Name:           "java",
+							PipelineRef:    tektonapi.PipelineRef{ResolverRef: defaultResolverRef},
+							WhenConditions: nonMatchingConditions,
+						},
+					},
+					Mode: buildappstudiov1alpha1.BuildPipelineSelectorModeOverride,
+				},
+			}
+			Expect(k8sClient.Create(ctx, namespaceSelector)).To(Succeed())
+
+			assertBuildFail(true, "No pipeline is selected")
+
+			Expect(k8sClient.Delete(ctx, namespaceSelector)).To(Succeed())
+		})
+
+		unsupportedResolverRef := tektonapi.ResolverRef{Resolver: "hub"}
 		noConditions := buildappstudiov1alpha1.WhenCondition{}
 
 		It("Initial build should fail when the matched pipelineRef uses an unsupported resolver", func() {
@@ -2205,6 +2648,24 @@ var _ = Describe("Component initial build controller", func() {
 			assertBuildFail(false, `The pipelineRef for this component is missing required parameters ('name' and/or 'bundle').`)
 		})
 
+		incompleteGitResolverRef := tektonapi.ResolverRef{
+			Resolver: "git",
+			Params: []tektonapi.Param{
+				{Name: "url", Value: *tektonapi.NewStructuredValues("https://github.com/org/pipelines")},
+				{Name: "pathInRepo", Value: *tektonapi.NewStructuredValues("pipelines/java-builder.yaml")},
+			},
+		}
+
+		It("Initial build should fail when the matched git resolver pipelineRef is incomplete", func() {
+			createBuildPipelineSelector(incompleteGitResolverRef, noConditions)
+			assertBuildFail(true, `The pipelineRef for this component is missing required parameters ('url', 'revision' and/or 'pathInRepo').`)
+		})
+
+		It("PaC provision should fail when the matched git resolver pipelineRef is incomplete", func() {
+			createBuildPipelineSelector(incompleteGitResolverRef, noConditions)
+			assertBuildFail(false, `The pipelineRef for this component is missing required parameters ('url', 'revision' and/or 'pathInRepo').`)
+		})
+
 	})
 
 	Context("Test Pipelines as Code trigger build", func() {
@@ -2285,6 +2746,9 @@ var _ = Describe("Component initial build controller", func() {
 			req.Reply(202).JSON(map[string]string{})
 
 			waitComponentAnnotationGone(resourcePacTriggerKey, BuildRequestAnnotationName)
+			buildStatus := readBuildStatus(getComponent(resourcePacTriggerKey))
+			Expect(buildStatus.PaC.LastBuildTriggerTime).ToNot(BeEmpty())
+			Expect(buildStatus.Message).To(Equal("done"))
 
 			repository = waitPaCRepositoryCreated(resourcePacTriggerKey)
 
@@ -2295,6 +2759,14 @@ var _ = Describe("Component initial build controller", func() {
 			Expect((*repository.Spec.Incomings)[0].Targets).To(Equal([]string{"main"}))
 		})
 
+		It("should clear PaC build request and report it if PaC isn't provisioned", func() {
+			createComponentAndProcessBuildRequest(resourcePacTriggerKey, BuildRequestTriggerPaCBuildAnnotationValue)
+
+			buildStatus := readBuildStatus(getComponent(resourcePacTriggerKey))
+			Expect(buildStatus.PaC).To(BeNil())
+			Expect(buildStatus.Message).To(ContainSubstring("Pipelines as Code isn't provisioned"))
+		})
+
 		It("should successfully trigger builds for 2 components with different branches in the same repo", func() {
 			mergeUrl := "merge-url"
 
@@ -0,0 +1,166 @@
+/*
+Copyright 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	appstudiov1alpha1 "github.com/redhat-appstudio/application-api/api/v1alpha1"
+	tektonapi "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"knative.dev/pkg/apis"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	l "github.com/konflux-ci/build-service/pkg/logs"
+)
+
+const (
+	PacEventPullRequestType = "pull_request"
+
+	LastBuildResultRunning   = "Running"
+	LastBuildResultSucceeded = "Succeeded"
+	LastBuildResultFailed    = "Failed"
+)
+
+// ComponentBuildStatusReconciler watches build PipelineRuns of Components in order to
+// show the last build of the Component in its build status annotation.
+type ComponentBuildStatusReconciler struct {
+	Client        client.Client
+	Scheme        *runtime.Scheme
+	EventRecorder record.EventRecorder
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ComponentBuildStatusReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("componentbuildstatus").
+		For(&tektonapi.PipelineRun{}, builder.WithPredicates(predicate.Funcs{
+			CreateFunc: func(e event.CreateEvent) bool {
+				return isComponentBuildPipelineRun(e.Object)
+			},
+			UpdateFunc: func(e event.UpdateEvent) bool {
+				return isComponentBuildPipelineRun(e.ObjectNew)
+			},
+			DeleteFunc: func(e event.DeleteEvent) bool {
+				return false
+			},
+			GenericFunc: func(e event.GenericEvent) bool {
+				return false
+			},
+		})).
+		Complete(r)
+}
+
+// +kubebuilder:rbac:groups=appstudio.redhat.com,resources=components,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=tekton.dev,resources=pipelineruns,verbs=get;list;watch
+func (r *ComponentBuildStatusReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := ctrllog.FromContext(ctx).WithName("ComponentBuildStatus")
+	ctx = ctrllog.IntoContext(ctx, log)
+
+	pipelineRun := &tektonapi.PipelineRun{}
+	if err := r.Client.Get(ctx, req.NamespacedName, pipelineRun); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "failed to get PipelineRun", l.Action, l.ActionView)
+		return ctrl.Result{}, err
+	}
+
+	componentKey := types.NamespacedName{Namespace: pipelineRun.Namespace, Name: pipelineRun.Labels[ComponentNameLabelName]}
+	component := &appstudiov1alpha1.Component{}
+	if err := r.Client.Get(ctx, componentKey, component); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "failed to get Component", "ComponentName", componentKey.Name, l.Action, l.ActionView)
+		return ctrl.Result{}, err
+	}
+	if !component.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	buildStatus := readBuildStatus(component)
+	lastBuildStatus := getLastBuildStatus(pipelineRun)
+	if buildStatus.LastBuild != nil && buildStatus.LastBuild.PipelineRunName != pipelineRun.Name {
+		if recordedStartTime, err := time.Parse(time.RFC1123, buildStatus.LastBuild.StartTime); err == nil &&
+			recordedStartTime.After(pipelineRun.CreationTimestamp.Time) {
+			// A newer build is already shown
+			return ctrl.Result{}, nil
+		}
+	}
+	if buildStatus.LastBuild != nil && *buildStatus.LastBuild == *lastBuildStatus {
+		return ctrl.Result{}, nil
+	}
+
+	buildStatus.LastBuild = lastBuildStatus
+	writeBuildStatus(component, buildStatus)
+	if err := r.Client.Update(ctx, component); err != nil {
+		log.Error(err, "failed to update last build status of the Component", "ComponentName", component.Name, l.Action, l.ActionUpdate)
+		return ctrl.Result{}, err
+	}
+	log.Info("updated last build status of the Component", "ComponentName", component.Name,
+		"PipelineRunName", pipelineRun.Name, "Result", lastBuildStatus.Result, l.Action, l.ActionUpdate)
+
+	return ctrl.Result{}, nil
+}
+
+// isComponentBuildPipelineRun checks whether the PipelineRun builds the Component from its git revision,
+// i.e. PaC push PipelineRun or simple build PipelineRun, but not pull request PipelineRun.
+func isComponentBuildPipelineRun(object client.Object) bool {
+	pipelineRun, ok := object.(*tektonapi.PipelineRun)
+	if !ok {
+		return false
+	}
+	if pipelineRun.Labels[ComponentNameLabelName] == "" || pipelineRun.Labels[PipelineRunTypeLabelName] != PipelineRunBuildType {
+		return false
+	}
+	return pipelineRun.Annotations[PacEventTypeAnnotationName] != PacEventPullRequestType
+}
+
+// getLastBuildStatus converts the PipelineRun state into the last build status of the Component.
+func getLastBuildStatus(pipelineRun *tektonapi.PipelineRun) *LastBuildStatus {
+	lastBuildStatus := &LastBuildStatus{
+		PipelineRunName: pipelineRun.Name,
+		Result:          LastBuildResultRunning,
+		StartTime:       pipelineRun.CreationTimestamp.Format(time.RFC1123),
+	}
+
+	condition := pipelineRun.Status.GetCondition(apis.ConditionSucceeded)
+	if condition == nil || condition.Status == corev1.ConditionUnknown {
+		return lastBuildStatus
+	}
+	if condition.Status == corev1.ConditionTrue {
+		lastBuildStatus.Result = LastBuildResultSucceeded
+	} else {
+		lastBuildStatus.Result = LastBuildResultFailed
+		lastBuildStatus.Reason = condition.Reason
+	}
+	if pipelineRun.Status.CompletionTime != nil {
+		lastBuildStatus.CompletionTime = pipelineRun.Status.CompletionTime.Format(time.RFC1123)
+	}
+	return lastBuildStatus
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	tektonapi "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"knative.dev/pkg/apis"
+)
+
+var _ = Describe("Component build status controller", func() {
+
+	var (
+		resourceKey = types.NamespacedName{Name: HASCompName + "-buildstatus", Namespace: HASAppNamespace}
+	)
+
+	createComponentBuildPipelineRun := func(name string, eventType string) *tektonapi.PipelineRun {
+		pipelineRun := &tektonapi.PipelineRun{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: resourceKey.Namespace,
+				Labels: map[string]string{
+					ComponentNameLabelName:   resourceKey.Name,
+					PipelineRunTypeLabelName: PipelineRunBuildType,
+				},
+				Annotations: map[string]string{},
+			},
+			Spec: tektonapi.PipelineRunSpec{
+				PipelineRef: &tektonapi.PipelineRef{Name: "build-pipeline"},
+			},
+		}
+		if eventType != "" {
+			pipelineRun.Annotations[PacEventTypeAnnotationName] = eventType
+		}
+		Expect(k8sClient.Create(ctx, pipelineRun)).To(Succeed())
+		return pipelineRun
+	}
+
+	getLastBuild := func() *LastBuildStatus {
+		return readBuildStatus(getComponent(resourceKey)).LastBuild
+	}
+
+	Context("Test last build status reporting", func() {
+
+		_ = BeforeEach(func() {
+			component := getSampleComponentData(resourceKey)
+			// Component without sources is ignored by the build controller
+			component.Spec.Source.GitSource = nil
+			createComponentCustom(component)
+		})
+
+		_ = AfterEach(func() {
+			deleteComponentPipelineRuns(resourceKey)
+			deleteComponent(resourceKey)
+		})
+
+		It("should show running and finished build in the build status", func() {
+			pipelineRun := createComponentBuildPipelineRun("buildstatus-push-1", PacEventPushType)
+
+			Eventually(func() bool {
+				lastBuild := getLastBuild()
+				return lastBuild != nil && lastBuild.PipelineRunName == pipelineRun.Name && lastBuild.Result == LastBuildResultRunning
+			}, timeout, interval).Should(BeTrue())
+
+			Eventually(func() error {
+				if err := k8sClient.Get(ctx, types.NamespacedName{Namespace: pipelineRun.Namespace, Name: pipelineRun.Name}, pipelineRun); err != nil {
+					return err
+				}
+				pipelineRun.Status.SetCondition(&apis.Condition{
+					Type:               apis.ConditionSucceeded,
+					Status:             "False",
+					Reason:             "Failed",
+					LastTransitionTime: apis.VolatileTime{Inner: metav1.Time{Time: time.Now()}},
+				})
+				pipelineRun.Status.CompletionTime = &metav1.Time{Time: time.Now()}
+				return k8sClient.Status().Update(ctx, pipelineRun)
+			}, timeout, interval).Should(Succeed())
+
+			Eventually(func() bool {
+				lastBuild := getLastBuild()
+				return lastBuild != nil && lastBuild.Result == LastBuildResultFailed && lastBuild.CompletionTime != ""
+			}, timeout, interval).Should(BeTrue())
+			Expect(getLastBuild().Reason).To(Equal("Failed"))
+		})
+
+		It("should not show pull request build in the build status", func() {
+			createComponentBuildPipelineRun("buildstatus-pr-1", PacEventPullRequestType)
+
+			Consistently(func() bool {
+				return getLastBuild() == nil
+			}, ensureTimeout, interval).Should(BeTrue())
+		})
+	})
+})
@@ -26,6 +26,7 @@ import (
 	"text/template"
 	"time"
 
+	"github.com/konflux-ci/build-service/pkg/featuregates"
 	l "github.com/konflux-ci/build-service/pkg/logs"
 	applicationapi "github.com/redhat-appstudio/application-api/api/v1alpha1"
 	releaseapi "github.com/redhat-appstudio/release-service/api/v1alpha1"
@@ -33,6 +34,7 @@ import (
 	"golang.org/x/exp/slices"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
@@ -70,6 +72,13 @@ const (
 	KubeApiUpdateMaxAttempts      = 5
 
 	FailureRetryTime = time.Minute * 5 // We retry after 5 minutes on failure
+
+	// Condition of the Component which shows result of the last update of the Components listed in its build nudges ref
+	BuildNudgeConditionType = "BuildNudge"
+
+	BuildNudgeRetryingReason  = "Retrying"
+	BuildNudgeFailedReason    = "Failed"
+	BuildNudgeSucceededReason = "Succeeded"
 )
 
 // The amount of time we wait before attempting to update the component, to try and avoid contention issues
@@ -141,7 +150,7 @@ type UpdateComponentDependenciesFunction = func(ctx context.Context, client clie
 var DefaultUpdateFunction = DefaultDependenciesUpdate
 
 // +kubebuilder:rbac:groups=appstudio.redhat.com,resources=components,verbs=get;list;watch;update;patch
-// +kubebuilder:rbac:groups=appstudio.redhat.com,resources=components/status,verbs=get;list;watch
+// +kubebuilder:rbac:groups=appstudio.redhat.com,resources=components/status,verbs=get;list;watch;update;patch
 // +kubebuilder:rbac:groups=tekton.dev,resources=pipelineruns,verbs=get;list;watch;create;update;patch;delete;deletecollection
 // +kubebuilder:rbac:groups=tekton.dev,resources=pipelineruns/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=tekton.dev,resources=pipelineruns/finalizers,verbs=update
@@ -153,6 +162,11 @@ func (r *ComponentDependencyUpdateReconciler) Reconcile(ctx context.Context, req
 	log := ctrllog.FromContext(ctx).WithName("ComponentNudge")
 	ctx = ctrllog.IntoContext(ctx, log)
 
+	if !featuregates.Enabled(featuregates.Nudging) {
+		log.V(l.DebugLevel).Info("Nudging feature is disabled, skipping")
+		return ctrl.Result{}, nil
+	}
+
 	pipelineRun := &tektonapi.PipelineRun{}
 	err := r.Get(ctx, req.NamespacedName, pipelineRun)
 	if err != nil {
@@ -346,16 +360,16 @@ func (r *ComponentDependencyUpdateReconciler) handleCompletedBuild(ctx context.C
 	var nudgeErr error
 	immediateRetry, nudgeErr = r.UpdateFunction(ctx, r.Client, r.Scheme, r.EventRecorder, toUpdate, &BuildResult{BuiltImageRepository: repo, BuiltImageTag: tag, Digest: digest, Component: updatedComponent, DistributionRepositories: distibutionRepositories, FileMatches: nudgeFiles})
 
-	if nudgeErr != nil {
-
-		componentDesc := ""
-
-		for _, comp := range toUpdate {
-			if componentDesc != "" {
-				componentDesc += ", "
-			}
-			componentDesc += comp.Namespace + "/" + comp.Name
+	componentDesc := ""
+	for _, comp := range toUpdate {
+		if componentDesc != "" {
+			componentDesc += ", "
 		}
+		componentDesc += comp.Namespace + "/" + comp.Name
+	}
+	condition := metav1.Condition{Type: BuildNudgeConditionType, ObservedGeneration: updatedComponent.Generation}
+
+	if nudgeErr != nil {
 		log.Error(nudgeErr, fmt.Sprintf("component update of components %s as a result of a build of %s failed", componentDesc, updatedComponent.Name), l.Audit, "true")
 
 		if pipelineRun.Annotations == nil {
@@ -376,11 +390,18 @@ func (r *ComponentDependencyUpdateReconciler) handleCompletedBuild(ctx context.C
 
 		r.EventRecorder.Event(updatedComponent, corev1.EventTypeWarning, ComponentNudgeFailedEventType, fmt.Sprintf("component update failed as a result of a build for %s, retry %d/%d", updatedComponent.Name, failureCount, MaxAttempts))
 
+		condition.Status = metav1.ConditionFalse
 		if failureCount >= MaxAttempts {
 			// We are at the failure limit, nothing much we can do
 			log.Info("not retrying as max failure limit has been reached", l.Audit, "true")
+			condition.Reason = BuildNudgeFailedReason
+			condition.Message = fmt.Sprintf("Update of components %s with image %s:%s@%s failed after %d attempts: %s", componentDesc, repo, tag, digest, failureCount, nudgeErr.Error())
+			setComponentCondition(ctx, r.Client, updatedComponent, condition)
 			return r.removePipelineFinalizer(ctx, pipelineRun, patch)
 		}
+		condition.Reason = BuildNudgeRetryingReason
+		condition.Message = fmt.Sprintf("Attempt %d of %d to update components %s with image %s:%s@%s failed, retrying: %s", failureCount, MaxAttempts, componentDesc, repo, tag, digest, nudgeErr.Error())
+		setComponentCondition(ctx, r.Client, updatedComponent, condition)
 		log.Info(fmt.Sprintf("failed to update component dependencies, retry %d/%d", failureCount, MaxAttempts))
 		err = r.Client.Patch(ctx, pipelineRun, patch)
 		if err != nil {
@@ -396,6 +417,11 @@ func (r *ComponentDependencyUpdateReconciler) handleCompletedBuild(ctx context.C
 		}
 	}
 
+	condition.Status = metav1.ConditionTrue
+	condition.Reason = BuildNudgeSucceededReason
+	condition.Message = fmt.Sprintf("Update of components %s with image %s:%s@%s has been triggered", componentDesc, repo, tag, digest)
+	setComponentCondition(ctx, r.Client, updatedComponent, condition)
+
 	_, err = r.removePipelineFinalizer(ctx, pipelineRun, patch)
 	if err != nil {
 		return ctrl.Result{}, err
@@ -20,6 +20,7 @@ import (
 	l "github.com/konflux-ci/build-service/pkg/logs"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -209,6 +210,8 @@ var _ = Describe("Component nudge controller", func() {
 				}
 				return op1nudged && op2nudged
 			}, timeout, interval).WithTimeout(ensureTimeout).Should(BeTrue())
+
+			assertBuildNudgeCondition(metav1.ConditionTrue, BuildNudgeSucceededReason)
 		})
 
 		It("Test state pipeline not nudged", func() {
@@ -345,6 +348,8 @@ var _ = Describe("Component nudge controller", func() {
 				}
 				return !op1nudged && !op2nudged && failureCount == 3
 			}, timeout, interval).WithTimeout(ensureTimeout).Should(BeTrue())
+
+			assertBuildNudgeCondition(metav1.ConditionFalse, BuildNudgeFailedReason)
 		})
 	})
 
@@ -379,6 +384,16 @@ var _ = Describe("Component nudge controller", func() {
 	})
 })
 
+func assertBuildNudgeCondition(status metav1.ConditionStatus, reason string) {
+	Eventually(func() bool {
+		component := applicationapi.Component{}
+		err := k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: UserNamespace, Name: BaseComponent}, &component)
+		Expect(err).ToNot(HaveOccurred())
+		condition := meta.FindStatusCondition(component.Status.Conditions, BuildNudgeConditionType)
+		return condition != nil && condition.Status == status && condition.Reason == reason
+	}, timeout, interval).WithTimeout(ensureTimeout).Should(BeTrue())
+}
+
 func getPipelineRun(name string, namespace string) *tektonapi.PipelineRun {
 	pr := tektonapi.PipelineRun{}
 	err := k8sClient.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: namespace}, &pr)
@@ -18,22 +18,33 @@ package controllers
 
 import (
 	"context"
+	"fmt"
 	"reflect"
-
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	appstudiov1alpha1 "github.com/redhat-appstudio/application-api/api/v1alpha1"
 
+	buildappstudiov1alpha1 "github.com/konflux-ci/build-service/api/v1alpha1"
+	"github.com/konflux-ci/build-service/pkg/bometrics"
 	. "github.com/konflux-ci/build-service/pkg/common"
+	"github.com/konflux-ci/build-service/pkg/featuregates"
 	"github.com/konflux-ci/build-service/pkg/git"
 	"github.com/konflux-ci/build-service/pkg/k8s"
 	l "github.com/konflux-ci/build-service/pkg/logs"
@@ -42,7 +53,27 @@ import (
 )
 
 const (
-	NextReconcile = 6 * time.Hour
+	// Default interval of periodic renovate sweeps, can be changed by renovate settings.
+	NextReconcile = renovate.DefaultSweepInterval
+	// Delay of the renovate sweep requested by a change of Components or GitHub App secrets.
+	RenovateTriggeredSweepDelay = 2 * time.Minute
+	// Minimum interval between the last renovate sweep and a sweep requested by a change of Components or GitHub App secrets,
+	// e.g. '1h', so that the tenant activity doesn't make the full sweeps run every few minutes. Periodic sweeps are not affected.
+	RenovateTriggeredSweepMinIntervalEnvName = "RENOVATE_TRIGGERED_SWEEP_MIN_INTERVAL"
+	DefaultRenovateTriggeredSweepMinInterval = 30 * time.Minute
+
+	// Component annotation which excludes the Component repository from renovate updates if set to "false".
+	RenovateAnnotationName = "build.appstudio.openshift.io/renovate"
+	// Component annotation with renovate schedule for the Component repository, e.g. "after 10pm every weekday".
+	// Several schedule entries are separated by semicolon, see https://docs.renovatebot.com/configuration-options/#schedule
+	RenovateScheduleAnnotationName = "build.appstudio.openshift.io/renovate-schedule"
+	// Component annotation which enables ("true") or disables ("false") automerge of renovate updates
+	// in the Component repository, regardless of the global setting.
+	RenovateAutomergeAnnotationName = "build.appstudio.openshift.io/renovate-automerge"
+	// Annotation of the BuildPipelineSelector in the build-service namespace which triggers an immediate renovate sweep if set to "true".
+	// The sweep CronJob sets the same annotation on the build pipeline ConfigMap, which is honored too.
+	// The annotation is removed from both objects once the renovate jobs are created.
+	RunRenovateAnnotationName = "build.appstudio.openshift.io/run-renovate"
 )
 
 // GitTektonResourcesRenovater watches build pipeline ConfigMap object in order to update
@@ -52,12 +83,16 @@ type GitTektonResourcesRenovater struct {
 	client         client.Client
 	eventRecorder  record.EventRecorder
 	jobCoordinator *renovate.JobCoordinator
+
+	lastSweepLock sync.Mutex
+	// Start of the last renovate sweep, the triggered sweeps are delayed to keep their minimum interval
+	lastSweep time.Time
 }
 
 func NewDefaultGitTektonResourcesRenovater(client client.Client, scheme *runtime.Scheme, eventRecorder record.EventRecorder) *GitTektonResourcesRenovater {
 	return NewGitTektonResourcesRenovater(client, scheme, eventRecorder,
 		[]renovate.TaskProvider{
-			renovate.NewGithubAppRenovaterTaskProvider(k8s.NewGithubAppConfigReader(client, scheme, eventRecorder)),
+			renovate.NewGithubAppRenovaterTaskProvider(k8s.NewGithubAppConfigReader(client, scheme, eventRecorder)).WithMatchMetrics(),
 			renovate.NewBasicAuthTaskProvider(k8s.NewGitCredentialProvider(client))})
 }
 
@@ -66,12 +101,16 @@ func NewGitTektonResourcesRenovater(client client.Client, scheme *runtime.Scheme
 		client:         client,
 		taskProviders:  taskProviders,
 		eventRecorder:  eventRecorder,
-		jobCoordinator: renovate.NewJobCoordinator(client, scheme),
+		jobCoordinator: renovate.NewJobCoordinator(client, scheme).WithEventRecorder(eventRecorder),
 	}
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *GitTektonResourcesRenovater) SetupWithManager(mgr ctrl.Manager) error {
+	// Creates queued renovate jobs when the limit of parallel jobs allows it
+	if err := mgr.Add(r.jobCoordinator); err != nil {
+		return err
+	}
 	return ctrl.NewControllerManagedBy(mgr).For(&corev1.ConfigMap{}, builder.WithPredicates(predicate.Funcs{
 		CreateFunc: func(e event.CreateEvent) bool {
 			return e.Object.GetNamespace() == BuildServiceNamespaceName && e.Object.GetName() == buildPipelineConfigMapResourceName
@@ -80,61 +119,476 @@ func (r *GitTektonResourcesRenovater) SetupWithManager(mgr ctrl.Manager) error {
 			return false
 		},
 		UpdateFunc: func(e event.UpdateEvent) bool {
-			return e.ObjectNew.GetNamespace() == BuildServiceNamespaceName && e.ObjectNew.GetName() == buildPipelineConfigMapResourceName
+			if e.ObjectNew.GetNamespace() != BuildServiceNamespaceName || e.ObjectNew.GetName() != buildPipelineConfigMapResourceName {
+				return false
+			}
+			// Do not trigger a new sweep when the controller removes the run renovate annotation
+			if e.ObjectNew.GetAnnotations()[RunRenovateAnnotationName] == "true" {
+				return true
+			}
+			oldConfigMap, okOld := e.ObjectOld.(*corev1.ConfigMap)
+			newConfigMap, okNew := e.ObjectNew.(*corev1.ConfigMap)
+			return !okOld || !okNew || !reflect.DeepEqual(oldConfigMap.Data, newConfigMap.Data)
 		},
 		GenericFunc: func(event.GenericEvent) bool {
 			return false
 		},
-	})).Complete(r)
+	})).
+		// New repositories get their first updates without waiting for the next periodic sweep
+		Watches(&appstudiov1alpha1.Component{}, handler.Funcs{
+			CreateFunc: func(ctx context.Context, e event.CreateEvent, q workqueue.RateLimitingInterface) {
+				if isCreatedAfterStart(e.Object) {
+					r.enqueueTriggeredSweep(q)
+				}
+			},
+			UpdateFunc: func(ctx context.Context, e event.UpdateEvent, q workqueue.RateLimitingInterface) {
+				if isRenovatedComponentChanged(e.ObjectOld, e.ObjectNew) {
+					r.enqueueTriggeredSweep(q)
+				}
+			},
+		}).
+		// On-demand sweeps requested by annotating the BuildPipelineSelector of the build-service namespace
+		Watches(&buildappstudiov1alpha1.BuildPipelineSelector{}, handler.Funcs{
+			CreateFunc: func(ctx context.Context, e event.CreateEvent, q workqueue.RateLimitingInterface) {
+				if isRunRenovateRequestedBySelector(e.Object) {
+					q.Add(newRenovateSweepRequest())
+				}
+			},
+			UpdateFunc: func(ctx context.Context, e event.UpdateEvent, q workqueue.RateLimitingInterface) {
+				if isRunRenovateRequestedBySelector(e.ObjectNew) {
+					q.Add(newRenovateSweepRequest())
+				}
+			},
+		}).
+		// Installing a GitHub App or rotating its key makes the App installations available without waiting for the next sweep
+		Watches(&corev1.Secret{}, handler.Funcs{
+			CreateFunc: func(ctx context.Context, e event.CreateEvent, q workqueue.RateLimitingInterface) {
+				if isGithubAppSecret(e.Object) && isCreatedAfterStart(e.Object) {
+					r.enqueueTriggeredSweep(q)
+				}
+			},
+			UpdateFunc: func(ctx context.Context, e event.UpdateEvent, q workqueue.RateLimitingInterface) {
+				oldSecret, okOld := e.ObjectOld.(*corev1.Secret)
+				newSecret, okNew := e.ObjectNew.(*corev1.Secret)
+				if okOld && okNew && isGithubAppSecret(newSecret) && !reflect.DeepEqual(oldSecret.Data, newSecret.Data) {
+					r.enqueueTriggeredSweep(q)
+				}
+			},
+		}).
+		Complete(r)
+}
+
+// enqueueTriggeredSweep requests a renovate sweep after RenovateTriggeredSweepDelay,
+// or later if the last sweep started less than the minimum interval of triggered sweeps ago.
+// The delaying queue keeps the earliest time of the request, so changes of many Components,
+// e.g. when a tenant onboards all its repositories at once, are collapsed into a single sweep.
+func (r *GitTektonResourcesRenovater) enqueueTriggeredSweep(q workqueue.RateLimitingInterface) {
+	r.lastSweepLock.Lock()
+	lastSweep := r.lastSweep
+	r.lastSweepLock.Unlock()
+	q.AddAfter(newRenovateSweepRequest(), getTriggeredSweepDelay(lastSweep))
+}
+
+// newRenovateSweepRequest returns the reconcile request of the renovate sweep, all sweeps share the build pipeline ConfigMap key.
+func newRenovateSweepRequest() ctrl.Request {
+	return ctrl.Request{NamespacedName: types.NamespacedName{Namespace: BuildServiceNamespaceName, Name: buildPipelineConfigMapResourceName}}
+}
+
+// isRunRenovateRequestedBySelector checks whether the object is the BuildPipelineSelector of the build-service namespace
+// which requests an on-demand renovate sweep.
+func isRunRenovateRequestedBySelector(object client.Object) bool {
+	return object.GetNamespace() == BuildServiceNamespaceName && object.GetName() == buildPipelineSelectorResourceName &&
+		object.GetAnnotations()[RunRenovateAnnotationName] == "true"
+}
+
+// getTriggeredSweepDelay returns the delay of a triggered sweep after the last sweep started at the given time.
+func getTriggeredSweepDelay(lastSweep time.Time) time.Duration {
+	minInterval, err := time.ParseDuration(GetConfigValue(RenovateTriggeredSweepMinIntervalEnvName))
+	if err != nil || minInterval < 0 {
+		minInterval = DefaultRenovateTriggeredSweepMinInterval
+	}
+	if delay := time.Until(lastSweep.Add(minInterval)); delay > RenovateTriggeredSweepDelay {
+		return delay
+	}
+	return RenovateTriggeredSweepDelay
+}
+
+// isCreatedAfterStart filters out the create events of the existing objects listed when the controller starts,
+// they are covered by the initial sweep.
+func isCreatedAfterStart(object client.Object) bool {
+	return time.Since(object.GetCreationTimestamp().Time) < RenovateTriggeredSweepDelay
+}
+
+// isGithubAppSecret checks whether the Secret holds the global Pipelines as Code GitHub App
+// or one of the additional GitHub Apps whose installations are renovated.
+func isGithubAppSecret(object client.Object) bool {
+	if object.GetNamespace() != BuildServiceNamespaceName {
+		return false
+	}
+	return object.GetName() == PipelinesAsCodeGitHubAppSecretName || object.GetLabels()[GitHubAppSecretLabel] == "true"
+}
+
+// isRenovatedComponentChanged checks whether the Component update changes what the renovate sweep matches,
+// i.e. the git source or the renovate annotations.
+func isRenovatedComponentChanged(oldObject, newObject client.Object) bool {
+	oldComponent, okOld := oldObject.(*appstudiov1alpha1.Component)
+	newComponent, okNew := newObject.(*appstudiov1alpha1.Component)
+	if !okOld || !okNew {
+		return false
+	}
+	if !reflect.DeepEqual(oldComponent.Spec.Source.GitSource, newComponent.Spec.Source.GitSource) {
+		return true
+	}
+	for _, annotation := range []string{RenovateAnnotationName, RenovateScheduleAnnotationName, RenovateAutomergeAnnotationName} {
+		if oldComponent.GetAnnotations()[annotation] != newComponent.GetAnnotations()[annotation] {
+			return true
+		}
+	}
+	return false
 }
 
 // Set Role for managing jobs/configmaps/secrets in the controller namespace
 
-// +kubebuilder:rbac:namespace=system,groups=batch,resources=jobs,verbs=create;get;list;watch;delete;deletecollection
+// +kubebuilder:rbac:namespace=system,groups=batch,resources=jobs,verbs=create;get;list;watch;patch;delete;deletecollection
 // +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;create;patch;update;delete;deletecollection
 // +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;create;patch;update;delete;deletecollection
 
-// +kubebuilder:rbac:groups=appstudio.redhat.com,resources=components,verbs=get;list
+// +kubebuilder:rbac:groups=tekton.dev,resources=taskruns,verbs=create;get;list;watch;delete
+// +kubebuilder:rbac:groups=appstudio.redhat.com,resources=components,verbs=get;list;watch
+// +kubebuilder:rbac:groups=appstudio.redhat.com,resources=renovatetektonconfigs,verbs=get;list;watch
 
 func (r *GitTektonResourcesRenovater) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	log := ctrllog.FromContext(ctx).WithName("GitTektonResourcesRenovator")
 	ctx = ctrllog.IntoContext(ctx, log)
 
+	if !featuregates.Enabled(featuregates.Renovater) {
+		log.V(l.DebugLevel).Info("Renovater feature is disabled, skipping")
+		return ctrl.Result{}, nil
+	}
+
+	settings := r.jobCoordinator.Settings(ctx)
+	// Scheduled or suspended sweeps run only on demand, e.g. when triggered by the sweep CronJob
+	if settings.SweepSchedule != "" || settings.SuspendSweeps {
+		onDemand, err := r.isRunRenovateRequested(ctx)
+		if err != nil {
+			log.Error(err, "failed to get run renovate annotation", l.Action, l.ActionView)
+			return ctrl.Result{}, err
+		}
+		if !onDemand {
+			log.Info("skipping renovate sweep, sweeps are scheduled or suspended", "schedule", settings.SweepSchedule, "suspended", settings.SuspendSweeps)
+			return r.nextSweep(settings), nil
+		}
+	}
+
+	// An invalid value would break the renovate config of every job
+	if err := settings.Validate(); err != nil {
+		log.Error(err, "invalid renovate settings, skipping renovate sweep")
+		r.recordSweepEvent(ctx, "Warning", "ErrorInvalidRenovateSettings", err.Error())
+		return r.nextSweep(settings), nil
+	}
+
+	r.lastSweepLock.Lock()
+	r.lastSweep = time.Now()
+	r.lastSweepLock.Unlock()
+
 	// Get Components
 	componentList := &appstudiov1alpha1.ComponentList{}
 	if err := r.client.List(ctx, componentList, &client.ListOptions{}); err != nil {
 		log.Error(err, "failed to list Components", l.Action, l.ActionView)
 		return ctrl.Result{}, err
 	}
+	scmComponents, err := newRenovateScmComponents(ctx, r.eventRecorder, componentList.Items, settings)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	taskProviders := getTaskProviders(r.taskProviders, settings)
+	var tasks []*renovate.Task
+	if len(scmComponents) == 0 {
+		// The task providers would list all installations and create their tokens just to match nothing
+		log.V(l.DebugLevel).Info("no Components to renovate, skipping renovate task providers")
+		bometrics.RenovateSweepsWithoutComponentsTotalMetric.Inc()
+		bometrics.RenovateInstallationsMatchedMetric.Set(0)
+		bometrics.RenovateRepositoriesMatchedMetric.Set(0)
+	} else {
+		tasks = getRenovateTasks(ctx, taskProviders, scmComponents)
+	}
+	retryAfter := getRenovateTasksRetryAfter(taskProviders)
+	failedTasks := getRenovateTasksFailures(taskProviders)
+
+	log.V(l.DebugLevel).Info("executing renovate tasks", "tasks", len(tasks))
+	createdJobs, err := r.jobCoordinator.ExecuteWithLimits(ctx, tasks)
+	if err != nil {
+		log.Error(err, "failed to create a job", l.Action, l.ActionAdd)
+		return r.nextSweep(settings), nil
+	}
+	// Makes the sweeps which didn't match any repository visible too
+	eventType := "Normal"
+	if failedTasks > 0 {
+		eventType = "Warning"
+	}
+	r.recordSweepEvent(ctx, eventType, "RenovateSweepCompleted", getRenovateSweepSummary(tasks, failedTasks, createdJobs, settings.DryRun))
+	if retryAfter > 0 {
+		// The on-demand request is kept until the tasks left out are executed
+		log.Info("some renovate tasks were left out, retrying the sweep", "retryAfter", retryAfter.String())
+		if nextSweep := r.nextSweep(settings); nextSweep.RequeueAfter == 0 || retryAfter < nextSweep.RequeueAfter {
+			return ctrl.Result{RequeueAfter: retryAfter}, nil
+		}
+	} else if err := r.removeRunRenovateAnnotation(ctx); err != nil {
+		log.Error(err, "failed to remove run renovate annotation", l.Action, l.ActionUpdate)
+	}
+	return r.nextSweep(settings), nil
+}
+
+// recordSweepEvent records an event about the renovate sweep on the BuildPipelineSelector of the build-service namespace.
+// Clusters which still configure build pipelines by the build pipeline ConfigMap get the event on the ConfigMap.
+func (r *GitTektonResourcesRenovater) recordSweepEvent(ctx context.Context, eventtype, reason, message string) {
+	for _, holder := range getRunRenovateAnnotationHolders() {
+		if err := r.client.Get(ctx, client.ObjectKeyFromObject(holder), holder); err != nil {
+			if !errors.IsNotFound(err) {
+				ctrllog.FromContext(ctx).Error(err, "failed to get renovate sweep event object", "name", holder.GetName(), l.Action, l.ActionView)
+				return
+			}
+			continue
+		}
+		r.eventRecorder.Event(holder, eventtype, reason, message)
+		return
+	}
+}
+
+// getRenovateSweepSummary returns the message of the renovate sweep event.
+// Every task holds the repositories of one GitHub App installation or one set of basic auth credentials.
+func getRenovateSweepSummary(tasks []*renovate.Task, failedTasks int, createdJobs int, dryRun bool) string {
+	repositories := 0
+	for _, task := range tasks {
+		repositories += len(task.Repositories)
+	}
+	summary := fmt.Sprintf("renovate sweep matched %d installations or credentials with %d repositories, %d jobs created", len(tasks), repositories, createdJobs)
+	if failedTasks > 0 {
+		summary += fmt.Sprintf(", %d installations or credentials failed", failedTasks)
+	}
+	if dryRun {
+		summary += " in dry-run mode"
+	}
+	return summary
+}
+
+// getRenovateTasksRetryAfter returns the longest delay requested by the task providers which left out some tasks.
+func getRenovateTasksRetryAfter(taskProviders []renovate.TaskProvider) time.Duration {
+	var retryAfter time.Duration
+	for _, taskProvider := range taskProviders {
+		if backoffTaskProvider, ok := taskProvider.(renovate.BackoffTaskProvider); ok && backoffTaskProvider.RetryAfter() > retryAfter {
+			retryAfter = backoffTaskProvider.RetryAfter()
+		}
+	}
+	return retryAfter
+}
+
+// getRenovateTasksFailures returns the number of tasks the task providers left out because of errors.
+func getRenovateTasksFailures(taskProviders []renovate.TaskProvider) int {
+	failures := 0
+	for _, taskProvider := range taskProviders {
+		if failureReportingTaskProvider, ok := taskProvider.(renovate.FailureReportingTaskProvider); ok {
+			failures += failureReportingTaskProvider.Failures()
+		}
+	}
+	return failures
+}
+
+// nextSweep returns the requeue after the configured sweep interval unless the sweeps are triggered by the sweep CronJob.
+func (r *GitTektonResourcesRenovater) nextSweep(settings renovate.Settings) ctrl.Result {
+	if settings.SweepSchedule != "" {
+		return ctrl.Result{}
+	}
+	return ctrl.Result{RequeueAfter: settings.SweepInterval}
+}
+
+// getRunRenovateAnnotationHolders returns the objects which drive renovate sweeps, the BuildPipelineSelector first.
+// Both of them may request an on-demand renovate sweep with the run renovate annotation.
+func getRunRenovateAnnotationHolders() []client.Object {
+	return []client.Object{
+		&buildappstudiov1alpha1.BuildPipelineSelector{ObjectMeta: metav1.ObjectMeta{Namespace: BuildServiceNamespaceName, Name: buildPipelineSelectorResourceName}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: BuildServiceNamespaceName, Name: buildPipelineConfigMapResourceName}},
+	}
+}
+
+// isRunRenovateRequested checks whether the BuildPipelineSelector or the build pipeline ConfigMap requests an on-demand renovate sweep.
+func (r *GitTektonResourcesRenovater) isRunRenovateRequested(ctx context.Context) (bool, error) {
+	for _, holder := range getRunRenovateAnnotationHolders() {
+		if err := r.client.Get(ctx, client.ObjectKeyFromObject(holder), holder); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return false, err
+		}
+		if holder.GetAnnotations()[RunRenovateAnnotationName] == "true" {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// newRenovateScmComponents converts Components into ScmComponents, skipping Components with disabled renovate updates
+// and Components of the namespaces and repositories excluded in the settings.
+// Misconfigured Components are reported by an event and skipped, so they don't prevent other Components from being updated.
+func newRenovateScmComponents(ctx context.Context, eventRecorder record.EventRecorder, components []appstudiov1alpha1.Component, settings renovate.Settings) ([]*git.ScmComponent, error) {
+	log := ctrllog.FromContext(ctx)
 	var scmComponents []*git.ScmComponent
-	for _, component := range componentList.Items {
+	// Repositories with Components which are not renovated, pruning would close their references pull requests
+	skippedRepositories := make(map[string]bool)
+	for _, component := range components {
+		if component.GetAnnotations()[RenovateAnnotationName] == "false" {
+			log.V(l.DebugLevel).Info("skipping component with disabled renovate updates", "component", component.Name, "namespace", component.Namespace)
+			addSkippedRepository(skippedRepositories, component)
+			continue
+		}
+		if !settings.IsNamespaceIncluded(component.Namespace) {
+			log.V(l.DebugLevel).Info("skipping component of excluded namespace", "component", component.Name, "namespace", component.Namespace)
+			addSkippedRepository(skippedRepositories, component)
+			continue
+		}
 		gitProvider, err := getGitProvider(component)
 		if err != nil {
-			// component misconfiguration shouldn't prevent other components from being updated
 			// deepcopy the component to avoid implicit memory aliasing in for loop
-			r.eventRecorder.Event(component.DeepCopy(), "Warning", "ErrorComponentProviderInfo", err.Error())
+			eventRecorder.Event(component.DeepCopy(), "Warning", "ErrorComponentProviderInfo", err.Error())
 			continue
 		}
 
 		scmComponent, err := git.NewScmComponent(gitProvider, component.Spec.Source.GitSource.URL, component.Spec.Source.GitSource.Revision, component.Name, component.Namespace)
 		if err != nil {
-			return ctrl.Result{}, err
+			return nil, err
 		}
+		// An invalid branch would break the renovate config of all repositories in the job
+		if err := renovate.ValidateBranchName(scmComponent.Branch()); err != nil {
+			eventRecorder.Event(component.DeepCopy(), "Warning", "ErrorInvalidRenovateBaseBranch", err.Error())
+			skippedRepositories[scmComponent.RepositoryUrlString()] = true
+			continue
+		}
+		if settings.IsRepositoryExcluded(scmComponent.Repository()) {
+			log.V(l.DebugLevel).Info("skipping component of excluded repository", "component", component.Name, "namespace", component.Namespace, "repository", scmComponent.Repository())
+			continue
+		}
+		scmComponent.SetSchedule(getRenovateSchedule(component))
+		scmComponent.SetAutomerge(getRenovateAutomerge(component))
+		scmComponent.SetGitAuthor(settings.NamespaceGitAuthor(component.Namespace))
 		scmComponents = append(scmComponents, scmComponent)
 	}
+	for _, scmComponent := range scmComponents {
+		scmComponent.SetKeepStaleBranches(skippedRepositories[scmComponent.RepositoryUrlString()])
+	}
+	return scmComponents, nil
+}
+
+// addSkippedRepository records the repository of the given Component which is not renovated.
+func addSkippedRepository(skippedRepositories map[string]bool, component appstudiov1alpha1.Component) {
+	gitProvider, err := getGitProvider(component)
+	if err != nil {
+		return
+	}
+	scmComponent, err := git.NewScmComponent(gitProvider, component.Spec.Source.GitSource.URL, component.Spec.Source.GitSource.Revision, component.Name, component.Namespace)
+	if err != nil {
+		return
+	}
+	skippedRepositories[scmComponent.RepositoryUrlString()] = true
+}
+
+// getTaskProviders returns the task providers for the given settings, scoped to the tenants
+// if renovate runs in the namespaces of the Components. Tasks of the providers which can't be scoped
+// are executed in the build-service namespace.
+func getTaskProviders(taskProviders []renovate.TaskProvider, settings renovate.Settings) []renovate.TaskProvider {
+	if !settings.IsTenantScoped() {
+		return taskProviders
+	}
+	scopedProviders := make([]renovate.TaskProvider, 0, len(taskProviders))
+	for _, taskProvider := range taskProviders {
+		if tenantScopedProvider, ok := taskProvider.(renovate.TenantScopedTaskProvider); ok {
+			taskProvider = tenantScopedProvider.TenantScoped(settings)
+		}
+		scopedProviders = append(scopedProviders, taskProvider)
+	}
+	return scopedProviders
+}
+
+// getRenovateTasks collects renovate tasks for the given components from all task providers.
+// The task providers are tried in order, Components whose repositories are covered by the tasks of a provider
+// are not passed to the next ones. So the credentials in the tenant namespaces are a fallback for the repositories
+// without the GitHub App installed, instead of renovating the repositories twice.
+func getRenovateTasks(ctx context.Context, taskProviders []renovate.TaskProvider, scmComponents []*git.ScmComponent) []*renovate.Task {
+	log := ctrllog.FromContext(ctx)
 	var tasks []*renovate.Task
-	for _, taskProvider := range r.taskProviders {
+	for _, taskProvider := range taskProviders {
+		if len(scmComponents) == 0 {
+			break
+		}
 		newTasks := taskProvider.GetNewTasks(ctx, scmComponents)
 		log.Info("found new tasks", "tasks", len(newTasks), "provider", reflect.TypeOf(taskProvider).String())
 		if len(newTasks) > 0 {
 			tasks = append(tasks, newTasks...)
+			scmComponents = getUncoveredComponents(scmComponents, newTasks)
 		}
 	}
+	return tasks
+}
 
-	log.V(l.DebugLevel).Info("executing renovate tasks", "tasks", len(tasks))
-	err := r.jobCoordinator.ExecuteWithLimits(ctx, tasks)
+// getUncoveredComponents returns the components whose repositories are not updated by any of the tasks.
+func getUncoveredComponents(scmComponents []*git.ScmComponent, tasks []*renovate.Task) []*git.ScmComponent {
+	var uncovered []*git.ScmComponent
+	for _, scmComponent := range scmComponents {
+		covered := false
+		for _, task := range tasks {
+			if task.HasComponentRepository(scmComponent) {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			uncovered = append(uncovered, scmComponent)
+		}
+	}
+	return uncovered
+}
+
+// removeRunRenovateAnnotation removes on-demand renovate sweep annotation from the BuildPipelineSelector
+// and the build pipeline ConfigMap, if present.
+// The annotation is removed by a patch, so concurrent changes of the objects are not overwritten.
+func (r *GitTektonResourcesRenovater) removeRunRenovateAnnotation(ctx context.Context) error {
+	for _, holder := range getRunRenovateAnnotationHolders() {
+		if err := r.client.Get(ctx, client.ObjectKeyFromObject(holder), holder); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		annotations := holder.GetAnnotations()
+		if _, exists := annotations[RunRenovateAnnotationName]; !exists {
+			continue
+		}
+		patch := client.MergeFrom(holder.DeepCopyObject().(client.Object))
+		delete(annotations, RunRenovateAnnotationName)
+		holder.SetAnnotations(annotations)
+		if err := r.client.Patch(ctx, holder, patch); err != nil {
+			return err
+		}
+		ctrllog.FromContext(ctx).Info("removed run renovate annotation", "name", holder.GetName(), l.Action, l.ActionUpdate)
+	}
+	return nil
+}
+
+// getRenovateSchedule returns renovate schedule entries from the Component schedule annotation.
+func getRenovateSchedule(component appstudiov1alpha1.Component) []string {
+	var schedule []string
+	for _, entry := range strings.Split(component.GetAnnotations()[RenovateScheduleAnnotationName], ";") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			schedule = append(schedule, entry)
+		}
+	}
+	return schedule
+}
+
+// getRenovateAutomerge returns the automerge setting from the Component automerge annotation, nil if not set.
+func getRenovateAutomerge(component appstudiov1alpha1.Component) *bool {
+	automerge, err := strconv.ParseBool(component.GetAnnotations()[RenovateAutomergeAnnotationName])
 	if err != nil {
-		log.Error(err, "failed to create a job", l.Action, l.ActionAdd)
+		return nil
 	}
-	return ctrl.Result{RequeueAfter: NextReconcile}, nil
+	return &automerge
 }
@@ -24,8 +24,13 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	batch "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
 
+	buildappstudiov1alpha1 "github.com/konflux-ci/build-service/api/v1alpha1"
 	. "github.com/konflux-ci/build-service/pkg/common"
 	"github.com/konflux-ci/build-service/pkg/git/github"
 	"github.com/konflux-ci/build-service/pkg/renovate"
@@ -141,6 +146,157 @@ var _ = Describe("Git tekton resources renovater", func() {
 			deleteComponent(componentNamespacedName)
 		})
 
+		It("It should trigger job on demand and remove run renovate annotation", func() {
+			installedRepositoryUrls := []string{
+				"https://github/test/repo1",
+			}
+			github.GetAllAppInstallations = func(appIdStr string, privateKeyPem []byte) ([]github.ApplicationInstallation, string, error) {
+				repositories := generateRepositories(installedRepositoryUrls)
+				return []github.ApplicationInstallation{generateInstallation(repositories)}, "slug", nil
+			}
+			componentNamespacedName := createComponentForPaCBuild(getComponentData(componentConfig{componentKey: types.NamespacedName{Name: "testondemand"}, gitURL: "https://github/test/repo1"}))
+			createDefaultBuildPipelineConfigMap(defaultPipelineConfigMapKey)
+			Eventually(listJobs).WithArguments(BuildServiceNamespaceName).WithTimeout(timeout).Should(HaveLen(1))
+			// Tasks of active jobs are skipped, finish the first job
+			job := listJobs(BuildServiceNamespaceName)[0]
+			now := metav1.Now()
+			job.Status.StartTime = &now
+			job.Status.CompletionTime = &now
+			job.Status.Conditions = []batch.JobCondition{{Type: batch.JobComplete, Status: corev1.ConditionTrue}}
+			Expect(k8sClient.Status().Update(ctx, &job)).Should(Succeed())
+
+			configMap := &corev1.ConfigMap{}
+			Expect(k8sClient.Get(ctx, defaultPipelineConfigMapKey, configMap)).Should(Succeed())
+			configMap.Annotations = map[string]string{RunRenovateAnnotationName: "true"}
+			Expect(k8sClient.Update(ctx, configMap)).Should(Succeed())
+
+			Eventually(listJobs).WithArguments(BuildServiceNamespaceName).WithTimeout(timeout).Should(HaveLen(2))
+			Eventually(func() bool {
+				Expect(k8sClient.Get(ctx, defaultPipelineConfigMapKey, configMap)).Should(Succeed())
+				_, exists := configMap.Annotations[RunRenovateAnnotationName]
+				return exists
+			}).WithTimeout(timeout).Should(BeFalse())
+			// Removal of the annotation must not trigger another sweep
+			Consistently(listJobs).WithArguments(BuildServiceNamespaceName).WithTimeout(time.Second * 5).Should(HaveLen(2))
+
+			deleteComponent(componentNamespacedName)
+		})
+
+		It("It should trigger job on demand of the build pipeline selector and remove run renovate annotation", func() {
+			installedRepositoryUrls := []string{
+				"https://github/test/repo1",
+			}
+			github.GetAllAppInstallations = func(appIdStr string, privateKeyPem []byte) ([]github.ApplicationInstallation, string, error) {
+				repositories := generateRepositories(installedRepositoryUrls)
+				return []github.ApplicationInstallation{generateInstallation(repositories)}, "slug", nil
+			}
+			componentNamespacedName := createComponentForPaCBuild(getComponentData(componentConfig{componentKey: types.NamespacedName{Name: "testselectorondemand"}, gitURL: "https://github/test/repo1"}))
+			createDefaultBuildPipelineRunSelector(defaultSelectorKey)
+			defer deleteBuildPipelineRunSelector(defaultSelectorKey)
+			createDefaultBuildPipelineConfigMap(defaultPipelineConfigMapKey)
+			Eventually(listJobs).WithArguments(BuildServiceNamespaceName).WithTimeout(timeout).Should(HaveLen(1))
+			// Tasks of active jobs are skipped, finish the first job
+			job := listJobs(BuildServiceNamespaceName)[0]
+			now := metav1.Now()
+			job.Status.StartTime = &now
+			job.Status.CompletionTime = &now
+			job.Status.Conditions = []batch.JobCondition{{Type: batch.JobComplete, Status: corev1.ConditionTrue}}
+			Expect(k8sClient.Status().Update(ctx, &job)).Should(Succeed())
+
+			selector := &buildappstudiov1alpha1.BuildPipelineSelector{}
+			Expect(k8sClient.Get(ctx, defaultSelectorKey, selector)).Should(Succeed())
+			selector.Annotations = map[string]string{RunRenovateAnnotationName: "true"}
+			Expect(k8sClient.Update(ctx, selector)).Should(Succeed())
+
+			Eventually(listJobs).WithArguments(BuildServiceNamespaceName).WithTimeout(timeout).Should(HaveLen(2))
+			Eventually(func() bool {
+				Expect(k8sClient.Get(ctx, defaultSelectorKey, selector)).Should(Succeed())
+				_, exists := selector.Annotations[RunRenovateAnnotationName]
+				return exists
+			}).WithTimeout(timeout).Should(BeFalse())
+			// Removal of the annotation must not trigger another sweep
+			Consistently(listJobs).WithArguments(BuildServiceNamespaceName).WithTimeout(time.Second * 5).Should(HaveLen(2))
+
+			deleteComponent(componentNamespacedName)
+		})
+
+		It("It should record renovate sweep summary event on the build pipeline selector", func() {
+			installedRepositoryUrls := []string{
+				"https://github/test/repo1",
+			}
+			github.GetAllAppInstallations = func(appIdStr string, privateKeyPem []byte) ([]github.ApplicationInstallation, string, error) {
+				repositories := generateRepositories(installedRepositoryUrls)
+				return []github.ApplicationInstallation{generateInstallation(repositories)}, "slug", nil
+			}
+			componentNamespacedName := createComponentForPaCBuild(getComponentData(componentConfig{componentKey: types.NamespacedName{Name: "testsweepevent"}, gitURL: "https://github/test/repo1"}))
+			createDefaultBuildPipelineRunSelector(defaultSelectorKey)
+			defer deleteBuildPipelineRunSelector(defaultSelectorKey)
+			createDefaultBuildPipelineConfigMap(defaultPipelineConfigMapKey)
+
+			Eventually(func() bool {
+				for _, event := range listEvents(BuildServiceNamespaceName) {
+					if event.Reason == "RenovateSweepCompleted" && event.Type == "Normal" &&
+						event.InvolvedObject.Kind == "BuildPipelineSelector" && event.InvolvedObject.Name == defaultSelectorKey.Name {
+						return true
+					}
+				}
+				return false
+			}).WithTimeout(timeout).Should(BeTrue())
+
+			deleteComponent(componentNamespacedName)
+		})
+
+		It("It should not trigger job for component with disabled renovate", func() {
+			installedRepositoryUrls := []string{
+				"https://github/test/repo1",
+			}
+			github.GetAllAppInstallations = func(appIdStr string, privateKeyPem []byte) ([]github.ApplicationInstallation, string, error) {
+				repositories := generateRepositories(installedRepositoryUrls)
+				return []github.ApplicationInstallation{generateInstallation(repositories)}, "slug", nil
+			}
+			componentNamespacedName := createComponentForPaCBuild(getComponentData(componentConfig{
+				componentKey: types.NamespacedName{Name: "testrenovatedisabled"},
+				gitURL:       "https://github/test/repo1",
+				annotations:  map[string]string{RenovateAnnotationName: "false"},
+			}))
+			createDefaultBuildPipelineConfigMap(defaultPipelineConfigMapKey)
+			Consistently(listJobs).WithArguments(BuildServiceNamespaceName).WithTimeout(time.Second * 5).Should(BeEmpty())
+
+			deleteComponent(componentNamespacedName)
+		})
+
+		It("It should trigger job with settings from RenovateTektonConfig", func() {
+			installedRepositoryUrls := []string{
+				"https://github/test/repo1",
+			}
+			github.GetAllAppInstallations = func(appIdStr string, privateKeyPem []byte) ([]github.ApplicationInstallation, string, error) {
+				repositories := generateRepositories(installedRepositoryUrls)
+				return []github.ApplicationInstallation{generateInstallation(repositories)}, "slug", nil
+			}
+			renovateConfig := &buildappstudiov1alpha1.RenovateTektonConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: buildappstudiov1alpha1.RenovateTektonConfigName},
+				Spec: buildappstudiov1alpha1.RenovateTektonConfigSpec{
+					Image: "quay.io/konflux-ci/renovate:test",
+					JobSettings: buildappstudiov1alpha1.RenovateJobSettings{
+						BackoffLimit: ptr.To(int32(3)),
+						NodeSelector: map[string]string{"node-role.kubernetes.io/infra": ""},
+						Tolerations:  []corev1.Toleration{{Key: "infra", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule}},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, renovateConfig)).Should(Succeed())
+			componentNamespacedName := createComponentForPaCBuild(getComponentData(componentConfig{componentKey: types.NamespacedName{Name: "testrenovateconfig"}, gitURL: "https://github/test/repo1"}))
+			createDefaultBuildPipelineConfigMap(defaultPipelineConfigMapKey)
+			Eventually(listJobs).WithArguments(BuildServiceNamespaceName).WithTimeout(timeout).Should(HaveLen(1))
+			job := listJobs(BuildServiceNamespaceName)[0]
+			Expect(job.Spec.Template.Spec.Containers[0].Image).To(Equal("quay.io/konflux-ci/renovate:test"))
+			Expect(*job.Spec.BackoffLimit).To(Equal(int32(3)))
+			Expect(job.Spec.Template.Spec.NodeSelector).To(Equal(map[string]string{"node-role.kubernetes.io/infra": ""}))
+			Expect(job.Spec.Template.Spec.Tolerations).To(HaveLen(1))
+			Expect(k8sClient.Delete(ctx, renovateConfig)).Should(Succeed())
+			deleteComponent(componentNamespacedName)
+		})
+
 		It("It should not trigger job", func() {
 			installedRepositoryUrls := []string{
 				"https://github/test/repo1",
@@ -0,0 +1,133 @@
+/*
+Copyright 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	l "github.com/konflux-ci/build-service/pkg/logs"
+)
+
+const (
+	// Image registry secrets with the label set to "true" are linked to the pipeline service account of the namespace.
+	ImageRegistrySecretLinkLabelName = "build.appstudio.openshift.io/link-to-pipeline-service-account"
+)
+
+// ImageRegistrySecretLinkReconciler links image registry secrets created by tenants
+// to the pipeline service account in the same namespace, so that build pipelines can push and pull with them.
+// The link is restored if removed from the service account and removed when the secret is deleted or unlabeled.
+type ImageRegistrySecretLinkReconciler struct {
+	Client        client.Client
+	Scheme        *runtime.Scheme
+	EventRecorder record.EventRecorder
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ImageRegistrySecretLinkReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("imageregistrysecretlink").
+		For(&corev1.Secret{}, builder.WithPredicates(predicate.Funcs{
+			CreateFunc: func(e event.CreateEvent) bool {
+				return isLinkableImageRegistrySecret(e.Object)
+			},
+			UpdateFunc: func(e event.UpdateEvent) bool {
+				// Also handle removal of the label, to unlink the secret
+				return isLinkableImageRegistrySecret(e.ObjectOld) || isLinkableImageRegistrySecret(e.ObjectNew)
+			},
+			DeleteFunc: func(e event.DeleteEvent) bool {
+				return isLinkableImageRegistrySecret(e.Object)
+			},
+			GenericFunc: func(e event.GenericEvent) bool {
+				return false
+			},
+		})).
+		Watches(&corev1.ServiceAccount{}, handler.EnqueueRequestsFromMapFunc(r.mapPipelineServiceAccountToSecrets),
+			builder.WithPredicates(predicate.NewPredicateFuncs(func(object client.Object) bool {
+				return object.GetName() == buildPipelineServiceAccountName
+			}))).
+		Complete(r)
+}
+
+// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=serviceaccounts,verbs=get;list;watch;update;patch
+func (r *ImageRegistrySecretLinkReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := ctrllog.FromContext(ctx).WithName("ImageRegistrySecretLink")
+	ctx = ctrllog.IntoContext(ctx, log)
+
+	secret := &corev1.Secret{}
+	if err := r.Client.Get(ctx, req.NamespacedName, secret); err != nil {
+		if !errors.IsNotFound(err) {
+			log.Error(err, "failed to get image registry secret", l.Action, l.ActionView)
+			return ctrl.Result{}, err
+		}
+		secret = nil
+	}
+
+	if secret == nil || !secret.DeletionTimestamp.IsZero() || !isLinkableImageRegistrySecret(secret) {
+		if _, err := unlinkSecretFromServiceAccount(ctx, r.Client, req.Name, buildPipelineServiceAccountName, req.Namespace); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
+	if _, err := linkSecretToServiceAccount(ctx, r.Client, secret.Name, buildPipelineServiceAccountName, secret.Namespace, true); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// mapPipelineServiceAccountToSecrets requests reconcile of all linkable image registry secrets in the namespace
+// of the pipeline service account, so that the links are restored if the service account is modified or recreated.
+func (r *ImageRegistrySecretLinkReconciler) mapPipelineServiceAccountToSecrets(ctx context.Context, serviceAccount client.Object) []reconcile.Request {
+	log := ctrllog.FromContext(ctx)
+
+	secrets := &corev1.SecretList{}
+	if err := r.Client.List(ctx, secrets, client.InNamespace(serviceAccount.GetNamespace()), client.MatchingLabels{ImageRegistrySecretLinkLabelName: "true"}); err != nil {
+		log.Error(err, "failed to list image registry secrets", l.Action, l.ActionView)
+		return nil
+	}
+	requests := []reconcile.Request{}
+	for i := range secrets.Items {
+		if isLinkableImageRegistrySecret(&secrets.Items[i]) {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: secrets.Items[i].Namespace, Name: secrets.Items[i].Name}})
+		}
+	}
+	return requests
+}
+
+// isLinkableImageRegistrySecret checks whether the secret is an image registry secret requested to be linked.
+func isLinkableImageRegistrySecret(object client.Object) bool {
+	if object.GetLabels()[ImageRegistrySecretLinkLabelName] != "true" {
+		return false
+	}
+	secret, ok := object.(*corev1.Secret)
+	return ok && (secret.Type == corev1.SecretTypeDockerConfigJson || secret.Type == corev1.SecretTypeDockercfg)
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var _ = Describe("Image registry secret link controller", func() {
+
+	var (
+		pipelineSAKey = types.NamespacedName{Name: buildPipelineServiceAccountName, Namespace: HASAppNamespace}
+		secretKey     = types.NamespacedName{Name: "tenant-registry-secret", Namespace: HASAppNamespace}
+	)
+
+	getPipelineServiceAccount := func() *corev1.ServiceAccount {
+		pipelineSA := &corev1.ServiceAccount{}
+		Expect(k8sClient.Get(ctx, pipelineSAKey, pipelineSA)).To(Succeed())
+		return pipelineSA
+	}
+
+	isSecretLinked := func(secretName string) bool {
+		pipelineSA := getPipelineServiceAccount()
+		linked := false
+		for _, secret := range pipelineSA.Secrets {
+			if secret.Name == secretName {
+				linked = true
+				break
+			}
+		}
+		if !linked {
+			return false
+		}
+		for _, secret := range pipelineSA.ImagePullSecrets {
+			if secret.Name == secretName {
+				return true
+			}
+		}
+		return false
+	}
+
+	isSecretUnlinked := func(secretName string) bool {
+		pipelineSA := getPipelineServiceAccount()
+		for _, secret := range pipelineSA.Secrets {
+			if secret.Name == secretName {
+				return false
+			}
+		}
+		for _, secret := range pipelineSA.ImagePullSecrets {
+			if secret.Name == secretName {
+				return false
+			}
+		}
+		return true
+	}
+
+	createImageRegistrySecret := func(secretType corev1.SecretType, labels map[string]string) {
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      secretKey.Name,
+				Namespace: secretKey.Namespace,
+				Labels:    labels,
+			},
+			Type:       secretType,
+			StringData: map[string]string{corev1.DockerConfigJsonKey: `{"auths":{}}`},
+		}
+		if secretType == corev1.SecretTypeOpaque {
+			secret.StringData = map[string]string{"token": "secret"}
+		}
+		Expect(k8sClient.Create(ctx, secret)).To(Succeed())
+	}
+
+	Context("Test image registry secret linking", func() {
+
+		_ = BeforeEach(func() {
+			pipelineSA := &corev1.ServiceAccount{
+				ObjectMeta: metav1.ObjectMeta{Name: pipelineSAKey.Name, Namespace: pipelineSAKey.Namespace},
+			}
+			if err := k8sClient.Create(ctx, pipelineSA); err != nil && !k8sErrors.IsAlreadyExists(err) {
+				Fail(err.Error())
+			}
+		})
+
+		_ = AfterEach(func() {
+			deleteSecret(secretKey)
+		})
+
+		It("should link labeled image registry secret and unlink it on deletion", func() {
+			createImageRegistrySecret(corev1.SecretTypeDockerConfigJson, map[string]string{ImageRegistrySecretLinkLabelName: "true"})
+			Eventually(func() bool {
+				return isSecretLinked(secretKey.Name)
+			}, timeout, interval).Should(BeTrue())
+
+			deleteSecret(secretKey)
+			Eventually(func() bool {
+				return isSecretUnlinked(secretKey.Name)
+			}, timeout, interval).Should(BeTrue())
+		})
+
+		It("should restore the link if removed from the pipeline service account", func() {
+			createImageRegistrySecret(corev1.SecretTypeDockerConfigJson, map[string]string{ImageRegistrySecretLinkLabelName: "true"})
+			Eventually(func() bool {
+				return isSecretLinked(secretKey.Name)
+			}, timeout, interval).Should(BeTrue())
+
+			Eventually(func() error {
+				pipelineSA := getPipelineServiceAccount()
+				pipelineSA.Secrets = nil
+				pipelineSA.ImagePullSecrets = nil
+				return k8sClient.Update(ctx, pipelineSA)
+			}, timeout, interval).Should(Succeed())
+
+			Eventually(func() bool {
+				return isSecretLinked(secretKey.Name)
+			}, timeout, interval).Should(BeTrue())
+		})
+
+		It("should unlink image registry secret when the label is removed", func() {
+			createImageRegistrySecret(corev1.SecretTypeDockerConfigJson, map[string]string{ImageRegistrySecretLinkLabelName: "true"})
+			Eventually(func() bool {
+				return isSecretLinked(secretKey.Name)
+			}, timeout, interval).Should(BeTrue())
+
+			Eventually(func() error {
+				secret := &corev1.Secret{}
+				if err := k8sClient.Get(ctx, secretKey, secret); err != nil {
+					return err
+				}
+				delete(secret.Labels, ImageRegistrySecretLinkLabelName)
+				return k8sClient.Update(ctx, secret)
+			}, timeout, interval).Should(Succeed())
+
+			Eventually(func() bool {
+				return isSecretUnlinked(secretKey.Name)
+			}, timeout, interval).Should(BeTrue())
+		})
+
+		It("should not link secrets without the label", func() {
+			createImageRegistrySecret(corev1.SecretTypeDockerConfigJson, nil)
+
+			Consistently(func() bool {
+				return isSecretUnlinked(secretKey.Name)
+			}, ensureTimeout, interval).Should(BeTrue())
+		})
+
+		It("should not link labeled secrets which are not image registry secrets", func() {
+			createImageRegistrySecret(corev1.SecretTypeOpaque, map[string]string{ImageRegistrySecretLinkLabelName: "true"})
+
+			Consistently(func() bool {
+				return isSecretUnlinked(secretKey.Name)
+			}, ensureTimeout, interval).Should(BeTrue())
+		})
+	})
+})
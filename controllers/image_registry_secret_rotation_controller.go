@@ -0,0 +1,187 @@
+/*
+Copyright 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	appstudiov1alpha1 "github.com/redhat-appstudio/application-api/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/konflux-ci/build-service/pkg/common"
+	l "github.com/konflux-ci/build-service/pkg/logs"
+)
+
+const (
+	// Period after which the push secret of the generated image repository is rotated, e.g. 720h.
+	// Rotation is disabled if not set.
+	ImageRegistrySecretRotationPeriodEnvName = "IMAGE_REGISTRY_SECRET_ROTATION_PERIOD"
+
+	// Requests image controller to regenerate robot account token of the Component image repository.
+	// Image controller updates the push secret and removes the annotation.
+	ImageRepoRegenerateTokenAnnotationName = "image.redhat.com/regenerate-token"
+	// Time of the last push secret rotation request, in RFC3339 format.
+	ImageRegistrySecretRotatedAtAnnotationName = "build.appstudio.openshift.io/image-registry-secret-rotated-at"
+	// Name of the push secret currently linked to the pipeline service account.
+	ImageRegistrySecretLinkedAnnotationName = "build.appstudio.openshift.io/image-registry-secret-linked"
+)
+
+// ImageRegistrySecretRotationReconciler periodically rotates push secrets of the image repositories
+// generated for Components and keeps the pipeline service account linked to the current secret.
+// Robot accounts are owned by image controller, so the rotation is requested from it.
+type ImageRegistrySecretRotationReconciler struct {
+	Client        client.Client
+	Scheme        *runtime.Scheme
+	EventRecorder record.EventRecorder
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ImageRegistrySecretRotationReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("imageregistrysecretrotation").
+		For(&appstudiov1alpha1.Component{}, builder.WithPredicates(predicate.NewPredicateFuncs(func(object client.Object) bool {
+			_, exists := object.GetAnnotations()[ImageRepoAnnotationName]
+			return exists
+		}))).
+		Complete(r)
+}
+
+// +kubebuilder:rbac:groups=appstudio.redhat.com,resources=components,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=serviceaccounts,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+func (r *ImageRegistrySecretRotationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := ctrllog.FromContext(ctx).WithName("ImageRegistrySecretRotation")
+	ctx = ctrllog.IntoContext(ctx, log)
+
+	component := &appstudiov1alpha1.Component{}
+	if err := r.Client.Get(ctx, req.NamespacedName, component); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	if !component.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	imageRepo, secretName, err := getComponentImageRepoAndSecretNameFromImageAnnotation(component)
+	if err != nil || secretName == "" {
+		// Nothing to rotate
+		return ctrl.Result{}, nil
+	}
+	// Check if the generated image is used
+	if component.Spec.ContainerImage != "" && imageRepo != getContainerImageRepository(component.Spec.ContainerImage) {
+		return ctrl.Result{}, nil
+	}
+
+	if err := r.relinkImageRegistrySecret(ctx, component, secretName); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	rotationPeriod := getImageRegistrySecretRotationPeriod()
+	if rotationPeriod == 0 {
+		return ctrl.Result{}, nil
+	}
+	if _, requested := component.Annotations[ImageRepoRegenerateTokenAnnotationName]; requested {
+		// Wait for image controller to process the previous request
+		return ctrl.Result{}, nil
+	}
+
+	secret := &corev1.Secret{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: component.Namespace, Name: secretName}, secret); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "failed to get image registry secret", "SecretName", secretName, l.Action, l.ActionView)
+		return ctrl.Result{}, err
+	}
+
+	rotateAfter := time.Until(getImageRegistrySecretRotatedAt(component, secret).Add(rotationPeriod))
+	if rotateAfter > 0 {
+		return ctrl.Result{RequeueAfter: rotateAfter}, nil
+	}
+
+	component.Annotations[ImageRepoRegenerateTokenAnnotationName] = "true"
+	component.Annotations[ImageRegistrySecretRotatedAtAnnotationName] = time.Now().UTC().Format(time.RFC3339)
+	if err := r.Client.Update(ctx, component); err != nil {
+		log.Error(err, "failed to request image registry secret rotation", l.Action, l.ActionUpdate)
+		return ctrl.Result{}, err
+	}
+	log.Info("Requested image registry secret rotation", "SecretName", secretName, l.Action, l.ActionUpdate, l.Audit, "true")
+	r.EventRecorder.Event(component, "Normal", "ImageRegistrySecretRotation", fmt.Sprintf("Requested rotation of %s image registry secret", secretName))
+
+	return ctrl.Result{RequeueAfter: rotationPeriod}, nil
+}
+
+// relinkImageRegistrySecret makes sure the given push secret is linked to the pipeline service account.
+// If the secret name changes after rotation, the new secret is linked before the previous one is unlinked,
+// so that running and newly created builds always have valid credentials.
+func (r *ImageRegistrySecretRotationReconciler) relinkImageRegistrySecret(ctx context.Context, component *appstudiov1alpha1.Component, secretName string) error {
+	log := ctrllog.FromContext(ctx)
+
+	linkedSecretName := component.Annotations[ImageRegistrySecretLinkedAnnotationName]
+	if linkedSecretName == secretName {
+		return nil
+	}
+
+	if _, err := linkSecretToServiceAccount(ctx, r.Client, secretName, buildPipelineServiceAccountName, component.Namespace, true); err != nil {
+		return err
+	}
+	if linkedSecretName != "" {
+		if _, err := unlinkSecretFromServiceAccount(ctx, r.Client, linkedSecretName, buildPipelineServiceAccountName, component.Namespace); err != nil {
+			return err
+		}
+	}
+
+	component.Annotations[ImageRegistrySecretLinkedAnnotationName] = secretName
+	if err := r.Client.Update(ctx, component); err != nil {
+		log.Error(err, "failed to record linked image registry secret", l.Action, l.ActionUpdate)
+		return err
+	}
+	log.Info("Image registry secret linked to pipeline service account", "SecretName", secretName, "PreviousSecretName", linkedSecretName, l.Action, l.ActionUpdate)
+	return nil
+}
+
+// getImageRegistrySecretRotationPeriod returns configured rotation period or 0 if the rotation is disabled.
+func getImageRegistrySecretRotationPeriod() time.Duration {
+	rotationPeriod, err := time.ParseDuration(common.GetConfigValue(ImageRegistrySecretRotationPeriodEnvName))
+	if err != nil || rotationPeriod < 0 {
+		return 0
+	}
+	return rotationPeriod
+}
+
+// getImageRegistrySecretRotatedAt returns time of the last rotation request
+// or creation time of the secret if it has never been rotated.
+func getImageRegistrySecretRotatedAt(component *appstudiov1alpha1.Component, secret *corev1.Secret) time.Time {
+	if rotatedAt, err := time.Parse(time.RFC3339, component.Annotations[ImageRegistrySecretRotatedAtAnnotationName]); err == nil {
+		return rotatedAt
+	}
+	return secret.CreationTimestamp.Time
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var _ = Describe("Image registry secret rotation controller", func() {
+
+	var (
+		resourceKey   = types.NamespacedName{Name: HASCompName + "-secretrotation", Namespace: HASAppNamespace}
+		pipelineSAKey = types.NamespacedName{Name: buildPipelineServiceAccountName, Namespace: HASAppNamespace}
+		secret1Key    = types.NamespacedName{Name: "rotation-secret-1", Namespace: HASAppNamespace}
+		secret2Key    = types.NamespacedName{Name: "rotation-secret-2", Namespace: HASAppNamespace}
+	)
+
+	isSecretLinked := func(secretName string) bool {
+		pipelineSA := &corev1.ServiceAccount{}
+		Expect(k8sClient.Get(ctx, pipelineSAKey, pipelineSA)).To(Succeed())
+		for _, secret := range pipelineSA.Secrets {
+			if secret.Name == secretName {
+				return true
+			}
+		}
+		return false
+	}
+
+	createComponentWithImageRepoSecret := func(secretName string, annotations map[string]string) {
+		component := getSampleComponentData(resourceKey)
+		// Component without sources is ignored by the build controller
+		component.Spec.Source.GitSource = nil
+		component.Spec.ContainerImage = ""
+		component.Annotations[ImageRepoAnnotationName] = fmt.Sprintf(`{"image":"%s","secret":"%s"}`, ComponentContainerImage, secretName)
+		for name, value := range annotations {
+			component.Annotations[name] = value
+		}
+		createComponentCustom(component)
+	}
+
+	Context("Test image registry secret rotation", func() {
+
+		_ = BeforeEach(func() {
+			pipelineSA := &corev1.ServiceAccount{
+				ObjectMeta: metav1.ObjectMeta{Name: pipelineSAKey.Name, Namespace: pipelineSAKey.Namespace},
+			}
+			if err := k8sClient.Create(ctx, pipelineSA); err != nil && !k8sErrors.IsAlreadyExists(err) {
+				Fail(err.Error())
+			}
+			createSecret(secret1Key, nil)
+			createSecret(secret2Key, nil)
+		})
+
+		_ = AfterEach(func() {
+			os.Unsetenv(ImageRegistrySecretRotationPeriodEnvName)
+			deleteComponent(resourceKey)
+			deleteSecret(secret1Key)
+			deleteSecret(secret2Key)
+		})
+
+		It("should link rotated secret and unlink the previous one", func() {
+			createComponentWithImageRepoSecret(secret1Key.Name, nil)
+			Eventually(func() bool {
+				return isSecretLinked(secret1Key.Name)
+			}, timeout, interval).Should(BeTrue())
+
+			Eventually(func() error {
+				component := getComponent(resourceKey)
+				component.Annotations[ImageRepoAnnotationName] = fmt.Sprintf(`{"image":"%s","secret":"%s"}`, ComponentContainerImage, secret2Key.Name)
+				return k8sClient.Update(ctx, component)
+			}, timeout, interval).Should(Succeed())
+
+			Eventually(func() bool {
+				return isSecretLinked(secret2Key.Name) && !isSecretLinked(secret1Key.Name)
+			}, timeout, interval).Should(BeTrue())
+			Expect(getComponent(resourceKey).Annotations[ImageRegistrySecretLinkedAnnotationName]).To(Equal(secret2Key.Name))
+		})
+
+		It("should request secret rotation when rotation period elapsed", func() {
+			os.Setenv(ImageRegistrySecretRotationPeriodEnvName, "1h")
+			createComponentWithImageRepoSecret(secret1Key.Name, map[string]string{
+				ImageRegistrySecretRotatedAtAnnotationName: time.Now().Add(-2 * time.Hour).UTC().Format(time.RFC3339),
+			})
+
+			Eventually(func() bool {
+				component := getComponent(resourceKey)
+				return component.Annotations[ImageRepoRegenerateTokenAnnotationName] == "true"
+			}, timeout, interval).Should(BeTrue())
+		})
+
+		It("should not request secret rotation before rotation period elapsed", func() {
+			os.Setenv(ImageRegistrySecretRotationPeriodEnvName, "1h")
+			createComponentWithImageRepoSecret(secret1Key.Name, nil)
+			Eventually(func() bool {
+				return isSecretLinked(secret1Key.Name)
+			}, timeout, interval).Should(BeTrue())
+
+			Consistently(func() bool {
+				component := getComponent(resourceKey)
+				_, requested := component.Annotations[ImageRepoRegenerateTokenAnnotationName]
+				return requested
+			}, ensureTimeout, interval).Should(BeFalse())
+		})
+	})
+})
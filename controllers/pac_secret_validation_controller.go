@@ -0,0 +1,82 @@
+/*
+Copyright 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/konflux-ci/build-service/pkg/bometrics"
+	l "github.com/konflux-ci/build-service/pkg/logs"
+)
+
+// PaCSecretValidationReconciler validates GitHub Application secrets in Build Service namespace
+// whenever they change, so that malformed configuration is reported right away
+// instead of silently breaking PaC provision and renovate of the Components.
+type PaCSecretValidationReconciler struct {
+	Client        client.Client
+	Scheme        *runtime.Scheme
+	EventRecorder record.EventRecorder
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *PaCSecretValidationReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("pacsecretvalidation").
+		For(&corev1.Secret{}, builder.WithPredicates(predicate.NewPredicateFuncs(isGithubAppSecret))).
+		Complete(r)
+}
+
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+func (r *PaCSecretValidationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := ctrllog.FromContext(ctx).WithName("PaCSecretValidation")
+
+	secret := &corev1.Secret{}
+	if err := r.Client.Get(ctx, req.NamespacedName, secret); err != nil {
+		if errors.IsNotFound(err) {
+			bometrics.PaCSecretInvalidMetric.DeleteLabelValues(req.Namespace, req.Name)
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "failed to get Pipelines as Code secret", l.Action, l.ActionView)
+		return ctrl.Result{}, err
+	}
+	if !secret.DeletionTimestamp.IsZero() {
+		bometrics.PaCSecretInvalidMetric.DeleteLabelValues(req.Namespace, req.Name)
+		return ctrl.Result{}, nil
+	}
+
+	if err := validatePaCConfiguration("github", *secret); err != nil {
+		log.Error(err, "Pipelines as Code secret is invalid", "SecretName", secret.Name)
+		bometrics.PaCSecretInvalidMetric.WithLabelValues(secret.Namespace, secret.Name).Set(1)
+		r.EventRecorder.Event(secret, "Warning", "ErrorValidatingPaCSecret", err.Error())
+		// Do not reconcile, the secret must be fixed
+		return ctrl.Result{}, nil
+	}
+	bometrics.PaCSecretInvalidMetric.WithLabelValues(secret.Namespace, secret.Name).Set(0)
+
+	return ctrl.Result{}, nil
+}
@@ -0,0 +1,250 @@
+/*
+Copyright 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"time"
+
+	tektonapi "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"knative.dev/pkg/apis"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/konflux-ci/build-service/pkg/common"
+	l "github.com/konflux-ci/build-service/pkg/logs"
+)
+
+const (
+	// Number of the latest succeeded build PipelineRuns to keep per Component and event type. Not limited if not set.
+	PipelineRunRetentionSucceededEnvName = "PIPELINERUN_RETENTION_KEEP_SUCCEEDED"
+	// Number of the latest failed build PipelineRuns to keep per Component and event type. Not limited if not set.
+	PipelineRunRetentionFailedEnvName = "PIPELINERUN_RETENTION_KEEP_FAILED"
+	// Maximum age of completed build PipelineRuns, e.g. 168h. Not limited if not set.
+	PipelineRunRetentionMaxAgeEnvName = "PIPELINERUN_RETENTION_MAX_AGE"
+
+	// Set by Tekton Chains once it finished signing the PipelineRun, to "true" or "failed".
+	ChainsSignedAnnotationName = "chains.tekton.dev/signed"
+	// Succeeded PipelineRuns not signed by Tekton Chains are kept for this period after completion,
+	// so pruning does not race Chains. Clusters without Chains get them pruned after the period.
+	ChainsSigningTimeout = time.Hour
+)
+
+// pipelineRunRetentionPolicy defines which completed build PipelineRuns of a Component are kept.
+// Negative count or zero age means no limit.
+type pipelineRunRetentionPolicy struct {
+	keepSucceeded int
+	keepFailed    int
+	maxAge        time.Duration
+}
+
+func (p pipelineRunRetentionPolicy) isEnabled() bool {
+	return p.keepSucceeded >= 0 || p.keepFailed >= 0 || p.maxAge > 0
+}
+
+// PipelineRunRetentionReconciler prunes completed build PipelineRuns of Components
+// according to the configured retention policy, so that tenant namespaces are not filled with old runs.
+// The policy applies to push and pull request PipelineRuns separately, so that a busy pull request flow
+// doesn't prune the latest push build. Running PipelineRuns and the ones being signed are never pruned.
+type PipelineRunRetentionReconciler struct {
+	Client        client.Client
+	Scheme        *runtime.Scheme
+	EventRecorder record.EventRecorder
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *PipelineRunRetentionReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("pipelinerunretention").
+		// Requests are per Component, PipelineRuns are pruned together
+		Watches(&tektonapi.PipelineRun{}, handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, object client.Object) []reconcile.Request {
+			return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: object.GetNamespace(), Name: object.GetLabels()[ComponentNameLabelName]}}}
+		}), builder.WithPredicates(predicate.Funcs{
+			CreateFunc: func(e event.CreateEvent) bool {
+				return isComponentBuildTypePipelineRun(e.Object) && isPipelineRunCompleted(e.Object)
+			},
+			UpdateFunc: func(e event.UpdateEvent) bool {
+				if !isComponentBuildTypePipelineRun(e.ObjectNew) || !isPipelineRunCompleted(e.ObjectNew) {
+					return false
+				}
+				_, wasSigned := e.ObjectOld.GetAnnotations()[ChainsSignedAnnotationName]
+				_, isSigned := e.ObjectNew.GetAnnotations()[ChainsSignedAnnotationName]
+				return !isPipelineRunCompleted(e.ObjectOld) || (!wasSigned && isSigned)
+			},
+			DeleteFunc: func(e event.DeleteEvent) bool {
+				return false
+			},
+			GenericFunc: func(e event.GenericEvent) bool {
+				return false
+			},
+		})).
+		Complete(r)
+}
+
+// +kubebuilder:rbac:groups=tekton.dev,resources=pipelineruns,verbs=get;list;watch;delete
+func (r *PipelineRunRetentionReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := ctrllog.FromContext(ctx).WithName("PipelineRunRetention")
+	ctx = ctrllog.IntoContext(ctx, log)
+
+	policy := getPipelineRunRetentionPolicy()
+	if !policy.isEnabled() {
+		return ctrl.Result{}, nil
+	}
+
+	pipelineRuns := &tektonapi.PipelineRunList{}
+	if err := r.Client.List(ctx, pipelineRuns, client.InNamespace(req.Namespace),
+		client.MatchingLabels{ComponentNameLabelName: req.Name, PipelineRunTypeLabelName: PipelineRunBuildType}); err != nil {
+		log.Error(err, "failed to list build PipelineRuns of the Component", "ComponentName", req.Name, l.Action, l.ActionView)
+		return ctrl.Result{}, err
+	}
+
+	pipelineRunsToPrune, requeueAfter := selectPipelineRunsToPrune(pipelineRuns.Items, policy, time.Now())
+	for _, pipelineRun := range pipelineRunsToPrune {
+		if err := r.Client.Delete(ctx, pipelineRun); err != nil && !errors.IsNotFound(err) {
+			log.Error(err, "failed to prune PipelineRun", "PipelineRunName", pipelineRun.Name, l.Action, l.ActionDelete)
+			return ctrl.Result{}, err
+		}
+	}
+	if len(pipelineRunsToPrune) > 0 {
+		log.Info("Pruned build PipelineRuns of the Component", "ComponentName", req.Name, "Count", len(pipelineRunsToPrune), l.Action, l.ActionDelete)
+	}
+
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// selectPipelineRunsToPrune returns completed PipelineRuns which are out of the retention policy
+// and the time after which the oldest kept PipelineRun exceeds the maximum age, if limited,
+// or a PipelineRun waiting for Tekton Chains reaches the signing timeout.
+// The PipelineRuns are grouped by the Pipelines as Code event type before the keep counts are applied.
+func selectPipelineRunsToPrune(pipelineRuns []tektonapi.PipelineRun, policy pipelineRunRetentionPolicy, now time.Time) ([]*tektonapi.PipelineRun, time.Duration) {
+	var toPrune []*tektonapi.PipelineRun
+	var requeueAfter time.Duration
+	requeueAt := func(after time.Duration) {
+		if requeueAfter == 0 || after < requeueAfter {
+			requeueAfter = after
+		}
+	}
+
+	succeeded := map[string][]*tektonapi.PipelineRun{}
+	failed := map[string][]*tektonapi.PipelineRun{}
+	for i := range pipelineRuns {
+		pipelineRun := &pipelineRuns[i]
+		if !pipelineRun.DeletionTimestamp.IsZero() || !isPipelineRunCompleted(pipelineRun) {
+			continue
+		}
+		eventType := getPipelineRunEventType(pipelineRun)
+		if pipelineRun.Status.GetCondition(apis.ConditionSucceeded).IsTrue() {
+			if _, signed := pipelineRun.Annotations[ChainsSignedAnnotationName]; !signed {
+				if signingTimeoutAfter := getPipelineRunCompletionTime(pipelineRun).Add(ChainsSigningTimeout).Sub(now); signingTimeoutAfter > 0 {
+					requeueAt(signingTimeoutAfter)
+					continue
+				}
+			}
+			succeeded[eventType] = append(succeeded[eventType], pipelineRun)
+		} else {
+			failed[eventType] = append(failed[eventType], pipelineRun)
+		}
+	}
+
+	selectGroup := func(group []*tektonapi.PipelineRun, keep int) {
+		// The latest first
+		sort.SliceStable(group, func(i, j int) bool {
+			return getPipelineRunCompletionTime(group[i]).After(getPipelineRunCompletionTime(group[j]))
+		})
+		for i, pipelineRun := range group {
+			if keep >= 0 && i >= keep {
+				toPrune = append(toPrune, pipelineRun)
+				continue
+			}
+			if policy.maxAge > 0 {
+				expiresAfter := getPipelineRunCompletionTime(pipelineRun).Add(policy.maxAge).Sub(now)
+				if expiresAfter <= 0 {
+					toPrune = append(toPrune, pipelineRun)
+				} else {
+					requeueAt(expiresAfter)
+				}
+			}
+		}
+	}
+	for _, group := range succeeded {
+		selectGroup(group, policy.keepSucceeded)
+	}
+	for _, group := range failed {
+		selectGroup(group, policy.keepFailed)
+	}
+
+	return toPrune, requeueAfter
+}
+
+// getPipelineRunRetentionPolicy reads the retention policy from the environment.
+// Invalid values are considered as not set.
+func getPipelineRunRetentionPolicy() pipelineRunRetentionPolicy {
+	policy := pipelineRunRetentionPolicy{keepSucceeded: -1, keepFailed: -1}
+	if keepSucceeded, err := strconv.Atoi(common.GetConfigValue(PipelineRunRetentionSucceededEnvName)); err == nil && keepSucceeded >= 0 {
+		policy.keepSucceeded = keepSucceeded
+	}
+	if keepFailed, err := strconv.Atoi(common.GetConfigValue(PipelineRunRetentionFailedEnvName)); err == nil && keepFailed >= 0 {
+		policy.keepFailed = keepFailed
+	}
+	if maxAge, err := time.ParseDuration(common.GetConfigValue(PipelineRunRetentionMaxAgeEnvName)); err == nil && maxAge > 0 {
+		policy.maxAge = maxAge
+	}
+	return policy
+}
+
+// isComponentBuildTypePipelineRun checks whether the PipelineRun is a build PipelineRun of a Component, including pull request ones.
+func isComponentBuildTypePipelineRun(object client.Object) bool {
+	return object.GetLabels()[ComponentNameLabelName] != "" && object.GetLabels()[PipelineRunTypeLabelName] == PipelineRunBuildType
+}
+
+// getPipelineRunEventType returns Pipelines as Code event type of the PipelineRun, e.g. push or pull_request.
+// PipelineRuns without the event type, e.g. simple builds, are grouped together.
+func getPipelineRunEventType(pipelineRun *tektonapi.PipelineRun) string {
+	if eventType := pipelineRun.Annotations[PacEventTypeAnnotationName]; eventType != "" {
+		return eventType
+	}
+	return pipelineRun.Labels[PacEventTypeAnnotationName]
+}
+
+func isPipelineRunCompleted(object client.Object) bool {
+	pipelineRun, ok := object.(*tektonapi.PipelineRun)
+	if !ok {
+		return false
+	}
+	condition := pipelineRun.Status.GetCondition(apis.ConditionSucceeded)
+	return condition != nil && condition.Status != corev1.ConditionUnknown
+}
+
+func getPipelineRunCompletionTime(pipelineRun *tektonapi.PipelineRun) time.Time {
+	if pipelineRun.Status.CompletionTime != nil {
+		return pipelineRun.Status.CompletionTime.Time
+	}
+	return pipelineRun.CreationTimestamp.Time
+}
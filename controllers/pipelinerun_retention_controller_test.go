@@ -0,0 +1,110 @@
+/*
+Copyright 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	tektonapi "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"knative.dev/pkg/apis"
+)
+
+var _ = Describe("PipelineRun retention controller", func() {
+
+	var (
+		componentKey = types.NamespacedName{Name: HASCompName + "-retention", Namespace: HASAppNamespace}
+	)
+
+	createCompletedBuildPipelineRun := func(name string, status corev1.ConditionStatus) {
+		pipelineRun := &tektonapi.PipelineRun{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: componentKey.Namespace,
+				Labels: map[string]string{
+					ComponentNameLabelName:   componentKey.Name,
+					PipelineRunTypeLabelName: PipelineRunBuildType,
+				},
+				// Do not let the build status controller look for the Component
+				Annotations: map[string]string{PacEventTypeAnnotationName: PacEventPullRequestType, ChainsSignedAnnotationName: "true"},
+			},
+			Spec: tektonapi.PipelineRunSpec{
+				PipelineRef: &tektonapi.PipelineRef{Name: "build-pipeline"},
+			},
+		}
+		Expect(k8sClient.Create(ctx, pipelineRun)).To(Succeed())
+
+		pipelineRun.Status.SetCondition(&apis.Condition{
+			Type:               apis.ConditionSucceeded,
+			Status:             status,
+			LastTransitionTime: apis.VolatileTime{Inner: metav1.Time{Time: time.Now()}},
+		})
+		pipelineRun.Status.CompletionTime = &metav1.Time{Time: time.Now()}
+		Expect(k8sClient.Status().Update(ctx, pipelineRun)).To(Succeed())
+	}
+
+	Context("Test build PipelineRuns pruning", func() {
+
+		_ = AfterEach(func() {
+			os.Unsetenv(PipelineRunRetentionSucceededEnvName)
+			os.Unsetenv(PipelineRunRetentionFailedEnvName)
+			deleteComponentPipelineRuns(componentKey)
+		})
+
+		It("should keep configured number of succeeded and failed PipelineRuns", func() {
+			os.Setenv(PipelineRunRetentionSucceededEnvName, "2")
+			os.Setenv(PipelineRunRetentionFailedEnvName, "1")
+
+			for i := 0; i < 4; i++ {
+				createCompletedBuildPipelineRun(fmt.Sprintf("retention-succeeded-%d", i), corev1.ConditionTrue)
+				// Completion time has seconds precision
+				time.Sleep(time.Second)
+			}
+			for i := 0; i < 2; i++ {
+				createCompletedBuildPipelineRun(fmt.Sprintf("retention-failed-%d", i), corev1.ConditionFalse)
+				time.Sleep(time.Second)
+			}
+
+			Eventually(func() []string {
+				names := []string{}
+				for _, pipelineRun := range listComponentPipelineRuns(componentKey) {
+					if pipelineRun.DeletionTimestamp.IsZero() {
+						names = append(names, pipelineRun.Name)
+					}
+				}
+				return names
+			}, timeout, interval).Should(ConsistOf("retention-succeeded-2", "retention-succeeded-3", "retention-failed-1"))
+		})
+
+		It("should not prune PipelineRuns if retention is not configured", func() {
+			for i := 0; i < 3; i++ {
+				createCompletedBuildPipelineRun(fmt.Sprintf("retention-kept-%d", i), corev1.ConditionTrue)
+			}
+
+			Consistently(func() int {
+				return len(listComponentPipelineRuns(componentKey))
+			}, ensureTimeout, interval).Should(Equal(3))
+		})
+	})
+})
@@ -0,0 +1,175 @@
+/*
+Copyright 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	tektonapi "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+
+	buildappstudiov1alpha1 "github.com/konflux-ci/build-service/api/v1alpha1"
+	"github.com/konflux-ci/build-service/pkg/bometrics"
+	l "github.com/konflux-ci/build-service/pkg/logs"
+	"github.com/konflux-ci/build-service/pkg/renovate"
+)
+
+const (
+	// Annotation set on finished renovate Jobs and TaskRuns once their outcome is counted in the metrics.
+	RenovateJobCountedAnnotationName = "build.appstudio.openshift.io/renovate-job-counted"
+)
+
+// RenovateJobReconciler watches renovate Jobs and TaskRuns in order to count their outcomes
+// and to report the outcomes of the sweep jobs in RenovateTektonConfig status.
+type RenovateJobReconciler struct {
+	Client client.Client
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *RenovateJobReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	renovateJobPredicate := predicate.NewPredicateFuncs(func(object client.Object) bool {
+		return object.GetLabels()[renovate.RenovateJobLabelName] == "true"
+	})
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("renovatejob").
+		For(&batchv1.Job{}, builder.WithPredicates(renovateJobPredicate)).
+		Watches(&tektonapi.TaskRun{}, &handler.EnqueueRequestForObject{}, builder.WithPredicates(renovateJobPredicate)).
+		Complete(r)
+}
+
+// +kubebuilder:rbac:groups=tekton.dev,resources=taskruns,verbs=patch
+// +kubebuilder:rbac:groups=appstudio.redhat.com,resources=renovatetektonconfigs/status,verbs=get;update;patch
+
+func (r *RenovateJobReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := ctrllog.FromContext(ctx).WithName("RenovateJob")
+
+	run, outcome, err := r.getRenovateJob(ctx, req.NamespacedName)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "failed to get renovate job", l.Action, l.ActionView)
+		return ctrl.Result{}, err
+	}
+	if outcome == buildappstudiov1alpha1.RenovateRunOutcomeRunning || run.GetAnnotations()[RenovateJobCountedAnnotationName] == "true" {
+		return ctrl.Result{}, nil
+	}
+
+	// Jobs requested by RenovateRuns are reported in the RenovateRun status
+	if run.GetLabels()[RenovateRunUidLabelName] == "" {
+		if err := r.updateSweepCondition(ctx, run, outcome); err != nil {
+			log.Error(err, "failed to update RenovateTektonConfig status", l.Action, l.ActionUpdate)
+			return ctrl.Result{}, err
+		}
+	}
+
+	// The annotation prevents counting the job again, e.g. after the operator restart
+	patch := client.MergeFrom(run.DeepCopyObject().(client.Object))
+	annotations := run.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[RenovateJobCountedAnnotationName] = "true"
+	run.SetAnnotations(annotations)
+	if err := r.Client.Patch(ctx, run, patch); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "failed to annotate renovate job", l.Action, l.ActionUpdate)
+		return ctrl.Result{}, err
+	}
+
+	if outcome == buildappstudiov1alpha1.RenovateRunOutcomeSucceeded {
+		bometrics.RenovateJobsSucceededTotalMetric.Inc()
+	} else {
+		bometrics.RenovateJobsFailedTotalMetric.Inc()
+	}
+	log.Info("renovate job finished", "jobname", run.GetName(), "outcome", outcome)
+	return ctrl.Result{}, nil
+}
+
+// getRenovateJob returns the renovate Job or TaskRun with the given name together with its outcome.
+// Both kinds share the reconcile queue, the random suffix of the generated names makes them unique.
+func (r *RenovateJobReconciler) getRenovateJob(ctx context.Context, key types.NamespacedName) (client.Object, buildappstudiov1alpha1.RenovateRunOutcome, error) {
+	job := &batchv1.Job{}
+	err := r.Client.Get(ctx, key, job)
+	if err == nil {
+		return job, getRenovateJobOutcome(job), nil
+	}
+	if !errors.IsNotFound(err) {
+		return nil, "", err
+	}
+	taskRun := &tektonapi.TaskRun{}
+	if err := r.Client.Get(ctx, key, taskRun); err != nil {
+		return nil, "", err
+	}
+	return taskRun, getRenovateTaskRunOutcome(taskRun), nil
+}
+
+// updateSweepCondition rolls the outcome of the finished sweep job up into the RenovateTektonConfig status.
+// Nothing is reported if the RenovateTektonConfig doesn't exist.
+func (r *RenovateJobReconciler) updateSweepCondition(ctx context.Context, run client.Object, outcome buildappstudiov1alpha1.RenovateRunOutcome) error {
+	renovateConfig := &buildappstudiov1alpha1.RenovateTektonConfig{}
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: buildappstudiov1alpha1.RenovateTektonConfigName}, renovateConfig); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	condition := getRenovateSweepCondition(renovateConfig.Status.Conditions, run, outcome)
+	if condition == nil {
+		return nil
+	}
+	meta.SetStatusCondition(&renovateConfig.Status.Conditions, *condition)
+	return r.Client.Status().Update(ctx, renovateConfig)
+}
+
+// getRenovateSweepCondition returns the sweep condition after the given job finished, or nil if the condition doesn't change.
+// A failure is kept until a job created after the condition turned False succeeds,
+// so that other jobs of the failed sweep don't hide it.
+func getRenovateSweepCondition(conditions []metav1.Condition, run client.Object, outcome buildappstudiov1alpha1.RenovateRunOutcome) *metav1.Condition {
+	if outcome == buildappstudiov1alpha1.RenovateRunOutcomeFailed {
+		return &metav1.Condition{
+			Type:    buildappstudiov1alpha1.RenovateSweepSucceededConditionType,
+			Status:  metav1.ConditionFalse,
+			Reason:  "RenovateJobFailed",
+			Message: fmt.Sprintf("renovate job %s failed", run.GetName()),
+		}
+	}
+	current := meta.FindStatusCondition(conditions, buildappstudiov1alpha1.RenovateSweepSucceededConditionType)
+	if current != nil && current.Status == metav1.ConditionFalse && !run.GetCreationTimestamp().After(current.LastTransitionTime.Time) {
+		return nil
+	}
+	return &metav1.Condition{
+		Type:    buildappstudiov1alpha1.RenovateSweepSucceededConditionType,
+		Status:  metav1.ConditionTrue,
+		Reason:  "RenovateJobSucceeded",
+		Message: fmt.Sprintf("renovate job %s succeeded", run.GetName()),
+	}
+}
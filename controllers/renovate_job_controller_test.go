@@ -0,0 +1,131 @@
+/*
+Copyright 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	batch "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	buildappstudiov1alpha1 "github.com/konflux-ci/build-service/api/v1alpha1"
+	"github.com/konflux-ci/build-service/pkg/bometrics"
+	. "github.com/konflux-ci/build-service/pkg/common"
+	"github.com/konflux-ci/build-service/pkg/renovate"
+)
+
+var _ = Describe("RenovateJob controller", func() {
+
+	Context("Test renovate job outcome metrics", Label("renovatejob"), func() {
+
+		_ = BeforeEach(func() {
+			createNamespace(BuildServiceNamespaceName)
+		})
+
+		_ = AfterEach(func() {
+			deleteJobs(BuildServiceNamespaceName)
+		})
+
+		It("should count finished renovate job once", func() {
+			succeededBefore := testutil.ToFloat64(bometrics.RenovateJobsSucceededTotalMetric)
+			job := &batch.Job{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "renovate-job-test-counted",
+					Namespace: BuildServiceNamespaceName,
+					Labels:    map[string]string{renovate.RenovateJobLabelName: "true"},
+				},
+				Spec: batch.JobSpec{
+					Template: corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{
+							Containers:    []corev1.Container{{Name: "renovate", Image: "renovate"}},
+							RestartPolicy: corev1.RestartPolicyNever,
+						},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, job)).Should(Succeed())
+			Consistently(func() float64 {
+				return testutil.ToFloat64(bometrics.RenovateJobsSucceededTotalMetric)
+			}).WithTimeout(time.Second).Should(Equal(succeededBefore))
+
+			now := metav1.Now()
+			job.Status.StartTime = &now
+			job.Status.CompletionTime = &now
+			job.Status.Conditions = []batch.JobCondition{{Type: batch.JobComplete, Status: corev1.ConditionTrue}}
+			Expect(k8sClient.Status().Update(ctx, job)).Should(Succeed())
+
+			jobKey := types.NamespacedName{Name: job.Name, Namespace: job.Namespace}
+			Eventually(func() bool {
+				Expect(k8sClient.Get(ctx, jobKey, job)).Should(Succeed())
+				return job.Annotations[RenovateJobCountedAnnotationName] == "true"
+			}).WithTimeout(timeout).Should(BeTrue())
+			Expect(testutil.ToFloat64(bometrics.RenovateJobsSucceededTotalMetric)).To(Equal(succeededBefore + 1))
+
+			// Further updates of the counted job are ignored
+			job.Labels["test"] = "updated"
+			Expect(k8sClient.Update(ctx, job)).Should(Succeed())
+			Consistently(func() float64 {
+				return testutil.ToFloat64(bometrics.RenovateJobsSucceededTotalMetric)
+			}).WithTimeout(time.Second).Should(Equal(succeededBefore + 1))
+		})
+
+		It("should report failed sweep job in RenovateTektonConfig status", func() {
+			renovateConfig := &buildappstudiov1alpha1.RenovateTektonConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: buildappstudiov1alpha1.RenovateTektonConfigName},
+			}
+			Expect(k8sClient.Create(ctx, renovateConfig)).Should(Succeed())
+			defer func() {
+				Expect(k8sClient.Delete(ctx, renovateConfig)).Should(Succeed())
+			}()
+
+			job := &batch.Job{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "renovate-job-test-sweep-failed",
+					Namespace: BuildServiceNamespaceName,
+					Labels:    map[string]string{renovate.RenovateJobLabelName: "true"},
+				},
+				Spec: batch.JobSpec{
+					Template: corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{
+							Containers:    []corev1.Container{{Name: "renovate", Image: "renovate"}},
+							RestartPolicy: corev1.RestartPolicyNever,
+						},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, job)).Should(Succeed())
+			now := metav1.Now()
+			job.Status.StartTime = &now
+			job.Status.Conditions = []batch.JobCondition{{Type: batch.JobFailed, Status: corev1.ConditionTrue}}
+			Expect(k8sClient.Status().Update(ctx, job)).Should(Succeed())
+
+			renovateConfigKey := types.NamespacedName{Name: buildappstudiov1alpha1.RenovateTektonConfigName}
+			Eventually(func() bool {
+				Expect(k8sClient.Get(ctx, renovateConfigKey, renovateConfig)).Should(Succeed())
+				condition := meta.FindStatusCondition(renovateConfig.Status.Conditions, buildappstudiov1alpha1.RenovateSweepSucceededConditionType)
+				return condition != nil && condition.Status == metav1.ConditionFalse && condition.Reason == "RenovateJobFailed"
+			}).WithTimeout(timeout).Should(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,396 @@
+/*
+Copyright 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/exp/slices"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+
+	appstudiov1alpha1 "github.com/redhat-appstudio/application-api/api/v1alpha1"
+	tektonapi "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"knative.dev/pkg/apis"
+
+	buildappstudiov1alpha1 "github.com/konflux-ci/build-service/api/v1alpha1"
+	. "github.com/konflux-ci/build-service/pkg/common"
+	"github.com/konflux-ci/build-service/pkg/featuregates"
+	"github.com/konflux-ci/build-service/pkg/git"
+	"github.com/konflux-ci/build-service/pkg/k8s"
+	l "github.com/konflux-ci/build-service/pkg/logs"
+	"github.com/konflux-ci/build-service/pkg/renovate"
+)
+
+const (
+	// Label with the UID of the RenovateRun which requested the renovate Job.
+	RenovateRunUidLabelName = "build.appstudio.openshift.io/renovate-run-uid"
+	// How often the renovate Jobs of a running RenovateRun are checked.
+	RenovateRunStatusCheckInterval = 30 * time.Second
+)
+
+// RenovateRunReconciler watches RenovateRun objects in order to run renovate
+// on the requested Component repositories and to report the results.
+type RenovateRunReconciler struct {
+	taskProviders  []renovate.TaskProvider
+	client         client.Client
+	eventRecorder  record.EventRecorder
+	jobCoordinator *renovate.JobCoordinator
+}
+
+func NewDefaultRenovateRunReconciler(client client.Client, scheme *runtime.Scheme, eventRecorder record.EventRecorder) *RenovateRunReconciler {
+	return NewRenovateRunReconciler(client, scheme, eventRecorder,
+		[]renovate.TaskProvider{
+			renovate.NewGithubAppRenovaterTaskProvider(k8s.NewGithubAppConfigReader(client, scheme, eventRecorder)),
+			renovate.NewBasicAuthTaskProvider(k8s.NewGitCredentialProvider(client))})
+}
+
+func NewRenovateRunReconciler(client client.Client, scheme *runtime.Scheme, eventRecorder record.EventRecorder, taskProviders []renovate.TaskProvider) *RenovateRunReconciler {
+	return &RenovateRunReconciler{
+		client:         client,
+		taskProviders:  taskProviders,
+		eventRecorder:  eventRecorder,
+		jobCoordinator: renovate.NewJobCoordinator(client, scheme).WithEventRecorder(eventRecorder),
+	}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *RenovateRunReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&buildappstudiov1alpha1.RenovateRun{}).
+		Complete(r)
+}
+
+// +kubebuilder:rbac:groups=appstudio.redhat.com,resources=renovateruns,verbs=get;list;watch
+// +kubebuilder:rbac:groups=appstudio.redhat.com,resources=renovateruns/status,verbs=get;update;patch
+// +kubebuilder:rbac:namespace=system,groups=core,resources=pods,verbs=get;list;watch
+
+func (r *RenovateRunReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := ctrllog.FromContext(ctx).WithName("RenovateRun")
+	ctx = ctrllog.IntoContext(ctx, log)
+
+	if !featuregates.Enabled(featuregates.Renovater) {
+		log.V(l.DebugLevel).Info("Renovater feature is disabled, skipping")
+		return ctrl.Result{}, nil
+	}
+
+	renovateRun := &buildappstudiov1alpha1.RenovateRun{}
+	if err := r.client.Get(ctx, req.NamespacedName, renovateRun); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "failed to get RenovateRun", l.Action, l.ActionView)
+		return ctrl.Result{}, err
+	}
+
+	if renovateRun.Status.CompletionTime != nil {
+		return ctrl.Result{}, nil
+	}
+	if renovateRun.Status.StartTime == nil {
+		return r.startRenovateRun(ctx, renovateRun)
+	}
+	return r.updateRenovateRunStatus(ctx, renovateRun)
+}
+
+// startRenovateRun creates renovate Jobs for the requested repositories and records them in the RenovateRun status.
+// The start time is persisted before the Jobs are created, so a requeued or stale RenovateRun never creates them again.
+func (r *RenovateRunReconciler) startRenovateRun(ctx context.Context, renovateRun *buildappstudiov1alpha1.RenovateRun) (ctrl.Result, error) {
+	log := ctrllog.FromContext(ctx)
+
+	componentList := &appstudiov1alpha1.ComponentList{}
+	if err := r.client.List(ctx, componentList, client.InNamespace(renovateRun.Namespace)); err != nil {
+		log.Error(err, "failed to list Components", l.Action, l.ActionView)
+		return ctrl.Result{}, err
+	}
+	settings := r.jobCoordinator.Settings(ctx)
+	scmComponents, err := newRenovateScmComponents(ctx, r.eventRecorder, componentList.Items, settings)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	requestedUrls := map[string]bool{}
+	for _, repositoryUrl := range renovateRun.Spec.Repositories {
+		requestedUrls[normalizeRenovateRunUrl(repositoryUrl)] = true
+	}
+	if len(requestedUrls) > 0 {
+		var requestedComponents []*git.ScmComponent
+		for _, scmComponent := range scmComponents {
+			if requestedUrls[scmComponent.RepositoryUrlString()] {
+				requestedComponents = append(requestedComponents, scmComponent)
+			}
+		}
+		scmComponents = requestedComponents
+	}
+
+	tasks := getRenovateTasks(ctx, getTaskProviders(r.taskProviders, settings), scmComponents)
+
+	// The update is conditional on the resource version, it fails with a conflict if the RenovateRun in the cache
+	// is stale, e.g. it has been started by the previous reconcile already
+	startTime := metav1.Now()
+	renovateRun.Status.StartTime = &startTime
+	if err := r.client.Status().Update(ctx, renovateRun); err != nil {
+		log.Error(err, "failed to record RenovateRun start time", l.Action, l.ActionUpdate)
+		return ctrl.Result{}, err
+	}
+
+	jobNames, jobsErr := r.jobCoordinator.ExecuteWithLabels(ctx, tasks, map[string]string{RenovateRunUidLabelName: string(renovateRun.UID)})
+	if jobsErr != nil {
+		log.Error(jobsErr, "failed to create a job", l.Action, l.ActionAdd)
+		r.eventRecorder.Event(renovateRun, "Warning", "ErrorCreatingRenovateJob", jobsErr.Error())
+	}
+
+	var repositories []buildappstudiov1alpha1.RenovateRunRepositoryStatus
+	foundUrls := map[string]bool{}
+	for _, scmComponent := range scmComponents {
+		repositoryUrl := scmComponent.RepositoryUrlString()
+		if foundUrls[repositoryUrl] {
+			// several Components may share the same repository
+			continue
+		}
+		foundUrls[repositoryUrl] = true
+		repositoryStatus := buildappstudiov1alpha1.RenovateRunRepositoryStatus{
+			Url:     repositoryUrl,
+			Outcome: buildappstudiov1alpha1.RenovateRunOutcomeSkipped,
+			Message: "no credentials found for the repository",
+		}
+		for _, task := range tasks {
+			if !task.HasComponentRepository(scmComponent) {
+				continue
+			}
+			repositoryStatus.Outcome = buildappstudiov1alpha1.RenovateRunOutcomeFailed
+			repositoryStatus.Message = "failed to create renovate job"
+			// Executed tasks may be split parts of the given ones
+			for executedTask, jobName := range jobNames {
+				if executedTask.HasComponentRepository(scmComponent) {
+					repositoryStatus.Job = jobName
+					repositoryStatus.Outcome = buildappstudiov1alpha1.RenovateRunOutcomeRunning
+					repositoryStatus.Message = ""
+					break
+				}
+			}
+			break
+		}
+		repositories = append(repositories, repositoryStatus)
+	}
+	for _, repositoryUrl := range renovateRun.Spec.Repositories {
+		if normalizedUrl := normalizeRenovateRunUrl(repositoryUrl); !foundUrls[normalizedUrl] {
+			foundUrls[normalizedUrl] = true
+			repositories = append(repositories, buildappstudiov1alpha1.RenovateRunRepositoryStatus{
+				Url:     repositoryUrl,
+				Outcome: buildappstudiov1alpha1.RenovateRunOutcomeSkipped,
+				Message: "no Component with enabled renovate updates found for the repository",
+			})
+		}
+	}
+
+	// The Jobs are created already, so on conflict the status is updated on top of the latest RenovateRun instead of requeueing
+	now := metav1.Now()
+	attempts := 0
+	err = retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		if attempts++; attempts > 1 {
+			if err := r.client.Get(ctx, types.NamespacedName{Name: renovateRun.Name, Namespace: renovateRun.Namespace}, renovateRun); err != nil {
+				return err
+			}
+		}
+		renovateRun.Status.Jobs = uniqueJobNames(jobNames)
+		renovateRun.Status.Repositories = repositories
+		if len(renovateRun.Status.Jobs) == 0 {
+			renovateRun.Status.CompletionTime = &now
+		}
+		return r.client.Status().Update(ctx, renovateRun)
+	})
+	if err != nil {
+		log.Error(err, "failed to update RenovateRun status", l.Action, l.ActionUpdate)
+		return ctrl.Result{}, err
+	}
+	log.Info("renovate run started", "jobs", renovateRun.Status.Jobs, l.Action, l.ActionAdd)
+
+	if renovateRun.Status.CompletionTime != nil {
+		return ctrl.Result{}, nil
+	}
+	return ctrl.Result{RequeueAfter: RenovateRunStatusCheckInterval}, nil
+}
+
+// updateRenovateRunStatus updates outcomes of repositories whose renovate Jobs have finished
+// and sets the completion time once all Jobs are done.
+func (r *RenovateRunReconciler) updateRenovateRunStatus(ctx context.Context, renovateRun *buildappstudiov1alpha1.RenovateRun) (ctrl.Result, error) {
+	log := ctrllog.FromContext(ctx)
+
+	settings := r.jobCoordinator.Settings(ctx)
+	jobOutcomes := map[string]buildappstudiov1alpha1.RenovateRunOutcome{}
+	jobPullRequests := map[string][]renovate.PullRequestReport{}
+	allFinished := true
+	for _, jobName := range renovateRun.Status.Jobs {
+		outcome, pullRequests, err := r.getJobOutcome(ctx, jobName, settings)
+		if err != nil {
+			log.Error(err, "failed to get renovate job", "jobname", jobName, l.Action, l.ActionView)
+			return ctrl.Result{}, err
+		}
+		if outcome == buildappstudiov1alpha1.RenovateRunOutcomeRunning {
+			allFinished = false
+		}
+		jobOutcomes[jobName] = outcome
+		jobPullRequests[jobName] = pullRequests
+	}
+
+	changed := false
+	for i := range renovateRun.Status.Repositories {
+		repositoryStatus := &renovateRun.Status.Repositories[i]
+		if repositoryStatus.Outcome != buildappstudiov1alpha1.RenovateRunOutcomeRunning {
+			continue
+		}
+		if outcome, ok := jobOutcomes[repositoryStatus.Job]; ok && outcome != repositoryStatus.Outcome {
+			repositoryStatus.Outcome = outcome
+			repositoryStatus.PullRequests = getRepositoryPullRequests(repositoryStatus.Url, jobPullRequests[repositoryStatus.Job])
+			changed = true
+		}
+	}
+	if allFinished {
+		now := metav1.Now()
+		renovateRun.Status.CompletionTime = &now
+		changed = true
+	}
+	if changed {
+		if err := r.client.Status().Update(ctx, renovateRun); err != nil {
+			log.Error(err, "failed to update RenovateRun status", l.Action, l.ActionUpdate)
+			return ctrl.Result{}, err
+		}
+	}
+	if allFinished {
+		log.Info("renovate run completed", l.Action, l.ActionUpdate)
+		return ctrl.Result{}, nil
+	}
+	return ctrl.Result{RequeueAfter: RenovateRunStatusCheckInterval}, nil
+}
+
+// getJobOutcome returns the outcome of the renovate Job or, in TaskRun execution mode, of the renovate TaskRun.
+// Pull requests created by the Job are returned once it has finished.
+func (r *RenovateRunReconciler) getJobOutcome(ctx context.Context, jobName string, settings renovate.Settings) (buildappstudiov1alpha1.RenovateRunOutcome, []renovate.PullRequestReport, error) {
+	if settings.IsTenantScoped() {
+		// The namespace of the TaskRun is not recorded, the renovate TaskRuns of all namespaces are cached anyway
+		taskRunList := &tektonapi.TaskRunList{}
+		if err := r.client.List(ctx, taskRunList, client.MatchingLabels{renovate.RenovateJobLabelName: "true"}); err != nil {
+			return "", nil, err
+		}
+		for i := range taskRunList.Items {
+			if taskRunList.Items[i].Name == jobName {
+				return r.getFinishedJobPullRequests(ctx, &taskRunList.Items[i], getRenovateTaskRunOutcome(&taskRunList.Items[i]))
+			}
+		}
+	} else if settings.ExecutionMode == renovate.ExecutionModeTaskRun {
+		taskRun := &tektonapi.TaskRun{}
+		err := r.client.Get(ctx, types.NamespacedName{Name: jobName, Namespace: settings.RunNamespace()}, taskRun)
+		if err == nil {
+			return r.getFinishedJobPullRequests(ctx, taskRun, getRenovateTaskRunOutcome(taskRun))
+		}
+		if !errors.IsNotFound(err) {
+			return "", nil, err
+		}
+		// The run may have been started as a Job before the execution mode was changed
+	}
+	job := &batchv1.Job{}
+	if err := r.client.Get(ctx, types.NamespacedName{Name: jobName, Namespace: BuildServiceNamespaceName}, job); err != nil {
+		if !errors.IsNotFound(err) {
+			return "", nil, err
+		}
+		// The job was deleted before its result was observed
+		return buildappstudiov1alpha1.RenovateRunOutcomeFailed, nil, nil
+	}
+	return r.getFinishedJobPullRequests(ctx, job, getRenovateJobOutcome(job))
+}
+
+// getFinishedJobPullRequests returns the outcome together with the pull requests created by the finished renovate run.
+func (r *RenovateRunReconciler) getFinishedJobPullRequests(ctx context.Context, run client.Object, outcome buildappstudiov1alpha1.RenovateRunOutcome) (buildappstudiov1alpha1.RenovateRunOutcome, []renovate.PullRequestReport, error) {
+	if outcome == buildappstudiov1alpha1.RenovateRunOutcomeRunning {
+		return outcome, nil, nil
+	}
+	pullRequests, err := r.jobCoordinator.GetPullRequestReport(ctx, run)
+	if err != nil {
+		return "", nil, err
+	}
+	return outcome, pullRequests, nil
+}
+
+// getRepositoryPullRequests returns the reported pull requests of the repository with the given URL.
+// Renovate reports the repositories by their full name, e.g. 'org/repo'.
+func getRepositoryPullRequests(repositoryUrl string, pullRequests []renovate.PullRequestReport) []buildappstudiov1alpha1.RenovateRunPullRequest {
+	var repositoryPullRequests []buildappstudiov1alpha1.RenovateRunPullRequest
+	for _, pullRequest := range pullRequests {
+		if pullRequest.Repository != "" && strings.HasSuffix(strings.ToLower(repositoryUrl), "/"+strings.ToLower(pullRequest.Repository)) {
+			repositoryPullRequests = append(repositoryPullRequests, buildappstudiov1alpha1.RenovateRunPullRequest{Number: pullRequest.Number, Branch: pullRequest.Branch})
+		}
+	}
+	return repositoryPullRequests
+}
+
+// getRenovateTaskRunOutcome returns Succeeded or Failed for finished renovate TaskRun and Running otherwise.
+func getRenovateTaskRunOutcome(taskRun *tektonapi.TaskRun) buildappstudiov1alpha1.RenovateRunOutcome {
+	condition := taskRun.Status.GetCondition(apis.ConditionSucceeded)
+	switch {
+	case condition == nil || condition.IsUnknown():
+		return buildappstudiov1alpha1.RenovateRunOutcomeRunning
+	case condition.IsTrue():
+		return buildappstudiov1alpha1.RenovateRunOutcomeSucceeded
+	default:
+		return buildappstudiov1alpha1.RenovateRunOutcomeFailed
+	}
+}
+
+// getRenovateJobOutcome returns Succeeded or Failed for finished renovate Job and Running otherwise.
+func getRenovateJobOutcome(job *batchv1.Job) buildappstudiov1alpha1.RenovateRunOutcome {
+	for _, condition := range job.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch condition.Type {
+		case batchv1.JobComplete:
+			return buildappstudiov1alpha1.RenovateRunOutcomeSucceeded
+		case batchv1.JobFailed:
+			return buildappstudiov1alpha1.RenovateRunOutcomeFailed
+		}
+	}
+	return buildappstudiov1alpha1.RenovateRunOutcomeRunning
+}
+
+// normalizeRenovateRunUrl brings the repository URL into the form used by ScmComponent.
+func normalizeRenovateRunUrl(repositoryUrl string) string {
+	return strings.TrimSuffix(strings.TrimSuffix(repositoryUrl, ".git"), "/")
+}
+
+// uniqueJobNames returns sorted distinct job names.
+func uniqueJobNames(jobNames map[*renovate.Task]string) []string {
+	var names []string
+	for _, name := range jobNames {
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	buildappstudiov1alpha1 "github.com/konflux-ci/build-service/api/v1alpha1"
+	. "github.com/konflux-ci/build-service/pkg/common"
+	"github.com/konflux-ci/build-service/pkg/git/github"
+)
+
+var _ = Describe("RenovateRun controller", func() {
+
+	var (
+		pacSecretKey   = types.NamespacedName{Name: PipelinesAsCodeGitHubAppSecretName, Namespace: BuildServiceNamespaceName}
+		renovateRunKey = types.NamespacedName{Name: "test-renovate-run", Namespace: HASAppNamespace}
+	)
+
+	getRenovateRun := func() *buildappstudiov1alpha1.RenovateRun {
+		renovateRun := &buildappstudiov1alpha1.RenovateRun{}
+		Expect(k8sClient.Get(ctx, renovateRunKey, renovateRun)).Should(Succeed())
+		return renovateRun
+	}
+
+	createRenovateRun := func(repositories []string) {
+		renovateRun := &buildappstudiov1alpha1.RenovateRun{
+			ObjectMeta: metav1.ObjectMeta{Name: renovateRunKey.Name, Namespace: renovateRunKey.Namespace},
+			Spec:       buildappstudiov1alpha1.RenovateRunSpec{Repositories: repositories},
+		}
+		Expect(k8sClient.Create(ctx, renovateRun)).Should(Succeed())
+	}
+
+	Context("Test RenovateRun processing", Label("renovaterun"), func() {
+
+		_ = BeforeEach(func() {
+			createNamespace(BuildServiceNamespaceName)
+			pacSecretData := map[string]string{
+				"github-application-id": "12345",
+				"github-private-key":    githubAppPrivateKey,
+			}
+			createSecret(pacSecretKey, pacSecretData)
+			github.GetAllAppInstallations = func(appIdStr string, privateKeyPem []byte) ([]github.ApplicationInstallation, string, error) {
+				repositories := generateRepositories([]string{"https://github/test/repo1", "https://github/test/repo2"})
+				return []github.ApplicationInstallation{generateInstallation(repositories)}, "slug", nil
+			}
+		})
+
+		_ = AfterEach(func() {
+			Expect(k8sClient.Delete(ctx, &buildappstudiov1alpha1.RenovateRun{
+				ObjectMeta: metav1.ObjectMeta{Name: renovateRunKey.Name, Namespace: renovateRunKey.Namespace},
+			})).Should(Succeed())
+			deleteJobs(BuildServiceNamespaceName)
+			deleteSecret(pacSecretKey)
+		})
+
+		It("should create job for requested repository and report it in status", func() {
+			component1Key := createComponentForPaCBuild(getComponentData(componentConfig{componentKey: types.NamespacedName{Name: "testrun1"}, gitURL: "https://github/test/repo1"}))
+			component2Key := createComponentForPaCBuild(getComponentData(componentConfig{componentKey: types.NamespacedName{Name: "testrun2"}, gitURL: "https://github/test/repo2"}))
+
+			createRenovateRun([]string{"https://github/test/repo1.git"})
+
+			Eventually(listJobs).WithArguments(BuildServiceNamespaceName).WithTimeout(timeout).Should(HaveLen(1))
+			job := listJobs(BuildServiceNamespaceName)[0]
+			Eventually(func() bool {
+				return getRenovateRun().Status.StartTime != nil
+			}).WithTimeout(timeout).Should(BeTrue())
+
+			renovateRun := getRenovateRun()
+			Expect(job.Labels[RenovateRunUidLabelName]).To(Equal(string(renovateRun.UID)))
+			Expect(renovateRun.Status.Jobs).To(Equal([]string{job.Name}))
+			Expect(renovateRun.Status.CompletionTime).To(BeNil())
+			Expect(renovateRun.Status.Repositories).To(HaveLen(1))
+			Expect(renovateRun.Status.Repositories[0].Url).To(Equal("https://github/test/repo1"))
+			Expect(renovateRun.Status.Repositories[0].Job).To(Equal(job.Name))
+			Expect(renovateRun.Status.Repositories[0].Outcome).To(Equal(buildappstudiov1alpha1.RenovateRunOutcomeRunning))
+
+			deleteComponent(component1Key)
+			deleteComponent(component2Key)
+		})
+
+		It("should complete immediately if there is no Component for requested repository", func() {
+			createRenovateRun([]string{"https://github/test/unknown"})
+
+			Eventually(func() bool {
+				return getRenovateRun().Status.CompletionTime != nil
+			}).WithTimeout(timeout).Should(BeTrue())
+
+			renovateRun := getRenovateRun()
+			Expect(renovateRun.Status.Jobs).To(BeEmpty())
+			Expect(renovateRun.Status.Repositories).To(HaveLen(1))
+			Expect(renovateRun.Status.Repositories[0].Url).To(Equal("https://github/test/unknown"))
+			Expect(renovateRun.Status.Repositories[0].Outcome).To(Equal(buildappstudiov1alpha1.RenovateRunOutcomeSkipped))
+			Expect(listJobs(BuildServiceNamespaceName)).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,180 @@
+/*
+Copyright 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	buildappstudiov1alpha1 "github.com/konflux-ci/build-service/api/v1alpha1"
+	. "github.com/konflux-ci/build-service/pkg/common"
+	l "github.com/konflux-ci/build-service/pkg/logs"
+)
+
+const (
+	RenovateSweepCronJobName = "renovate-sweep-trigger"
+	// Service account of the sweep CronJob, allowed to annotate the build pipeline ConfigMap.
+	// The name includes the prefix added by the kustomize deployment, see config/rbac/renovate_sweep_trigger_service_account.yaml
+	RenovateSweepServiceAccountName = "build-service-renovate-sweep-trigger"
+	RenovateSweepImageEnvName       = "RENOVATE_SWEEP_TRIGGER_IMAGE"
+	DefaultRenovateSweepImage       = "quay.io/openshift/origin-cli:4.15"
+)
+
+// RenovateSweepScheduler watches RenovateTektonConfig object in order to manage the CronJob
+// which triggers renovate sweeps according to the configured schedule.
+// Status updates of RenovateTektonConfig are ignored, they don't change the schedule.
+type RenovateSweepScheduler struct {
+	Client        client.Client
+	Scheme        *runtime.Scheme
+	EventRecorder record.EventRecorder
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *RenovateSweepScheduler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&buildappstudiov1alpha1.RenovateTektonConfig{}, builder.WithPredicates(predicate.NewPredicateFuncs(func(object client.Object) bool {
+			return object.GetName() == buildappstudiov1alpha1.RenovateTektonConfigName
+		}), predicate.GenerationChangedPredicate{})).
+		Owns(&batchv1.CronJob{}).
+		Complete(r)
+}
+
+// +kubebuilder:rbac:namespace=system,groups=batch,resources=cronjobs,verbs=create;get;list;watch;update;delete
+
+func (r *RenovateSweepScheduler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := ctrllog.FromContext(ctx).WithName("RenovateSweepScheduler")
+
+	renovateConfig := &buildappstudiov1alpha1.RenovateTektonConfig{}
+	if err := r.Client.Get(ctx, req.NamespacedName, renovateConfig); err != nil {
+		if errors.IsNotFound(err) {
+			// The CronJob is garbage collected together with its owner
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "failed to get RenovateTektonConfig", l.Action, l.ActionView)
+		return ctrl.Result{}, err
+	}
+
+	cronJob := &batchv1.CronJob{}
+	cronJobKey := types.NamespacedName{Name: RenovateSweepCronJobName, Namespace: BuildServiceNamespaceName}
+	cronJobExists := true
+	if err := r.Client.Get(ctx, cronJobKey, cronJob); err != nil {
+		if !errors.IsNotFound(err) {
+			log.Error(err, "failed to get renovate sweep CronJob", l.Action, l.ActionView)
+			return ctrl.Result{}, err
+		}
+		cronJobExists = false
+	}
+
+	if renovateConfig.Spec.SweepSchedule == "" {
+		if cronJobExists {
+			if err := r.Client.Delete(ctx, cronJob); err != nil && !errors.IsNotFound(err) {
+				log.Error(err, "failed to delete renovate sweep CronJob", l.Action, l.ActionDelete)
+				return ctrl.Result{}, err
+			}
+			log.Info("renovate sweep CronJob deleted", l.Action, l.ActionDelete)
+		}
+		return ctrl.Result{}, nil
+	}
+
+	if !cronJobExists {
+		cronJob = &batchv1.CronJob{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      cronJobKey.Name,
+				Namespace: cronJobKey.Namespace,
+			},
+		}
+		cronJob.Spec = getRenovateSweepCronJobSpec(renovateConfig.Spec)
+		if err := controllerutil.SetControllerReference(renovateConfig, cronJob, r.Scheme); err != nil {
+			return ctrl.Result{}, err
+		}
+		if err := r.Client.Create(ctx, cronJob); err != nil {
+			log.Error(err, "failed to create renovate sweep CronJob", l.Action, l.ActionAdd)
+			r.EventRecorder.Event(renovateConfig, "Warning", "ErrorCreatingSweepCronJob", err.Error())
+			return ctrl.Result{}, err
+		}
+		log.Info("renovate sweep CronJob created", "schedule", cronJob.Spec.Schedule, l.Action, l.ActionAdd)
+		return ctrl.Result{}, nil
+	}
+
+	cronJob.Spec = getRenovateSweepCronJobSpec(renovateConfig.Spec)
+	if err := r.Client.Update(ctx, cronJob); err != nil {
+		log.Error(err, "failed to update renovate sweep CronJob", l.Action, l.ActionUpdate)
+		return ctrl.Result{}, err
+	}
+	log.Info("renovate sweep CronJob updated", "schedule", cronJob.Spec.Schedule, "suspended", *cronJob.Spec.Suspend, l.Action, l.ActionUpdate)
+	return ctrl.Result{}, nil
+}
+
+// getRenovateSweepCronJobSpec returns the spec of the CronJob which requests on-demand renovate sweep
+// by annotating the build pipeline ConfigMap.
+func getRenovateSweepCronJobSpec(spec buildappstudiov1alpha1.RenovateTektonConfigSpec) batchv1.CronJobSpec {
+	image := GetConfigValue(RenovateSweepImageEnvName)
+	if image == "" {
+		image = DefaultRenovateSweepImage
+	}
+	return batchv1.CronJobSpec{
+		Schedule:                   spec.SweepSchedule,
+		Suspend:                    ptr.To(spec.SuspendSweeps),
+		ConcurrencyPolicy:          batchv1.ForbidConcurrent,
+		SuccessfulJobsHistoryLimit: ptr.To(int32(1)),
+		FailedJobsHistoryLimit:     ptr.To(int32(1)),
+		JobTemplate: batchv1.JobTemplateSpec{
+			Spec: batchv1.JobSpec{
+				BackoffLimit: ptr.To(int32(1)),
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						ServiceAccountName: RenovateSweepServiceAccountName,
+						Containers: []corev1.Container{
+							{
+								Name:  "trigger",
+								Image: image,
+								Command: []string{"kubectl", "annotate", "--overwrite",
+									"--namespace", BuildServiceNamespaceName,
+									"configmap", buildPipelineConfigMapResourceName,
+									fmt.Sprintf("%s=true", RunRenovateAnnotationName)},
+								SecurityContext: &corev1.SecurityContext{
+									Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+									RunAsNonRoot:             ptr.To(true),
+									AllowPrivilegeEscalation: ptr.To(false),
+									SeccompProfile: &corev1.SeccompProfile{
+										Type: corev1.SeccompProfileTypeRuntimeDefault,
+									},
+								},
+							},
+						},
+						RestartPolicy: corev1.RestartPolicyNever,
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	batchv1 "k8s.io/api/batch/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	buildappstudiov1alpha1 "github.com/konflux-ci/build-service/api/v1alpha1"
+	. "github.com/konflux-ci/build-service/pkg/common"
+	"github.com/konflux-ci/build-service/pkg/git/github"
+)
+
+var _ = Describe("Renovate sweep scheduler", func() {
+
+	var (
+		cronJobKey        = types.NamespacedName{Name: RenovateSweepCronJobName, Namespace: BuildServiceNamespaceName}
+		renovateConfigKey = types.NamespacedName{Name: buildappstudiov1alpha1.RenovateTektonConfigName}
+		pacSecretKey      = types.NamespacedName{Name: PipelinesAsCodeGitHubAppSecretName, Namespace: BuildServiceNamespaceName}
+	)
+
+	getCronJob := func() (*batchv1.CronJob, error) {
+		cronJob := &batchv1.CronJob{}
+		err := k8sClient.Get(ctx, cronJobKey, cronJob)
+		return cronJob, err
+	}
+
+	Context("Test renovate sweep CronJob management", Label("renovater"), func() {
+
+		_ = BeforeEach(func() {
+			createNamespace(BuildServiceNamespaceName)
+		})
+
+		_ = AfterEach(func() {
+			renovateConfig := &buildappstudiov1alpha1.RenovateTektonConfig{ObjectMeta: metav1.ObjectMeta{Name: renovateConfigKey.Name}}
+			Expect(k8sClient.Delete(ctx, renovateConfig)).Should(Succeed())
+			// envtest has no garbage collector
+			if cronJob, err := getCronJob(); err == nil {
+				Expect(k8sClient.Delete(ctx, cronJob)).Should(Succeed())
+			}
+		})
+
+		It("should create, update and delete the sweep CronJob according to RenovateTektonConfig", func() {
+			renovateConfig := &buildappstudiov1alpha1.RenovateTektonConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: renovateConfigKey.Name},
+				Spec:       buildappstudiov1alpha1.RenovateTektonConfigSpec{SweepSchedule: "0 2 * * *"},
+			}
+			Expect(k8sClient.Create(ctx, renovateConfig)).Should(Succeed())
+
+			Eventually(func() error {
+				_, err := getCronJob()
+				return err
+			}).WithTimeout(timeout).Should(Succeed())
+			cronJob, _ := getCronJob()
+			Expect(cronJob.Spec.Schedule).To(Equal("0 2 * * *"))
+			Expect(*cronJob.Spec.Suspend).To(BeFalse())
+			Expect(cronJob.OwnerReferences).To(HaveLen(1))
+			Expect(cronJob.Spec.JobTemplate.Spec.Template.Spec.Containers[0].Command).To(ContainElement(RunRenovateAnnotationName + "=true"))
+
+			Expect(k8sClient.Get(ctx, renovateConfigKey, renovateConfig)).Should(Succeed())
+			renovateConfig.Spec.SweepSchedule = "0 3 * * 6"
+			renovateConfig.Spec.SuspendSweeps = true
+			Expect(k8sClient.Update(ctx, renovateConfig)).Should(Succeed())
+			Eventually(func() bool {
+				cronJob, err := getCronJob()
+				return err == nil && cronJob.Spec.Schedule == "0 3 * * 6" && *cronJob.Spec.Suspend
+			}).WithTimeout(timeout).Should(BeTrue())
+
+			Expect(k8sClient.Get(ctx, renovateConfigKey, renovateConfig)).Should(Succeed())
+			renovateConfig.Spec.SweepSchedule = ""
+			Expect(k8sClient.Update(ctx, renovateConfig)).Should(Succeed())
+			Eventually(func() bool {
+				_, err := getCronJob()
+				return k8sErrors.IsNotFound(err)
+			}).WithTimeout(timeout).Should(BeTrue())
+		})
+
+		It("should not trigger periodic sweep if sweeps are scheduled", func() {
+			pacSecretData := map[string]string{
+				"github-application-id": "12345",
+				"github-private-key":    githubAppPrivateKey,
+			}
+			createSecret(pacSecretKey, pacSecretData)
+			github.GetAllAppInstallations = func(appIdStr string, privateKeyPem []byte) ([]github.ApplicationInstallation, string, error) {
+				repositories := generateRepositories([]string{"https://github/test/repo1"})
+				return []github.ApplicationInstallation{generateInstallation(repositories)}, "slug", nil
+			}
+			renovateConfig := &buildappstudiov1alpha1.RenovateTektonConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: renovateConfigKey.Name},
+				Spec:       buildappstudiov1alpha1.RenovateTektonConfigSpec{SweepSchedule: "0 2 * * *"},
+			}
+			Expect(k8sClient.Create(ctx, renovateConfig)).Should(Succeed())
+			componentNamespacedName := createComponentForPaCBuild(getComponentData(componentConfig{componentKey: types.NamespacedName{Name: "testscheduledsweep"}, gitURL: "https://github/test/repo1"}))
+
+			createDefaultBuildPipelineConfigMap(defaultPipelineConfigMapKey)
+			Consistently(listJobs).WithArguments(BuildServiceNamespaceName).WithTimeout(time.Second * 5).Should(BeEmpty())
+
+			deleteBuildPipelineConfigMap(defaultPipelineConfigMapKey)
+			deleteComponent(componentNamespacedName)
+			deleteSecret(pacSecretKey)
+		})
+	})
+})
@@ -3,7 +3,6 @@ package controllers
 import (
 	"context"
 	"fmt"
-	"os"
 	"strings"
 	"time"
 
@@ -165,7 +164,7 @@ func CreateRenovaterPipeline(ctx context.Context, client client.Client, scheme *
 	}
 	trueBool := true
 	falseBool := false
-	renovateImageUrl := os.Getenv(renovate.RenovateImageEnvName)
+	renovateImageUrl := GetConfigValue(renovate.RenovateImageEnvName)
 	if renovateImageUrl == "" {
 		renovateImageUrl = renovate.DefaultRenovateImageUrl
 	}
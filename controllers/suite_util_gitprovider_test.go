@@ -27,6 +27,7 @@ var (
 	UndoPacMergeRequestURL  = "https://githost.com/mr/5678"
 
 	EnsurePaCMergeRequestFunc        func(repoUrl string, data *gp.MergeRequestData) (webUrl string, err error)
+	PushPaCConfigurationFunc         func(repoUrl string, data *gp.MergeRequestData) (webUrl string, err error)
 	UndoPaCMergeRequestFunc          func(repoUrl string, data *gp.MergeRequestData) (webUrl string, err error)
 	FindUnmergedPaCMergeRequestFunc  func(repoUrl string, data *gp.MergeRequestData) (*gp.MergeRequest, error)
 	SetupPaCWebhookFunc              func(repoUrl string, webhookUrl string, webhookSecret string) error
@@ -48,6 +49,9 @@ func ResetTestGitProviderClient() {
 	EnsurePaCMergeRequestFunc = func(repoUrl string, data *gp.MergeRequestData) (webUrl string, err error) {
 		return "https://githost.com/mr/1234", nil
 	}
+	PushPaCConfigurationFunc = func(repoUrl string, data *gp.MergeRequestData) (webUrl string, err error) {
+		return DefaultBrowseRepository + "abcd890", nil
+	}
 	UndoPaCMergeRequestFunc = func(repoUrl string, data *gp.MergeRequestData) (webUrl string, err error) {
 		return UndoPacMergeRequestURL, nil
 	}
@@ -90,6 +94,9 @@ type TestGitProviderClient struct{}
 func (*TestGitProviderClient) EnsurePaCMergeRequest(repoUrl string, data *gp.MergeRequestData) (webUrl string, err error) {
 	return EnsurePaCMergeRequestFunc(repoUrl, data)
 }
+func (*TestGitProviderClient) PushPaCConfiguration(repoUrl string, data *gp.MergeRequestData) (webUrl string, err error) {
+	return PushPaCConfigurationFunc(repoUrl, data)
+}
 func (*TestGitProviderClient) UndoPaCMergeRequest(repoUrl string, data *gp.MergeRequestData) (webUrl string, err error) {
 	return UndoPaCMergeRequestFunc(repoUrl, data)
 }
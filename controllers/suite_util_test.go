@@ -238,9 +238,10 @@ func setComponentBuildRequest(componentKey types.NamespacedName, buildRequest st
 
 // createComponentForPaCBuild is deprecated
 func createComponentForPaCBuild(sampleComponentData *appstudiov1alpha1.Component) types.NamespacedName {
-	sampleComponentData.Annotations = map[string]string{
-		BuildRequestAnnotationName: BuildRequestConfigurePaCAnnotationValue,
+	if sampleComponentData.Annotations == nil {
+		sampleComponentData.Annotations = make(map[string]string)
 	}
+	sampleComponentData.Annotations[BuildRequestAnnotationName] = BuildRequestConfigurePaCAnnotationValue
 
 	Expect(k8sClient.Create(ctx, sampleComponentData)).Should(Succeed())
 
@@ -36,6 +36,7 @@ import (
 
 	"github.com/go-logr/logr"
 	routev1 "github.com/openshift/api/route/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -56,10 +57,14 @@ import (
 	tektonapi "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
 
 	appstudioredhatcomv1alpha1 "github.com/konflux-ci/build-service/api/v1alpha1"
+	appstudioredhatcomv1beta1 "github.com/konflux-ci/build-service/api/v1beta1"
 	"github.com/konflux-ci/build-service/controllers"
 	"github.com/konflux-ci/build-service/pkg/bometrics"
+	"github.com/konflux-ci/build-service/pkg/common"
+	"github.com/konflux-ci/build-service/pkg/featuregates"
 	"github.com/konflux-ci/build-service/pkg/k8s"
 	l "github.com/konflux-ci/build-service/pkg/logs"
+	"github.com/konflux-ci/build-service/pkg/renovate"
 	"github.com/konflux-ci/build-service/pkg/webhook"
 	//+kubebuilder:scaffold:imports
 )
@@ -74,6 +79,7 @@ func init() {
 
 	utilruntime.Must(appstudiov1alpha1.AddToScheme(scheme))
 	utilruntime.Must(appstudioredhatcomv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(appstudioredhatcomv1beta1.AddToScheme(scheme))
 	//+kubebuilder:scaffold:scheme
 }
 
@@ -84,6 +90,7 @@ func main() {
 	var enableLeaderElection bool
 	var probeAddr string
 	var webhookConfigPath string
+	var featureGates string
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
@@ -95,6 +102,9 @@ func main() {
 		"",
 		"Path to a file that contains webhook configurations",
 	)
+	flag.StringVar(&featureGates, "feature-gates", "",
+		"Comma separated list of Feature=true|false pairs to enable or disable Build Service capabilities. "+
+			"Known features: "+strings.Join(featuregates.KnownFeatures(), ", ")+". BuildServiceConfig takes precedence.")
 
 	zapOpts := zap.Options{
 		TimeEncoder: uberzapcore.ISO8601TimeEncoder,
@@ -108,6 +118,11 @@ func main() {
 	setupLog = ctrl.Log.WithName("setup")
 	klog.SetLogger(setupLog)
 
+	if err := featuregates.SetFromFlag(featureGates); err != nil {
+		setupLog.Error(err, "invalid feature gates")
+		os.Exit(1)
+	}
+
 	if err := routev1.AddToScheme(scheme); err != nil {
 		setupLog.Error(err, "unable to add openshift route api to the scheme")
 		os.Exit(1)
@@ -186,6 +201,27 @@ func main() {
 		os.Exit(1)
 	}
 
+	if err = (controllers.NewDefaultRenovateRunReconciler(mgr.GetClient(), mgr.GetScheme(), mgr.GetEventRecorderFor("RenovateRun"))).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "RenovateRun")
+		os.Exit(1)
+	}
+
+	if err = (&controllers.RenovateJobReconciler{
+		Client: mgr.GetClient(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "RenovateJob")
+		os.Exit(1)
+	}
+
+	if err = (&controllers.RenovateSweepScheduler{
+		Client:        mgr.GetClient(),
+		Scheme:        mgr.GetScheme(),
+		EventRecorder: mgr.GetEventRecorderFor("RenovateSweepScheduler"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "RenovateSweepScheduler")
+		os.Exit(1)
+	}
+
 	if err = (&controllers.ComponentDependencyUpdateReconciler{
 		Client:         mgr.GetClient(),
 		ApiReader:      mgr.GetAPIReader(),
@@ -197,6 +233,68 @@ func main() {
 		os.Exit(1)
 	}
 
+	if err = (&controllers.ImageRegistrySecretRotationReconciler{
+		Client:        mgr.GetClient(),
+		Scheme:        mgr.GetScheme(),
+		EventRecorder: mgr.GetEventRecorderFor("ImageRegistrySecretRotation"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ImageRegistrySecretRotation")
+		os.Exit(1)
+	}
+
+	if err = (&controllers.ImageRegistrySecretLinkReconciler{
+		Client:        mgr.GetClient(),
+		Scheme:        mgr.GetScheme(),
+		EventRecorder: mgr.GetEventRecorderFor("ImageRegistrySecretLink"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ImageRegistrySecretLink")
+		os.Exit(1)
+	}
+
+	if err = (&controllers.ComponentBuildStatusReconciler{
+		Client:        mgr.GetClient(),
+		Scheme:        mgr.GetScheme(),
+		EventRecorder: mgr.GetEventRecorderFor("ComponentBuildStatus"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ComponentBuildStatus")
+		os.Exit(1)
+	}
+
+	if err = (&controllers.PipelineRunRetentionReconciler{
+		Client:        mgr.GetClient(),
+		Scheme:        mgr.GetScheme(),
+		EventRecorder: mgr.GetEventRecorderFor("PipelineRunRetention"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "PipelineRunRetention")
+		os.Exit(1)
+	}
+
+	if err = (&controllers.PaCSecretValidationReconciler{
+		Client:        mgr.GetClient(),
+		Scheme:        mgr.GetScheme(),
+		EventRecorder: mgr.GetEventRecorderFor("PaCSecretValidation"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "PaCSecretValidation")
+		os.Exit(1)
+	}
+
+	if err = (&controllers.BuildServiceConfigReconciler{
+		Client:        mgr.GetClient(),
+		Scheme:        mgr.GetScheme(),
+		EventRecorder: mgr.GetEventRecorderFor("BuildServiceConfig"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "BuildServiceConfig")
+		os.Exit(1)
+	}
+
+	// The webhook server requires serving certificates, so it's enabled only in deployments which provide them
+	if os.Getenv("ENABLE_WEBHOOKS") == "true" {
+		if err = (&appstudioredhatcomv1alpha1.BuildPipelineSelector{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "BuildPipelineSelector")
+			os.Exit(1)
+		}
+	}
+
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -221,6 +319,10 @@ func main() {
 	}
 
 	ctx := ctrl.SetupSignalHandler()
+	if err := controllers.LoadBuildServiceConfig(ctx, mgr.GetAPIReader()); err != nil {
+		// The controller applies the configuration once it's reconciled
+		setupLog.Error(err, "unable to load BuildServiceConfig, using environment variables")
+	}
 	buildMetrics := bometrics.NewBuildMetrics([]bometrics.AvailabilityProbe{bometrics.NewGithubAppAvailabilityProbe(mgr.GetClient())})
 	if err := buildMetrics.InitMetrics(metrics.Registry); err != nil {
 		setupLog.Error(err, "unable to initialize metrics")
@@ -249,6 +351,8 @@ func getCacheOptions() cache.Options {
 		panic(err)
 	}
 	appStudioComponentPipelineRunSelector := labels.NewSelector().Add(*componentPipelineRunRequirement)
+	renovateJobSelector := labels.SelectorFromSet(labels.Set{renovate.RenovateJobLabelName: "true"})
+	imageRegistrySecretLinkSelector := labels.SelectorFromSet(labels.Set{controllers.ImageRegistrySecretLinkLabelName: "true"})
 
 	return cache.Options{
 		ByObject: map[client.Object]cache.ByObject{
@@ -256,6 +360,30 @@ func getCacheOptions() cache.Options {
 				Label: appStudioComponentPipelineRunSelector,
 			},
 			&releaseapi.ReleasePlanAdmission{}: {},
+			// Only renovate TaskRuns are read by the operator
+			&tektonapi.TaskRun{}: {
+				Label: renovateJobSelector,
+			},
+			// Renovate jobs are allowed to be managed only in the build-service namespace
+			&batchv1.Job{}: {
+				Namespaces: map[string]cache.Config{common.BuildServiceNamespaceName: {}},
+			},
+			// Pods of renovate jobs are read to get the jobs reports
+			&corev1.Pod{}: {
+				Namespaces: map[string]cache.Config{common.BuildServiceNamespaceName: {}},
+				Label:      renovateJobSelector,
+			},
+			&batchv1.CronJob{}: {
+				Namespaces: map[string]cache.Config{common.BuildServiceNamespaceName: {}},
+			},
+			// Only GitHub App secrets in the build-service namespace and image registry secrets
+			// requested to be linked to the pipeline service account are watched, secrets are read without cache
+			&corev1.Secret{}: {
+				Namespaces: map[string]cache.Config{
+					common.BuildServiceNamespaceName: {},
+					cache.AllNamespaces:              {LabelSelector: imageRegistrySecretLinkSelector},
+				},
+			},
 		},
 	}
 }
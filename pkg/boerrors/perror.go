@@ -92,6 +92,10 @@ const (
 	EPaCDuplicateRepository BOErrorId = 53
 	// Git repository url isn't allowed
 	EPaCNotAllowedRepositoryUrl BOErrorId = 54
+	// Template of Pipelines as Code configuration merge request branch, commit message or description is invalid.
+	EPaCMergeRequestTemplateInvalid BOErrorId = 55
+	// Unknown Pipelines as Code provision mode is requested.
+	EPaCProvisionModeInvalid BOErrorId = 56
 
 	// Happens when Component source repository is hosted on unsupported / unknown git provider.
 	// For example: https://my-gitlab.com
@@ -100,6 +104,8 @@ const (
 	EUnknownGitProvider BOErrorId = 60
 	// Insecure HTTP can't be used for git repository URL
 	EHttpUsedForRepository BOErrorId = 61
+	// Support of the git provider of Component source repository is disabled by a feature gate on the cluster.
+	EGitProviderDisabled BOErrorId = 62
 
 	// Happens when configured in cluster Pipelines as Code application is not installed in Component source repository.
 	// User must install the application to fix this error.
@@ -141,6 +147,22 @@ const (
 	// EGitLabSecretTypeNotSupported the secret type with GitLab credentials is not supported.
 	EGitLabSecretTypeNotSupported BOErrorId = 93
 
+	// EBitbucketTokenUnauthorized access token or app password is not recognized by Bitbucket and 401 is responded.
+	// The credentials may be malformed, expired or revoked.
+	EBitbucketTokenUnauthorized BOErrorId = 100
+	// EBitbucketTokenInsufficientScope the access token or app password does not have sufficient permissions and 403 is responded.
+	EBitbucketTokenInsufficientScope BOErrorId = 101
+	// EBitbucketSecretTypeNotSupported the secret type with Bitbucket credentials is not supported.
+	EBitbucketSecretTypeNotSupported BOErrorId = 102
+
+	// EGiteaTokenUnauthorized access token is not recognized by Gitea and 401 is responded.
+	// The access token may be malformed or revoked.
+	EGiteaTokenUnauthorized BOErrorId = 110
+	// EGiteaTokenInsufficientScope the access token does not have sufficient scope and 403 is responded.
+	EGiteaTokenInsufficientScope BOErrorId = 111
+	// EGiteaSecretTypeNotSupported the secret type with Gitea credentials is not supported.
+	EGiteaSecretTypeNotSupported BOErrorId = 112
+
 	// Value of 'image.redhat.com/image' component annotation is not a valid json or the json has invalid structure.
 	EFailedToParseImageAnnotation BOErrorId = 200
 	// The secret with git credentials specified in component.Spec.Secret does not exist in the user's namespace.
@@ -164,6 +186,10 @@ const (
 	EUnsupportedPipelineRef BOErrorId = 302
 	// EMissingParamsForBundleResolver The pipelineRef selected for a component is missing parameters required for the bundle resolver.
 	EMissingParamsForBundleResolver BOErrorId = 303
+	// EMissingParamsForGitResolver The pipelineRef selected for a component is missing parameters required for the git resolver.
+	EMissingParamsForGitResolver BOErrorId = 304
+	// EInvalidPipelineRunPolicy The PipelineRun policy ConfigMap which applies to a component is not valid.
+	EInvalidPipelineRunPolicy BOErrorId = 305
 
 	// EPipelineRetrievalFailed Failed to retrieve a Tekton Pipeline.
 	EPipelineRetrievalFailed BOErrorId = 400
@@ -176,14 +202,17 @@ var boErrorMessages = map[BOErrorId]string{
 	ETransientError: "",
 	EUnknownError:   "unknown error",
 
-	EPaCSecretNotFound:          "Pipelines as Code secret does not exist",
-	EPaCSecretInvalid:           "Invalid Pipelines as Code secret",
-	EPaCRouteDoesNotExist:       "Pipelines as Code public route does not exist",
-	EPaCDuplicateRepository:     "Git repository is already handled by Pipelines as Code",
-	EPaCNotAllowedRepositoryUrl: "Git repository url isn't allowed",
+	EPaCSecretNotFound:              "Pipelines as Code secret does not exist",
+	EPaCSecretInvalid:               "Invalid Pipelines as Code secret",
+	EPaCRouteDoesNotExist:           "Pipelines as Code public route does not exist",
+	EPaCDuplicateRepository:         "Git repository is already handled by Pipelines as Code",
+	EPaCNotAllowedRepositoryUrl:     "Git repository url isn't allowed",
+	EPaCMergeRequestTemplateInvalid: "Invalid Pipelines as Code merge request template",
+	EPaCProvisionModeInvalid:        "Invalid Pipelines as Code provision mode",
 
 	EUnknownGitProvider:    "unknown git provider of the source repository",
 	EHttpUsedForRepository: "http used for git repository, use secure connection",
+	EGitProviderDisabled:   "git provider of the source repository is disabled on the cluster",
 
 	EGitHubAppNotInstalled:         "GitHub Application is not installed in user repository",
 	EGitHubAppMalformedPrivateKey:  "malformed GitHub Application private key",
@@ -199,6 +228,14 @@ var boErrorMessages = map[BOErrorId]string{
 	EGitLabTokenInsufficientScope: "GitLab access token does not have enough scope",
 	EGitLabTokenUnauthorized:      "Access token is unrecognizable by remote GitLab service",
 
+	EBitbucketTokenUnauthorized:      "Access token is unrecognizable by Bitbucket",
+	EBitbucketTokenInsufficientScope: "Bitbucket access token does not have enough permissions",
+	EBitbucketSecretTypeNotSupported: "Bitbucket secret type is not supported",
+
+	EGiteaTokenUnauthorized:      "Access token is unrecognizable by remote Gitea service",
+	EGiteaTokenInsufficientScope: "Gitea access token does not have enough scope",
+	EGiteaSecretTypeNotSupported: "Gitea secret type is not supported",
+
 	EFailedToParseImageAnnotation:        "Failed to parse image.redhat.com/image annotation value",
 	EComponentGitSecretMissing:           "Secret with git credential not found",
 	EComponentImageRegistrySecretMissing: "Component image repository secret not found",
@@ -211,6 +248,8 @@ var boErrorMessages = map[BOErrorId]string{
 	EBuildPipelineSelectorNotDefined: "Build pipeline selector is not defined yet.",
 	EUnsupportedPipelineRef:          "The pipelineRef for this component (based on pipeline selectors) is not supported.",
 	EMissingParamsForBundleResolver:  "The pipelineRef for this component is missing required parameters ('name' and/or 'bundle').",
+	EMissingParamsForGitResolver:     "The pipelineRef for this component is missing required parameters ('url', 'revision' and/or 'pathInRepo').",
+	EInvalidPipelineRunPolicy:        "The PipelineRun policy (timeouts, task compute resources, pod template) for this component is not valid.",
 
 	EPipelineRetrievalFailed:  "Failed to retrieve the pipeline selected for this component.",
 	EPipelineConversionFailed: "Failed to convert the selected pipeline to the supported Tekton API version.",
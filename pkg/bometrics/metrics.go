@@ -50,6 +50,72 @@ var (
 		Name:      "Push_pipeline_rebuild_trigger_time",
 		Help:      "The time in seconds spent from the moment of requesting push pipeline rebuild till Pipelines-as-Code API trigger.",
 	})
+	RenovateJobsCreatedTotalMetric = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: MetricsNamespace,
+		Subsystem: MetricsSubsystem,
+		Name:      "renovate_jobs_created_total",
+		Help:      "The number of renovate Jobs and TaskRuns created by renovate sweeps and RenovateRuns.",
+	})
+	RenovateJobsSucceededTotalMetric = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: MetricsNamespace,
+		Subsystem: MetricsSubsystem,
+		Name:      "renovate_jobs_succeeded_total",
+		Help:      "The number of renovate Jobs and TaskRuns which finished successfully.",
+	})
+	RenovateJobsFailedTotalMetric = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: MetricsNamespace,
+		Subsystem: MetricsSubsystem,
+		Name:      "renovate_jobs_failed_total",
+		Help:      "The number of renovate Jobs and TaskRuns which failed.",
+	})
+	RenovateInstallationsMatchedMetric = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: MetricsNamespace,
+		Subsystem: MetricsSubsystem,
+		Name:      "renovate_installations_matched",
+		Help:      "The number of GitHub App installations with repositories of Components in the last renovate sweep.",
+	})
+	RenovateRepositoriesMatchedMetric = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: MetricsNamespace,
+		Subsystem: MetricsSubsystem,
+		Name:      "renovate_repositories_matched",
+		Help:      "The number of GitHub App installed repositories matched with Components in the last renovate sweep.",
+	})
+	RenovateRepositoriesSkippedNoComponentMetric = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: MetricsNamespace,
+		Subsystem: MetricsSubsystem,
+		Name:      "renovate_repositories_skipped_no_component",
+		Help:      "The number of GitHub App installed repositories skipped in the last renovate sweep, because no Component matched them.",
+	})
+	RenovateSweepsWithoutComponentsTotalMetric = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: MetricsNamespace,
+		Subsystem: MetricsSubsystem,
+		Name:      "renovate_sweeps_without_components_total",
+		Help:      "The number of renovate sweeps which found no Components to renovate and skipped listing of the installations and credentials.",
+	})
+	GitHubAppRateLimitRemainingMetric = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: MetricsNamespace,
+		Subsystem: MetricsSubsystem,
+		Name:      "github_app_rate_limit_remaining",
+		Help:      "The number of GitHub API requests remaining in the rate limit of the GitHub App, as reported by the last listing of its installations.",
+	}, []string{"app_id"})
+	GitHubAppInstallationsSuspendedMetric = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: MetricsNamespace,
+		Subsystem: MetricsSubsystem,
+		Name:      "github_app_installations_suspended",
+		Help:      "The number of suspended installations of the GitHub App skipped by the last listing of its installations, their repositories are not renovated.",
+	}, []string{"app_id"})
+	GitHubAppInstallationTokensReusedTotalMetric = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: MetricsNamespace,
+		Subsystem: MetricsSubsystem,
+		Name:      "github_app_installation_tokens_reused_total",
+		Help:      "The number of cached GitHub App installation tokens reused by the listings of installations instead of creating new tokens.",
+	})
+	PaCSecretInvalidMetric = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: MetricsNamespace,
+		Subsystem: MetricsSubsystem,
+		Name:      "pac_secret_invalid",
+		Help:      "Shows 1 if the last validation of the Pipelines as Code secret found its content malformed, e.g. unparsable GitHub App ID, invalid private key or empty token, 0 otherwise.",
+	}, []string{"namespace", "name"})
 	ComponentTimesForMetrics = map[string]ComponentMetricsInfo{}
 )
 
@@ -69,7 +135,11 @@ func NewBuildMetrics(probes []AvailabilityProbe) *BuildMetrics {
 }
 
 func (m *BuildMetrics) InitMetrics(registerer prometheus.Registerer) error {
-	registerer.MustRegister(ComponentOnboardingTimeMetric, SimpleBuildPipelineCreationTimeMetric, PipelinesAsCodeComponentProvisionTimeMetric, PipelinesAsCodeComponentUnconfigureTimeMetric, PushPipelineRebuildTriggerTimeMetric)
+	registerer.MustRegister(ComponentOnboardingTimeMetric, SimpleBuildPipelineCreationTimeMetric, PipelinesAsCodeComponentProvisionTimeMetric, PipelinesAsCodeComponentUnconfigureTimeMetric, PushPipelineRebuildTriggerTimeMetric,
+		RenovateJobsCreatedTotalMetric, RenovateJobsSucceededTotalMetric, RenovateJobsFailedTotalMetric,
+		RenovateInstallationsMatchedMetric, RenovateRepositoriesMatchedMetric, RenovateRepositoriesSkippedNoComponentMetric, RenovateSweepsWithoutComponentsTotalMetric,
+		GitHubAppRateLimitRemainingMetric, GitHubAppInstallationsSuspendedMetric, GitHubAppInstallationTokensReusedTotalMetric,
+		PaCSecretInvalidMetric)
 	for _, probe := range m.probes {
 		if err := registerer.Register(probe.AvailabilityGauge()); err != nil {
 			return fmt.Errorf("failed to register the availability metric: %w", err)
@@ -0,0 +1,32 @@
+package common
+
+import (
+	"os"
+	"sync"
+)
+
+var (
+	configOverridesLock sync.RWMutex
+	configOverrides     map[string]string
+)
+
+// GetConfigValue returns value of the operator configuration option with the given environment variable name.
+// The value set in the BuildServiceConfig object takes precedence over the environment variable,
+// so the configuration can be changed without restart of the operator.
+func GetConfigValue(envName string) string {
+	configOverridesLock.RLock()
+	value, isOverridden := configOverrides[envName]
+	configOverridesLock.RUnlock()
+	if isOverridden {
+		return value
+	}
+	return os.Getenv(envName)
+}
+
+// SetConfigOverrides replaces the values of the configuration options which override the environment variables.
+// nil removes all the overrides.
+func SetConfigOverrides(overrides map[string]string) {
+	configOverridesLock.Lock()
+	defer configOverridesLock.Unlock()
+	configOverrides = overrides
+}
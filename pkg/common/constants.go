@@ -8,4 +8,7 @@ const (
 	// Keys of the GitHub app ID and the app private key in the pipelines-as-code-secret
 	PipelinesAsCodeGithubAppIdKey   = "github-application-id"
 	PipelinesAsCodeGithubPrivateKey = "github-private-key"
+	// Label of additional GitHub application configuration secrets in Build Service namespace.
+	// Such secrets have the same format as pipelines-as-code-secret.
+	GitHubAppSecretLabel = "build.appstudio.openshift.io/github-app"
 )
@@ -90,3 +90,23 @@ func TestIsPaCApplicationConfigured(t *testing.T) {
 		})
 	}
 }
+
+func TestGetConfigValue(t *testing.T) {
+	const envName = "BUILD_SERVICE_TEST_CONFIG_OPTION"
+	t.Setenv(envName, "env-value")
+	defer SetConfigOverrides(nil)
+
+	if got := GetConfigValue(envName); got != "env-value" {
+		t.Errorf("want environment variable value, but got %q", got)
+	}
+
+	SetConfigOverrides(map[string]string{envName: "config-value"})
+	if got := GetConfigValue(envName); got != "config-value" {
+		t.Errorf("want overridden value, but got %q", got)
+	}
+
+	SetConfigOverrides(nil)
+	if got := GetConfigValue(envName); got != "env-value" {
+		t.Errorf("want environment variable value after overrides removed, but got %q", got)
+	}
+}
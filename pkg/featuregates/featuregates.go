@@ -0,0 +1,138 @@
+/*
+Copyright 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package featuregates allows to enable or disable individual capabilities of Build Service per cluster.
+// The gates are set by --feature-gates flag and can be overridden by BuildServiceConfig at runtime.
+package featuregates
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+type Feature string
+
+const (
+	// Renovate updates of Tekton references in Component repositories, including sweeps and RenovateRuns.
+	Renovater Feature = "Renovater"
+	// Nudging of the Components which depend on the built Component.
+	Nudging Feature = "Nudging"
+	// Scheduling of renovate pods on nodes of the configured architecture with the architecture specific image.
+	MultiArch Feature = "MultiArch"
+	// Support of Components with source repositories hosted on GitLab.
+	GitLab Feature = "GitLab"
+)
+
+// defaultGates defines the known features and whether they are enabled by default.
+var defaultGates = map[Feature]bool{
+	Renovater: true,
+	Nudging:   true,
+	MultiArch: true,
+	GitLab:    true,
+}
+
+var (
+	gatesLock sync.RWMutex
+	// Gates set by the operator flag
+	flagGates = map[Feature]bool{}
+	// Gates set by BuildServiceConfig, take precedence over the flag
+	overrideGates = map[Feature]bool{}
+)
+
+// Enabled checks whether the given feature is enabled.
+func Enabled(feature Feature) bool {
+	gatesLock.RLock()
+	defer gatesLock.RUnlock()
+	if enabled, isSet := overrideGates[feature]; isSet {
+		return enabled
+	}
+	if enabled, isSet := flagGates[feature]; isSet {
+		return enabled
+	}
+	return defaultGates[feature]
+}
+
+// Validate checks that all the given names are known features.
+func Validate(gates map[string]bool) error {
+	var unknown []string
+	for name := range gates {
+		if _, isKnown := defaultGates[Feature(name)]; !isKnown {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("unknown feature gates: %s, known are: %s", strings.Join(unknown, ", "), strings.Join(KnownFeatures(), ", "))
+	}
+	return nil
+}
+
+// KnownFeatures returns sorted names of the known features.
+func KnownFeatures() []string {
+	var features []string
+	for feature := range defaultGates {
+		features = append(features, string(feature))
+	}
+	sort.Strings(features)
+	return features
+}
+
+// SetFromFlag sets the gates from the flag value in 'Feature1=true,Feature2=false' form.
+func SetFromFlag(value string) error {
+	gates := map[string]bool{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item == "" {
+			continue
+		}
+		name, enabledValue, found := strings.Cut(item, "=")
+		if !found {
+			return fmt.Errorf("feature gate %q must be in 'Feature=true|false' form", item)
+		}
+		enabled, err := strconv.ParseBool(strings.TrimSpace(enabledValue))
+		if err != nil {
+			return fmt.Errorf("invalid value of %q feature gate: %w", name, err)
+		}
+		gates[strings.TrimSpace(name)] = enabled
+	}
+	if err := Validate(gates); err != nil {
+		return err
+	}
+
+	gatesLock.Lock()
+	defer gatesLock.Unlock()
+	flagGates = toFeatureGates(gates)
+	return nil
+}
+
+// SetOverrides replaces the gates which override the flag. Unknown features are ignored, nil removes all the overrides.
+func SetOverrides(gates map[string]bool) {
+	gatesLock.Lock()
+	defer gatesLock.Unlock()
+	overrideGates = toFeatureGates(gates)
+}
+
+func toFeatureGates(gates map[string]bool) map[Feature]bool {
+	featureGates := map[Feature]bool{}
+	for name, enabled := range gates {
+		if _, isKnown := defaultGates[Feature(name)]; isKnown {
+			featureGates[Feature(name)] = enabled
+		}
+	}
+	return featureGates
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package featuregates
+
+import "testing"
+
+func TestEnabled(t *testing.T) {
+	defer SetOverrides(nil)
+	defer func() { _ = SetFromFlag("") }()
+
+	if !Enabled(Renovater) || !Enabled(GitLab) {
+		t.Errorf("features should be enabled by default")
+	}
+
+	if err := SetFromFlag("Renovater=false, GitLab=false"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if Enabled(Renovater) || Enabled(GitLab) {
+		t.Errorf("features should be disabled by the flag")
+	}
+	if !Enabled(Nudging) {
+		t.Errorf("features not set by the flag should keep the default")
+	}
+
+	SetOverrides(map[string]bool{"Renovater": true, "Unknown": false})
+	if !Enabled(Renovater) {
+		t.Errorf("override should take precedence over the flag")
+	}
+	if Enabled(GitLab) {
+		t.Errorf("features not overridden should keep the flag value")
+	}
+
+	SetOverrides(nil)
+	if Enabled(Renovater) {
+		t.Errorf("flag value should be used after the overrides removed")
+	}
+}
+
+func TestSetFromFlag(t *testing.T) {
+	defer func() { _ = SetFromFlag("") }()
+
+	tests := []struct {
+		name        string
+		value       string
+		expectError bool
+	}{
+		{name: "should accept empty value", value: ""},
+		{name: "should accept known features", value: "Nudging=false,MultiArch=true"},
+		{name: "should reject unknown feature", value: "Multiarch=false", expectError: true},
+		{name: "should reject missing value", value: "Nudging", expectError: true},
+		{name: "should reject invalid value", value: "Nudging=off", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := SetFromFlag(tt.value); (err != nil) != tt.expectError {
+				t.Errorf("SetFromFlag(%q) error = %v, expectError %t", tt.value, err, tt.expectError)
+			}
+		})
+	}
+}
@@ -26,6 +26,33 @@ func (g *GitlabAPIEndpoint) APIEndpoint(host string) string {
 	return fmt.Sprintf("https://%s/api/v4/", host)
 }
 
+// BitbucketAPIEndpoint represents an API endpoint for Bitbucket Cloud.
+type BitbucketAPIEndpoint struct {
+}
+
+// APIEndpoint returns the Bitbucket Cloud API endpoint.
+func (g *BitbucketAPIEndpoint) APIEndpoint(host string) string {
+	return fmt.Sprintf("https://api.%s/2.0/", host)
+}
+
+// GiteaAPIEndpoint represents an API endpoint for Gitea and Forgejo.
+type GiteaAPIEndpoint struct {
+}
+
+// APIEndpoint returns the Gitea API endpoint.
+func (g *GiteaAPIEndpoint) APIEndpoint(host string) string {
+	return fmt.Sprintf("https://%s/api/v1/", host)
+}
+
+// AzureAPIEndpoint represents an API endpoint for Azure DevOps.
+type AzureAPIEndpoint struct {
+}
+
+// APIEndpoint returns the Azure DevOps organization endpoint, the host is expected to contain the organization, e.g. dev.azure.com/umbrellacorp.
+func (g *AzureAPIEndpoint) APIEndpoint(host string) string {
+	return fmt.Sprintf("https://%s/", host)
+}
+
 // UnknownAPIEndpoint represents an endpoint for unknown or non existed provider. It returns empty string for api endpoint.
 type UnknownAPIEndpoint struct {
 }
@@ -42,6 +69,12 @@ func BuildAPIEndpoint(endpointType string) APIEndpoint {
 		return &GithubAPIEndpoint{}
 	case "gitlab":
 		return &GitlabAPIEndpoint{}
+	case "bitbucket":
+		return &BitbucketAPIEndpoint{}
+	case "gitea":
+		return &GiteaAPIEndpoint{}
+	case "azure":
+		return &AzureAPIEndpoint{}
 	default:
 		return &UnknownAPIEndpoint{}
 	}
@@ -36,6 +36,24 @@ func TestBuildEndpoint(t *testing.T) {
 			host:         "gitlab.umbrella.com",
 			wantEndpoint: "https://gitlab.umbrella.com/api/v4/",
 		},
+		{
+			name:         "Bitbucket Cloud",
+			endpointType: "bitbucket",
+			host:         "bitbucket.org",
+			wantEndpoint: "https://api.bitbucket.org/2.0/",
+		},
+		{
+			name:         "Gitea On-Prem",
+			endpointType: "gitea",
+			host:         "gitea.umbrella.com",
+			wantEndpoint: "https://gitea.umbrella.com/api/v1/",
+		},
+		{
+			name:         "Azure DevOps",
+			endpointType: "azure",
+			host:         "dev.azure.com/umbrellacorp",
+			wantEndpoint: "https://dev.azure.com/umbrellacorp/",
+		},
 		{
 			name:         "Unknown provider",
 			endpointType: "bibi",
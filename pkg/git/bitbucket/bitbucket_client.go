@@ -0,0 +1,386 @@
+/*
+Copyright 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bitbucket
+
+import (
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	gp "github.com/konflux-ci/build-service/pkg/git/gitprovider"
+)
+
+const (
+	// Bitbucket Cloud REST API base URL
+	bitbucketApiUrl = "https://api.bitbucket.org/2.0/"
+)
+
+// Allow mocking for tests
+var NewBitbucketClient func(accessToken string) *BitbucketClient = newBitbucketClient
+var NewBitbucketClientWithBasicAuth func(username, appPassword string) *BitbucketClient = newBitbucketClientWithBasicAuth
+
+var _ gp.GitProviderClient = (*BitbucketClient)(nil)
+
+type BitbucketClient struct {
+	httpClient *http.Client
+	baseUrl    string
+	// Bearer token of a repository, project or workspace access token
+	accessToken string
+	// Username and app password for basic authentication
+	username    string
+	appPassword string
+}
+
+// EnsurePaCMergeRequest creates or updates existing (if needed) Pipelines as Code configuration proposal pull request.
+// Returns the pull request web URL.
+// If there is no error and web URL is empty, it means that the pull request is not needed (main branch is up to date).
+func (b *BitbucketClient) EnsurePaCMergeRequest(repoUrl string, d *gp.MergeRequestData) (webUrl string, err error) {
+	repoPath, err := getRepositoryPathFromRepoUrl(repoUrl)
+	if err != nil {
+		return "", err
+	}
+
+	// Fallback to the default branch if base branch is not set
+	if d.BaseBranchName == "" {
+		baseBranch, err := b.getDefaultBranch(repoPath)
+		if err != nil {
+			return "", err
+		}
+		d.BaseBranchName = baseBranch
+	}
+
+	pacConfigurationUpToDate, err := b.filesUpToDate(repoPath, d.BaseBranchName, d.Files)
+	if err != nil {
+		return "", err
+	}
+	if pacConfigurationUpToDate {
+		// Nothing to do, the configuration is alredy in the main branch of the repository
+		return "", nil
+	}
+
+	prBranch, err := b.getBranch(repoPath, d.BranchName)
+	if err != nil {
+		return "", err
+	}
+
+	if prBranch != nil {
+		prBranchUpToDate, err := b.filesUpToDate(repoPath, d.BranchName, d.Files)
+		if err != nil {
+			return "", err
+		}
+		if !prBranchUpToDate {
+			err := b.commitFilesIntoBranch(repoPath, d.BranchName, d.CommitMessage, d.AuthorName, d.AuthorEmail, d.Files)
+			if err != nil {
+				return "", err
+			}
+		}
+
+		pr, err := b.findPullRequestByBranches(repoPath, d.BranchName, d.BaseBranchName)
+		if err != nil {
+			return "", err
+		}
+		if pr != nil {
+			// Pull request already exists
+			return pr.Links.Html.Href, nil
+		}
+
+		diffExists, err := b.diffNotEmpty(repoPath, d.BranchName, d.BaseBranchName)
+		if err != nil {
+			return "", err
+		}
+		if !diffExists {
+			// This situation occurs if a PR was merged but the branch was not deleted and main is changed after the merge.
+			// Despite the fact that there is actual diff between branches, git treats it as no diff,
+			// because the branch is already "included" in main.
+			if _, err := b.deleteBranch(repoPath, d.BranchName); err != nil {
+				return "", err
+			}
+			return b.EnsurePaCMergeRequest(repoUrl, d)
+		}
+
+		return b.createPullRequestWithinRepository(repoPath, d.BranchName, d.BaseBranchName, d.Title, d.Text)
+	} else {
+		// Need to create branch and PR with Pipelines as Code configuration
+		err = b.createBranch(repoPath, d.BranchName, d.BaseBranchName)
+		if err != nil {
+			return "", err
+		}
+
+		err = b.commitFilesIntoBranch(repoPath, d.BranchName, d.CommitMessage, d.AuthorName, d.AuthorEmail, d.Files)
+		if err != nil {
+			return "", err
+		}
+
+		return b.createPullRequestWithinRepository(repoPath, d.BranchName, d.BaseBranchName, d.Title, d.Text)
+	}
+}
+
+// PushPaCConfiguration commits Pipelines as Code configuration directly into the base branch, without a pull request.
+// Returns web URL of the repository state at the pushed commit.
+// If there is no error and web URL is empty, it means that the push is not needed (base branch is up to date).
+func (b *BitbucketClient) PushPaCConfiguration(repoUrl string, d *gp.MergeRequestData) (webUrl string, err error) {
+	repoPath, err := getRepositoryPathFromRepoUrl(repoUrl)
+	if err != nil {
+		return "", err
+	}
+
+	// Fallback to the default branch if base branch is not set
+	if d.BaseBranchName == "" {
+		baseBranch, err := b.getDefaultBranch(repoPath)
+		if err != nil {
+			return "", err
+		}
+		d.BaseBranchName = baseBranch
+	}
+
+	pacConfigurationUpToDate, err := b.filesUpToDate(repoPath, d.BaseBranchName, d.Files)
+	if err != nil {
+		return "", err
+	}
+	if pacConfigurationUpToDate {
+		// Nothing to do, the configuration is alredy in the base branch of the repository
+		return "", nil
+	}
+
+	if err := b.commitFilesIntoBranch(repoPath, d.BaseBranchName, d.CommitMessage, d.AuthorName, d.AuthorEmail, d.Files); err != nil {
+		return "", err
+	}
+
+	sha, err := b.GetBranchSha(repoUrl, d.BaseBranchName)
+	if err != nil {
+		return "", err
+	}
+	return b.GetBrowseRepositoryAtShaLink(repoUrl, sha), nil
+}
+
+// UndoPaCMergeRequest creates or updates existing Pipelines as Code configuration removal pull request.
+// Returns the pull request web URL.
+// If there is no error and web URL is empty, it means that the pull request is not needed (the configuraton has already been deleted).
+func (b *BitbucketClient) UndoPaCMergeRequest(repoUrl string, d *gp.MergeRequestData) (webUrl string, err error) {
+	repoPath, err := getRepositoryPathFromRepoUrl(repoUrl)
+	if err != nil {
+		return "", err
+	}
+
+	// Fallback to the default branch if base branch is not set
+	if d.BaseBranchName == "" {
+		baseBranchName, err := b.getDefaultBranch(repoPath)
+		if err != nil {
+			return "", err
+		}
+		d.BaseBranchName = baseBranchName
+	}
+
+	files, err := b.filesExistInDirectory(repoPath, d.BaseBranchName, ".tekton", d.Files)
+	if err != nil {
+		return "", err
+	}
+	if len(files) == 0 {
+		// Nothing to prune
+		return "", nil
+	}
+
+	// Need to create PR that deletes PaC configuration of the component
+
+	// Delete old branch, if any
+	if _, err := b.deleteBranch(repoPath, d.BranchName); err != nil {
+		return "", err
+	}
+
+	// Create branch, commit and pull request
+	if err := b.createBranch(repoPath, d.BranchName, d.BaseBranchName); err != nil {
+		return "", err
+	}
+
+	err = b.addDeleteCommitToBranch(repoPath, d.BranchName, d.AuthorName, d.AuthorEmail, d.CommitMessage, files)
+	if err != nil {
+		return "", err
+	}
+
+	return b.createPullRequestWithinRepository(repoPath, d.BranchName, d.BaseBranchName, d.Title, d.Text)
+}
+
+// FindUnmergedPaCMergeRequest searches for existing Pipelines as Code configuration proposal pull request
+func (b *BitbucketClient) FindUnmergedPaCMergeRequest(repoUrl string, d *gp.MergeRequestData) (*gp.MergeRequest, error) {
+	repoPath, err := getRepositoryPathFromRepoUrl(repoUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	pr, err := b.findPullRequestByBranches(repoPath, d.BranchName, d.BaseBranchName)
+	if err != nil {
+		return nil, err
+	}
+	if pr == nil {
+		return nil, nil
+	}
+	var createdAt *time.Time
+	if createdOn, err := time.Parse(time.RFC3339Nano, pr.CreatedOn); err == nil {
+		createdAt = &createdOn
+	}
+	return &gp.MergeRequest{
+		Id:        pr.Id,
+		CreatedAt: createdAt,
+		WebUrl:    pr.Links.Html.Href,
+		Title:     pr.Title,
+	}, nil
+}
+
+// SetupPaCWebhook creates Pipelines as Code webhook in the given repository
+func (b *BitbucketClient) SetupPaCWebhook(repoUrl, webhookUrl, webhookSecret string) error {
+	repoPath, err := getRepositoryPathFromRepoUrl(repoUrl)
+	if err != nil {
+		return err
+	}
+
+	existingWebhook, err := b.getWebhookByTargetUrl(repoPath, webhookUrl)
+	if err != nil {
+		return err
+	}
+
+	if existingWebhook == nil {
+		return b.createPaCWebhook(repoPath, webhookUrl, webhookSecret)
+	}
+
+	return b.updatePaCWebhook(repoPath, existingWebhook.Uuid, webhookUrl, webhookSecret)
+}
+
+// DeletePaCWebhook deletes Pipelines as Code webhook in the given repository
+func (b *BitbucketClient) DeletePaCWebhook(repoUrl, webhookUrl string) error {
+	repoPath, err := getRepositoryPathFromRepoUrl(repoUrl)
+	if err != nil {
+		return err
+	}
+
+	existingWebhook, err := b.getWebhookByTargetUrl(repoPath, webhookUrl)
+	if err != nil {
+		return err
+	}
+
+	if existingWebhook == nil {
+		// Webhook doesn't exist, nothing to do
+		return nil
+	}
+
+	return b.deleteWebhook(repoPath, existingWebhook.Uuid)
+}
+
+// GetDefaultBranch returns name of default branch in the given repository
+func (b *BitbucketClient) GetDefaultBranch(repoUrl string) (string, error) {
+	repoPath, err := getRepositoryPathFromRepoUrl(repoUrl)
+	if err != nil {
+		return "", err
+	}
+
+	return b.getDefaultBranch(repoPath)
+}
+
+// DeleteBranch deletes given branch from repository
+func (b *BitbucketClient) DeleteBranch(repoUrl, branchName string) (bool, error) {
+	repoPath, err := getRepositoryPathFromRepoUrl(repoUrl)
+	if err != nil {
+		return false, err
+	}
+	return b.deleteBranch(repoPath, branchName)
+}
+
+// GetBranchSha returns SHA of top commit in the given branch
+func (b *BitbucketClient) GetBranchSha(repoUrl, branchName string) (string, error) {
+	repoPath, err := getRepositoryPathFromRepoUrl(repoUrl)
+	if err != nil {
+		return "", err
+	}
+
+	branch, err := b.getBranch(repoPath, branchName)
+	if err != nil {
+		return "", err
+	}
+	if branch == nil || branch.Target.Hash == "" {
+		return "", fmt.Errorf("unexpected response while getting branch top commit SHA")
+	}
+	return branch.Target.Hash, nil
+}
+
+// IsFileExist check whether given file exists in the given branch of the reposiotry.
+// If branch is empty string, default branch is used.
+func (b *BitbucketClient) IsFileExist(repoUrl, branchName, filePath string) (bool, error) {
+	repoPath, err := getRepositoryPathFromRepoUrl(repoUrl)
+	if err != nil {
+		return false, err
+	}
+
+	if branchName == "" {
+		var err error
+		branchName, err = b.getDefaultBranch(repoPath)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	directory := filepath.Dir(filePath)
+	files, err := b.filesExistInDirectory(repoPath, branchName, directory, []gp.RepositoryFile{{FullPath: filePath}})
+	if err != nil {
+		return false, err
+	}
+	return len(files) > 0, nil
+}
+
+// IsRepositoryPublic returns true if the repository could be accessed without authentication
+func (b *BitbucketClient) IsRepositoryPublic(repoUrl string) (bool, error) {
+	repoPath, err := getRepositoryPathFromRepoUrl(repoUrl)
+	if err != nil {
+		return false, err
+	}
+
+	repositoryInfo, err := b.getRepositoryInfo(repoPath)
+	if err != nil {
+		return false, err
+	}
+	if repositoryInfo == nil {
+		return false, nil
+	}
+	return !repositoryInfo.IsPrivate, nil
+}
+
+// GetBrowseRepositoryAtShaLink returns web URL of repository state at given SHA
+func (b *BitbucketClient) GetBrowseRepositoryAtShaLink(repoUrl, sha string) string {
+	repoUrl = strings.TrimSuffix(strings.TrimSuffix(repoUrl, ".git"), "/")
+	return fmt.Sprintf("%s/src/%s", repoUrl, sha)
+}
+
+func (b *BitbucketClient) GetConfiguredGitAppName() (string, string, error) {
+	return "", "", fmt.Errorf("Bitbucket does not support applications")
+}
+
+func newBitbucketClient(accessToken string) *BitbucketClient {
+	return &BitbucketClient{
+		httpClient:  &http.Client{Timeout: 30 * time.Second},
+		baseUrl:     bitbucketApiUrl,
+		accessToken: accessToken,
+	}
+}
+
+func newBitbucketClientWithBasicAuth(username, appPassword string) *BitbucketClient {
+	return &BitbucketClient{
+		httpClient:  &http.Client{Timeout: 30 * time.Second},
+		baseUrl:     bitbucketApiUrl,
+		username:    username,
+		appPassword: appPassword,
+	}
+}
@@ -0,0 +1,526 @@
+/*
+Copyright 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bitbucket
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/konflux-ci/build-service/pkg/boerrors"
+	gp "github.com/konflux-ci/build-service/pkg/git/gitprovider"
+)
+
+// Events which Pipelines as Code listens to
+var pacWebhookEvents = []string{"repo:push", "pullrequest:created", "pullrequest:updated", "pullrequest:comment_created"}
+
+type repository struct {
+	IsPrivate  bool `json:"is_private"`
+	MainBranch struct {
+		Name string `json:"name"`
+	} `json:"mainbranch"`
+}
+
+type branch struct {
+	Name   string `json:"name"`
+	Target struct {
+		Hash string `json:"hash"`
+	} `json:"target"`
+}
+
+type pullRequest struct {
+	Id        int64  `json:"id"`
+	Title     string `json:"title"`
+	CreatedOn string `json:"created_on"`
+	Links     struct {
+		Html struct {
+			Href string `json:"href"`
+		} `json:"html"`
+	} `json:"links"`
+}
+
+type webhook struct {
+	Uuid                 string   `json:"uuid,omitempty"`
+	Url                  string   `json:"url"`
+	Description          string   `json:"description"`
+	Active               bool     `json:"active"`
+	SkipCertVerification bool     `json:"skip_cert_verification"`
+	Secret               string   `json:"secret,omitempty"`
+	Events               []string `json:"events"`
+}
+
+type treeEntry struct {
+	Path string `json:"path"`
+	Type string `json:"type"`
+}
+
+type page struct {
+	Values []json.RawMessage `json:"values"`
+	Next   string            `json:"next"`
+}
+
+type BitbucketApiError struct {
+	Method     string
+	Url        string
+	StatusCode int
+	Message    string
+}
+
+func (e BitbucketApiError) Error() string {
+	return fmt.Sprintf("Bitbucket API request %s %s failed with status %d: %s", e.Method, e.Url, e.StatusCode, e.Message)
+}
+
+type InvalidRepositoryUrlError struct {
+	url string
+}
+
+func (e InvalidRepositoryUrlError) Error() string {
+	return fmt.Sprintf("Failed to detect Bitbucket workspace and repository in url %s", e.url)
+}
+
+// getRepositoryPathFromRepoUrl returns workspace/repository path of the given Bitbucket repository URL.
+func getRepositoryPathFromRepoUrl(repoUrl string) (string, error) {
+	u, err := url.Parse(repoUrl)
+	if err != nil {
+		return "", err
+	}
+	repoPath := strings.Trim(strings.TrimSuffix(strings.TrimSuffix(u.Path, "/"), ".git"), "/")
+	if len(strings.Split(repoPath, "/")) != 2 {
+		return "", InvalidRepositoryUrlError{repoUrl}
+	}
+	return repoPath, nil
+}
+
+// refineGitHostingServiceError generates expected permanent error from Bitbucket response.
+// If no one is detected, the original error will be returned.
+// refineGitHostingServiceError should be called just after every Bitbucket API call.
+func refineGitHostingServiceError(response *http.Response, originErr error) error {
+	if response == nil {
+		return originErr
+	}
+	switch response.StatusCode {
+	case 401:
+		return boerrors.NewBuildOpError(boerrors.EBitbucketTokenUnauthorized, originErr)
+	case 403:
+		return boerrors.NewBuildOpError(boerrors.EBitbucketTokenInsufficientScope, originErr)
+	default:
+		return originErr
+	}
+}
+
+// doRequest sends the request to Bitbucket REST API and returns the response with its body.
+// Given path is relative to the API base URL, unless it is an absolute URL, e.g. next page link.
+// An error is returned for any response with unsuccessful status code.
+func (b *BitbucketClient) doRequest(method, path string, query url.Values, body io.Reader, contentType string) (*http.Response, []byte, error) {
+	requestUrl := path
+	if !strings.HasPrefix(path, "https://") && !strings.HasPrefix(path, "http://") {
+		requestUrl = b.baseUrl + path
+	}
+	if len(query) > 0 {
+		requestUrl += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequest(method, requestUrl, body)
+	if err != nil {
+		return nil, nil, err
+	}
+	if b.accessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+b.accessToken)
+	} else {
+		req.SetBasicAuth(b.username, b.appPassword)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := b.httpClient.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp, nil, err
+	}
+
+	if resp.StatusCode >= 300 {
+		apiErr := BitbucketApiError{Method: method, Url: requestUrl, StatusCode: resp.StatusCode, Message: getErrorMessage(respBody)}
+		return resp, respBody, refineGitHostingServiceError(resp, apiErr)
+	}
+	return resp, respBody, nil
+}
+
+// doJsonRequest sends the given object as JSON and decodes the JSON response into the result, if any.
+func (b *BitbucketClient) doJsonRequest(method, path string, payload interface{}, result interface{}) (*http.Response, error) {
+	var body io.Reader
+	contentType := ""
+	if payload != nil {
+		payloadBytes, err := json.Marshal(payload)
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.NewReader(payloadBytes)
+		contentType = "application/json"
+	}
+	resp, respBody, err := b.doRequest(method, path, nil, body, contentType)
+	if err != nil {
+		return resp, err
+	}
+	if result != nil && len(respBody) > 0 {
+		if err := json.Unmarshal(respBody, result); err != nil {
+			return resp, fmt.Errorf("failed to parse Bitbucket API response: %w", err)
+		}
+	}
+	return resp, nil
+}
+
+// listAllValues returns values of all pages of the paginated Bitbucket API collection.
+func (b *BitbucketClient) listAllValues(path string, query url.Values) ([]json.RawMessage, *http.Response, error) {
+	var values []json.RawMessage
+	for path != "" {
+		resp, respBody, err := b.doRequest("GET", path, query, nil, "")
+		if err != nil {
+			return nil, resp, err
+		}
+		p := &page{}
+		if err := json.Unmarshal(respBody, p); err != nil {
+			return nil, resp, fmt.Errorf("failed to parse Bitbucket API response: %w", err)
+		}
+		values = append(values, p.Values...)
+		// The next page link already contains all the query parameters
+		path = p.Next
+		query = nil
+	}
+	return values, nil, nil
+}
+
+func getErrorMessage(respBody []byte) string {
+	errorResponse := struct {
+		Error struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}{}
+	if err := json.Unmarshal(respBody, &errorResponse); err == nil && errorResponse.Error.Message != "" {
+		return errorResponse.Error.Message
+	}
+	return string(respBody)
+}
+
+func isNotFound(resp *http.Response) bool {
+	return resp != nil && resp.StatusCode == 404
+}
+
+func (b *BitbucketClient) getRepositoryInfo(repoPath string) (*repository, error) {
+	repositoryInfo := &repository{}
+	resp, err := b.doJsonRequest("GET", "repositories/"+repoPath, nil, repositoryInfo)
+	if err != nil {
+		if isNotFound(resp) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return repositoryInfo, nil
+}
+
+func (b *BitbucketClient) getDefaultBranch(repoPath string) (string, error) {
+	repositoryInfo, err := b.getRepositoryInfo(repoPath)
+	if err != nil {
+		return "", err
+	}
+	if repositoryInfo == nil || repositoryInfo.MainBranch.Name == "" {
+		return "", fmt.Errorf("repository info is empty in Bitbucket API response")
+	}
+	return repositoryInfo.MainBranch.Name, nil
+}
+
+func (b *BitbucketClient) getBranch(repoPath, branchName string) (*branch, error) {
+	branchInfo := &branch{}
+	resp, err := b.doJsonRequest("GET", "repositories/"+repoPath+"/refs/branches/"+url.PathEscape(branchName), nil, branchInfo)
+	if err != nil {
+		if isNotFound(resp) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return branchInfo, nil
+}
+
+// getBranchHead returns SHA of the top commit of the given branch.
+// Commit SHA is used to reference the branch in other API calls, because branch names may contain slashes.
+func (b *BitbucketClient) getBranchHead(repoPath, branchName string) (string, error) {
+	branchInfo, err := b.getBranch(repoPath, branchName)
+	if err != nil {
+		return "", err
+	}
+	if branchInfo == nil {
+		return "", nil
+	}
+	return branchInfo.Target.Hash, nil
+}
+
+func (b *BitbucketClient) createBranch(repoPath, branchName, baseBranchName string) error {
+	baseBranchSha, err := b.getBranchHead(repoPath, baseBranchName)
+	if err != nil {
+		return err
+	}
+	if baseBranchSha == "" {
+		return fmt.Errorf("base branch %s not found", baseBranchName)
+	}
+
+	newBranch := &branch{Name: branchName}
+	newBranch.Target.Hash = baseBranchSha
+	_, err = b.doJsonRequest("POST", "repositories/"+repoPath+"/refs/branches", newBranch, nil)
+	return err
+}
+
+func (b *BitbucketClient) deleteBranch(repoPath, branchName string) (bool, error) {
+	resp, _, err := b.doRequest("DELETE", "repositories/"+repoPath+"/refs/branches/"+url.PathEscape(branchName), nil, nil, "")
+	if err != nil {
+		if isNotFound(resp) {
+			// The given branch doesn't exist
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func (b *BitbucketClient) filesUpToDate(repoPath, branchName string, files []gp.RepositoryFile) (bool, error) {
+	sha, err := b.getBranchHead(repoPath, branchName)
+	if err != nil {
+		return false, err
+	}
+	if sha == "" {
+		return false, nil
+	}
+
+	for _, file := range files {
+		resp, fileContent, err := b.doRequest("GET", "repositories/"+repoPath+"/src/"+sha+"/"+file.FullPath, nil, nil, "")
+		if err != nil {
+			if isNotFound(resp) {
+				return false, nil
+			}
+			return false, err
+		}
+		if !bytes.Equal(fileContent, file.Content) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// filesExistInDirectory checks if given files exist under specified directory.
+// Returns subset of given files which exist.
+func (b *BitbucketClient) filesExistInDirectory(repoPath, branchName, directoryPath string, files []gp.RepositoryFile) ([]gp.RepositoryFile, error) {
+	existingFiles := make([]gp.RepositoryFile, 0, len(files))
+
+	sha, err := b.getBranchHead(repoPath, branchName)
+	if err != nil {
+		return nil, err
+	}
+	if sha == "" {
+		return existingFiles, nil
+	}
+
+	query := url.Values{"pagelen": []string{"100"}}
+	values, resp, err := b.listAllValues("repositories/"+repoPath+"/src/"+sha+"/"+strings.Trim(directoryPath, "/")+"/", query)
+	if err != nil {
+		if isNotFound(resp) {
+			return existingFiles, nil
+		}
+		return existingFiles, err
+	}
+
+	for _, value := range values {
+		entry := &treeEntry{}
+		if err := json.Unmarshal(value, entry); err != nil {
+			return nil, fmt.Errorf("failed to parse Bitbucket API response: %w", err)
+		}
+		for _, f := range files {
+			if entry.Path == f.FullPath {
+				existingFiles = append(existingFiles, gp.RepositoryFile{FullPath: entry.Path})
+				break
+			}
+		}
+	}
+
+	return existingFiles, nil
+}
+
+// commitFilesIntoBranch creates commit into specified branch that creates or updates given files.
+func (b *BitbucketClient) commitFilesIntoBranch(repoPath, branchName, commitMessage, authorName, authorEmail string, files []gp.RepositoryFile) error {
+	fields := [][2]string{}
+	for _, file := range files {
+		fields = append(fields, [2]string{file.FullPath, string(file.Content)})
+	}
+	return b.createCommit(repoPath, branchName, commitMessage, authorName, authorEmail, fields)
+}
+
+// addDeleteCommitToBranch creates commit into specified branch that deletes given files.
+func (b *BitbucketClient) addDeleteCommitToBranch(repoPath, branchName, authorName, authorEmail, commitMessage string, files []gp.RepositoryFile) error {
+	fields := [][2]string{}
+	for _, file := range files {
+		fields = append(fields, [2]string{"files", file.FullPath})
+	}
+	return b.createCommit(repoPath, branchName, commitMessage, authorName, authorEmail, fields)
+}
+
+// createCommit creates commit using the Bitbucket src endpoint.
+// Each field is either a file path with the new file content or "files" with a path of the file to delete.
+func (b *BitbucketClient) createCommit(repoPath, branchName, commitMessage, authorName, authorEmail string, fields [][2]string) error {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	commitFields := [][2]string{
+		{"message", commitMessage},
+		{"author", fmt.Sprintf("%s <%s>", authorName, authorEmail)},
+		{"branch", branchName},
+	}
+	for _, field := range append(commitFields, fields...) {
+		if err := writer.WriteField(field[0], field[1]); err != nil {
+			return err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	_, _, err := b.doRequest("POST", "repositories/"+repoPath+"/src", nil, body, writer.FormDataContentType())
+	return err
+}
+
+func (b *BitbucketClient) diffNotEmpty(repoPath, branchName, baseBranchName string) (bool, error) {
+	sha, err := b.getBranchHead(repoPath, branchName)
+	if err != nil {
+		return false, err
+	}
+	baseSha, err := b.getBranchHead(repoPath, baseBranchName)
+	if err != nil {
+		return false, err
+	}
+	if sha == "" || baseSha == "" {
+		return false, fmt.Errorf("failed to compare %s and %s branches: branch not found", branchName, baseBranchName)
+	}
+
+	// The diff is computed against the merge base of the branches
+	values, _, err := b.listAllValues("repositories/"+repoPath+"/diffstat/"+sha+".."+baseSha, nil)
+	if err != nil {
+		return false, err
+	}
+	return len(values) > 0, nil
+}
+
+func (b *BitbucketClient) findPullRequestByBranches(repoPath, branchName, targetBranchName string) (*pullRequest, error) {
+	query := url.Values{
+		"q":       []string{fmt.Sprintf(`source.branch.name="%s" AND destination.branch.name="%s" AND state="OPEN"`, branchName, targetBranchName)},
+		"pagelen": []string{"50"},
+	}
+	values, _, err := b.listAllValues("repositories/"+repoPath+"/pullrequests", query)
+	if err != nil {
+		return nil, err
+	}
+	switch len(values) {
+	case 0:
+		return nil, nil
+	case 1:
+		pr := &pullRequest{}
+		if err := json.Unmarshal(values[0], pr); err != nil {
+			return nil, fmt.Errorf("failed to parse Bitbucket API response: %w", err)
+		}
+		return pr, nil
+	default:
+		return nil, fmt.Errorf("failed to find pull request by branch: %d matches found", len(values))
+	}
+}
+
+func (b *BitbucketClient) createPullRequestWithinRepository(repoPath, branchName, baseBranchName, prTitle, prText string) (string, error) {
+	type branchRef struct {
+		Branch struct {
+			Name string `json:"name"`
+		} `json:"branch"`
+	}
+	payload := struct {
+		Title       string    `json:"title"`
+		Description string    `json:"description"`
+		Source      branchRef `json:"source"`
+		Destination branchRef `json:"destination"`
+	}{
+		Title:       prTitle,
+		Description: prText,
+	}
+	payload.Source.Branch.Name = branchName
+	payload.Destination.Branch.Name = baseBranchName
+
+	pr := &pullRequest{}
+	if _, err := b.doJsonRequest("POST", "repositories/"+repoPath+"/pullrequests", payload, pr); err != nil {
+		return "", err
+	}
+	return pr.Links.Html.Href, nil
+}
+
+func (b *BitbucketClient) getWebhookByTargetUrl(repoPath, webhookTargetUrl string) (*webhook, error) {
+	query := url.Values{"pagelen": []string{"100"}}
+	values, _, err := b.listAllValues("repositories/"+repoPath+"/hooks", query)
+	if err != nil {
+		return nil, err
+	}
+	for _, value := range values {
+		hook := &webhook{}
+		if err := json.Unmarshal(value, hook); err != nil {
+			return nil, fmt.Errorf("failed to parse Bitbucket API response: %w", err)
+		}
+		if hook.Url == webhookTargetUrl {
+			return hook, nil
+		}
+	}
+	// Webhook with the given URL not found
+	return nil, nil
+}
+
+func (b *BitbucketClient) createPaCWebhook(repoPath, webhookTargetUrl, webhookSecret string) error {
+	_, err := b.doJsonRequest("POST", "repositories/"+repoPath+"/hooks", getPaCWebhook(webhookTargetUrl, webhookSecret), nil)
+	return err
+}
+
+func (b *BitbucketClient) updatePaCWebhook(repoPath, webhookUuid, webhookTargetUrl, webhookSecret string) error {
+	_, err := b.doJsonRequest("PUT", "repositories/"+repoPath+"/hooks/"+url.PathEscape(webhookUuid), getPaCWebhook(webhookTargetUrl, webhookSecret), nil)
+	return err
+}
+
+func (b *BitbucketClient) deleteWebhook(repoPath, webhookUuid string) error {
+	resp, _, err := b.doRequest("DELETE", "repositories/"+repoPath+"/hooks/"+url.PathEscape(webhookUuid), nil, nil, "")
+	if err != nil && !isNotFound(resp) {
+		return err
+	}
+	return nil
+}
+
+func getPaCWebhook(webhookTargetUrl, webhookSecret string) *webhook {
+	return &webhook{
+		Url:                  webhookTargetUrl,
+		Description:          "Pipelines as Code",
+		Active:               true,
+		SkipCertVerification: gp.IsInsecureSSL(),
+		Secret:               webhookSecret,
+		Events:               pacWebhookEvents,
+	}
+}
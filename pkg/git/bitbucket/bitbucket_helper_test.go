@@ -0,0 +1,197 @@
+package bitbucket
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/konflux-ci/build-service/pkg/boerrors"
+	gp "github.com/konflux-ci/build-service/pkg/git/gitprovider"
+)
+
+func TestGetRepositoryPathFromRepoUrl(t *testing.T) {
+	var params = []struct {
+		in        string
+		out       string
+		expectErr bool
+	}{
+		{"https://bitbucket.org/workspace/repository", "workspace/repository", false},
+		{"https://bitbucket.org/workspace/repository.git", "workspace/repository", false},
+		{"https://bitbucket.org/workspace/repository/", "workspace/repository", false},
+		{"https://bitbucket.org/workspace", "", true},
+		{"https://bitbucket.org/workspace/repository/src/main", "", true},
+		{"http!!://abc", "", true},
+	}
+
+	for _, tt := range params {
+		actual, err := getRepositoryPathFromRepoUrl(tt.in)
+		if err != nil && !tt.expectErr {
+			t.Fatalf("Expected call to succeed, found error %s", err.Error())
+		}
+		if err == nil && tt.expectErr {
+			t.Fatalf("Expected call to end with an error, but it succeed")
+		}
+		if actual != tt.out {
+			t.Fatalf("Expected %s found %s", tt.out, actual)
+		}
+	}
+}
+
+func newTestBitbucketClient(serverUrl string) *BitbucketClient {
+	client := NewBitbucketClient("token")
+	client.baseUrl = serverUrl + "/2.0/"
+	return client
+}
+
+func TestEnsurePaCMergeRequestCreatesBranchAndPullRequest(t *testing.T) {
+	var commitFields map[string][]string
+	var pullRequestPayload map[string]interface{}
+	createdBranches := []string{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(401)
+			return
+		}
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/2.0/repositories/workspace/repo/refs/branches/main":
+			fmt.Fprint(w, `{"name": "main", "target": {"hash": "base-sha"}}`)
+		case r.Method == "GET" && r.URL.Path == "/2.0/repositories/workspace/repo/refs/branches/konflux-component":
+			w.WriteHeader(404)
+		case r.Method == "GET" && strings.HasPrefix(r.URL.Path, "/2.0/repositories/workspace/repo/src/base-sha/"):
+			w.WriteHeader(404)
+		case r.Method == "POST" && r.URL.Path == "/2.0/repositories/workspace/repo/refs/branches":
+			newBranch := &branch{}
+			if err := json.NewDecoder(r.Body).Decode(newBranch); err != nil || newBranch.Target.Hash != "base-sha" {
+				w.WriteHeader(400)
+				return
+			}
+			createdBranches = append(createdBranches, newBranch.Name)
+			w.WriteHeader(201)
+			fmt.Fprint(w, `{}`)
+		case r.Method == "POST" && r.URL.Path == "/2.0/repositories/workspace/repo/src":
+			if err := r.ParseMultipartForm(1 << 20); err != nil {
+				w.WriteHeader(400)
+				return
+			}
+			commitFields = r.MultipartForm.Value
+			w.WriteHeader(201)
+		case r.Method == "POST" && r.URL.Path == "/2.0/repositories/workspace/repo/pullrequests":
+			if err := json.NewDecoder(r.Body).Decode(&pullRequestPayload); err != nil {
+				w.WriteHeader(400)
+				return
+			}
+			w.WriteHeader(201)
+			fmt.Fprint(w, `{"id": 1, "links": {"html": {"href": "https://bitbucket.org/workspace/repo/pull-requests/1"}}}`)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(500)
+		}
+	}))
+	defer server.Close()
+
+	client := newTestBitbucketClient(server.URL)
+	mergeRequestData := &gp.MergeRequestData{
+		CommitMessage:  "Konflux update component",
+		BranchName:     "konflux-component",
+		BaseBranchName: "main",
+		Title:          "Konflux update component",
+		Text:           "Pipelines as Code configuration proposal",
+		AuthorName:     "konflux",
+		AuthorEmail:    "konflux@no-reply.konflux-ci.dev",
+		Files: []gp.RepositoryFile{
+			{FullPath: ".tekton/component-push.yaml", Content: []byte("push pipeline")},
+		},
+	}
+
+	webUrl, err := client.EnsurePaCMergeRequest("https://bitbucket.org/workspace/repo", mergeRequestData)
+	if err != nil {
+		t.Fatalf("Expected call to succeed, found error %s", err.Error())
+	}
+	if webUrl != "https://bitbucket.org/workspace/repo/pull-requests/1" {
+		t.Fatalf("Unexpected pull request URL %s", webUrl)
+	}
+	if len(createdBranches) != 1 || createdBranches[0] != "konflux-component" {
+		t.Fatalf("Expected konflux-component branch to be created, got %v", createdBranches)
+	}
+	if commitFields["branch"][0] != "konflux-component" ||
+		commitFields["author"][0] != "konflux <konflux@no-reply.konflux-ci.dev>" ||
+		commitFields[".tekton/component-push.yaml"][0] != "push pipeline" {
+		t.Fatalf("Unexpected commit %v", commitFields)
+	}
+	source := pullRequestPayload["source"].(map[string]interface{})["branch"].(map[string]interface{})["name"]
+	destination := pullRequestPayload["destination"].(map[string]interface{})["branch"].(map[string]interface{})["name"]
+	if source != "konflux-component" || destination != "main" {
+		t.Fatalf("Unexpected pull request branches %s -> %s", source, destination)
+	}
+}
+
+func TestSetupPaCWebhook(t *testing.T) {
+	tests := []struct {
+		name           string
+		existingHooks  string
+		expectedMethod string
+		expectedPath   string
+	}{
+		{
+			name:           "should create webhook",
+			existingHooks:  `{"values": [{"uuid": "{other}", "url": "https://other.io"}]}`,
+			expectedMethod: "POST",
+			expectedPath:   "/2.0/repositories/workspace/repo/hooks",
+		},
+		{
+			name:           "should update existing webhook",
+			existingHooks:  `{"values": [{"uuid": "{hook-uuid}", "url": "https://pac.io"}]}`,
+			expectedMethod: "PUT",
+			expectedPath:   "/2.0/repositories/workspace/repo/hooks/{hook-uuid}",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var hook *webhook
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == "GET" && r.URL.Path == "/2.0/repositories/workspace/repo/hooks" {
+					fmt.Fprint(w, tt.existingHooks)
+					return
+				}
+				if r.Method == tt.expectedMethod && r.URL.Path == tt.expectedPath {
+					hook = &webhook{}
+					if err := json.NewDecoder(r.Body).Decode(hook); err != nil {
+						w.WriteHeader(400)
+						return
+					}
+					fmt.Fprint(w, `{}`)
+					return
+				}
+				t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+				w.WriteHeader(500)
+			}))
+			defer server.Close()
+
+			client := newTestBitbucketClient(server.URL)
+			if err := client.SetupPaCWebhook("https://bitbucket.org/workspace/repo", "https://pac.io", "secret"); err != nil {
+				t.Fatalf("Expected call to succeed, found error %s", err.Error())
+			}
+			if hook == nil || hook.Url != "https://pac.io" || hook.Secret != "secret" || !hook.Active || len(hook.Events) != len(pacWebhookEvents) {
+				t.Fatalf("Unexpected webhook %v", hook)
+			}
+		})
+	}
+}
+
+func TestRefineGitHostingServiceError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(403)
+		fmt.Fprint(w, `{"type": "error", "error": {"message": "Your credentials lack one or more required privilege scopes."}}`)
+	}))
+	defer server.Close()
+
+	client := newTestBitbucketClient(server.URL)
+	_, err := client.GetDefaultBranch("https://bitbucket.org/workspace/repo")
+	if !boerrors.IsBuildOpError(err, boerrors.EBitbucketTokenInsufficientScope) {
+		t.Fatalf("Expected insufficient scope error, got %v", err)
+	}
+}
@@ -0,0 +1,377 @@
+/*
+Copyright 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitea
+
+import (
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	gp "github.com/konflux-ci/build-service/pkg/git/gitprovider"
+)
+
+// Allow mocking for tests
+var NewGiteaClient func(accessToken, baseUrl string) *GiteaClient = newGiteaClient
+var NewGiteaClientWithBasicAuth func(username, password, baseUrl string) *GiteaClient = newGiteaClientWithBasicAuth
+
+var _ gp.GitProviderClient = (*GiteaClient)(nil)
+
+// GiteaClient works with Gitea and Forgejo instances via Gitea REST API v1.
+type GiteaClient struct {
+	httpClient *http.Client
+	// Gitea API URL, e.g. https://gitea.mydomain.com/api/v1/
+	baseUrl     string
+	accessToken string
+	username    string
+	password    string
+}
+
+// EnsurePaCMergeRequest creates or updates existing (if needed) Pipelines as Code configuration proposal pull request.
+// Returns the pull request web URL.
+// If there is no error and web URL is empty, it means that the pull request is not needed (main branch is up to date).
+func (g *GiteaClient) EnsurePaCMergeRequest(repoUrl string, d *gp.MergeRequestData) (webUrl string, err error) {
+	repoPath, err := getRepositoryPathFromRepoUrl(repoUrl)
+	if err != nil {
+		return "", err
+	}
+
+	// Fallback to the default branch if base branch is not set
+	if d.BaseBranchName == "" {
+		baseBranch, err := g.getDefaultBranch(repoPath)
+		if err != nil {
+			return "", err
+		}
+		d.BaseBranchName = baseBranch
+	}
+
+	pacConfigurationUpToDate, err := g.filesUpToDate(repoPath, d.BaseBranchName, d.Files)
+	if err != nil {
+		return "", err
+	}
+	if pacConfigurationUpToDate {
+		// Nothing to do, the configuration is alredy in the main branch of the repository
+		return "", nil
+	}
+
+	prBranch, err := g.getBranch(repoPath, d.BranchName)
+	if err != nil {
+		return "", err
+	}
+
+	if prBranch != nil {
+		prBranchUpToDate, err := g.filesUpToDate(repoPath, d.BranchName, d.Files)
+		if err != nil {
+			return "", err
+		}
+		if !prBranchUpToDate {
+			err := g.commitFilesIntoBranch(repoPath, d.BranchName, d.CommitMessage, d.AuthorName, d.AuthorEmail, d.Files)
+			if err != nil {
+				return "", err
+			}
+		}
+
+		pr, err := g.findPullRequestByBranches(repoPath, d.BranchName, d.BaseBranchName)
+		if err != nil {
+			return "", err
+		}
+		if pr != nil {
+			// Pull request already exists
+			return pr.HtmlUrl, nil
+		}
+
+		diffExists, err := g.diffNotEmpty(repoPath, d.BranchName, d.BaseBranchName)
+		if err != nil {
+			return "", err
+		}
+		if !diffExists {
+			// This situation occurs if a PR was merged but the branch was not deleted and main is changed after the merge.
+			// Despite the fact that there is actual diff between branches, git treats it as no diff,
+			// because the branch is already "included" in main.
+			if _, err := g.deleteBranch(repoPath, d.BranchName); err != nil {
+				return "", err
+			}
+			return g.EnsurePaCMergeRequest(repoUrl, d)
+		}
+
+		return g.createPullRequestWithinRepository(repoPath, d.BranchName, d.BaseBranchName, d.Title, d.Text)
+	} else {
+		// Need to create branch and PR with Pipelines as Code configuration
+		err = g.createBranch(repoPath, d.BranchName, d.BaseBranchName)
+		if err != nil {
+			return "", err
+		}
+
+		err = g.commitFilesIntoBranch(repoPath, d.BranchName, d.CommitMessage, d.AuthorName, d.AuthorEmail, d.Files)
+		if err != nil {
+			return "", err
+		}
+
+		return g.createPullRequestWithinRepository(repoPath, d.BranchName, d.BaseBranchName, d.Title, d.Text)
+	}
+}
+
+// PushPaCConfiguration commits Pipelines as Code configuration directly into the base branch, without a pull request.
+// Returns web URL of the repository state at the pushed commit.
+// If there is no error and web URL is empty, it means that the push is not needed (base branch is up to date).
+func (g *GiteaClient) PushPaCConfiguration(repoUrl string, d *gp.MergeRequestData) (webUrl string, err error) {
+	repoPath, err := getRepositoryPathFromRepoUrl(repoUrl)
+	if err != nil {
+		return "", err
+	}
+
+	// Fallback to the default branch if base branch is not set
+	if d.BaseBranchName == "" {
+		baseBranch, err := g.getDefaultBranch(repoPath)
+		if err != nil {
+			return "", err
+		}
+		d.BaseBranchName = baseBranch
+	}
+
+	pacConfigurationUpToDate, err := g.filesUpToDate(repoPath, d.BaseBranchName, d.Files)
+	if err != nil {
+		return "", err
+	}
+	if pacConfigurationUpToDate {
+		// Nothing to do, the configuration is alredy in the base branch of the repository
+		return "", nil
+	}
+
+	if err := g.commitFilesIntoBranch(repoPath, d.BaseBranchName, d.CommitMessage, d.AuthorName, d.AuthorEmail, d.Files); err != nil {
+		return "", err
+	}
+
+	sha, err := g.GetBranchSha(repoUrl, d.BaseBranchName)
+	if err != nil {
+		return "", err
+	}
+	return g.GetBrowseRepositoryAtShaLink(repoUrl, sha), nil
+}
+
+// UndoPaCMergeRequest creates or updates existing Pipelines as Code configuration removal pull request.
+// Returns the pull request web URL.
+// If there is no error and web URL is empty, it means that the pull request is not needed (the configuraton has already been deleted).
+func (g *GiteaClient) UndoPaCMergeRequest(repoUrl string, d *gp.MergeRequestData) (webUrl string, err error) {
+	repoPath, err := getRepositoryPathFromRepoUrl(repoUrl)
+	if err != nil {
+		return "", err
+	}
+
+	// Fallback to the default branch if base branch is not set
+	if d.BaseBranchName == "" {
+		baseBranchName, err := g.getDefaultBranch(repoPath)
+		if err != nil {
+			return "", err
+		}
+		d.BaseBranchName = baseBranchName
+	}
+
+	files, err := g.filesExistInDirectory(repoPath, d.BaseBranchName, ".tekton", d.Files)
+	if err != nil {
+		return "", err
+	}
+	if len(files) == 0 {
+		// Nothing to prune
+		return "", nil
+	}
+
+	// Need to create PR that deletes PaC configuration of the component
+
+	// Delete old branch, if any
+	if _, err := g.deleteBranch(repoPath, d.BranchName); err != nil {
+		return "", err
+	}
+
+	// Create branch, commit and pull request
+	if err := g.createBranch(repoPath, d.BranchName, d.BaseBranchName); err != nil {
+		return "", err
+	}
+
+	err = g.addDeleteCommitToBranch(repoPath, d.BranchName, d.AuthorName, d.AuthorEmail, d.CommitMessage, files)
+	if err != nil {
+		return "", err
+	}
+
+	return g.createPullRequestWithinRepository(repoPath, d.BranchName, d.BaseBranchName, d.Title, d.Text)
+}
+
+// FindUnmergedPaCMergeRequest searches for existing Pipelines as Code configuration proposal pull request
+func (g *GiteaClient) FindUnmergedPaCMergeRequest(repoUrl string, d *gp.MergeRequestData) (*gp.MergeRequest, error) {
+	repoPath, err := getRepositoryPathFromRepoUrl(repoUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	pr, err := g.findPullRequestByBranches(repoPath, d.BranchName, d.BaseBranchName)
+	if err != nil {
+		return nil, err
+	}
+	if pr == nil {
+		return nil, nil
+	}
+	return &gp.MergeRequest{
+		Id:        pr.Id,
+		CreatedAt: pr.CreatedAt,
+		WebUrl:    pr.HtmlUrl,
+		Title:     pr.Title,
+	}, nil
+}
+
+// SetupPaCWebhook creates Pipelines as Code webhook in the given repository
+func (g *GiteaClient) SetupPaCWebhook(repoUrl, webhookUrl, webhookSecret string) error {
+	repoPath, err := getRepositoryPathFromRepoUrl(repoUrl)
+	if err != nil {
+		return err
+	}
+
+	existingWebhook, err := g.getWebhookByTargetUrl(repoPath, webhookUrl)
+	if err != nil {
+		return err
+	}
+
+	if existingWebhook == nil {
+		return g.createPaCWebhook(repoPath, webhookUrl, webhookSecret)
+	}
+
+	return g.updatePaCWebhook(repoPath, existingWebhook.Id, webhookUrl, webhookSecret)
+}
+
+// DeletePaCWebhook deletes Pipelines as Code webhook in the given repository
+func (g *GiteaClient) DeletePaCWebhook(repoUrl, webhookUrl string) error {
+	repoPath, err := getRepositoryPathFromRepoUrl(repoUrl)
+	if err != nil {
+		return err
+	}
+
+	existingWebhook, err := g.getWebhookByTargetUrl(repoPath, webhookUrl)
+	if err != nil {
+		return err
+	}
+
+	if existingWebhook == nil {
+		// Webhook doesn't exist, nothing to do
+		return nil
+	}
+
+	return g.deleteWebhook(repoPath, existingWebhook.Id)
+}
+
+// GetDefaultBranch returns name of default branch in the given repository
+func (g *GiteaClient) GetDefaultBranch(repoUrl string) (string, error) {
+	repoPath, err := getRepositoryPathFromRepoUrl(repoUrl)
+	if err != nil {
+		return "", err
+	}
+
+	return g.getDefaultBranch(repoPath)
+}
+
+// DeleteBranch deletes given branch from repository
+func (g *GiteaClient) DeleteBranch(repoUrl, branchName string) (bool, error) {
+	repoPath, err := getRepositoryPathFromRepoUrl(repoUrl)
+	if err != nil {
+		return false, err
+	}
+	return g.deleteBranch(repoPath, branchName)
+}
+
+// GetBranchSha returns SHA of top commit in the given branch
+func (g *GiteaClient) GetBranchSha(repoUrl, branchName string) (string, error) {
+	repoPath, err := getRepositoryPathFromRepoUrl(repoUrl)
+	if err != nil {
+		return "", err
+	}
+
+	branch, err := g.getBranch(repoPath, branchName)
+	if err != nil {
+		return "", err
+	}
+	if branch == nil || branch.Commit.Id == "" {
+		return "", fmt.Errorf("unexpected response while getting branch top commit SHA")
+	}
+	return branch.Commit.Id, nil
+}
+
+// IsFileExist check whether given file exists in the given branch of the reposiotry.
+// If branch is empty string, default branch is used.
+func (g *GiteaClient) IsFileExist(repoUrl, branchName, filePath string) (bool, error) {
+	repoPath, err := getRepositoryPathFromRepoUrl(repoUrl)
+	if err != nil {
+		return false, err
+	}
+
+	if branchName == "" {
+		var err error
+		branchName, err = g.getDefaultBranch(repoPath)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	directory := filepath.Dir(filePath)
+	files, err := g.filesExistInDirectory(repoPath, branchName, directory, []gp.RepositoryFile{{FullPath: filePath}})
+	if err != nil {
+		return false, err
+	}
+	return len(files) > 0, nil
+}
+
+// IsRepositoryPublic returns true if the repository could be accessed without authentication
+func (g *GiteaClient) IsRepositoryPublic(repoUrl string) (bool, error) {
+	repoPath, err := getRepositoryPathFromRepoUrl(repoUrl)
+	if err != nil {
+		return false, err
+	}
+
+	repositoryInfo, err := g.getRepositoryInfo(repoPath)
+	if err != nil {
+		return false, err
+	}
+	if repositoryInfo == nil {
+		return false, nil
+	}
+	return !repositoryInfo.Private && !repositoryInfo.Internal, nil
+}
+
+// GetBrowseRepositoryAtShaLink returns web URL of repository state at given SHA
+func (g *GiteaClient) GetBrowseRepositoryAtShaLink(repoUrl, sha string) string {
+	repoUrl = strings.TrimSuffix(strings.TrimSuffix(repoUrl, ".git"), "/")
+	return fmt.Sprintf("%s/src/commit/%s", repoUrl, sha)
+}
+
+func (g *GiteaClient) GetConfiguredGitAppName() (string, string, error) {
+	return "", "", fmt.Errorf("Gitea does not support applications")
+}
+
+func newGiteaClient(accessToken, baseUrl string) *GiteaClient {
+	return &GiteaClient{
+		httpClient:  &http.Client{Timeout: 30 * time.Second},
+		baseUrl:     baseUrl,
+		accessToken: accessToken,
+	}
+}
+
+func newGiteaClientWithBasicAuth(username, password, baseUrl string) *GiteaClient {
+	return &GiteaClient{
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		baseUrl:    baseUrl,
+		username:   username,
+		password:   password,
+	}
+}
@@ -0,0 +1,519 @@
+/*
+Copyright 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitea
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/konflux-ci/build-service/pkg/boerrors"
+	gp "github.com/konflux-ci/build-service/pkg/git/gitprovider"
+)
+
+const (
+	// Max page size allowed by default Gitea configuration
+	pageSize = 50
+)
+
+// Events which Pipelines as Code listens to.
+// Gitea subscribes to all pull request events, including comments, for "pull_request".
+var pacWebhookEvents = []string{"push", "pull_request"}
+
+type repository struct {
+	DefaultBranch string `json:"default_branch"`
+	Private       bool   `json:"private"`
+	Internal      bool   `json:"internal"`
+}
+
+type branch struct {
+	Name   string `json:"name"`
+	Commit struct {
+		Id string `json:"id"`
+	} `json:"commit"`
+}
+
+type contentsEntry struct {
+	Path string `json:"path"`
+	Type string `json:"type"`
+	Sha  string `json:"sha"`
+}
+
+type pullRequest struct {
+	Id        int64      `json:"id"`
+	Title     string     `json:"title"`
+	HtmlUrl   string     `json:"html_url"`
+	CreatedAt *time.Time `json:"created_at"`
+	Head      struct {
+		Ref string `json:"ref"`
+	} `json:"head"`
+	Base struct {
+		Ref string `json:"ref"`
+	} `json:"base"`
+}
+
+type webhook struct {
+	Id     int64             `json:"id,omitempty"`
+	Type   string            `json:"type,omitempty"`
+	Config map[string]string `json:"config"`
+	Events []string          `json:"events"`
+	Active bool              `json:"active"`
+}
+
+type GiteaApiError struct {
+	Method     string
+	Url        string
+	StatusCode int
+	Message    string
+}
+
+func (e GiteaApiError) Error() string {
+	return fmt.Sprintf("Gitea API request %s %s failed with status %d: %s", e.Method, e.Url, e.StatusCode, e.Message)
+}
+
+type InvalidRepositoryUrlError struct {
+	url string
+}
+
+func (e InvalidRepositoryUrlError) Error() string {
+	return fmt.Sprintf("Failed to detect Gitea owner and repository in url %s", e.url)
+}
+
+// GetBaseUrl returns Gitea API URL of the instance which hosts the given repository.
+func GetBaseUrl(repoUrl string) (string, error) {
+	u, err := url.Parse(repoUrl)
+	if err != nil {
+		return "", fmt.Errorf("Failed to parse url: %s, error: %w", repoUrl, err)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return "", fmt.Errorf("Failed to detect schema or host in url %s", repoUrl)
+	}
+	return fmt.Sprintf("%s://%s/api/v1/", u.Scheme, u.Host), nil
+}
+
+// getRepositoryPathFromRepoUrl returns owner/repository path of the given Gitea repository URL.
+func getRepositoryPathFromRepoUrl(repoUrl string) (string, error) {
+	u, err := url.Parse(repoUrl)
+	if err != nil {
+		return "", err
+	}
+	repoPath := strings.Trim(strings.TrimSuffix(strings.TrimSuffix(u.Path, "/"), ".git"), "/")
+	if len(strings.Split(repoPath, "/")) != 2 {
+		return "", InvalidRepositoryUrlError{repoUrl}
+	}
+	return repoPath, nil
+}
+
+// escapePath escapes each segment of the given path, keeping the slashes, e.g. in branch names.
+func escapePath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// refineGitHostingServiceError generates expected permanent error from Gitea response.
+// If no one is detected, the original error will be returned.
+// refineGitHostingServiceError should be called just after every Gitea API call.
+func refineGitHostingServiceError(response *http.Response, originErr error) error {
+	if response == nil {
+		return originErr
+	}
+	switch response.StatusCode {
+	case 401:
+		return boerrors.NewBuildOpError(boerrors.EGiteaTokenUnauthorized, originErr)
+	case 403:
+		return boerrors.NewBuildOpError(boerrors.EGiteaTokenInsufficientScope, originErr)
+	default:
+		return originErr
+	}
+}
+
+// doRequest sends the request to Gitea REST API and returns the response with its body.
+// An error is returned for any response with unsuccessful status code.
+func (g *GiteaClient) doRequest(method, path string, query url.Values, payload interface{}) (*http.Response, []byte, error) {
+	requestUrl := g.baseUrl + path
+	if len(query) > 0 {
+		requestUrl += "?" + query.Encode()
+	}
+
+	var body io.Reader
+	if payload != nil {
+		payloadBytes, err := json.Marshal(payload)
+		if err != nil {
+			return nil, nil, err
+		}
+		body = bytes.NewReader(payloadBytes)
+	}
+
+	req, err := http.NewRequest(method, requestUrl, body)
+	if err != nil {
+		return nil, nil, err
+	}
+	if g.accessToken != "" {
+		req.Header.Set("Authorization", "token "+g.accessToken)
+	} else {
+		req.SetBasicAuth(g.username, g.password)
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := g.httpClient.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp, nil, err
+	}
+
+	if resp.StatusCode >= 300 {
+		apiErr := GiteaApiError{Method: method, Url: requestUrl, StatusCode: resp.StatusCode, Message: getErrorMessage(respBody)}
+		return resp, respBody, refineGitHostingServiceError(resp, apiErr)
+	}
+	return resp, respBody, nil
+}
+
+// doJsonRequest sends the request to Gitea REST API and decodes the JSON response into the result, if any.
+func (g *GiteaClient) doJsonRequest(method, path string, query url.Values, payload interface{}, result interface{}) (*http.Response, error) {
+	resp, respBody, err := g.doRequest(method, path, query, payload)
+	if err != nil {
+		return resp, err
+	}
+	if result != nil && len(respBody) > 0 {
+		if err := json.Unmarshal(respBody, result); err != nil {
+			return resp, fmt.Errorf("failed to parse Gitea API response: %w", err)
+		}
+	}
+	return resp, nil
+}
+
+func getErrorMessage(respBody []byte) string {
+	errorResponse := struct {
+		Message string `json:"message"`
+	}{}
+	if err := json.Unmarshal(respBody, &errorResponse); err == nil && errorResponse.Message != "" {
+		return errorResponse.Message
+	}
+	return string(respBody)
+}
+
+func isNotFound(resp *http.Response) bool {
+	return resp != nil && resp.StatusCode == 404
+}
+
+func (g *GiteaClient) getRepositoryInfo(repoPath string) (*repository, error) {
+	repositoryInfo := &repository{}
+	resp, err := g.doJsonRequest("GET", "repos/"+repoPath, nil, nil, repositoryInfo)
+	if err != nil {
+		if isNotFound(resp) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return repositoryInfo, nil
+}
+
+func (g *GiteaClient) getDefaultBranch(repoPath string) (string, error) {
+	repositoryInfo, err := g.getRepositoryInfo(repoPath)
+	if err != nil {
+		return "", err
+	}
+	if repositoryInfo == nil || repositoryInfo.DefaultBranch == "" {
+		return "", fmt.Errorf("repository info is empty in Gitea API response")
+	}
+	return repositoryInfo.DefaultBranch, nil
+}
+
+func (g *GiteaClient) getBranch(repoPath, branchName string) (*branch, error) {
+	branchInfo := &branch{}
+	resp, err := g.doJsonRequest("GET", "repos/"+repoPath+"/branches/"+escapePath(branchName), nil, nil, branchInfo)
+	if err != nil {
+		if isNotFound(resp) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return branchInfo, nil
+}
+
+func (g *GiteaClient) createBranch(repoPath, branchName, baseBranchName string) error {
+	payload := map[string]string{
+		"new_branch_name": branchName,
+		"old_branch_name": baseBranchName,
+	}
+	_, err := g.doJsonRequest("POST", "repos/"+repoPath+"/branches", nil, payload, nil)
+	return err
+}
+
+func (g *GiteaClient) deleteBranch(repoPath, branchName string) (bool, error) {
+	resp, _, err := g.doRequest("DELETE", "repos/"+repoPath+"/branches/"+escapePath(branchName), nil, nil)
+	if err != nil {
+		if isNotFound(resp) {
+			// The given branch doesn't exist
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// getFileSha returns blob SHA of the given file or empty string if the file doesn't exist.
+func (g *GiteaClient) getFileSha(repoPath, branchName, filePath string) (string, error) {
+	entry := &contentsEntry{}
+	query := url.Values{"ref": []string{branchName}}
+	resp, err := g.doJsonRequest("GET", "repos/"+repoPath+"/contents/"+escapePath(filePath), query, nil, entry)
+	if err != nil {
+		if isNotFound(resp) {
+			return "", nil
+		}
+		return "", err
+	}
+	return entry.Sha, nil
+}
+
+func (g *GiteaClient) filesUpToDate(repoPath, branchName string, files []gp.RepositoryFile) (bool, error) {
+	for _, file := range files {
+		query := url.Values{"ref": []string{branchName}}
+		resp, fileContent, err := g.doRequest("GET", "repos/"+repoPath+"/raw/"+escapePath(file.FullPath), query, nil)
+		if err != nil {
+			if isNotFound(resp) {
+				return false, nil
+			}
+			return false, err
+		}
+		if !bytes.Equal(fileContent, file.Content) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// filesExistInDirectory checks if given files exist under specified directory.
+// Returns subset of given files which exist.
+func (g *GiteaClient) filesExistInDirectory(repoPath, branchName, directoryPath string, files []gp.RepositoryFile) ([]gp.RepositoryFile, error) {
+	existingFiles := make([]gp.RepositoryFile, 0, len(files))
+
+	var dirContent []contentsEntry
+	query := url.Values{"ref": []string{branchName}}
+	resp, err := g.doJsonRequest("GET", "repos/"+repoPath+"/contents/"+escapePath(strings.Trim(directoryPath, "/")), query, nil, &dirContent)
+	if err != nil {
+		if isNotFound(resp) {
+			return existingFiles, nil
+		}
+		return existingFiles, err
+	}
+
+	for _, entry := range dirContent {
+		for _, f := range files {
+			if entry.Path == f.FullPath {
+				existingFiles = append(existingFiles, gp.RepositoryFile{FullPath: entry.Path})
+				break
+			}
+		}
+	}
+
+	return existingFiles, nil
+}
+
+type changeFileOperation struct {
+	Operation string `json:"operation"`
+	Path      string `json:"path"`
+	Content   string `json:"content,omitempty"`
+	Sha       string `json:"sha,omitempty"`
+}
+
+// commitFilesIntoBranch creates commit into specified branch that creates or updates given files.
+func (g *GiteaClient) commitFilesIntoBranch(repoPath, branchName, commitMessage, authorName, authorEmail string, files []gp.RepositoryFile) error {
+	var operations []changeFileOperation
+	for _, file := range files {
+		// Detect file action: update or create
+		sha, err := g.getFileSha(repoPath, branchName, file.FullPath)
+		if err != nil {
+			return err
+		}
+		operation := "create"
+		if sha != "" {
+			operation = "update"
+		}
+		operations = append(operations, changeFileOperation{
+			Operation: operation,
+			Path:      file.FullPath,
+			Content:   base64.StdEncoding.EncodeToString(file.Content),
+			Sha:       sha,
+		})
+	}
+	return g.createCommit(repoPath, branchName, commitMessage, authorName, authorEmail, operations)
+}
+
+// addDeleteCommitToBranch creates commit into specified branch that deletes given files.
+func (g *GiteaClient) addDeleteCommitToBranch(repoPath, branchName, authorName, authorEmail, commitMessage string, files []gp.RepositoryFile) error {
+	var operations []changeFileOperation
+	for _, file := range files {
+		sha, err := g.getFileSha(repoPath, branchName, file.FullPath)
+		if err != nil {
+			return err
+		}
+		if sha == "" {
+			continue
+		}
+		operations = append(operations, changeFileOperation{
+			Operation: "delete",
+			Path:      file.FullPath,
+			Sha:       sha,
+		})
+	}
+	if len(operations) == 0 {
+		return nil
+	}
+	return g.createCommit(repoPath, branchName, commitMessage, authorName, authorEmail, operations)
+}
+
+func (g *GiteaClient) createCommit(repoPath, branchName, commitMessage, authorName, authorEmail string, operations []changeFileOperation) error {
+	identity := map[string]string{"name": authorName, "email": authorEmail}
+	payload := map[string]interface{}{
+		"branch":    branchName,
+		"message":   commitMessage,
+		"author":    identity,
+		"committer": identity,
+		"files":     operations,
+	}
+	_, err := g.doJsonRequest("POST", "repos/"+repoPath+"/contents", nil, payload, nil)
+	return err
+}
+
+func (g *GiteaClient) diffNotEmpty(repoPath, branchName, baseBranchName string) (bool, error) {
+	comparison := struct {
+		TotalCommits int `json:"total_commits"`
+	}{}
+	// Commits which are in the branch but not in the base branch
+	_, err := g.doJsonRequest("GET", "repos/"+repoPath+"/compare/"+escapePath(baseBranchName)+"..."+escapePath(branchName), nil, nil, &comparison)
+	if err != nil {
+		return false, err
+	}
+	return comparison.TotalCommits > 0, nil
+}
+
+func (g *GiteaClient) findPullRequestByBranches(repoPath, branchName, targetBranchName string) (*pullRequest, error) {
+	var matches []pullRequest
+	for page := 1; ; page++ {
+		var pullRequests []pullRequest
+		query := url.Values{
+			"state": []string{"open"},
+			"limit": []string{strconv.Itoa(pageSize)},
+			"page":  []string{strconv.Itoa(page)},
+		}
+		if _, err := g.doJsonRequest("GET", "repos/"+repoPath+"/pulls", query, nil, &pullRequests); err != nil {
+			return nil, err
+		}
+		for _, pr := range pullRequests {
+			if pr.Head.Ref == branchName && pr.Base.Ref == targetBranchName {
+				matches = append(matches, pr)
+			}
+		}
+		if len(pullRequests) < pageSize {
+			break
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, nil
+	case 1:
+		return &matches[0], nil
+	default:
+		return nil, fmt.Errorf("failed to find pull request by branch: %d matches found", len(matches))
+	}
+}
+
+func (g *GiteaClient) createPullRequestWithinRepository(repoPath, branchName, baseBranchName, prTitle, prText string) (string, error) {
+	payload := map[string]string{
+		"head":  branchName,
+		"base":  baseBranchName,
+		"title": prTitle,
+		"body":  prText,
+	}
+	pr := &pullRequest{}
+	if _, err := g.doJsonRequest("POST", "repos/"+repoPath+"/pulls", nil, payload, pr); err != nil {
+		return "", err
+	}
+	return pr.HtmlUrl, nil
+}
+
+func (g *GiteaClient) getWebhookByTargetUrl(repoPath, webhookTargetUrl string) (*webhook, error) {
+	for page := 1; ; page++ {
+		var webhooks []webhook
+		query := url.Values{
+			"limit": []string{strconv.Itoa(pageSize)},
+			"page":  []string{strconv.Itoa(page)},
+		}
+		if _, err := g.doJsonRequest("GET", "repos/"+repoPath+"/hooks", query, nil, &webhooks); err != nil {
+			return nil, err
+		}
+		for _, hook := range webhooks {
+			if hook.Config["url"] == webhookTargetUrl {
+				return &hook, nil
+			}
+		}
+		if len(webhooks) < pageSize {
+			break
+		}
+	}
+	// Webhook with the given URL not found
+	return nil, nil
+}
+
+func (g *GiteaClient) createPaCWebhook(repoPath, webhookTargetUrl, webhookSecret string) error {
+	hook := getPaCWebhook(webhookTargetUrl, webhookSecret)
+	hook.Type = "gitea"
+	_, err := g.doJsonRequest("POST", "repos/"+repoPath+"/hooks", nil, hook, nil)
+	return err
+}
+
+func (g *GiteaClient) updatePaCWebhook(repoPath string, webhookId int64, webhookTargetUrl, webhookSecret string) error {
+	_, err := g.doJsonRequest("PATCH", fmt.Sprintf("repos/%s/hooks/%d", repoPath, webhookId), nil, getPaCWebhook(webhookTargetUrl, webhookSecret), nil)
+	return err
+}
+
+func (g *GiteaClient) deleteWebhook(repoPath string, webhookId int64) error {
+	resp, _, err := g.doRequest("DELETE", fmt.Sprintf("repos/%s/hooks/%d", repoPath, webhookId), nil, nil)
+	if err != nil && !isNotFound(resp) {
+		return err
+	}
+	return nil
+}
+
+func getPaCWebhook(webhookTargetUrl, webhookSecret string) *webhook {
+	return &webhook{
+		Config: map[string]string{
+			"url":          webhookTargetUrl,
+			"content_type": "json",
+			"secret":       webhookSecret,
+		},
+		Events: pacWebhookEvents,
+		Active: true,
+	}
+}
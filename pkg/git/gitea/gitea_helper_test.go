@@ -0,0 +1,266 @@
+package gitea
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/konflux-ci/build-service/pkg/boerrors"
+	gp "github.com/konflux-ci/build-service/pkg/git/gitprovider"
+)
+
+func TestGetRepositoryPathFromRepoUrl(t *testing.T) {
+	var params = []struct {
+		in        string
+		out       string
+		expectErr bool
+	}{
+		{"https://gitea.mydomain.com/owner/repository", "owner/repository", false},
+		{"https://codeberg.org/owner/repository.git", "owner/repository", false},
+		{"https://gitea.mydomain.com/owner/repository/", "owner/repository", false},
+		{"https://gitea.mydomain.com/owner", "", true},
+		{"http!!://abc", "", true},
+	}
+
+	for _, tt := range params {
+		actual, err := getRepositoryPathFromRepoUrl(tt.in)
+		if err != nil && !tt.expectErr {
+			t.Fatalf("Expected call to succeed, found error %s", err.Error())
+		}
+		if err == nil && tt.expectErr {
+			t.Fatalf("Expected call to end with an error, but it succeed")
+		}
+		if actual != tt.out {
+			t.Fatalf("Expected %s found %s", tt.out, actual)
+		}
+	}
+}
+
+func TestGetBaseUrl(t *testing.T) {
+	var params = []struct {
+		in        string
+		out       string
+		expectErr bool
+	}{
+		{"https://gitea.mydomain.com/owner/repository.git", "https://gitea.mydomain.com/api/v1/", false},
+		{"http://gitea.local:3000/owner/repository", "http://gitea.local:3000/api/v1/", false},
+		{"gitea.mydomain.com/owner/repository", "", true},
+		{"http!!://abc", "", true},
+	}
+
+	for _, tt := range params {
+		actual, err := GetBaseUrl(tt.in)
+		if err != nil && !tt.expectErr {
+			t.Fatalf("Expected call to succeed, found error %s", err.Error())
+		}
+		if err == nil && tt.expectErr {
+			t.Fatalf("Expected call to end with an error, but it succeed")
+		}
+		if actual != tt.out {
+			t.Fatalf("Expected %s found %s", tt.out, actual)
+		}
+	}
+}
+
+func TestEnsurePaCMergeRequestCreatesBranchAndPullRequest(t *testing.T) {
+	var commitPayload struct {
+		Branch string                `json:"branch"`
+		Author map[string]string     `json:"author"`
+		Files  []changeFileOperation `json:"files"`
+	}
+	var pullRequestPayload map[string]string
+	var newBranchPayload map[string]string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "token token" {
+			w.WriteHeader(401)
+			return
+		}
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/api/v1/repos/owner/repo/raw/.tekton/component-push.yaml":
+			w.WriteHeader(404)
+		case r.Method == "GET" && r.URL.Path == "/api/v1/repos/owner/repo/branches/konflux/component":
+			w.WriteHeader(404)
+		case r.Method == "POST" && r.URL.Path == "/api/v1/repos/owner/repo/branches":
+			if err := json.NewDecoder(r.Body).Decode(&newBranchPayload); err != nil {
+				w.WriteHeader(400)
+				return
+			}
+			w.WriteHeader(201)
+			fmt.Fprint(w, `{}`)
+		case r.Method == "GET" && r.URL.Path == "/api/v1/repos/owner/repo/contents/.tekton/component-push.yaml":
+			w.WriteHeader(404)
+		case r.Method == "POST" && r.URL.Path == "/api/v1/repos/owner/repo/contents":
+			if err := json.NewDecoder(r.Body).Decode(&commitPayload); err != nil {
+				w.WriteHeader(400)
+				return
+			}
+			w.WriteHeader(201)
+			fmt.Fprint(w, `{}`)
+		case r.Method == "POST" && r.URL.Path == "/api/v1/repos/owner/repo/pulls":
+			if err := json.NewDecoder(r.Body).Decode(&pullRequestPayload); err != nil {
+				w.WriteHeader(400)
+				return
+			}
+			w.WriteHeader(201)
+			fmt.Fprint(w, `{"id": 1, "html_url": "https://gitea.mydomain.com/owner/repo/pulls/1"}`)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(500)
+		}
+	}))
+	defer server.Close()
+
+	client := NewGiteaClient("token", server.URL+"/api/v1/")
+	mergeRequestData := &gp.MergeRequestData{
+		CommitMessage:  "Konflux update component",
+		BranchName:     "konflux/component",
+		BaseBranchName: "main",
+		Title:          "Konflux update component",
+		Text:           "Pipelines as Code configuration proposal",
+		AuthorName:     "konflux",
+		AuthorEmail:    "konflux@no-reply.konflux-ci.dev",
+		Files: []gp.RepositoryFile{
+			{FullPath: ".tekton/component-push.yaml", Content: []byte("push pipeline")},
+		},
+	}
+
+	webUrl, err := client.EnsurePaCMergeRequest("https://gitea.mydomain.com/owner/repo", mergeRequestData)
+	if err != nil {
+		t.Fatalf("Expected call to succeed, found error %s", err.Error())
+	}
+	if webUrl != "https://gitea.mydomain.com/owner/repo/pulls/1" {
+		t.Fatalf("Unexpected pull request URL %s", webUrl)
+	}
+	if newBranchPayload["new_branch_name"] != "konflux/component" || newBranchPayload["old_branch_name"] != "main" {
+		t.Fatalf("Unexpected branch creation %v", newBranchPayload)
+	}
+	if commitPayload.Branch != "konflux/component" || commitPayload.Author["email"] != "konflux@no-reply.konflux-ci.dev" ||
+		len(commitPayload.Files) != 1 || commitPayload.Files[0].Operation != "create" {
+		t.Fatalf("Unexpected commit %v", commitPayload)
+	}
+	if pullRequestPayload["head"] != "konflux/component" || pullRequestPayload["base"] != "main" {
+		t.Fatalf("Unexpected pull request %v", pullRequestPayload)
+	}
+}
+
+func TestPushPaCConfigurationCommitsIntoBaseBranch(t *testing.T) {
+	var commitPayload struct {
+		Branch string                `json:"branch"`
+		Files  []changeFileOperation `json:"files"`
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/api/v1/repos/owner/repo/raw/.tekton/component-push.yaml":
+			fmt.Fprint(w, "outdated push pipeline")
+		case r.Method == "GET" && r.URL.Path == "/api/v1/repos/owner/repo/contents/.tekton/component-push.yaml":
+			fmt.Fprint(w, `{"sha": "fileSha"}`)
+		case r.Method == "POST" && r.URL.Path == "/api/v1/repos/owner/repo/contents":
+			if err := json.NewDecoder(r.Body).Decode(&commitPayload); err != nil {
+				w.WriteHeader(400)
+				return
+			}
+			w.WriteHeader(201)
+			fmt.Fprint(w, `{}`)
+		case r.Method == "GET" && r.URL.Path == "/api/v1/repos/owner/repo/branches/main":
+			fmt.Fprint(w, `{"name": "main", "commit": {"id": "commitSha"}}`)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(500)
+		}
+	}))
+	defer server.Close()
+
+	client := NewGiteaClient("token", server.URL+"/api/v1/")
+	data := &gp.MergeRequestData{
+		CommitMessage:  "Konflux update component",
+		BaseBranchName: "main",
+		AuthorName:     "konflux",
+		AuthorEmail:    "konflux@no-reply.konflux-ci.dev",
+		Files: []gp.RepositoryFile{
+			{FullPath: ".tekton/component-push.yaml", Content: []byte("push pipeline")},
+		},
+	}
+
+	webUrl, err := client.PushPaCConfiguration("https://gitea.mydomain.com/owner/repo", data)
+	if err != nil {
+		t.Fatalf("Expected call to succeed, found error %s", err.Error())
+	}
+	if webUrl != "https://gitea.mydomain.com/owner/repo/src/commit/commitSha" {
+		t.Fatalf("Unexpected commit URL %s", webUrl)
+	}
+	if commitPayload.Branch != "main" || len(commitPayload.Files) != 1 ||
+		commitPayload.Files[0].Operation != "update" || commitPayload.Files[0].Sha != "fileSha" {
+		t.Fatalf("Unexpected commit %v", commitPayload)
+	}
+}
+
+func TestSetupPaCWebhook(t *testing.T) {
+	tests := []struct {
+		name           string
+		existingHooks  string
+		expectedMethod string
+		expectedPath   string
+	}{
+		{
+			name:           "should create webhook",
+			existingHooks:  `[{"id": 1, "config": {"url": "https://other.io"}}]`,
+			expectedMethod: "POST",
+			expectedPath:   "/api/v1/repos/owner/repo/hooks",
+		},
+		{
+			name:           "should update existing webhook",
+			existingHooks:  `[{"id": 2, "config": {"url": "https://pac.io"}}]`,
+			expectedMethod: "PATCH",
+			expectedPath:   "/api/v1/repos/owner/repo/hooks/2",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var hook *webhook
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == "GET" && r.URL.Path == "/api/v1/repos/owner/repo/hooks" {
+					fmt.Fprint(w, tt.existingHooks)
+					return
+				}
+				if r.Method == tt.expectedMethod && r.URL.Path == tt.expectedPath {
+					hook = &webhook{}
+					if err := json.NewDecoder(r.Body).Decode(hook); err != nil {
+						w.WriteHeader(400)
+						return
+					}
+					fmt.Fprint(w, `{}`)
+					return
+				}
+				t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+				w.WriteHeader(500)
+			}))
+			defer server.Close()
+
+			client := NewGiteaClient("token", server.URL+"/api/v1/")
+			if err := client.SetupPaCWebhook("https://gitea.mydomain.com/owner/repo", "https://pac.io", "secret"); err != nil {
+				t.Fatalf("Expected call to succeed, found error %s", err.Error())
+			}
+			if hook == nil || hook.Config["url"] != "https://pac.io" || hook.Config["secret"] != "secret" || !hook.Active {
+				t.Fatalf("Unexpected webhook %v", hook)
+			}
+		})
+	}
+}
+
+func TestRefineGitHostingServiceError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(401)
+		fmt.Fprint(w, `{"message": "token is required"}`)
+	}))
+	defer server.Close()
+
+	client := NewGiteaClient("token", server.URL+"/api/v1/")
+	_, err := client.GetDefaultBranch("https://gitea.mydomain.com/owner/repo")
+	if !boerrors.IsBuildOpError(err, boerrors.EGiteaTokenUnauthorized) {
+		t.Fatalf("Expected unauthorized error, got %v", err)
+	}
+}
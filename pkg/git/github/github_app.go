@@ -18,18 +18,108 @@ package github
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/rand"
 	"net/http"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	ghinstallation "github.com/bradleyfalzon/ghinstallation/v2"
 	"github.com/google/go-github/v45/github"
 	"github.com/konflux-ci/build-service/pkg/boerrors"
+	"github.com/konflux-ci/build-service/pkg/bometrics"
 )
 
+// Listing of App installations stops if fewer API requests remain in the App rate limit,
+// so the limit is not exhausted in the middle of the installations.
+const MinAppRateLimitRemaining = 100
+
+// AppRateLimitError is returned when the GitHub App rate limit is nearly exhausted.
+type AppRateLimitError struct {
+	Remaining int
+	Reset     time.Time
+}
+
+func (e *AppRateLimitError) Error() string {
+	return fmt.Sprintf("GitHub App rate limit nearly exhausted, %d requests remaining until %s", e.Remaining, e.Reset.Format(time.RFC3339))
+}
+
+// checkAppRateLimit exports the remaining App rate limit reported by the GitHub API response
+// and returns AppRateLimitError if the limit is nearly exhausted.
+func checkAppRateLimit(githubAppIdStr string, resp *github.Response) error {
+	if resp == nil || resp.Rate.Limit == 0 {
+		return nil
+	}
+	bometrics.GitHubAppRateLimitRemainingMetric.WithLabelValues(githubAppIdStr).Set(float64(resp.Rate.Remaining))
+	if resp.Rate.Remaining < MinAppRateLimitRemaining {
+		return &AppRateLimitError{Remaining: resp.Rate.Remaining, Reset: resp.Rate.Reset.Time}
+	}
+	return nil
+}
+
+const (
+	// Number of times a GitHub API request of the App installations listing is retried
+	// after a secondary rate limit or a server error.
+	MaxAppRequestRetries = 3
+	// Requests are not retried if GitHub asks to wait longer, the installations are listed again by the next sweep.
+	MaxAppRequestRetryWait = time.Minute
+)
+
+// PartialInstallationsError is returned together with the listed installations
+// if some installations of the App couldn't be listed.
+type PartialInstallationsError struct {
+	AppId string
+	// Errors of the installations left out, by installation ID
+	Failed map[int64]error
+	// Set if the listing stopped because the App rate limit is nearly exhausted.
+	// Installations with cached tokens are still listed, the others are left out until the limit resets.
+	RateLimit *AppRateLimitError
+}
+
+func (e *PartialInstallationsError) Error() string {
+	var ids []string
+	for id := range e.Failed {
+		ids = append(ids, strconv.FormatInt(id, 10))
+	}
+	sort.Strings(ids)
+	message := fmt.Sprintf("failed to list %d installations of GitHub App %s: %s", len(e.Failed), e.AppId, strings.Join(ids, ", "))
+	if e.RateLimit != nil {
+		message += ", listing stopped: " + e.RateLimit.Error()
+	}
+	return message
+}
+
+// Allow mocking for tests
+var sleep = time.Sleep
+
+// withAppRequestRetries runs the GitHub API request and repeats it after secondary rate limits
+// and server errors, waiting as requested by the Retry-After header or with exponential backoff.
+func withAppRequestRetries(request func() (*github.Response, error)) error {
+	for attempt := 0; ; attempt++ {
+		resp, err := request()
+		if err == nil || attempt == MaxAppRequestRetries {
+			return err
+		}
+		wait := time.Second << attempt
+		var abuseRateLimitErr *github.AbuseRateLimitError
+		if errors.As(err, &abuseRateLimitErr) {
+			if abuseRateLimitErr.RetryAfter != nil {
+				wait = *abuseRateLimitErr.RetryAfter
+			}
+		} else if resp == nil || resp.Response == nil || resp.StatusCode < http.StatusInternalServerError {
+			return err
+		}
+		if wait > MaxAppRequestRetryWait {
+			return err
+		}
+		sleep(wait)
+	}
+}
+
 // Allow mocking for tests
 var NewGithubClientByApp func(appId int64, privateKeyPem []byte, repoUrl string) (*GithubClient, error) = newGithubClientByApp
 var NewGithubClientForSimpleBuildByApp func(appId int64, privateKeyPem []byte) (*GithubClient, error) = newGithubClientForSimpleBuildByApp
@@ -37,6 +127,7 @@ var NewGithubClientForSimpleBuildByApp func(appId int64, privateKeyPem []byte) (
 var IsAppInstalledIntoRepository func(ghclient *GithubClient, repoUrl string) (bool, error) = isAppInstalledIntoRepository
 var GetAllAppInstallations func(githubAppIdStr string, appPrivateKeyPem []byte) ([]ApplicationInstallation, string, error) = getAppInstallations
 var GetAppInstallationsForRepository func(githubAppIdStr string, appPrivateKeyPem []byte, repoUrl string) (*ApplicationInstallation, string, error) = getAppInstallationsForRepository
+var CreateInstallationTokenForRepositories func(githubAppIdStr string, appPrivateKeyPem []byte, installationId int64, repositoryIds []int64) (string, error) = createInstallationTokenForRepositories
 
 func newGithubClientByApp(appId int64, privateKeyPem []byte, repoUrl string) (*GithubClient, error) {
 	owner, _ := getOwnerAndRepoFromUrl(repoUrl)
@@ -46,7 +137,7 @@ func newGithubClientByApp(appId int64, privateKeyPem []byte, repoUrl string) (*G
 		// Inability to create transport based on a private key indicates that the key is bad formatted
 		return nil, boerrors.NewBuildOpError(boerrors.EGitHubAppMalformedPrivateKey, err)
 	}
-	client := github.NewClient(&http.Client{Transport: itr})
+	client := newGoGithubClient(&http.Client{Transport: itr})
 
 	var installId int64
 	opt := &github.RepositoryListByOrgOptions{
@@ -110,7 +201,7 @@ func newGithubClientForSimpleBuildByApp(appId int64, privateKeyPem []byte) (*Git
 		// Inability to create transport based on a private key indicates that the key is bad formatted
 		return nil, boerrors.NewBuildOpError(boerrors.EGitHubAppMalformedPrivateKey, err)
 	}
-	client := github.NewClient(&http.Client{Transport: itr})
+	client := newGoGithubClient(&http.Client{Transport: itr})
 
 	opt := &github.RepositoryListByOrgOptions{
 		ListOptions: github.ListOptions{PerPage: 100},
@@ -197,7 +288,7 @@ func (g *GithubClient) GetConfiguredGitAppName() (string, string, error) {
 		// Inability to create transport based on a private key indicates that the key is bad formatted
 		return "", "", boerrors.NewBuildOpError(boerrors.EGitHubAppMalformedPrivateKey, err)
 	}
-	client := github.NewClient(&http.Client{Transport: itr})
+	client := newGoGithubClient(&http.Client{Transport: itr})
 	githubApp, _, err := client.Apps.Get(g.ctx, "")
 	if err != nil {
 		return "", "", err
@@ -233,7 +324,7 @@ func getAppInstallations(githubAppIdStr string, appPrivateKeyPem []byte) ([]Appl
 		// Inability to create transport based on a private key indicates that the key is bad formatted
 		return nil, "", boerrors.NewBuildOpError(boerrors.EGitHubAppMalformedPrivateKey, err)
 	}
-	client := github.NewClient(&http.Client{Transport: itr})
+	client := newGoGithubClient(&http.Client{Transport: itr})
 	appInstallations := []ApplicationInstallation{}
 	opt := &github.RepositoryListByOrgOptions{
 		ListOptions: github.ListOptions{PerPage: 100},
@@ -243,9 +334,24 @@ func getAppInstallations(githubAppIdStr string, appPrivateKeyPem []byte) ([]Appl
 		return nil, "", fmt.Errorf("failed to load GitHub app metadata, %w", err)
 	}
 	slug := (githubApp.GetSlug())
+	failedInstallations := map[int64]error{}
+	suspendedInstallations := 0
+	// Set once the App rate limit is nearly exhausted, no more App requests are made then
+	var rateLimited *AppRateLimitError
 	for {
-		installations, resp, err := client.Apps.ListInstallations(context.Background(), &opt.ListOptions)
+		var installations []*github.Installation
+		var resp *github.Response
+		err := withAppRequestRetries(func() (*github.Response, error) {
+			var err error
+			installations, resp, err = client.Apps.ListInstallations(context.Background(), &opt.ListOptions)
+			return resp, err
+		})
 		if err != nil {
+			var rateLimitErr *github.RateLimitError
+			if errors.As(err, &rateLimitErr) {
+				rateLimited = &AppRateLimitError{Remaining: rateLimitErr.Rate.Remaining, Reset: rateLimitErr.Rate.Reset.Time}
+				break
+			}
 			if resp != nil && resp.Response != nil && resp.Response.StatusCode != 0 {
 				switch resp.StatusCode {
 				case 401:
@@ -256,36 +362,130 @@ func getAppInstallations(githubAppIdStr string, appPrivateKeyPem []byte) ([]Appl
 			}
 			return nil, "", boerrors.NewBuildOpError(boerrors.ETransientError, err)
 		}
+		if err := checkAppRateLimit(githubAppIdStr, resp); err != nil {
+			// The installations of the page are still listed if their tokens are cached
+			errors.As(err, &rateLimited)
+		}
 		for _, val := range installations {
-			token, _, err := client.Apps.CreateInstallationToken(
-				context.Background(),
-				*val.ID,
-				&github.InstallationTokenOptions{})
-			if err != nil {
-				// TODO analyze the error
+			// Tokens of suspended installations are rejected, the installations are skipped until they are unsuspended
+			if val.SuspendedAt != nil {
+				suspendedInstallations++
+				continue
+			}
+			tokenKey := installationTokenKey{appId: githubAppId, installationId: *val.ID}
+			token, ok := installationTokens.get(tokenKey)
+			if ok {
+				bometrics.GitHubAppInstallationTokensReusedTotalMetric.Inc()
+			} else if rateLimited != nil {
 				continue
+			} else {
+				var installationToken *github.InstallationToken
+				var tokenResp *github.Response
+				err := withAppRequestRetries(func() (*github.Response, error) {
+					var err error
+					installationToken, tokenResp, err = client.Apps.CreateInstallationToken(
+						context.Background(),
+						*val.ID,
+						&github.InstallationTokenOptions{})
+					return tokenResp, err
+				})
+				var rateLimitErr *github.RateLimitError
+				if errors.As(err, &rateLimitErr) {
+					rateLimited = &AppRateLimitError{Remaining: rateLimitErr.Rate.Remaining, Reset: rateLimitErr.Rate.Reset.Time}
+					continue
+				}
+				if err != nil {
+					failedInstallations[*val.ID] = err
+					continue
+				}
+				token = installationToken.GetToken()
+				installationTokens.put(tokenKey, token, installationToken.GetExpiresAt())
+				// The created token is used even if the limit is nearly exhausted now, only the following ones are not created
+				if err := checkAppRateLimit(githubAppIdStr, tokenResp); err != nil {
+					errors.As(err, &rateLimited)
+				}
 			}
-			installationClient := NewGithubClient(token.GetToken())
+			installationClient := NewGithubClient(token)
 
 			repositories, err := getRepositoriesFromClient(installationClient)
 			if err != nil {
+				// The cached token may have been revoked, the next listing creates a new one
+				installationTokens.delete(tokenKey)
+				failedInstallations[*val.ID] = err
 				continue
 			}
 			appInstallations = append(appInstallations, ApplicationInstallation{
-				Token:        token.GetToken(),
+				Token:        token,
 				ID:           *val.ID,
 				Repositories: repositories,
 			})
 		}
-		if resp.NextPage == 0 {
+		if resp.NextPage == 0 || rateLimited != nil {
 			break
 		}
 		opt.Page = resp.NextPage
 	}
 
+	bometrics.GitHubAppInstallationsSuspendedMetric.WithLabelValues(githubAppIdStr).Set(float64(suspendedInstallations))
+	if rateLimited != nil && len(appInstallations) == 0 && len(failedInstallations) == 0 {
+		return nil, "", rateLimited
+	}
+	if len(failedInstallations) > 0 || rateLimited != nil {
+		return appInstallations, slug, &PartialInstallationsError{AppId: githubAppIdStr, Failed: failedInstallations, RateLimit: rateLimited}
+	}
 	return appInstallations, slug, nil
 }
 
+// renovateTokenPermissions are the only permissions of the installation tokens created for the given repositories,
+// whatever else the application is granted. Renovate pushes its branches, manages pull requests together with their labels
+// and the dependency dashboard issue, sets its commit statuses and reads the checks of the pull requests before automerge.
+var renovateTokenPermissions = &github.InstallationPermissions{
+	Contents:     github.String("write"),
+	PullRequests: github.String("write"),
+	Issues:       github.String("write"),
+	Statuses:     github.String("write"),
+	Checks:       github.String("read"),
+	Metadata:     github.String("read"),
+}
+
+// createInstallationTokenForRepositories creates a token of the installation which is valid only for the given repositories
+// and has only the permissions renovate needs, since the token is handed over to the tenant.
+// The tokens are not cached, each of them is scoped to a different subset of the installation repositories.
+func createInstallationTokenForRepositories(githubAppIdStr string, appPrivateKeyPem []byte, installationId int64, repositoryIds []int64) (string, error) {
+	githubAppId, err := strconv.ParseInt(githubAppIdStr, 10, 64)
+	if err != nil {
+		return "", boerrors.NewBuildOpError(boerrors.EGitHubAppMalformedId,
+			fmt.Errorf("failed to convert %s to int: %w", githubAppIdStr, err))
+	}
+	itr, err := ghinstallation.NewAppsTransport(http.DefaultTransport, githubAppId, appPrivateKeyPem)
+	if err != nil {
+		// Inability to create transport based on a private key indicates that the key is bad formatted
+		return "", boerrors.NewBuildOpError(boerrors.EGitHubAppMalformedPrivateKey, err)
+	}
+	client := newGoGithubClient(&http.Client{Transport: itr})
+	var installationToken *github.InstallationToken
+	var resp *github.Response
+	err = withAppRequestRetries(func() (*github.Response, error) {
+		var err error
+		installationToken, resp, err = client.Apps.CreateInstallationToken(
+			context.Background(),
+			installationId,
+			&github.InstallationTokenOptions{RepositoryIDs: repositoryIds, Permissions: renovateTokenPermissions})
+		return resp, err
+	})
+	var rateLimitErr *github.RateLimitError
+	if errors.As(err, &rateLimitErr) {
+		return "", &AppRateLimitError{Remaining: rateLimitErr.Rate.Remaining, Reset: rateLimitErr.Rate.Reset.Time}
+	}
+	if err != nil {
+		return "", boerrors.NewBuildOpError(boerrors.ETransientError, err)
+	}
+	if err := checkAppRateLimit(githubAppIdStr, resp); err != nil {
+		return "", err
+	}
+	return installationToken.GetToken(), nil
+}
+
 func getAppInstallationsForRepository(githubAppIdStr string, appPrivateKeyPem []byte, repoUrl string) (*ApplicationInstallation, string, error) {
 	githubAppId, err := strconv.ParseInt(githubAppIdStr, 10, 64)
 	if err != nil {
@@ -293,7 +493,7 @@ func getAppInstallationsForRepository(githubAppIdStr string, appPrivateKeyPem []
 			fmt.Errorf("failed to convert %s to int: %w", githubAppIdStr, err))
 	}
 
-	ghUrlRegex, err := regexp.Compile(`github.com/([^/]+)/([^/]+)(\.git)?$`)
+	ghUrlRegex, err := regexp.Compile(regexp.QuoteMeta(GetHost()) + `/([^/]+)/([^/]+)(\.git)?$`)
 	if err != nil {
 		return nil, "", err
 	}
@@ -309,7 +509,7 @@ func getAppInstallationsForRepository(githubAppIdStr string, appPrivateKeyPem []
 		// Inability to create transport based on a private key indicates that the key is bad formatted
 		return nil, "", boerrors.NewBuildOpError(boerrors.EGitHubAppMalformedPrivateKey, err)
 	}
-	client := github.NewClient(&http.Client{Transport: itr})
+	client := newGoGithubClient(&http.Client{Transport: itr})
 	githubApp, _, err := client.Apps.Get(context.Background(), "")
 	if err != nil {
 		return nil, "", fmt.Errorf("failed to load GitHub app metadata, %w", err)
@@ -361,7 +561,13 @@ func getRepositoriesFromClient(ghClient *GithubClient) ([]*github.Repository, er
 	opt := &github.ListOptions{PerPage: 100}
 	var repos []*github.Repository
 	for {
-		repoList, resp, err := ghClient.client.Apps.ListRepos(context.TODO(), opt)
+		var repoList *github.ListRepositories
+		var resp *github.Response
+		err := withAppRequestRetries(func() (*github.Response, error) {
+			var err error
+			repoList, resp, err = ghClient.client.Apps.ListRepos(context.TODO(), opt)
+			return resp, err
+		})
 		if err != nil {
 			return nil, err
 		}
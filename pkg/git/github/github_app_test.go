@@ -0,0 +1,302 @@
+/*
+Copyright 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v45/github"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/konflux-ci/build-service/pkg/bometrics"
+)
+
+func TestCheckAppRateLimit(t *testing.T) {
+	reset := time.Now().Add(time.Hour)
+	tests := []struct {
+		name          string
+		resp          *github.Response
+		wantRateLimit bool
+	}{
+		{
+			name: "should accept response without rate limit",
+			resp: &github.Response{},
+		},
+		{
+			name: "should accept enough remaining requests",
+			resp: &github.Response{Rate: github.Rate{Limit: 5000, Remaining: 4000, Reset: github.Timestamp{Time: reset}}},
+		},
+		{
+			name:          "should return error if rate limit is nearly exhausted",
+			resp:          &github.Response{Rate: github.Rate{Limit: 5000, Remaining: 99, Reset: github.Timestamp{Time: reset}}},
+			wantRateLimit: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkAppRateLimit("12345", tt.resp)
+			var rateLimitErr *AppRateLimitError
+			if got := errors.As(err, &rateLimitErr); got != tt.wantRateLimit {
+				t.Fatalf("checkAppRateLimit() error = %v, want rate limit error %v", err, tt.wantRateLimit)
+			}
+			if tt.wantRateLimit && (rateLimitErr.Remaining != tt.resp.Rate.Remaining || !rateLimitErr.Reset.Equal(reset)) {
+				t.Errorf("checkAppRateLimit() error = %v, want remaining %d until %s", err, tt.resp.Rate.Remaining, reset)
+			}
+			if tt.resp.Rate.Limit != 0 {
+				if got := testutil.ToFloat64(bometrics.GitHubAppRateLimitRemainingMetric.WithLabelValues("12345")); got != float64(tt.resp.Rate.Remaining) {
+					t.Errorf("remaining rate limit metric = %v, want %v", got, tt.resp.Rate.Remaining)
+				}
+			}
+		})
+	}
+}
+
+func TestGetAppInstallations(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	privateKeyPem := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(privateKey)})
+
+	tokenRequests := map[string]int{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/app", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"slug": "test-app"}`)
+	})
+	mux.HandleFunc("/api/v3/app/installations", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "2" {
+			fmt.Fprint(w, `[{"id": 3}]`)
+			return
+		}
+		w.Header().Set("Link", fmt.Sprintf(`<%s?page=2>; rel="next"`, r.URL.Path))
+		fmt.Fprint(w, `[{"id": 1}, {"id": 2}, {"id": 4, "suspended_at": "2024-01-01T00:00:00Z"}]`)
+	})
+	mux.HandleFunc("/api/v3/app/installations/", func(w http.ResponseWriter, r *http.Request) {
+		id := strings.Split(r.URL.Path, "/")[5]
+		tokenRequests[id]++
+		switch {
+		case id == "1" && tokenRequests[id] == 1:
+			w.Header().Set("Retry-After", "5")
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"message": "secondary rate limit", "documentation_url": "https://docs.github.com/rest#secondary-rate-limits"}`)
+		case id == "2" && tokenRequests[id] == 1:
+			w.WriteHeader(http.StatusBadGateway)
+		case id == "3":
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message": "Not Found"}`)
+		default:
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprintf(w, `{"token": "token-%s", "expires_at": "%s"}`, id, time.Now().Add(time.Hour).Format(time.RFC3339))
+		}
+	})
+	mux.HandleFunc("/api/v3/installation/repositories", func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		fmt.Fprintf(w, `{"total_count": 1, "repositories": [{"full_name": "org/%s"}]}`, token)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	t.Setenv(GithubEnterpriseUrlEnvName, server.URL)
+
+	defer func(f func(time.Duration)) { sleep = f }(sleep)
+	var waits []time.Duration
+	sleep = func(d time.Duration) { waits = append(waits, d) }
+	installationTokens = newInstallationTokenCache()
+
+	installations, slug, err := getAppInstallations("12345", privateKeyPem)
+	var partialErr *PartialInstallationsError
+	if !errors.As(err, &partialErr) {
+		t.Fatalf("getAppInstallations() error = %v, want partial installations error", err)
+	}
+	if _, ok := partialErr.Failed[3]; !ok || len(partialErr.Failed) != 1 {
+		t.Errorf("failed installations = %v, want installation 3", partialErr.Failed)
+	}
+	if slug != "test-app" {
+		t.Errorf("getAppInstallations() slug = %s, want test-app", slug)
+	}
+	// Both pages are listed and the requests failed transiently are retried
+	if len(installations) != 2 || installations[0].Token != "token-1" || installations[1].Repositories[0].GetFullName() != "org/token-2" {
+		t.Errorf("getAppInstallations() = %v, want installations 1 and 2", installations)
+	}
+	if want := []time.Duration{5 * time.Second, time.Second}; !reflect.DeepEqual(waits, want) {
+		t.Errorf("retry waits = %v, want %v", waits, want)
+	}
+
+	if tokenRequests["4"] != 0 {
+		t.Errorf("token of suspended installation requested")
+	}
+	if got := testutil.ToFloat64(bometrics.GitHubAppInstallationsSuspendedMetric.WithLabelValues("12345")); got != 1 {
+		t.Errorf("suspended installations metric = %v, want 1", got)
+	}
+
+	// Valid tokens are reused by the next listing
+	if _, _, err := getAppInstallations("12345", privateKeyPem); !errors.As(err, &partialErr) {
+		t.Fatalf("getAppInstallations() error = %v, want partial installations error", err)
+	}
+	if tokenRequests["1"] != 2 || tokenRequests["2"] != 2 {
+		t.Errorf("token requests = %v, want no new tokens of installations 1 and 2", tokenRequests)
+	}
+}
+
+func TestGetAppInstallationsKeepsListedInstallationsWhenRateLimitIsNearlyExhausted(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	privateKeyPem := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(privateKey)})
+
+	reset := time.Now().Add(30 * time.Minute).Truncate(time.Second)
+	setRateLimit := func(w http.ResponseWriter, remaining int) {
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+	}
+	installationsRemaining := 1000
+	tokenRequests := map[string]int{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/app", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"slug": "test-app"}`)
+	})
+	mux.HandleFunc("/api/v3/app/installations", func(w http.ResponseWriter, r *http.Request) {
+		setRateLimit(w, installationsRemaining)
+		fmt.Fprint(w, `[{"id": 1}, {"id": 2}, {"id": 3}]`)
+	})
+	mux.HandleFunc("/api/v3/app/installations/", func(w http.ResponseWriter, r *http.Request) {
+		id := strings.Split(r.URL.Path, "/")[5]
+		tokenRequests[id]++
+		setRateLimit(w, 50)
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"token": "token-%s", "expires_at": "%s"}`, id, time.Now().Add(time.Hour).Format(time.RFC3339))
+	})
+	mux.HandleFunc("/api/v3/installation/repositories", func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		fmt.Fprintf(w, `{"total_count": 1, "repositories": [{"full_name": "org/%s"}]}`, token)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	t.Setenv(GithubEnterpriseUrlEnvName, server.URL)
+	installationTokens = newInstallationTokenCache()
+
+	// The token created before the limit got low is used, no more tokens are created
+	installations, _, err := getAppInstallations("12345", privateKeyPem)
+	var partialErr *PartialInstallationsError
+	if !errors.As(err, &partialErr) || partialErr.RateLimit == nil {
+		t.Fatalf("getAppInstallations() error = %v, want partial installations error with rate limit", err)
+	}
+	if len(partialErr.Failed) != 0 || partialErr.RateLimit.Remaining != 50 || !partialErr.RateLimit.Reset.Equal(reset) {
+		t.Errorf("getAppInstallations() error = %v, want no failed installations and 50 remaining requests", err)
+	}
+	if len(installations) != 1 || installations[0].Token != "token-1" {
+		t.Errorf("getAppInstallations() = %v, want installation 1", installations)
+	}
+	if tokenRequests["1"] != 1 || tokenRequests["2"] != 0 || tokenRequests["3"] != 0 {
+		t.Errorf("token requests = %v, want only the token of installation 1", tokenRequests)
+	}
+
+	// Installations with cached tokens are listed even if the limit is low already
+	installationsRemaining = 50
+	installations, _, err = getAppInstallations("12345", privateKeyPem)
+	if !errors.As(err, &partialErr) || partialErr.RateLimit == nil {
+		t.Fatalf("getAppInstallations() error = %v, want partial installations error with rate limit", err)
+	}
+	if len(installations) != 1 || installations[0].Token != "token-1" {
+		t.Errorf("getAppInstallations() = %v, want installation 1", installations)
+	}
+	if tokenRequests["1"] != 1 || tokenRequests["2"] != 0 || tokenRequests["3"] != 0 {
+		t.Errorf("token requests = %v, want no new tokens", tokenRequests)
+	}
+}
+
+func TestGetRepositoriesFromClientListsAllPages(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/installation/repositories", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("per_page") != "100" {
+			t.Errorf("repositories requested with page size %s, want 100", r.URL.Query().Get("per_page"))
+		}
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if page == 0 {
+			page = 1
+		}
+		if page < 3 {
+			w.Header().Set("Link", fmt.Sprintf(`<%s?per_page=100&page=%d>; rel="next"`, r.URL.Path, page+1))
+		}
+		var repositories []string
+		for i := 0; i < 100 && (page-1)*100+i < 250; i++ {
+			repositories = append(repositories, fmt.Sprintf(`{"full_name": "org/repo%d"}`, (page-1)*100+i))
+		}
+		fmt.Fprintf(w, `{"total_count": 250, "repositories": [%s]}`, strings.Join(repositories, ","))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	t.Setenv(GithubEnterpriseUrlEnvName, server.URL)
+
+	repositories, err := getRepositoriesFromClient(newGithubClient("token"))
+	if err != nil {
+		t.Fatalf("getRepositoriesFromClient() error = %v", err)
+	}
+	if len(repositories) != 250 || repositories[249].GetFullName() != "org/repo249" {
+		t.Errorf("getRepositoriesFromClient() returned %d repositories, want all 250", len(repositories))
+	}
+}
+
+func TestCreateInstallationTokenForRepositories(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	privateKeyPem := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(privateKey)})
+
+	var options github.InstallationTokenOptions
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/app/installations/7/access_tokens", func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&options); err != nil {
+			t.Errorf("failed to decode token options: %v", err)
+		}
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"token": "scoped-token", "expires_at": "%s"}`, time.Now().Add(time.Hour).Format(time.RFC3339))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	t.Setenv(GithubEnterpriseUrlEnvName, server.URL)
+
+	token, err := createInstallationTokenForRepositories("12345", privateKeyPem, 7, []int64{10, 20})
+	if err != nil {
+		t.Fatalf("createInstallationTokenForRepositories() error = %v", err)
+	}
+	if token != "scoped-token" {
+		t.Errorf("createInstallationTokenForRepositories() = %s, want scoped-token", token)
+	}
+	if want := []int64{10, 20}; !reflect.DeepEqual(options.RepositoryIDs, want) {
+		t.Errorf("token repository IDs = %v, want %v", options.RepositoryIDs, want)
+	}
+	if options.Permissions == nil || options.Permissions.GetContents() != "write" || options.Permissions.GetAdministration() != "" || options.Permissions.GetWorkflows() != "" {
+		t.Errorf("token permissions = %v, want only the permissions renovate needs", options.Permissions)
+	}
+}
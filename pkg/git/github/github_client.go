@@ -136,6 +136,40 @@ func (g *GithubClient) EnsurePaCMergeRequest(repoUrl string, d *gp.MergeRequestD
 	}
 }
 
+// PushPaCConfiguration commits Pipelines as Code configuration directly into the base branch, without a merge request.
+// Returns web URL of the repository state at the pushed commit.
+// If there is no error and web URL is empty, it means that the push is not needed (base branch is up to date).
+func (g *GithubClient) PushPaCConfiguration(repoUrl string, d *gp.MergeRequestData) (webUrl string, err error) {
+	owner, repository := getOwnerAndRepoFromUrl(repoUrl)
+
+	// Fallback to the default branch if base branch is not set
+	if d.BaseBranchName == "" {
+		baseBranch, err := g.getDefaultBranch(owner, repository)
+		if err != nil {
+			return "", err
+		}
+		d.BaseBranchName = baseBranch
+	}
+
+	upToDate, err := g.filesUpToDate(owner, repository, d.BaseBranchName, d.Files)
+	if err != nil {
+		return "", err
+	}
+	if upToDate {
+		// Nothing to do, the configuration is alredy in the base branch of the repository
+		return "", nil
+	}
+
+	branchRef, err := g.getBranch(owner, repository, d.BaseBranchName)
+	if err != nil {
+		return "", err
+	}
+	if err := g.addCommitToBranch(owner, repository, d.AuthorName, d.AuthorEmail, d.CommitMessage, d.Files, branchRef); err != nil {
+		return "", err
+	}
+	return g.GetBrowseRepositoryAtShaLink(repoUrl, *branchRef.Object.SHA), nil
+}
+
 // UndoPaCMergeRequest creates or updates existing Pipelines as Code configuration removal merge request
 func (g *GithubClient) UndoPaCMergeRequest(repoUrl string, d *gp.MergeRequestData) (webUrl string, err error) {
 	owner, repository := getOwnerAndRepoFromUrl(repoUrl)
@@ -383,7 +417,7 @@ func newGithubClient(accessToken string) *GithubClient {
 	)
 	tc := oauth2.NewClient(gh.ctx, ts)
 
-	gh.client = github.NewClient(tc)
+	gh.client = newGoGithubClient(tc)
 
 	return gh
 }
@@ -396,7 +430,7 @@ func newGithubClientWithBasicAuth(username, password string) *GithubClient {
 		Username: strings.TrimSpace(username),
 		Password: strings.TrimSpace(password),
 	}
-	gh.client = github.NewClient(tp.Client())
+	gh.client = newGoGithubClient(tp.Client())
 
 	return gh
 }
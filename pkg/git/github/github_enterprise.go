@@ -0,0 +1,70 @@
+/*
+Copyright 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/google/go-github/v45/github"
+
+	"github.com/konflux-ci/build-service/pkg/common"
+)
+
+const (
+	// GithubEnterpriseUrlEnvName is the name of the environment variable that contains the URL of
+	// GitHub Enterprise Server instance, e.g. https://github.umbrella.com
+	// If not set, github.com is used.
+	GithubEnterpriseUrlEnvName = "GITHUB_ENTERPRISE_URL"
+
+	githubHost = "github.com"
+)
+
+// getEnterpriseUrl returns the configured GitHub Enterprise Server URL without trailing slash or empty string.
+func getEnterpriseUrl() string {
+	return strings.TrimSuffix(strings.TrimSpace(common.GetConfigValue(GithubEnterpriseUrlEnvName)), "/")
+}
+
+// GetHost returns the host of the GitHub instance the application works with.
+func GetHost() string {
+	if enterpriseUrl := getEnterpriseUrl(); enterpriseUrl != "" {
+		if u, err := url.Parse(enterpriseUrl); err == nil && u.Host != "" {
+			return u.Host
+		}
+	}
+	return githubHost
+}
+
+// GetAPIEndpoint returns the REST API endpoint of the GitHub instance the application works with.
+func GetAPIEndpoint() string {
+	if enterpriseUrl := getEnterpriseUrl(); enterpriseUrl != "" {
+		return enterpriseUrl + "/api/v3/"
+	}
+	return "https://api.github.com/"
+}
+
+// newGoGithubClient creates GitHub API client for github.com or for GitHub Enterprise Server, if configured.
+func newGoGithubClient(httpClient *http.Client) *github.Client {
+	if enterpriseUrl := getEnterpriseUrl(); enterpriseUrl != "" {
+		client, err := github.NewEnterpriseClient(enterpriseUrl, enterpriseUrl, httpClient)
+		if err == nil {
+			return client
+		}
+	}
+	return github.NewClient(httpClient)
+}
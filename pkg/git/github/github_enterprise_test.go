@@ -0,0 +1,69 @@
+/*
+Copyright 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestGithubEnterpriseConfiguration(t *testing.T) {
+	tests := []struct {
+		name            string
+		enterpriseUrl   string
+		wantHost        string
+		wantAPIEndpoint string
+		wantBaseUrl     string
+	}{
+		{
+			name:            "should use github.com if enterprise URL is not set",
+			enterpriseUrl:   "",
+			wantHost:        "github.com",
+			wantAPIEndpoint: "https://api.github.com/",
+			wantBaseUrl:     "https://api.github.com/",
+		},
+		{
+			name:            "should use GitHub Enterprise Server",
+			enterpriseUrl:   "https://github.umbrella.com",
+			wantHost:        "github.umbrella.com",
+			wantAPIEndpoint: "https://github.umbrella.com/api/v3/",
+			wantBaseUrl:     "https://github.umbrella.com/api/v3/",
+		},
+		{
+			name:            "should use GitHub Enterprise Server if URL has trailing slash",
+			enterpriseUrl:   "https://github.umbrella.com/",
+			wantHost:        "github.umbrella.com",
+			wantAPIEndpoint: "https://github.umbrella.com/api/v3/",
+			wantBaseUrl:     "https://github.umbrella.com/api/v3/",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(GithubEnterpriseUrlEnvName, tt.enterpriseUrl)
+			if got := GetHost(); got != tt.wantHost {
+				t.Errorf("GetHost() = %v, want %v", got, tt.wantHost)
+			}
+			if got := GetAPIEndpoint(); got != tt.wantAPIEndpoint {
+				t.Errorf("GetAPIEndpoint() = %v, want %v", got, tt.wantAPIEndpoint)
+			}
+			if got := newGoGithubClient(http.DefaultClient).BaseURL.String(); got != tt.wantBaseUrl {
+				t.Errorf("newGoGithubClient() base URL = %v, want %v", got, tt.wantBaseUrl)
+			}
+		})
+	}
+}
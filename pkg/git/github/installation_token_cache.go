@@ -0,0 +1,84 @@
+/*
+Copyright 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"sync"
+	"time"
+)
+
+// Cached installation tokens are reused only if they stay valid at least this long,
+// so renovate jobs created with them don't lose access in the middle of the run.
+// GitHub installation tokens expire one hour after they are created.
+const MinInstallationTokenValidity = 30 * time.Minute
+
+type installationTokenKey struct {
+	appId          int64
+	installationId int64
+}
+
+type cachedInstallationToken struct {
+	token     string
+	expiresAt time.Time
+}
+
+// installationTokenCache keeps the installation tokens created by the listing of App installations,
+// so the following listings don't create a new token for every installation.
+type installationTokenCache struct {
+	mu     sync.Mutex
+	tokens map[installationTokenKey]cachedInstallationToken
+}
+
+func newInstallationTokenCache() *installationTokenCache {
+	return &installationTokenCache{tokens: map[installationTokenKey]cachedInstallationToken{}}
+}
+
+var installationTokens = newInstallationTokenCache()
+
+// get returns the cached token of the installation if it's valid long enough.
+func (c *installationTokenCache) get(key installationTokenKey) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cached, ok := c.tokens[key]
+	if !ok || time.Until(cached.expiresAt) < MinInstallationTokenValidity {
+		return "", false
+	}
+	return cached.token, true
+}
+
+// put caches the token of the installation. Tokens without expiration time are not cached.
+// Tokens of other installations which are no longer usable are dropped.
+func (c *installationTokenCache) put(key installationTokenKey, token string, expiresAt time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for cachedKey, cached := range c.tokens {
+		if time.Until(cached.expiresAt) < MinInstallationTokenValidity {
+			delete(c.tokens, cachedKey)
+		}
+	}
+	if expiresAt.IsZero() {
+		return
+	}
+	c.tokens[key] = cachedInstallationToken{token: token, expiresAt: expiresAt}
+}
+
+// delete drops the cached token of the installation, e.g. after it was rejected.
+func (c *installationTokenCache) delete(key installationTokenKey) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.tokens, key)
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2023 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"testing"
+	"time"
+)
+
+func TestInstallationTokenCache(t *testing.T) {
+	cache := newInstallationTokenCache()
+	validKey := installationTokenKey{appId: 1, installationId: 10}
+	expiringKey := installationTokenKey{appId: 1, installationId: 11}
+	otherAppKey := installationTokenKey{appId: 2, installationId: 10}
+
+	cache.put(validKey, "valid", time.Now().Add(time.Hour))
+	cache.put(expiringKey, "expiring", time.Now().Add(MinInstallationTokenValidity-time.Minute))
+	cache.put(otherAppKey, "no-expiration", time.Time{})
+
+	if token, ok := cache.get(validKey); !ok || token != "valid" {
+		t.Errorf("get() = %q, %v, want the valid token", token, ok)
+	}
+	if token, ok := cache.get(expiringKey); ok {
+		t.Errorf("get() = %q, want no token expiring soon", token)
+	}
+	if token, ok := cache.get(otherAppKey); ok {
+		t.Errorf("get() = %q, want no token without expiration", token)
+	}
+
+	// Tokens which are no longer usable are dropped once another token is cached
+	cache.put(otherAppKey, "other", time.Now().Add(time.Hour))
+	if _, ok := cache.tokens[expiringKey]; ok {
+		t.Errorf("expiring token is still cached")
+	}
+
+	cache.delete(validKey)
+	if token, ok := cache.get(validKey); ok {
+		t.Errorf("get() = %q, want no deleted token", token)
+	}
+	if token, ok := cache.get(otherAppKey); !ok || token != "other" {
+		t.Errorf("get() = %q, %v, want the token of the other App", token, ok)
+	}
+}
@@ -8,3 +8,14 @@ import (
 type ConfigReader interface {
 	GetConfig(ctx context.Context) (githubAppIdStr string, appPrivateKeyPem []byte, err error)
 }
+
+// Config holds configuration of a single GitHub App.
+type Config struct {
+	AppId         string
+	PrivateKeyPem []byte
+}
+
+// MultiConfigReader is an interface for reading configurations of all GitHub Apps available for the Build Service.
+type MultiConfigReader interface {
+	GetConfigs(ctx context.Context) ([]Config, error)
+}
@@ -120,6 +120,44 @@ func (g *GitlabClient) EnsurePaCMergeRequest(repoUrl string, d *gp.MergeRequestD
 	}
 }
 
+// PushPaCConfiguration commits Pipelines as Code configuration directly into the base branch, without a merge request.
+// Returns web URL of the repository state at the pushed commit.
+// If there is no error and web URL is empty, it means that the push is not needed (base branch is up to date).
+func (g *GitlabClient) PushPaCConfiguration(repoUrl string, d *gp.MergeRequestData) (webUrl string, err error) {
+	projectPath, err := getProjectPathFromRepoUrl(repoUrl)
+	if err != nil {
+		return "", err
+	}
+
+	// Fallback to the default branch if base branch is not set
+	if d.BaseBranchName == "" {
+		baseBranch, err := g.getDefaultBranch(projectPath)
+		if err != nil {
+			return "", err
+		}
+		d.BaseBranchName = baseBranch
+	}
+
+	pacConfigurationUpToDate, err := g.filesUpToDate(projectPath, d.BaseBranchName, d.Files)
+	if err != nil {
+		return "", err
+	}
+	if pacConfigurationUpToDate {
+		// Nothing to do, the configuration is alredy in the base branch of the repository
+		return "", nil
+	}
+
+	if err := g.commitFilesIntoBranch(projectPath, d.BaseBranchName, d.CommitMessage, d.AuthorName, d.AuthorEmail, d.Files); err != nil {
+		return "", err
+	}
+
+	sha, err := g.GetBranchSha(repoUrl, d.BaseBranchName)
+	if err != nil {
+		return "", err
+	}
+	return g.GetBrowseRepositoryAtShaLink(repoUrl, sha), nil
+}
+
 // UndoPaCMergeRequest creates or updates existing Pipelines as Code configuration removal merge request.
 // Returns the merge request web URL.
 // If there is no error and web URL is empty, it means that the merge request is not needed (the configuraton has already been deleted).
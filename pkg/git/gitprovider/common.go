@@ -16,14 +16,14 @@ limitations under the License.
 
 package gitprovider
 
-import "os"
+import "github.com/konflux-ci/build-service/pkg/common"
 
 const (
 	PipelinesAsCodeWebhhokInsecureSslEnvVar = "PAC_WEBHOOK_INSECURE_SSL"
 )
 
 func IsInsecureSSL() bool {
-	if insecureSSLVal := os.Getenv(PipelinesAsCodeWebhhokInsecureSslEnvVar); insecureSSLVal != "" {
+	if insecureSSLVal := common.GetConfigValue(PipelinesAsCodeWebhhokInsecureSslEnvVar); insecureSSLVal != "" {
 		disableValues := []string{"1", "true", "True"}
 		for _, val := range disableValues {
 			if insecureSSLVal == val {
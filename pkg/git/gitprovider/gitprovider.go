@@ -24,6 +24,11 @@ type GitProviderClient interface {
 	// If there is no error and web URL is empty, it means that the merge request is not needed (main branch is up to date).
 	EnsurePaCMergeRequest(repoUrl string, data *MergeRequestData) (webUrl string, err error)
 
+	// PushPaCConfiguration commits Pipelines as Code configuration directly into the base branch, without a merge request.
+	// Returns web URL of the repository state at the pushed commit.
+	// If there is no error and web URL is empty, it means that the push is not needed (base branch is up to date).
+	PushPaCConfiguration(repoUrl string, data *MergeRequestData) (webUrl string, err error)
+
 	// UndoPaCMergeRequest creates or updates existing Pipelines as Code configuration removal merge request.
 	// Returns the merge request web URL.
 	// If there is no error and web URL is empty, it means that the merge request is not needed (the configuraton has already been deleted).
@@ -22,6 +22,9 @@ import (
 
 	"github.com/konflux-ci/build-service/pkg/boerrors"
 	. "github.com/konflux-ci/build-service/pkg/common"
+	"github.com/konflux-ci/build-service/pkg/featuregates"
+	"github.com/konflux-ci/build-service/pkg/git/bitbucket"
+	"github.com/konflux-ci/build-service/pkg/git/gitea"
 	"github.com/konflux-ci/build-service/pkg/git/github"
 	"github.com/konflux-ci/build-service/pkg/git/gitlab"
 	"github.com/konflux-ci/build-service/pkg/git/gitprovider"
@@ -111,6 +114,10 @@ func createGitClient(gitClientConfig GitClientConfig) (gitprovider.GitProviderCl
 		}
 
 	case "gitlab":
+		if !featuregates.Enabled(featuregates.GitLab) {
+			return nil, boerrors.NewBuildOpError(boerrors.EGitProviderDisabled,
+				fmt.Errorf("failed to create git client: GitLab support is disabled by %s feature gate", featuregates.GitLab))
+		}
 		if isAppUsed {
 			return nil, fmt.Errorf("GitLab does not have applications")
 		}
@@ -132,7 +139,45 @@ func createGitClient(gitClientConfig GitClientConfig) (gitprovider.GitProviderCl
 			fmt.Errorf("failed to create git client: unsupported secret data. Expected username/password or token"))
 
 	case "bitbucket":
-		return nil, boerrors.NewBuildOpError(boerrors.EUnknownGitProvider, fmt.Errorf("git provider %s is not supported", gitProvider))
+		if isAppUsed {
+			return nil, fmt.Errorf("Bitbucket does not have applications")
+		}
+		if usernameExists && passwordExists {
+			// Bitbucket Cloud app password
+			return bitbucket.NewBitbucketClientWithBasicAuth(string(username), string(password)), nil
+		}
+		if !usernameExists && passwordExists {
+			// Repository, project or workspace access token
+			return bitbucket.NewBitbucketClient(string(password)), nil
+		}
+		if sshKeyExists {
+			return nil, boerrors.NewBuildOpError(boerrors.EBitbucketSecretTypeNotSupported,
+				fmt.Errorf("failed to create git client: Bitbucket ssh key authentication not yet supported"))
+		}
+		return nil, boerrors.NewBuildOpError(boerrors.EBitbucketSecretTypeNotSupported,
+			fmt.Errorf("failed to create git client: unsupported secret data. Expected username/app password or token"))
+
+	case "gitea":
+		if isAppUsed {
+			return nil, fmt.Errorf("Gitea does not have applications")
+		}
+		baseUrl, err := gitea.GetBaseUrl(gitClientConfig.RepoUrl)
+		if err != nil {
+			return nil, err
+		}
+		if usernameExists && passwordExists {
+			return gitea.NewGiteaClientWithBasicAuth(string(username), string(password), baseUrl), nil
+		}
+		if !usernameExists && passwordExists {
+			return gitea.NewGiteaClient(string(password), baseUrl), nil
+		}
+		if sshKeyExists {
+			return nil, boerrors.NewBuildOpError(boerrors.EGiteaSecretTypeNotSupported,
+				fmt.Errorf("failed to create git client: Gitea ssh key authentication not yet supported"))
+		}
+		return nil, boerrors.NewBuildOpError(boerrors.EGiteaSecretTypeNotSupported,
+			fmt.Errorf("failed to create git client: unsupported secret data. Expected username/password or token"))
+
 	default:
 		return nil, boerrors.NewBuildOpError(boerrors.EUnknownGitProvider, fmt.Errorf("git provider %s is not supported", gitProvider))
 	}
@@ -20,7 +20,11 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/konflux-ci/build-service/pkg/boerrors"
 	. "github.com/konflux-ci/build-service/pkg/common"
+	"github.com/konflux-ci/build-service/pkg/featuregates"
+	"github.com/konflux-ci/build-service/pkg/git/bitbucket"
+	"github.com/konflux-ci/build-service/pkg/git/gitea"
 	"github.com/konflux-ci/build-service/pkg/git/github"
 	"github.com/konflux-ci/build-service/pkg/git/gitlab"
 )
@@ -51,6 +55,22 @@ func TestGetContainerImageRepository(t *testing.T) {
 			t.Errorf("should not be invoked")
 			return nil, nil
 		}
+		bitbucket.NewBitbucketClient = func(accessToken string) *bitbucket.BitbucketClient {
+			t.Errorf("should not be invoked")
+			return nil
+		}
+		bitbucket.NewBitbucketClientWithBasicAuth = func(username, appPassword string) *bitbucket.BitbucketClient {
+			t.Errorf("should not be invoked")
+			return nil
+		}
+		gitea.NewGiteaClient = func(accessToken, baseUrl string) *gitea.GiteaClient {
+			t.Errorf("should not be invoked")
+			return nil
+		}
+		gitea.NewGiteaClientWithBasicAuth = func(username, password, baseUrl string) *gitea.GiteaClient {
+			t.Errorf("should not be invoked")
+			return nil
+		}
 	}
 
 	repoUrl := "https://github.com/org/repository"
@@ -295,6 +315,119 @@ func TestGetContainerImageRepository(t *testing.T) {
 			allowConstructors: func() {},
 			expectError:       true,
 		},
+		{
+			name: "should create Bitbucket client from token",
+			gitClientConfig: GitClientConfig{
+				PacSecretData: map[string][]byte{
+					"password": []byte("token"),
+				},
+				GitProvider:               "bitbucket",
+				RepoUrl:                   "https://bitbucket.org/my-workspace/my-repo",
+				IsAppInstallationExpected: true,
+			},
+			allowConstructors: func() {
+				bitbucket.NewBitbucketClient = func(accessToken string) *bitbucket.BitbucketClient {
+					if accessToken != "token" {
+						t.Errorf("Expected to get token: token, got %s", accessToken)
+					}
+					return &bitbucket.BitbucketClient{}
+				}
+			},
+			expectError: false,
+		},
+		{
+			name: "should create Bitbucket client from username and app password",
+			gitClientConfig: GitClientConfig{
+				PacSecretData: map[string][]byte{
+					"username": []byte("user"),
+					"password": []byte("app-password"),
+				},
+				GitProvider:               "bitbucket",
+				RepoUrl:                   "https://bitbucket.org/my-workspace/my-repo",
+				IsAppInstallationExpected: true,
+			},
+			allowConstructors: func() {
+				bitbucket.NewBitbucketClientWithBasicAuth = func(username, appPassword string) *bitbucket.BitbucketClient {
+					if username != "user" || appPassword != "app-password" {
+						t.Errorf("Expected to get user/app-password credentials, got %s/%s", username, appPassword)
+					}
+					return &bitbucket.BitbucketClient{}
+				}
+			},
+			expectError: false,
+		},
+		{
+			name: "should not create Bitbucket client from ssh key",
+			gitClientConfig: GitClientConfig{
+				PacSecretData: map[string][]byte{
+					"ssh-privatekey": []byte("private key"),
+				},
+				GitProvider:               "bitbucket",
+				RepoUrl:                   "https://bitbucket.org/my-workspace/my-repo",
+				IsAppInstallationExpected: true,
+			},
+			allowConstructors: func() {},
+			expectError:       true,
+		},
+		{
+			name: "should create Gitea client from token",
+			gitClientConfig: GitClientConfig{
+				PacSecretData: map[string][]byte{
+					"password": []byte("token"),
+				},
+				GitProvider:               "gitea",
+				RepoUrl:                   "https://gitea.mydomain.com/my-org/my-repo",
+				IsAppInstallationExpected: true,
+			},
+			allowConstructors: func() {
+				gitea.NewGiteaClient = func(accessToken, baseUrl string) *gitea.GiteaClient {
+					expectedBaseUrl := "https://gitea.mydomain.com/api/v1/"
+					if baseUrl != expectedBaseUrl {
+						t.Errorf("Expected to get baseUrl: %s, got %s", expectedBaseUrl, baseUrl)
+					}
+					if accessToken != "token" {
+						t.Errorf("Expected to get token: token, got %s", accessToken)
+					}
+					return &gitea.GiteaClient{}
+				}
+			},
+			expectError: false,
+		},
+		{
+			name: "should create Gitea client from username and password",
+			gitClientConfig: GitClientConfig{
+				PacSecretData: map[string][]byte{
+					"username": []byte("user"),
+					"password": []byte("pass"),
+				},
+				GitProvider:               "gitea",
+				RepoUrl:                   "https://codeberg.org/my-org/my-repo",
+				IsAppInstallationExpected: true,
+			},
+			allowConstructors: func() {
+				gitea.NewGiteaClientWithBasicAuth = func(username, password, baseUrl string) *gitea.GiteaClient {
+					expectedBaseUrl := "https://codeberg.org/api/v1/"
+					if baseUrl != expectedBaseUrl {
+						t.Errorf("Expected to get baseUrl: %s, got %s", expectedBaseUrl, baseUrl)
+					}
+					return &gitea.GiteaClient{}
+				}
+			},
+			expectError: false,
+		},
+		{
+			name: "should not create Gitea client from ssh key",
+			gitClientConfig: GitClientConfig{
+				PacSecretData: map[string][]byte{
+					"ssh-privatekey": []byte("private key"),
+				},
+				GitProvider:               "gitea",
+				RepoUrl:                   "https://gitea.mydomain.com/my-org/my-repo",
+				IsAppInstallationExpected: true,
+			},
+			allowConstructors: func() {},
+			expectError:       true,
+		},
 		{
 			name: "should not create BitBucket client",
 			gitClientConfig: GitClientConfig{
@@ -352,3 +485,23 @@ func TestGetContainerImageRepository(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateGitClientWithDisabledGitLab(t *testing.T) {
+	featuregates.SetOverrides(map[string]bool{string(featuregates.GitLab): false})
+	defer featuregates.SetOverrides(nil)
+	gitlab.NewGitlabClient = func(accessToken, baseUrl string) (*gitlab.GitlabClient, error) {
+		t.Errorf("should not be invoked")
+		return nil, nil
+	}
+
+	_, err := createGitClient(GitClientConfig{
+		PacSecretData: map[string][]byte{
+			"password": []byte("token"),
+		},
+		GitProvider: "gitlab",
+		RepoUrl:     "https://gitlab.com/my-org/my-repo",
+	})
+	if !boerrors.IsBuildOpError(err, boerrors.EGitProviderDisabled) {
+		t.Errorf("expected git provider disabled error, got: %v", err)
+	}
+}
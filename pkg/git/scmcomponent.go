@@ -7,15 +7,26 @@ import (
 
 const InternalDefaultBranch = "$DEFAULTBRANCH"
 
+// AzureDevOpsSshHost is the host of Azure DevOps SSH remote URLs, e.g. git@ssh.dev.azure.com:v3/organization/project/repository
+const AzureDevOpsSshHost = "ssh.dev.azure.com"
+
 type ScmComponent struct {
 	namespaceName string
 	componentName string
 	repositoryUrl *url.URL
 	branch        string
 	platform      string
+	schedule      []string
+	automerge     *bool
+	gitAuthor     string
+	// Other Components of the repository are not renovated, so its stale branches must be kept
+	keepStaleBranches bool
 }
 
 func NewScmComponent(platform string, repositoryUrl string, revision string, componentName string, namespaceName string) (*ScmComponent, error) {
+	if platform == "azure" {
+		repositoryUrl = azureSshUrlToHttps(repositoryUrl)
+	}
 	url, err := url.Parse(strings.TrimSuffix(strings.TrimSuffix(repositoryUrl, ".git"), "/"))
 	if err != nil {
 		return nil, err
@@ -28,8 +39,31 @@ func NewScmComponent(platform string, repositoryUrl string, revision string, com
 	return &ScmComponent{platform: platform, branch: branch, repositoryUrl: url, componentName: componentName, namespaceName: namespaceName}, nil
 }
 
+// azureSshUrlToHttps converts Azure DevOps SSH remote URL into the https form,
+// e.g. git@ssh.dev.azure.com:v3/umbrellacorp/project/repo to https://dev.azure.com/umbrellacorp/project/_git/repo
+func azureSshUrlToHttps(repositoryUrl string) string {
+	path, isSsh := strings.CutPrefix(repositoryUrl, "git@"+AzureDevOpsSshHost+":v3/")
+	if !isSsh {
+		return repositoryUrl
+	}
+	parts := strings.Split(strings.TrimSuffix(path, "/"), "/")
+	if len(parts) != 3 {
+		return repositoryUrl
+	}
+	return "https://dev.azure.com/" + parts[0] + "/" + parts[1] + "/_git/" + parts[2]
+}
+
+// Repository returns the repository name in the form used by the git platform API.
+// Azure DevOps repository URLs have the organization/project/_git/repository form,
+// the organization is a part of the API endpoint, so only project/repository is returned.
 func (s ScmComponent) Repository() string {
-	return strings.Trim(s.repositoryUrl.Path, "/")
+	path := strings.Trim(s.repositoryUrl.Path, "/")
+	if s.platform == "azure" {
+		if parts := strings.Split(path, "/"); len(parts) == 4 && parts[2] == "_git" {
+			return parts[1] + "/" + parts[3]
+		}
+	}
+	return path
 }
 
 func (s ScmComponent) Platform() string {
@@ -50,6 +84,17 @@ func (s ScmComponent) RepositoryHost() string {
 	return s.repositoryUrl.Host
 }
 
+// EndpointHost returns the host the platform API endpoint is built for.
+// Azure DevOps API endpoints are scoped by organization, so the organization is appended to the host.
+func (s ScmComponent) EndpointHost() string {
+	if s.platform == "azure" {
+		if organization, _, found := strings.Cut(strings.Trim(s.repositoryUrl.Path, "/"), "/"); found {
+			return s.repositoryUrl.Host + "/" + organization
+		}
+	}
+	return s.repositoryUrl.Host
+}
+
 func (s ScmComponent) ComponentName() string {
 	return s.componentName
 }
@@ -58,6 +103,56 @@ func (s ScmComponent) NamespaceName() string {
 	return s.namespaceName
 }
 
+// Schedule returns the renovate schedule configured for the component, if any.
+func (s ScmComponent) Schedule() []string {
+	return s.schedule
+}
+
+// SetSchedule sets the renovate schedule of the component, see https://docs.renovatebot.com/configuration-options/#schedule
+func (s *ScmComponent) SetSchedule(schedule []string) {
+	s.schedule = schedule
+}
+
+// Automerge returns the renovate automerge setting of the component, nil if the global setting applies.
+func (s ScmComponent) Automerge() *bool {
+	return s.automerge
+}
+
+// SetAutomerge sets the renovate automerge setting of the component, see https://docs.renovatebot.com/configuration-options/#automerge
+func (s *ScmComponent) SetAutomerge(automerge *bool) {
+	s.automerge = automerge
+}
+
+// GitAuthor returns the author of renovate commits configured for the namespace of the component, empty if the global author applies.
+func (s ScmComponent) GitAuthor() string {
+	return s.gitAuthor
+}
+
+// SetGitAuthor sets the author of renovate commits in the component repository, see https://docs.renovatebot.com/configuration-options/#gitauthor
+func (s *ScmComponent) SetGitAuthor(gitAuthor string) {
+	s.gitAuthor = gitAuthor
+}
+
+// KeepStaleBranches returns whether the stale renovate branches of the component repository must be kept.
+func (s ScmComponent) KeepStaleBranches() bool {
+	return s.keepStaleBranches
+}
+
+// SetKeepStaleBranches disables pruning of stale renovate branches in the component repository,
+// see https://docs.renovatebot.com/configuration-options/#prunestalebranches
+func (s *ScmComponent) SetKeepStaleBranches(keepStaleBranches bool) {
+	s.keepStaleBranches = keepStaleBranches
+}
+
+// ComponentUrlToComponentsMap groups the components by their repository URL.
+func ComponentUrlToComponentsMap(components []*ScmComponent) map[string][]*ScmComponent {
+	componentUrlToComponentsMap := make(map[string][]*ScmComponent)
+	for _, component := range components {
+		componentUrlToComponentsMap[component.RepositoryUrlString()] = append(componentUrlToComponentsMap[component.RepositoryUrlString()], component)
+	}
+	return componentUrlToComponentsMap
+}
+
 func ComponentUrlToBranchesMap(components []*ScmComponent) map[string][]string {
 	componentUrlToBranchesMap := make(map[string][]string)
 	for _, component := range components {
@@ -88,10 +183,12 @@ func PlatformToComponentMap(components []*ScmComponent) map[string][]*ScmCompone
 	}
 	return componentNamespaceNameMap
 }
+
+// HostToComponentMap groups components by the host of the platform API endpoint, see EndpointHost.
 func HostToComponentMap(components []*ScmComponent) map[string][]*ScmComponent {
 	componentNamespaceNameMap := make(map[string][]*ScmComponent)
 	for _, component := range components {
-		componentNamespaceNameMap[component.RepositoryHost()] = append(componentNamespaceNameMap[component.RepositoryHost()], component)
+		componentNamespaceNameMap[component.EndpointHost()] = append(componentNamespaceNameMap[component.EndpointHost()], component)
 	}
 	return componentNamespaceNameMap
 }
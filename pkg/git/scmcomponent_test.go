@@ -0,0 +1,67 @@
+package git
+
+import (
+	"testing"
+)
+
+func TestScmComponentRepository(t *testing.T) {
+
+	tests := []struct {
+		name             string
+		platform         string
+		repositoryUrl    string
+		wantRepository   string
+		wantEndpointHost string
+	}{
+		{
+			name:             "Github repository",
+			platform:         "github",
+			repositoryUrl:    "https://github.com/umbrellacorp/devfile-sample-go-basic.git",
+			wantRepository:   "umbrellacorp/devfile-sample-go-basic",
+			wantEndpointHost: "github.com",
+		},
+		{
+			name:             "Gitlab repository in subgroup",
+			platform:         "gitlab",
+			repositoryUrl:    "https://gitlab.com/umbrellacorp/samples/devfile-sample-go-basic/",
+			wantRepository:   "umbrellacorp/samples/devfile-sample-go-basic",
+			wantEndpointHost: "gitlab.com",
+		},
+		{
+			name:             "Azure DevOps repository",
+			platform:         "azure",
+			repositoryUrl:    "https://dev.azure.com/umbrellacorp/samples/_git/devfile-sample-go-basic",
+			wantRepository:   "samples/devfile-sample-go-basic",
+			wantEndpointHost: "dev.azure.com/umbrellacorp",
+		},
+		{
+			name:             "Azure DevOps repository with user in URL",
+			platform:         "azure",
+			repositoryUrl:    "https://umbrellacorp@dev.azure.com/umbrellacorp/samples/_git/devfile-sample-go-basic",
+			wantRepository:   "samples/devfile-sample-go-basic",
+			wantEndpointHost: "dev.azure.com/umbrellacorp",
+		},
+		{
+			name:             "Azure DevOps repository with SSH URL",
+			platform:         "azure",
+			repositoryUrl:    "git@ssh.dev.azure.com:v3/umbrellacorp/samples/devfile-sample-go-basic",
+			wantRepository:   "samples/devfile-sample-go-basic",
+			wantEndpointHost: "dev.azure.com/umbrellacorp",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			component, err := NewScmComponent(tt.platform, tt.repositoryUrl, "main", "component", "namespace")
+			if err != nil {
+				t.Fatalf("NewScmComponent() error = %v", err)
+			}
+			if got := component.Repository(); got != tt.wantRepository {
+				t.Errorf("Repository() = %v, want %v", got, tt.wantRepository)
+			}
+			if got := component.EndpointHost(); got != tt.wantEndpointHost {
+				t.Errorf("EndpointHost() = %v, want %v", got, tt.wantEndpointHost)
+			}
+		})
+	}
+}
@@ -18,6 +18,7 @@ import (
 	. "github.com/konflux-ci/build-service/pkg/common"
 	"github.com/konflux-ci/build-service/pkg/git"
 	. "github.com/konflux-ci/build-service/pkg/git/credentials"
+	"github.com/konflux-ci/build-service/pkg/git/githubapp"
 	bslices "github.com/konflux-ci/build-service/pkg/slices"
 )
 
@@ -52,6 +53,44 @@ func (k ConfigReader) GetConfig(ctx context.Context) (githubAppIdStr string, app
 	return string(pacSecret.Data[PipelinesAsCodeGithubAppIdKey]), pacSecret.Data[PipelinesAsCodeGithubPrivateKey], err
 }
 
+// GetConfigs returns configurations of the global Pipelines as Code GitHub App and of all additional GitHub Apps
+// defined by secrets labeled with GitHubAppSecretLabel in Build Service namespace.
+// Apps with the same ID are returned only once.
+func (k ConfigReader) GetConfigs(ctx context.Context) ([]githubapp.Config, error) {
+	log := ctrllog.FromContext(ctx)
+
+	var configs []githubapp.Config
+	githubAppIdStr, appPrivateKeyPem, globalAppErr := k.GetConfig(ctx)
+	if globalAppErr == nil {
+		configs = append(configs, githubapp.Config{AppId: githubAppIdStr, PrivateKeyPem: appPrivateKeyPem})
+	}
+
+	secretList := &corev1.SecretList{}
+	opts := client.ListOption(&client.MatchingLabels{GitHubAppSecretLabel: "true"})
+	if err := k.client.List(ctx, secretList, client.InNamespace(BuildServiceNamespaceName), opts); err != nil {
+		return nil, fmt.Errorf("failed to list GitHub App secrets in %s namespace: %w", BuildServiceNamespaceName, err)
+	}
+	for _, secret := range secretList.Items {
+		if secret.Name == PipelinesAsCodeGitHubAppSecretName {
+			continue
+		}
+		appId := string(secret.Data[PipelinesAsCodeGithubAppIdKey])
+		if _, err := strconv.ParseInt(appId, 10, 64); err != nil {
+			log.Error(err, "failed to parse GitHub application ID", "secret", secret.Name)
+			continue
+		}
+		if len(bslices.Filter(configs, func(config githubapp.Config) bool { return config.AppId == appId })) > 0 {
+			continue
+		}
+		configs = append(configs, githubapp.Config{AppId: appId, PrivateKeyPem: secret.Data[PipelinesAsCodeGithubPrivateKey]})
+	}
+
+	if len(configs) == 0 {
+		return nil, globalAppErr
+	}
+	return configs, nil
+}
+
 // GitCredentialProvider is an implementation of the git.CredentialsProvider that retrieves
 // the git credentials from the Kubernetes secrets
 type GitCredentialProvider struct {
@@ -2,11 +2,16 @@ package k8s
 
 import (
 	"context"
+	"reflect"
 	"testing"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
+	. "github.com/konflux-ci/build-service/pkg/common"
 	. "github.com/konflux-ci/build-service/pkg/git/credentials"
 )
 
@@ -113,3 +118,90 @@ func TestSecretMatching(t *testing.T) {
 		})
 	}
 }
+
+func TestGetGithubAppConfigs(t *testing.T) {
+	appSecret := func(name, appId string, labeled bool) *corev1.Secret {
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: BuildServiceNamespaceName,
+			},
+			Data: map[string][]byte{
+				PipelinesAsCodeGithubAppIdKey:   []byte(appId),
+				PipelinesAsCodeGithubPrivateKey: []byte("private-key-" + appId),
+			},
+		}
+		if labeled {
+			secret.Labels = map[string]string{GitHubAppSecretLabel: "true"}
+		}
+		return secret
+	}
+
+	tests := []struct {
+		name          string
+		secrets       []client.Object
+		expectedIds   []string
+		expectedError bool
+	}{
+		{
+			name:          "should return error if no GitHub App is configured",
+			expectedError: true,
+		},
+		{
+			name:        "should return global GitHub App",
+			secrets:     []client.Object{appSecret(PipelinesAsCodeGitHubAppSecretName, "12345", false)},
+			expectedIds: []string{"12345"},
+		},
+		{
+			name: "should return global and additional GitHub Apps",
+			secrets: []client.Object{
+				appSecret(PipelinesAsCodeGitHubAppSecretName, "12345", false),
+				appSecret("github-app-org1", "23456", true),
+				appSecret("github-app-org2", "34567", true),
+				appSecret("not-github-app", "45678", false),
+			},
+			expectedIds: []string{"12345", "23456", "34567"},
+		},
+		{
+			name: "should return additional GitHub Apps if global one is missing",
+			secrets: []client.Object{
+				appSecret("github-app-org1", "23456", true),
+			},
+			expectedIds: []string{"23456"},
+		},
+		{
+			name: "should skip duplicated and invalid GitHub Apps",
+			secrets: []client.Object{
+				appSecret(PipelinesAsCodeGitHubAppSecretName, "12345", true),
+				appSecret("github-app-org1", "12345", true),
+				appSecret("github-app-org2", "invalid", true),
+			},
+			expectedIds: []string{"12345"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeClient := fake.NewClientBuilder().WithObjects(tt.secrets...).Build()
+			configReader := NewGithubAppConfigReader(fakeClient, fakeClient.Scheme(), record.NewFakeRecorder(10))
+
+			configs, err := configReader.GetConfigs(context.TODO())
+			if tt.expectedError {
+				if err == nil {
+					t.Errorf("GetConfigs() expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("GetConfigs() unexpected error: %v", err)
+			}
+			var ids []string
+			for _, config := range configs {
+				ids = append(ids, config.AppId)
+			}
+			if !reflect.DeepEqual(ids, tt.expectedIds) {
+				t.Errorf("GetConfigs() = %v, want %v", ids, tt.expectedIds)
+			}
+		})
+	}
+}
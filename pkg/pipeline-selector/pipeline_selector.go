@@ -27,14 +27,22 @@ import (
 	devfile "github.com/redhat-appstudio/application-service/cdq-analysis/pkg"
 )
 
+// ProjectFileChecker reports whether the file with the given name is present in the component sources.
+type ProjectFileChecker func(fileName string) (bool, error)
+
 // SelectPipelineForComponent evaluates given list of pipeline selectors against specified component
 // to find the build pipeline for the component.
-// The first match is returned.
-func SelectPipelineForComponent(component *appstudiov1alpha1.Component, selectors []buildappstudiov1alpha1.BuildPipelineSelector) (*tektonapi.PipelineRef, []tektonapi.Param, error) {
+// The selectors are evaluated in the given order, the first of them which has a matching item is used.
+// isProjectFileExist is used to detect the project files referenced by the selectors.
+// If it's nil, the project files are considered missing.
+func SelectPipelineForComponent(component *appstudiov1alpha1.Component, selectors []buildappstudiov1alpha1.BuildPipelineSelector, isProjectFileExist ProjectFileChecker) (*tektonapi.PipelineRef, []tektonapi.Param, error) {
 	selectionParameters, err := getPipelineSelectionParametersForComponent(component)
 	if err != nil {
 		return nil, nil, err
 	}
+	if selectionParameters.ProjectFiles, err = detectProjectFiles(selectors, isProjectFileExist); err != nil {
+		return nil, nil, err
+	}
 
 	for i := range selectors {
 		if buildPipelineRef, buildPipelineAdditionalParams := findMatchingPipeline(selectionParameters, &selectors[i]); buildPipelineRef != nil {
@@ -82,22 +90,63 @@ func getPipelineSelectionParametersForComponent(component *appstudiov1alpha1.Com
 	return parameters, nil
 }
 
+// detectProjectFiles checks which of the project files referenced by the selectors are present in the component sources.
+// The present files are returned as comma separated list.
+// Only the referenced files are checked, so the git repository is not accessed if no selector has the project files condition.
+func detectProjectFiles(selectors []buildappstudiov1alpha1.BuildPipelineSelector, isProjectFileExist ProjectFileChecker) (string, error) {
+	if isProjectFileExist == nil {
+		return "", nil
+	}
+
+	checkedFiles := map[string]bool{}
+	var presentFiles []string
+	for _, selector := range selectors {
+		for _, pipelineSelector := range selector.Spec.Selectors {
+			for _, fileName := range strings.Split(pipelineSelector.WhenConditions.ProjectFiles, ",") {
+				fileName = strings.TrimSpace(fileName)
+				if fileName == "" || checkedFiles[fileName] {
+					continue
+				}
+				checkedFiles[fileName] = true
+
+				exists, err := isProjectFileExist(fileName)
+				if err != nil {
+					return "", err
+				}
+				if exists {
+					presentFiles = append(presentFiles, fileName)
+				}
+			}
+		}
+	}
+	return strings.Join(presentFiles, ","), nil
+}
+
 // findMatchingPipeline evaluates given selectors chain against component parameters.
-// The first match is returned.
+// The match with the highest priority is returned, the first one if several matches have the same priority.
 func findMatchingPipeline(selectionParameters *buildappstudiov1alpha1.WhenCondition, selectors *buildappstudiov1alpha1.BuildPipelineSelector) (*tektonapi.PipelineRef, []tektonapi.Param) {
-	for _, pipelineSelector := range selectors.Spec.Selectors {
+	var matchingSelector *buildappstudiov1alpha1.PipelineSelector
+	for i := range selectors.Spec.Selectors {
+		pipelineSelector := &selectors.Spec.Selectors[i]
+		if matchingSelector != nil && pipelineSelector.Priority <= matchingSelector.Priority {
+			continue
+		}
 		if pipelineConditionsMatchComponentParameters(&pipelineSelector.WhenConditions, selectionParameters) {
-			var pipelineParams []tektonapi.Param
-			for _, param := range pipelineSelector.PipelineParams {
-				pipelineParams = append(pipelineParams, tektonapi.Param{
-					Name:  param.Name,
-					Value: *tektonapi.NewStructuredValues(param.Value),
-				})
-			}
-			return &pipelineSelector.PipelineRef, pipelineParams
+			matchingSelector = pipelineSelector
 		}
 	}
-	return nil, nil
+	if matchingSelector == nil {
+		return nil, nil
+	}
+
+	var pipelineParams []tektonapi.Param
+	for _, param := range matchingSelector.PipelineParams {
+		pipelineParams = append(pipelineParams, tektonapi.Param{
+			Name:  param.Name,
+			Value: *tektonapi.NewStructuredValues(param.Value),
+		})
+	}
+	return &matchingSelector.PipelineRef, pipelineParams
 }
 
 // pipelineConditionsMatchComponentParameters evaluates given pipeline selector against component parameters.
@@ -114,6 +163,10 @@ func pipelineConditionsMatchComponentParameters(pipeline, component *buildappstu
 		return false
 	}
 
+	if pipeline.ProjectFiles != "" && !pipelineMatchesComponentProjectFiles(pipeline.ProjectFiles, component.ProjectFiles) {
+		return false
+	}
+
 	if pipeline.ComponentName != "" && !pipelineMatchesComponentCondition(pipeline.ComponentName, component.ComponentName) {
 		return false
 	}
@@ -144,6 +197,21 @@ func pipelineMatchesComponentCondition(pipelineConditions, componentCondition st
 	return false
 }
 
+// pipelineMatchesComponentProjectFiles checks if any of the files required by the pipeline is present in the component sources.
+// Unlike other conditions, file names are case sensitive.
+// For example, component project files are "go.mod,Makefile", pipeline conditions are "package.json,go.mod", result is true.
+func pipelineMatchesComponentProjectFiles(pipelineProjectFiles, componentProjectFiles string) bool {
+	for _, pipelineFile := range strings.Split(pipelineProjectFiles, ",") {
+		pipelineFile = strings.TrimSpace(pipelineFile)
+		for _, componentFile := range strings.Split(componentProjectFiles, ",") {
+			if pipelineFile != "" && pipelineFile == componentFile {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // pipelineMatchesComponentLabels checks if given pipeline supports build of the the component by looking at labels.
 // For example, component labels are:
 //
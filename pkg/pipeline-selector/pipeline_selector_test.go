@@ -17,6 +17,7 @@ limitations under the License.
 package pipelineselector
 
 import (
+	"fmt"
 	"reflect"
 	"testing"
 
@@ -49,6 +50,7 @@ func TestSelectPipelineForComponent(t *testing.T) {
 		name               string
 		component          *appstudiov1alpha1.Component
 		selectors          []buildappstudiov1alpha1.BuildPipelineSelector
+		isProjectFileExist ProjectFileChecker
 		wantPipelineRef    *tektonapi.PipelineRef
 		wantPipelineParams []tektonapi.Param
 		wantErr            bool
@@ -369,10 +371,133 @@ func TestSelectPipelineForComponent(t *testing.T) {
 			wantPipelineParams: nil,
 			wantErr:            true,
 		},
+		{
+			name: "Should select pipeline for component by project files",
+			component: &appstudiov1alpha1.Component{
+				ObjectMeta: v1.ObjectMeta{
+					Name:      "test-component",
+					Namespace: "test-namespace",
+				},
+				Status: appstudiov1alpha1.ComponentStatus{
+					Devfile: `
+                        schemaVersion: 2.2.0
+                        metadata:
+                            name: test-devfile
+                    `,
+				},
+			},
+			selectors: []buildappstudiov1alpha1.BuildPipelineSelector{
+				{
+					Spec: buildappstudiov1alpha1.BuildPipelineSelectorSpec{
+						Selectors: []buildappstudiov1alpha1.PipelineSelector{
+							{
+								Name:           "Node.js",
+								PipelineRef:    newBundleResolverPipelineRef("nodejs-bundle", "nodejs-build"),
+								WhenConditions: buildappstudiov1alpha1.WhenCondition{ProjectFiles: "package.json"},
+							},
+							{
+								Name:           "Java",
+								PipelineRef:    newBundleResolverPipelineRef("java-bundle", "java-build"),
+								WhenConditions: buildappstudiov1alpha1.WhenCondition{ProjectFiles: "pom.xml, build.gradle"},
+							},
+							{
+								Name:           "Go",
+								PipelineRef:    newBundleResolverPipelineRef("go-bundle", "go-build"),
+								WhenConditions: buildappstudiov1alpha1.WhenCondition{ProjectFiles: "go.mod"},
+							},
+						},
+					},
+				},
+			},
+			isProjectFileExist: func(fileName string) (bool, error) {
+				return fileName == "build.gradle" || fileName == "go.mod", nil
+			},
+			wantPipelineRef: func() *tektonapi.PipelineRef {
+				pipelineRef := newBundleResolverPipelineRef("java-bundle", "java-build")
+				return &pipelineRef
+			}(),
+			wantPipelineParams: nil,
+			wantErr:            false,
+		},
+		{
+			name: "Should not select pipeline by project files if the files can't be checked",
+			component: &appstudiov1alpha1.Component{
+				ObjectMeta: v1.ObjectMeta{
+					Name:      "test-component",
+					Namespace: "test-namespace",
+				},
+				Status: appstudiov1alpha1.ComponentStatus{
+					Devfile: `
+                        schemaVersion: 2.2.0
+                        metadata:
+                            name: test-devfile
+                    `,
+				},
+			},
+			selectors: []buildappstudiov1alpha1.BuildPipelineSelector{
+				{
+					Spec: buildappstudiov1alpha1.BuildPipelineSelectorSpec{
+						Selectors: []buildappstudiov1alpha1.PipelineSelector{
+							{
+								Name:           "Go",
+								PipelineRef:    newBundleResolverPipelineRef("go-bundle", "go-build"),
+								WhenConditions: buildappstudiov1alpha1.WhenCondition{ProjectFiles: "go.mod"},
+							},
+							{
+								Name:        "Fallback",
+								PipelineRef: newBundleResolverPipelineRef("my-bundle", "default-build-pipeline"),
+							},
+						},
+					},
+				},
+			},
+			isProjectFileExist: nil,
+			wantPipelineRef: func() *tektonapi.PipelineRef {
+				pipelineRef := newBundleResolverPipelineRef("my-bundle", "default-build-pipeline")
+				return &pipelineRef
+			}(),
+			wantPipelineParams: nil,
+			wantErr:            false,
+		},
+		{
+			name: "Should fail if project files check fails",
+			component: &appstudiov1alpha1.Component{
+				ObjectMeta: v1.ObjectMeta{
+					Name:      "test-component",
+					Namespace: "test-namespace",
+				},
+				Status: appstudiov1alpha1.ComponentStatus{
+					Devfile: `
+                        schemaVersion: 2.2.0
+                        metadata:
+                            name: test-devfile
+                    `,
+				},
+			},
+			selectors: []buildappstudiov1alpha1.BuildPipelineSelector{
+				{
+					Spec: buildappstudiov1alpha1.BuildPipelineSelectorSpec{
+						Selectors: []buildappstudiov1alpha1.PipelineSelector{
+							{
+								Name:           "Go",
+								PipelineRef:    newBundleResolverPipelineRef("go-bundle", "go-build"),
+								WhenConditions: buildappstudiov1alpha1.WhenCondition{ProjectFiles: "go.mod"},
+							},
+						},
+					},
+				},
+			},
+			isProjectFileExist: func(fileName string) (bool, error) {
+				return false, fmt.Errorf("failed to check file")
+			},
+			wantPipelineRef:    nil,
+			wantPipelineParams: nil,
+			wantErr:            true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			pipelineRef, pipelineParams, err := SelectPipelineForComponent(tt.component, tt.selectors)
+			pipelineRef, pipelineParams, err := SelectPipelineForComponent(tt.component, tt.selectors, tt.isProjectFileExist)
 
 			if tt.wantErr {
 				if err == nil {
@@ -972,6 +1097,70 @@ func TestFindMatchingPipeline(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "should match pipeline with the highest priority",
+			componentConditions: buildappstudiov1alpha1.WhenCondition{
+				Language:    "java",
+				ProjectType: "quarkus",
+			},
+			pipelinesChain: buildappstudiov1alpha1.BuildPipelineSelector{
+				Spec: buildappstudiov1alpha1.BuildPipelineSelectorSpec{
+					Selectors: []buildappstudiov1alpha1.PipelineSelector{
+						{
+							PipelineRef:    newBundleResolverPipelineRef("my-bundle", "java-build-pipeline"),
+							WhenConditions: buildappstudiov1alpha1.WhenCondition{Language: "java"},
+						},
+						{
+							PipelineRef:    newBundleResolverPipelineRef("my-bundle", "quarkus-build-pipeline"),
+							WhenConditions: buildappstudiov1alpha1.WhenCondition{ProjectType: "quarkus"},
+							Priority:       10,
+						},
+						{
+							PipelineRef:    newBundleResolverPipelineRef("my-bundle", "nodejs-build-pipeline"),
+							WhenConditions: buildappstudiov1alpha1.WhenCondition{Language: "nodejs"},
+							Priority:       20,
+						},
+					},
+				},
+			},
+			wantPipelineRef: func() *tektonapi.PipelineRef {
+				pipelineRef := newBundleResolverPipelineRef("my-bundle", "quarkus-build-pipeline")
+				return &pipelineRef
+			}(),
+			wantPipelineParams: nil,
+		},
+		{
+			name: "should match the first pipeline of the ones with the same priority",
+			componentConditions: buildappstudiov1alpha1.WhenCondition{
+				Language:    "java",
+				ProjectType: "quarkus",
+			},
+			pipelinesChain: buildappstudiov1alpha1.BuildPipelineSelector{
+				Spec: buildappstudiov1alpha1.BuildPipelineSelectorSpec{
+					Selectors: []buildappstudiov1alpha1.PipelineSelector{
+						{
+							PipelineRef: newBundleResolverPipelineRef("my-bundle", "default-build-pipeline"),
+							Priority:    -1,
+						},
+						{
+							PipelineRef:    newBundleResolverPipelineRef("my-bundle", "java-build-pipeline"),
+							WhenConditions: buildappstudiov1alpha1.WhenCondition{Language: "java"},
+							Priority:       5,
+						},
+						{
+							PipelineRef:    newBundleResolverPipelineRef("my-bundle", "quarkus-build-pipeline"),
+							WhenConditions: buildappstudiov1alpha1.WhenCondition{ProjectType: "quarkus"},
+							Priority:       5,
+						},
+					},
+				},
+			},
+			wantPipelineRef: func() *tektonapi.PipelineRef {
+				pipelineRef := newBundleResolverPipelineRef("my-bundle", "java-build-pipeline")
+				return &pipelineRef
+			}(),
+			wantPipelineParams: nil,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -1184,6 +1373,34 @@ func TestPipelineConditionsMatchComponentParameters(t *testing.T) {
 			}(),
 			wantMatch: false,
 		},
+		{
+			name: "should match if any of the project files is present",
+			componentConditions: func() buildappstudiov1alpha1.WhenCondition {
+				conditions := getSampleConditions()
+				conditions.ProjectFiles = "build.gradle,Makefile"
+				return conditions
+			}(),
+			pipelineConditions: func() buildappstudiov1alpha1.WhenCondition {
+				conditions := getSampleConditions()
+				conditions.ProjectFiles = "pom.xml, build.gradle"
+				return conditions
+			}(),
+			wantMatch: true,
+		},
+		{
+			name: "should not match if project files are not present",
+			componentConditions: func() buildappstudiov1alpha1.WhenCondition {
+				conditions := getSampleConditions()
+				conditions.ProjectFiles = "Pom.xml"
+				return conditions
+			}(),
+			pipelineConditions: func() buildappstudiov1alpha1.WhenCondition {
+				conditions := getSampleConditions()
+				conditions.ProjectFiles = "pom.xml"
+				return conditions
+			}(),
+			wantMatch: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -11,10 +11,15 @@ import (
 	"github.com/konflux-ci/build-service/pkg/git/credentials"
 )
 
+// DefaultGitAuthorName is used in the git author of renovate commits when credentials contain no username.
+const DefaultGitAuthorName = "konflux"
+
 // BasicAuthTaskProvider is an implementation of the renovate.TaskProvider that creates the renovate.Task for the components
 // based on the generic algorithm and not tied to any specific SCM provider implementation.
 type BasicAuthTaskProvider struct {
 	credentialsProvider credentials.BasicAuthCredentialsProvider
+	// Whether the tasks are executed in the namespaces of their Components
+	tenantScoped bool
 }
 
 func NewBasicAuthTaskProvider(credentialsProvider credentials.BasicAuthCredentialsProvider) BasicAuthTaskProvider {
@@ -23,6 +28,13 @@ func NewBasicAuthTaskProvider(credentialsProvider credentials.BasicAuthCredentia
 	}
 }
 
+// TenantScoped returns the task provider whose tasks are executed in the namespaces of their Components.
+// The tasks are created per namespace from the credentials available in the namespace, so they are not scoped any further.
+func (g BasicAuthTaskProvider) TenantScoped(settings Settings) TaskProvider {
+	g.tenantScoped = true
+	return g
+}
+
 // GetNewTasks returns the list of new renovate tasks for the components. It uses such an algorithm:
 // 1. Group components by namespace
 // 2. Group components by platform
@@ -66,17 +78,22 @@ func (g BasicAuthTaskProvider) GetNewTasks(ctx context.Context, components []*gi
 						// Step 6
 						if !AddNewRepoToTasksOnTheSameHostsWithSameCredentials(tasksOnHost, component, creds) {
 							// Step 7
-							tasksOnHost = append(tasksOnHost, NewBasicAuthTask(platform, host, endpoint, creds, []*Repository{
-								{
-									Repository:   component.Repository(),
-									BaseBranches: []string{component.Branch()},
-								},
-							}))
+							repository := &Repository{
+								Repository:   component.Repository(),
+								BaseBranches: []string{component.Branch()},
+							}
+							repository.AddComponentSettings(component)
+							tasksOnHost = append(tasksOnHost, NewBasicAuthTask(platform, component.RepositoryHost(), endpoint, creds, []*Repository{repository}))
 						}
 					}
 				}
 			}
 			//Step 8
+			if g.tenantScoped {
+				for _, task := range tasksOnHost {
+					task.Namespace = namespace
+				}
+			}
 			newTasks = append(newTasks, tasksOnHost...)
 		}
 
@@ -85,11 +102,18 @@ func (g BasicAuthTaskProvider) GetNewTasks(ctx context.Context, components []*gi
 	return newTasks
 }
 
+// NewBasicAuthTask creates renovate task for the given credentials.
+// Webhook based Pipelines as Code secrets might contain only a personal access token without username,
+// in this case the default git author name is used for commits.
 func NewBasicAuthTask(platform, host, endpoint string, credentials *credentials.BasicAuthCredentials, repositories []*Repository) *Task {
+	gitAuthorName := credentials.Username
+	if gitAuthorName == "" {
+		gitAuthorName = DefaultGitAuthorName
+	}
 	return &Task{
 		Platform:     platform,
 		Username:     credentials.Username,
-		GitAuthor:    fmt.Sprintf("%s <123456+%s[bot]@users.noreply.%s>", credentials.Username, credentials.Username, host),
+		GitAuthor:    fmt.Sprintf("%s <123456+%s[bot]@users.noreply.%s>", gitAuthorName, gitAuthorName, host),
 		Token:        credentials.Password,
 		Endpoint:     endpoint,
 		Repositories: repositories,
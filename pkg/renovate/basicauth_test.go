@@ -19,6 +19,11 @@ var StaticCredentialsFunc credentials.BasicAuthCredentialsProviderFunc = func(ct
 	return staticCredentials, nil
 }
 
+var tokenOnlyCredentials = &credentials.BasicAuthCredentials{Password: "glpat-234"}
+var TokenOnlyCredentialsFunc credentials.BasicAuthCredentialsProviderFunc = func(ctx context.Context, component *git.ScmComponent) (*credentials.BasicAuthCredentials, error) {
+	return tokenOnlyCredentials, nil
+}
+
 func TestNewTasks(t *testing.T) {
 	logf.SetLogger(zap.New(zap.WriteTo(os.Stdout), zap.UseDevMode(true)))
 	tests := []struct {
@@ -40,6 +45,7 @@ func TestNewTasks(t *testing.T) {
 					{
 						Repository:   "umbrellacorp/devfile-sample-go-basic",
 						BaseBranches: []string{"main"},
+						Components:   []string{"devfile-sample-go-basic"},
 					},
 				}),
 			},
@@ -69,10 +75,12 @@ func TestNewTasks(t *testing.T) {
 					{
 						Repository:   "umbrellacorp/devfile-sample-python-basic",
 						BaseBranches: []string{"develop"},
+						Components:   []string{"devfile-sample-python-basic"},
 					},
 					{
 						Repository:   "umbrellacorp/devfile-sample-go-basic",
 						BaseBranches: []string{"main"},
+						Components:   []string{"devfile-sample-go-basic"},
 					},
 				}),
 			},
@@ -109,10 +117,12 @@ func TestNewTasks(t *testing.T) {
 					{
 						Repository:   "umbrellacorp/devfile-sample-python-basic",
 						BaseBranches: []string{"develop", "main"},
+						Components:   []string{"devfile-sample-python-basic"},
 					},
 					{
 						Repository:   "umbrellacorp/devfile-sample-go-basic",
 						BaseBranches: []string{"main"},
+						Components:   []string{"devfile-sample-go-basic"},
 					},
 				}),
 			},
@@ -142,12 +152,129 @@ func TestNewTasks(t *testing.T) {
 					{
 						Repository:   "umbrellacorp/devfile-sample-python-basic",
 						BaseBranches: []string{"develop"},
+						Components:   []string{"devfile-sample-python-basic"},
 					},
 				}),
 				NewBasicAuthTask("gitlab", "gitlab.com", "https://gitlab.com/api/v4/", staticCredentials, []*Repository{
 					{
 						Repository:   "umbrellacorp/devfile-sample-go-basic",
 						BaseBranches: []string{"main"},
+						Components:   []string{"devfile-sample-go-basic"},
+					},
+				}),
+			},
+		},
+		{
+			name:            "Bitbucket Cloud component",
+			credentialsFunc: StaticCredentialsFunc,
+			components: []*git.ScmComponent{
+				ignoreError(
+					git.NewScmComponent(
+						"bitbucket",
+						"https://bitbucket.org/umbrellacorp/devfile-sample-go-basic",
+						"main",
+						"devfile-sample-go-basic",
+						"umbrellacorp-tenant")).(*git.ScmComponent),
+			},
+			expected: []*Task{
+				NewBasicAuthTask("bitbucket", "bitbucket.org", "https://api.bitbucket.org/2.0/", staticCredentials, []*Repository{
+					{
+						Repository:   "umbrellacorp/devfile-sample-go-basic",
+						BaseBranches: []string{"main"},
+						Components:   []string{"devfile-sample-go-basic"},
+					},
+				}),
+			},
+		},
+		{
+			name:            "Azure DevOps component",
+			credentialsFunc: StaticCredentialsFunc,
+			components: []*git.ScmComponent{
+				ignoreError(
+					git.NewScmComponent(
+						"azure",
+						"https://dev.azure.com/umbrellacorp/devfile-samples/_git/devfile-sample-go-basic",
+						"main",
+						"devfile-sample-go-basic",
+						"umbrellacorp-tenant")).(*git.ScmComponent),
+			},
+			expected: []*Task{
+				NewBasicAuthTask("azure", "dev.azure.com", "https://dev.azure.com/umbrellacorp/", staticCredentials, []*Repository{
+					{
+						Repository:   "devfile-samples/devfile-sample-go-basic",
+						BaseBranches: []string{"main"},
+						Components:   []string{"devfile-sample-go-basic"},
+					},
+				}),
+			},
+		},
+		{
+			name:            "Component with token only credentials",
+			credentialsFunc: TokenOnlyCredentialsFunc,
+			components: []*git.ScmComponent{
+				ignoreError(
+					git.NewScmComponent(
+						"gitlab",
+						"https://gitlab.com/umbrellacorp/devfile-sample-go-basic",
+						"main",
+						"devfile-sample-go-basic",
+						"umbrellacorp-tenant")).(*git.ScmComponent),
+			},
+			expected: []*Task{
+				{
+					Platform:  "gitlab",
+					Username:  "",
+					GitAuthor: "konflux <123456+konflux[bot]@users.noreply.gitlab.com>",
+					Token:     "glpat-234",
+					Endpoint:  "https://gitlab.com/api/v4/",
+					Repositories: []*Repository{
+						{
+							Repository:   "umbrellacorp/devfile-sample-go-basic",
+							BaseBranches: []string{"main"},
+							Components:   []string{"devfile-sample-go-basic"},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:            "Components with renovate schedule",
+			credentialsFunc: StaticCredentialsFunc,
+			components: []*git.ScmComponent{
+				withSchedule(ignoreError(
+					git.NewScmComponent(
+						"github",
+						"https://github.com/umbrellacorp/devfile-sample-python-basic",
+						"develop",
+						"devfile-sample-python-basic",
+						"umbrellacorp-tenant")).(*git.ScmComponent), "after 10pm every weekday"),
+				withSchedule(ignoreError(
+					git.NewScmComponent(
+						"github",
+						"https://github.com/umbrellacorp/devfile-sample-python-basic",
+						"main",
+						"devfile-sample-python-basic",
+						"umbrellacorp-tenant")).(*git.ScmComponent), "after 10pm every weekday", "every weekend"),
+				ignoreError(
+					git.NewScmComponent(
+						"github",
+						"https://github.com/umbrellacorp/devfile-sample-go-basic",
+						"main",
+						"devfile-sample-go-basic",
+						"umbrellacorp-tenant")).(*git.ScmComponent),
+			},
+			expected: []*Task{
+				NewBasicAuthTask("github", "github.com", "https://api.github.com/", staticCredentials, []*Repository{
+					{
+						Repository:   "umbrellacorp/devfile-sample-python-basic",
+						BaseBranches: []string{"develop", "main"},
+						Components:   []string{"devfile-sample-python-basic"},
+						Schedule:     []string{"after 10pm every weekday", "every weekend"},
+					},
+					{
+						Repository:   "umbrellacorp/devfile-sample-go-basic",
+						BaseBranches: []string{"main"},
+						Components:   []string{"devfile-sample-go-basic"},
 					},
 				}),
 			},
@@ -170,6 +297,28 @@ func TestNewTasks(t *testing.T) {
 
 }
 
+func TestCredentialsEnvName(t *testing.T) {
+	tests := []struct {
+		platform string
+		expected string
+	}{
+		{platform: "github", expected: "RENOVATE_TOKEN"},
+		{platform: "gitlab", expected: "RENOVATE_TOKEN"},
+		{platform: "bitbucket", expected: "RENOVATE_PASSWORD"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.platform, func(t *testing.T) {
+			task := &Task{Platform: tt.platform}
+			assert.Equal(t, tt.expected, task.CredentialsEnvName())
+		})
+	}
+}
+
+func withSchedule(component *git.ScmComponent, schedule ...string) *git.ScmComponent {
+	component.SetSchedule(schedule)
+	return component
+}
+
 func ignoreError(val interface{}, err error) interface{} {
 	return val
 }
@@ -1,24 +1,43 @@
 package renovate
 
 import (
+	"encoding/json"
 	"fmt"
-	"os"
+	"regexp"
+	"regexp/syntax"
+	"strings"
 
+	"k8s.io/utils/ptr"
 	"k8s.io/utils/strings/slices"
+
+	"github.com/konflux-ci/build-service/pkg/common"
+	"github.com/konflux-ci/build-service/pkg/git"
 )
 
 const (
 	RenovateMatchPatternEnvName = "RENOVATE_PATTERN"
 	DefaultRenovateMatchPattern = "^quay.io/redhat-appstudio-tekton-catalog/"
+	DefaultCommitMessageTopic   = "RHTAP references"
+	DefaultIncludePath          = ".tekton/**"
+	DefaultSemanticCommits      = "enabled"
+	DefaultRebaseWhen           = "behind-base-branch"
+	ReferencesGroupName         = "RHTAP references"
+	ReferencesBranchPrefix      = "konflux/references/"
+	ReferencesBranchName        = ReferencesBranchPrefix + "{{baseBranch}}"
+	// Key of the renovate config in the config overlay ConfigMap
+	ConfigOverlayConfigMapKey = "config.json"
 )
 
 var (
 	DisableAllPackageRules = PackageRule{MatchPackagePatterns: []string{"[*]"}, Enabled: false}
+	// Values of renovate rebaseWhen setting, see https://docs.renovatebot.com/configuration-options/#rebasewhen
+	RebaseWhenValues = []string{"auto", "never", "conflicted", "behind-base-branch", "automerging"}
+	gitAuthorRegexp  = regexp.MustCompile(`^[^<>]+ <[^<>\s@]+@[^<>\s@]+>$`)
 )
 
 type JobConfig struct {
 	Platform            string        `json:"platform"`
-	Username            string        `json:"username"`
+	Username            string        `json:"username,omitempty"`
 	GitAuthor           string        `json:"gitAuthor"`
 	Onboarding          bool          `json:"onboarding"`
 	RequireConfig       string        `json:"requireConfig"`
@@ -28,11 +47,66 @@ type JobConfig struct {
 	ForkProcessing      string        `json:"forkProcessing"`
 	DependencyDashboard bool          `json:"dependencyDashboard"`
 	Endpoint            string        `json:"endpoint,omitempty"`
+	Schedule            []string      `json:"schedule,omitempty"`
+	PackageRules        []PackageRule `json:"packageRules,omitempty"`
+	PRHourlyLimit       *int          `json:"prHourlyLimit,omitempty"`
+	PRConcurrentLimit   *int          `json:"prConcurrentLimit,omitempty"`
+	StopUpdatingLabel   string        `json:"stopUpdatingLabel,omitempty"`
+	BranchPrefix        string        `json:"branchPrefix,omitempty"`
+	// Renovate prunes stale branches with the branch prefix or the old branch prefix
+	BranchPrefixOld    string `json:"branchPrefixOld,omitempty"`
+	PruneStaleBranches *bool  `json:"pruneStaleBranches,omitempty"`
+	// Regular expressions of the commands allowed in post upgrade tasks
+	AllowedPostUpgradeCommands []string `json:"allowedPostUpgradeCommands,omitempty"`
+	// Configuration of the enabled managers other than tekton, keyed by the manager name
+	Managers map[string]Manager `json:"-"`
+}
+
+// Manager holds the configuration of a renovate manager.
+type Manager struct {
+	FileMatch    []string `json:"fileMatch,omitempty"`
+	IncludePaths []string `json:"includePaths,omitempty"`
+}
+
+// MarshalJSON adds the configuration of the additional managers as top level keys named by the managers.
+func (c JobConfig) MarshalJSON() ([]byte, error) {
+	type jobConfig JobConfig
+	data, err := json.Marshal(jobConfig(c))
+	if err != nil || len(c.Managers) == 0 {
+		return data, err
+	}
+	config := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, err
+	}
+	for name, manager := range c.Managers {
+		if config[name], err = json.Marshal(manager); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(config)
 }
 
 type Repository struct {
-	Repository   string   `json:"repository"`
-	BaseBranches []string `json:"baseBranches"`
+	Repository   string            `json:"repository"`
+	BaseBranches []string          `json:"baseBranches"`
+	Schedule     []string          `json:"schedule,omitempty"`
+	Tekton       *RepositoryTekton `json:"tekton,omitempty"`
+	// Author of the commits in the repository if the namespace of its Components has a specific one
+	GitAuthor string `json:"gitAuthor,omitempty"`
+	// Disables pruning of the stale branches if other Components of the repository are not renovated,
+	// their base branches would be considered stale and their pull requests closed
+	PruneStaleBranches *bool `json:"pruneStaleBranches,omitempty"`
+	// Repository specific automerge setting, applied to the tekton package rules in the job config
+	Automerge *bool `json:"-"`
+	// Names of the Components built from the repository, several in case of a monorepo
+	Components []string `json:"-"`
+}
+
+// RepositoryTekton holds repository specific tekton manager settings,
+// its package rules are appended to the global ones.
+type RepositoryTekton struct {
+	PackageRules []PackageRule `json:"packageRules"`
 }
 
 func (r *Repository) AddBranch(branch string) {
@@ -41,6 +115,41 @@ func (r *Repository) AddBranch(branch string) {
 	}
 }
 
+// AddComponentSettings adds renovate settings of the given component to the repository.
+// If several components share the repository, the automerge setting and the git author of the first component which sets them win.
+func (r *Repository) AddComponentSettings(component *git.ScmComponent) {
+	if component.ComponentName() != "" && !slices.Contains(r.Components, component.ComponentName()) {
+		r.Components = append(r.Components, component.ComponentName())
+	}
+	r.AddSchedule(component.Schedule())
+	if r.Automerge == nil {
+		r.Automerge = component.Automerge()
+	}
+	if r.GitAuthor == "" {
+		r.GitAuthor = component.GitAuthor()
+	}
+	if component.KeepStaleBranches() {
+		r.PruneStaleBranches = ptr.To(false)
+	}
+}
+
+// AddSchedule adds the given renovate schedule entries to the repository specific schedule.
+func (r *Repository) AddSchedule(schedule []string) {
+	for _, entry := range schedule {
+		if !slices.Contains(r.Schedule, entry) {
+			r.Schedule = append(r.Schedule, entry)
+		}
+	}
+}
+
+// HostRule holds the credentials of a host, see https://docs.renovatebot.com/configuration-options/#hostrules
+type HostRule struct {
+	HostType  string `json:"hostType,omitempty"`
+	MatchHost string `json:"matchHost"`
+	Username  string `json:"username,omitempty"`
+	Password  string `json:"password,omitempty"`
+}
+
 type Tekton struct {
 	FileMatch    []string      `json:"fileMatch"`
 	IncludePaths []string      `json:"includePaths"`
@@ -48,59 +157,387 @@ type Tekton struct {
 }
 
 type PackageRule struct {
-	MatchPackagePatterns []string `json:"matchPackagePatterns"`
-	Enabled              bool     `json:"enabled"`
-	MatchDepPatterns     []string `json:"matchDepPatterns,omitempty"`
-	GroupName            string   `json:"groupName,omitempty"`
-	BranchName           string   `json:"branchName,omitempty"`
-	CommitBody           string   `json:"commitBody,omitempty"`
-	CommitMessageExtra   string   `json:"commitMessageExtra,omitempty"`
-	CommitMessageTopic   string   `json:"commitMessageTopic,omitempty"`
-	SemanticCommits      string   `json:"semanticCommits,omitempty"`
-	PRFooter             string   `json:"prFooter,omitempty"`
-	PRBodyColumns        []string `json:"prBodyColumns,omitempty"`
-	PRBodyDefinitions    string   `json:"prBodyDefinitions,omitempty"`
-	PRBodyTemplate       string   `json:"prBodyTemplate,omitempty"`
-	RecreateWhen         string   `json:"recreateWhen,omitempty"`
-	RebaseWhen           string   `json:"rebaseWhen,omitempty"`
-}
-
-func NewTektonJobConfig(platform, endpoint, username, gitAuthor string, repositories []*Repository) JobConfig {
-	renovatePattern := GetRenovatePatternConfiguration()
+	MatchPackagePatterns  []string `json:"matchPackagePatterns"`
+	Enabled               bool     `json:"enabled"`
+	MatchDepPatterns      []string `json:"matchDepPatterns,omitempty"`
+	GroupName             string   `json:"groupName,omitempty"`
+	BranchName            string   `json:"branchName,omitempty"`
+	CommitBody            string   `json:"commitBody,omitempty"`
+	CommitMessageExtra    string   `json:"commitMessageExtra,omitempty"`
+	CommitMessagePrefix   string   `json:"commitMessagePrefix,omitempty"`
+	CommitMessageTopic    string   `json:"commitMessageTopic,omitempty"`
+	SemanticCommits       string   `json:"semanticCommits,omitempty"`
+	SemanticCommitType    string   `json:"semanticCommitType,omitempty"`
+	SemanticCommitScope   string   `json:"semanticCommitScope,omitempty"`
+	PRFooter              string   `json:"prFooter,omitempty"`
+	PRTitle               string   `json:"prTitle,omitempty"`
+	PRBodyColumns         []string `json:"prBodyColumns,omitempty"`
+	PRBodyDefinitions     string   `json:"prBodyDefinitions,omitempty"`
+	PRBodyTemplate        string   `json:"prBodyTemplate,omitempty"`
+	RecreateWhen          string   `json:"recreateWhen,omitempty"`
+	RebaseWhen            string   `json:"rebaseWhen,omitempty"`
+	Automerge             *bool    `json:"automerge,omitempty"`
+	PlatformAutomerge     *bool    `json:"platformAutomerge,omitempty"`
+	PinDigests            *bool    `json:"pinDigests,omitempty"`
+	MatchFileNames        []string `json:"matchFileNames,omitempty"`
+	SeparateMajorMinor    *bool    `json:"separateMajorMinor,omitempty"`
+	SeparateMultipleMajor *bool    `json:"separateMultipleMajor,omitempty"`
+	// Commands run after the update, see https://docs.renovatebot.com/configuration-options/#postupgradetasks
+	PostUpgradeTasks *PostUpgradeTasks `json:"postUpgradeTasks,omitempty"`
+}
+
+type PostUpgradeTasks struct {
+	Commands      []string `json:"commands"`
+	FileFilters   []string `json:"fileFilters,omitempty"`
+	ExecutionMode string   `json:"executionMode,omitempty"`
+}
+
+func NewTektonJobConfig(platform, endpoint, username, gitAuthor string, repositories []*Repository, settings Settings) JobConfig {
+	renovatePatterns := settings.MatchPatterns
+	var automerge *bool
+	if settings.Automerge {
+		automerge = ptr.To(true)
+	}
+	var pinDigests *bool
+	if settings.PinDigests {
+		pinDigests = ptr.To(true)
+	}
+	enabledManagers := []string{"tekton"}
+	managers := map[string]Manager{}
+	for _, manager := range settings.Managers {
+		if slices.Contains(enabledManagers, manager.Name) {
+			continue
+		}
+		enabledManagers = append(enabledManagers, manager.Name)
+		managers[manager.Name] = Manager{FileMatch: manager.FileMatch, IncludePaths: manager.IncludePaths}
+	}
+	commitMessageTopic := settings.Templates.CommitMessageTopic
+	if commitMessageTopic == "" {
+		commitMessageTopic = DefaultCommitMessageTopic
+	}
+	semanticCommits := settings.Templates.SemanticCommits
+	if semanticCommits == "" {
+		semanticCommits = DefaultSemanticCommits
+	}
+	rebaseWhen := settings.RebaseWhen
+	if rebaseWhen == "" {
+		rebaseWhen = DefaultRebaseWhen
+	}
+	includePaths := settings.IncludePaths
+	if len(includePaths) == 0 {
+		includePaths = []string{DefaultIncludePath}
+	}
+	branchName := referencesBranchName(settings.BranchPrefix)
+	var separateUpdates *bool
+	var globalPackageRules []PackageRule
+	if settings.GroupAllUpdates {
+		separateUpdates = ptr.To(false)
+		// Puts the updates of all managers into the branch of the tekton references
+		globalPackageRules = append(globalPackageRules, PackageRule{
+			MatchPackagePatterns:  []string{"*"},
+			GroupName:             ReferencesGroupName,
+			BranchName:            branchName,
+			SeparateMajorMinor:    separateUpdates,
+			SeparateMultipleMajor: separateUpdates,
+			Enabled:               true,
+		})
+	}
+	prFooter := "To execute skipped test pipelines write comment `/ok-to-test`"
+	if settings.StopUpdatingLabel != "" {
+		prFooter += fmt.Sprintf(". To keep your changes of this pull request from being overwritten, add the `%s` label", settings.StopUpdatingLabel)
+	}
+	var postUpgradeTasks *PostUpgradeTasks
+	var allowedPostUpgradeCommands []string
+	if tasks := settings.PostUpgradeTasks; tasks != nil && len(tasks.Commands) > 0 {
+		postUpgradeTasks = &PostUpgradeTasks{Commands: tasks.Commands, FileFilters: tasks.FileFilters, ExecutionMode: tasks.ExecutionMode}
+		allowedPostUpgradeCommands = tasks.AllowedCommands
+		if len(allowedPostUpgradeCommands) == 0 {
+			for _, command := range tasks.Commands {
+				allowedPostUpgradeCommands = append(allowedPostUpgradeCommands, "^"+regexp.QuoteMeta(command)+"$")
+			}
+		}
+	}
+	packageRules := []PackageRule{DisableAllPackageRules}
+	for _, renovatePattern := range renovatePatterns {
+		packageRules = append(packageRules, PackageRule{
+			MatchPackagePatterns:  []string{renovatePattern},
+			MatchDepPatterns:      []string{renovatePattern},
+			GroupName:             ReferencesGroupName,
+			BranchName:            branchName,
+			CommitMessageExtra:    "",
+			CommitMessageTopic:    commitMessageTopic,
+			CommitMessagePrefix:   settings.Templates.CommitMessagePrefix,
+			PRTitle:               settings.Templates.PRTitle,
+			CommitBody:            "Signed-off-by: {{{gitAuthor}}}",
+			SemanticCommits:       semanticCommits,
+			SemanticCommitType:    settings.Templates.SemanticCommitType,
+			SemanticCommitScope:   settings.Templates.SemanticCommitScope,
+			PRFooter:              prFooter,
+			PRBodyColumns:         []string{"Package", "Change", "Notes"},
+			PRBodyDefinitions:     fmt.Sprintf("{ \"Notes\": \"{{#if (or (containsString updateType 'minor') (containsString updateType 'major'))}}:warning:[migration](https://github.com/redhat-appstudio/build-definitions/blob/main/task/{{{replace '%stask-' '' packageName}}}/{{{newVersion}}}/MIGRATION.md):warning:{{/if}}\" }", renovatePattern),
+			PRBodyTemplate:        "{{{header}}}{{{table}}}{{{notes}}}{{{changelogs}}}{{{footer}}}",
+			RecreateWhen:          "always",
+			RebaseWhen:            rebaseWhen,
+			Automerge:             automerge,
+			PlatformAutomerge:     automerge,
+			PinDigests:            pinDigests,
+			SeparateMajorMinor:    separateUpdates,
+			SeparateMultipleMajor: separateUpdates,
+			PostUpgradeTasks:      postUpgradeTasks,
+			Enabled:               true,
+		})
+	}
+	if settings.GitAuthor != "" {
+		gitAuthor = settings.GitAuthor
+	}
+	// The Tekton references branches don't have the default renovate prefix, nor the configured one
+	// if it was changed after the branches were created
+	var branchPrefixOld string
+	if settings.PruneStaleBranches == nil || *settings.PruneStaleBranches {
+		branchPrefixOld = ReferencesBranchPrefix
+	}
 	return JobConfig{
-		Platform:        platform,
-		Username:        username,
-		GitAuthor:       gitAuthor,
-		Onboarding:      false,
-		RequireConfig:   "ignored",
-		EnabledManagers: []string{"tekton"},
-		Endpoint:        endpoint,
-		Repositories:    repositories,
-		Tekton: Tekton{FileMatch: []string{"\\.yaml$", "\\.yml$"}, IncludePaths: []string{".tekton/**"}, PackageRules: []PackageRule{DisableAllPackageRules, {
-			MatchPackagePatterns: []string{renovatePattern},
-			MatchDepPatterns:     []string{renovatePattern},
-			GroupName:            "RHTAP references",
-			BranchName:           "konflux/references/{{baseBranch}}",
-			CommitMessageExtra:   "",
-			CommitMessageTopic:   "RHTAP references",
-			CommitBody:           "Signed-off-by: {{{gitAuthor}}}",
-			SemanticCommits:      "enabled",
-			PRFooter:             "To execute skipped test pipelines write comment `/ok-to-test`",
-			PRBodyColumns:        []string{"Package", "Change", "Notes"},
-			PRBodyDefinitions:    fmt.Sprintf("{ \"Notes\": \"{{#if (or (containsString updateType 'minor') (containsString updateType 'major'))}}:warning:[migration](https://github.com/redhat-appstudio/build-definitions/blob/main/task/{{{replace '%stask-' '' packageName}}}/{{{newVersion}}}/MIGRATION.md):warning:{{/if}}\" }", renovatePattern),
-			PRBodyTemplate:       "{{{header}}}{{{table}}}{{{notes}}}{{{changelogs}}}{{{footer}}}",
-			RecreateWhen:         "always",
-			RebaseWhen:           "behind-base-branch",
-			Enabled:              true,
-		}}},
-		ForkProcessing:      "enabled",
-		DependencyDashboard: false,
-	}
-}
-func GetRenovatePatternConfiguration() string {
-	renovatePattern := os.Getenv(RenovateMatchPatternEnvName)
-	if renovatePattern == "" {
-		renovatePattern = DefaultRenovateMatchPattern
-	}
-	return renovatePattern
+		Platform:           platform,
+		Username:           username,
+		GitAuthor:          gitAuthor,
+		Onboarding:         false,
+		RequireConfig:      "ignored",
+		EnabledManagers:    enabledManagers,
+		Managers:           managers,
+		Endpoint:           endpoint,
+		Repositories:       withRepositoryPackageRules(repositories, renovatePatterns, branchName),
+		Schedule:           settings.Schedule,
+		PackageRules:       globalPackageRules,
+		PRHourlyLimit:      settings.PRHourlyLimit,
+		PRConcurrentLimit:  settings.PRConcurrentLimit,
+		StopUpdatingLabel:  settings.StopUpdatingLabel,
+		BranchPrefix:       settings.BranchPrefix,
+		BranchPrefixOld:    branchPrefixOld,
+		PruneStaleBranches: settings.PruneStaleBranches,
+		Tekton: Tekton{
+			FileMatch:    []string{"\\.yaml$", "\\.yml$"},
+			IncludePaths: includePaths,
+			PackageRules: packageRules,
+		},
+		ForkProcessing:             "enabled",
+		DependencyDashboard:        false,
+		AllowedPostUpgradeCommands: allowedPostUpgradeCommands,
+	}
+}
+
+// referencesBranchName returns the name of the branch with the updates of the Tekton references.
+// Without a configured prefix the branch keeps its historical name, so the open pull requests are not recreated.
+func referencesBranchName(branchPrefix string) string {
+	if branchPrefix == "" {
+		return ReferencesBranchName
+	}
+	return branchPrefix + "references/{{baseBranch}}"
+}
+
+// withRepositoryPackageRules returns copies of the repositories with package rules applying their specific settings
+// to the references matching the renovate patterns.
+// If several Components are built from the repository (monorepo), the updates of the pipeline definitions
+// of each Component are proposed in a separate branch, so the Components can be updated independently.
+func withRepositoryPackageRules(repositories []*Repository, renovatePatterns []string, branchName string) []*Repository {
+	var result []*Repository
+	for _, repository := range repositories {
+		if repository.Automerge == nil && len(repository.Components) < 2 {
+			result = append(result, repository)
+			continue
+		}
+		repositoryCopy := *repository
+		repositoryCopy.Tekton = &RepositoryTekton{PackageRules: []PackageRule{}}
+		if repository.Automerge != nil {
+			repositoryCopy.Tekton.PackageRules = append(repositoryCopy.Tekton.PackageRules, PackageRule{
+				MatchPackagePatterns: renovatePatterns,
+				MatchDepPatterns:     renovatePatterns,
+				Automerge:            repository.Automerge,
+				PlatformAutomerge:    repository.Automerge,
+				Enabled:              true,
+			})
+		}
+		if len(repository.Components) > 1 {
+			for _, component := range repository.Components {
+				repositoryCopy.Tekton.PackageRules = append(repositoryCopy.Tekton.PackageRules, PackageRule{
+					MatchPackagePatterns: renovatePatterns,
+					MatchDepPatterns:     renovatePatterns,
+					MatchFileNames:       componentPipelineFileNames(component),
+					GroupName:            ReferencesGroupName + " " + component,
+					BranchName:           branchName + "-" + component,
+					Enabled:              true,
+				})
+			}
+		}
+		result = append(result, &repositoryCopy)
+	}
+	return result
+}
+
+// componentPipelineFileNames returns paths of the Pipelines as Code definitions generated for the Component.
+func componentPipelineFileNames(component string) []string {
+	return []string{".tekton/" + component + "-push.yaml", ".tekton/" + component + "-pull-request.yaml"}
+}
+
+// GetRenovatePatternsConfiguration returns the regular expressions matching Tekton references to update.
+// RENOVATE_PATTERN environment variable may hold several comma separated patterns.
+func GetRenovatePatternsConfiguration() []string {
+	var renovatePatterns []string
+	for _, renovatePattern := range strings.Split(common.GetConfigValue(RenovateMatchPatternEnvName), ",") {
+		if renovatePattern = strings.TrimSpace(renovatePattern); renovatePattern != "" {
+			renovatePatterns = append(renovatePatterns, renovatePattern)
+		}
+	}
+	if len(renovatePatterns) == 0 {
+		renovatePatterns = []string{DefaultRenovateMatchPattern}
+	}
+	return renovatePatterns
+}
+
+// MergeConfigOverlay merges the overlay into the JSON renovate config.
+// Objects are merged recursively, package rules of the overlay are appended and other values replace the generated ones.
+// The merged config is validated like the generated one, so the values set by the overlay can't break the job.
+func MergeConfigOverlay(config []byte, overlay map[string]interface{}) ([]byte, error) {
+	merged := map[string]interface{}{}
+	if err := json.Unmarshal(config, &merged); err != nil {
+		return nil, err
+	}
+	mergeConfig(merged, overlay)
+	mergedConfig, err := json.Marshal(merged)
+	if err != nil {
+		return nil, err
+	}
+	var jobConfig JobConfig
+	if err := json.Unmarshal(mergedConfig, &jobConfig); err != nil {
+		return nil, fmt.Errorf("invalid renovate config overlay: %w", err)
+	}
+	if err := jobConfig.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid renovate config overlay: %w", err)
+	}
+	return mergedConfig, nil
+}
+
+func mergeConfig(config, overlay map[string]interface{}) {
+	for key, value := range overlay {
+		switch value := value.(type) {
+		case map[string]interface{}:
+			if nested, ok := config[key].(map[string]interface{}); ok {
+				mergeConfig(nested, value)
+				continue
+			}
+		case []interface{}:
+			if rules, ok := config[key].([]interface{}); ok && key == "packageRules" {
+				config[key] = append(rules, value...)
+				continue
+			}
+		}
+		config[key] = value
+	}
+}
+
+// Validate checks the values which would make renovate reject the whole config, and so fail for all repositories of the job.
+func (c JobConfig) Validate() error {
+	for _, repository := range c.Repositories {
+		for _, branch := range repository.BaseBranches {
+			if err := ValidateBranchName(branch); err != nil {
+				return fmt.Errorf("invalid base branch of repository %s: %w", repository.Repository, err)
+			}
+		}
+	}
+	for _, rule := range c.Tekton.PackageRules {
+		for _, pattern := range rule.MatchDepPatterns {
+			if err := ValidateMatchPattern(pattern); err != nil {
+				return err
+			}
+		}
+		for _, template := range []string{rule.CommitMessagePrefix, rule.CommitMessageTopic, rule.PRTitle, rule.SemanticCommitScope} {
+			if err := ValidateTemplate(template); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ValidateMatchPattern checks the regular expression matching Tekton references to update.
+// Renovate patterns are JavaScript regular expressions, so only the syntax errors common to both flavors are reported.
+// The pattern is also a part of the pull request notes template, where a quote or braces would break the template.
+func ValidateMatchPattern(pattern string) error {
+	if strings.ContainsAny(pattern, "'{}") {
+		return fmt.Errorf("invalid match pattern %q: quotes and braces are not allowed", pattern)
+	}
+	if err := checkRegexpSyntax(pattern); err != nil {
+		return fmt.Errorf("invalid match pattern %q: %w", pattern, err)
+	}
+	return nil
+}
+
+// ValidateAllowedCommand checks the regular expression of the commands allowed in post upgrade tasks,
+// renovate refuses to start with an invalid one.
+func ValidateAllowedCommand(pattern string) error {
+	if err := checkRegexpSyntax(pattern); err != nil {
+		return fmt.Errorf("invalid allowed post upgrade command %q: %w", pattern, err)
+	}
+	return nil
+}
+
+// checkRegexpSyntax returns the syntax errors of the regular expression which are common to Go and JavaScript flavors.
+func checkRegexpSyntax(pattern string) error {
+	if _, err := syntax.Parse(pattern, syntax.Perl); err != nil {
+		if syntaxErr, ok := err.(*syntax.Error); ok {
+			switch syntaxErr.Code {
+			case syntax.ErrMissingBracket, syntax.ErrMissingParen, syntax.ErrUnexpectedParen, syntax.ErrTrailingBackslash,
+				syntax.ErrMissingRepeatArgument, syntax.ErrInvalidCharRange:
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ValidateTemplate checks that the handlebars expressions of the commit or pull request template are closed.
+func ValidateTemplate(template string) error {
+	if strings.Count(template, "{{") != strings.Count(template, "}}") {
+		return fmt.Errorf("invalid template %q: unbalanced handlebars braces", template)
+	}
+	return nil
+}
+
+// FormatGitAuthor returns the git author in the 'Name <email>' form renovate expects.
+func FormatGitAuthor(name, email string) string {
+	return fmt.Sprintf("%s <%s>", strings.TrimSpace(name), strings.TrimSpace(email))
+}
+
+// ValidateGitAuthor checks that the git author has the 'Name <email>' form, renovate fails on authors it can't parse.
+func ValidateGitAuthor(author string) error {
+	if !gitAuthorRegexp.MatchString(author) {
+		return fmt.Errorf("invalid git author %q: expected 'Name <email>'", author)
+	}
+	return nil
+}
+
+// ValidateBranchName checks that the branch name is a valid git reference name, see 'git check-ref-format'.
+func ValidateBranchName(branch string) error {
+	invalid := func(reason string) error {
+		return fmt.Errorf("invalid branch name %q: %s", branch, reason)
+	}
+	if branch == "" || branch == "@" {
+		return invalid("empty name")
+	}
+	for _, r := range branch {
+		if r < 0x20 || r == 0x7f || strings.ContainsRune(" ~^:?*[\\", r) {
+			return invalid(fmt.Sprintf("character %q is not allowed", r))
+		}
+	}
+	if strings.Contains(branch, "..") || strings.Contains(branch, "@{") || strings.Contains(branch, "//") {
+		return invalid("'..', '@{' and '//' are not allowed")
+	}
+	if strings.HasPrefix(branch, "/") || strings.HasSuffix(branch, "/") || strings.HasSuffix(branch, ".") {
+		return invalid("must not start or end with '/' or end with '.'")
+	}
+	for _, part := range strings.Split(branch, "/") {
+		if strings.HasPrefix(part, ".") || strings.HasSuffix(part, ".lock") {
+			return invalid("components must not start with '.' or end with '.lock'")
+		}
+	}
+	return nil
 }
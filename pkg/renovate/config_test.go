@@ -0,0 +1,383 @@
+package renovate
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/utils/ptr"
+
+	buildappstudiov1alpha1 "github.com/konflux-ci/build-service/api/v1alpha1"
+	"github.com/konflux-ci/build-service/pkg/git"
+)
+
+func TestNewTektonJobConfigAutomerge(t *testing.T) {
+	settings := Settings{MatchPatterns: []string{DefaultRenovateMatchPattern}}
+	repositories := []*Repository{
+		{Repository: "org/repo1", BaseBranches: []string{"main"}},
+		{Repository: "org/repo2", BaseBranches: []string{"main"}, Automerge: ptr.To(false)},
+	}
+
+	config := NewTektonJobConfig("github", "https://api.github.com/", "", "author", repositories, settings)
+	assert.Nil(t, config.Tekton.PackageRules[1].Automerge)
+	assert.Nil(t, config.Repositories[0].Tekton)
+	assert.Equal(t, ptr.To(false), config.Repositories[1].Tekton.PackageRules[0].Automerge)
+	assert.Equal(t, []string{DefaultRenovateMatchPattern}, config.Repositories[1].Tekton.PackageRules[0].MatchPackagePatterns)
+	// The task repositories are not modified
+	assert.Nil(t, repositories[1].Tekton)
+
+	settings.Automerge = true
+	config = NewTektonJobConfig("github", "https://api.github.com/", "", "author", repositories, settings)
+	assert.Equal(t, ptr.To(true), config.Tekton.PackageRules[1].Automerge)
+	assert.Equal(t, ptr.To(true), config.Tekton.PackageRules[1].PlatformAutomerge)
+}
+
+func TestNewTektonJobConfigTemplates(t *testing.T) {
+	settings := Settings{MatchPatterns: []string{DefaultRenovateMatchPattern}}
+	config := NewTektonJobConfig("github", "https://api.github.com/", "", "author", nil, settings)
+	assert.Equal(t, DefaultCommitMessageTopic, config.Tekton.PackageRules[1].CommitMessageTopic)
+	assert.Empty(t, config.Tekton.PackageRules[1].CommitMessagePrefix)
+	assert.Empty(t, config.Tekton.PackageRules[1].PRTitle)
+
+	settings.Templates = buildappstudiov1alpha1.RenovateTemplates{
+		CommitMessagePrefix: "chore(deps):",
+		CommitMessageTopic:  "Konflux references",
+		PRTitle:             "Update Konflux references on {{baseBranch}}",
+	}
+	config = NewTektonJobConfig("github", "https://api.github.com/", "", "author", nil, settings)
+	assert.Equal(t, "Konflux references", config.Tekton.PackageRules[1].CommitMessageTopic)
+	assert.Equal(t, "chore(deps):", config.Tekton.PackageRules[1].CommitMessagePrefix)
+	assert.Equal(t, "Update Konflux references on {{baseBranch}}", config.Tekton.PackageRules[1].PRTitle)
+}
+
+func TestNewTektonJobConfigSemanticCommits(t *testing.T) {
+	settings := Settings{MatchPatterns: []string{DefaultRenovateMatchPattern}}
+	config := NewTektonJobConfig("github", "https://api.github.com/", "", "author", nil, settings)
+	assert.Equal(t, DefaultSemanticCommits, config.Tekton.PackageRules[1].SemanticCommits)
+	assert.Empty(t, config.Tekton.PackageRules[1].SemanticCommitType)
+	assert.Empty(t, config.Tekton.PackageRules[1].SemanticCommitScope)
+
+	settings.Templates = buildappstudiov1alpha1.RenovateTemplates{SemanticCommits: "auto", SemanticCommitType: "build", SemanticCommitScope: "tekton"}
+	config = NewTektonJobConfig("github", "https://api.github.com/", "", "author", nil, settings)
+	assert.Equal(t, "auto", config.Tekton.PackageRules[1].SemanticCommits)
+	assert.Equal(t, "build", config.Tekton.PackageRules[1].SemanticCommitType)
+	assert.Equal(t, "tekton", config.Tekton.PackageRules[1].SemanticCommitScope)
+
+	config.Tekton.PackageRules[1].SemanticCommitScope = "{{parentDir}"
+	assert.Error(t, config.Validate())
+}
+
+func TestNewTektonJobConfigManagers(t *testing.T) {
+	settings := Settings{MatchPatterns: []string{DefaultRenovateMatchPattern}}
+	config := NewTektonJobConfig("github", "https://api.github.com/", "", "author", nil, settings)
+	assert.Equal(t, []string{"tekton"}, config.EnabledManagers)
+	data, err := json.Marshal(config)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(data), "dockerfile")
+
+	settings.Managers = []buildappstudiov1alpha1.RenovateManager{
+		{Name: "tekton", IncludePaths: []string{"ignored/**"}},
+		{Name: "dockerfile", IncludePaths: []string{"docker/**"}},
+		{Name: "gomod"},
+	}
+	config = NewTektonJobConfig("github", "https://api.github.com/", "", "author", nil, settings)
+	assert.Equal(t, []string{"tekton", "dockerfile", "gomod"}, config.EnabledManagers)
+
+	data, err = json.Marshal(config)
+	assert.NoError(t, err)
+	var rendered map[string]interface{}
+	assert.NoError(t, json.Unmarshal(data, &rendered))
+	assert.Equal(t, map[string]interface{}{"includePaths": []interface{}{"docker/**"}}, rendered["dockerfile"])
+	assert.Equal(t, map[string]interface{}{}, rendered["gomod"])
+	assert.Equal(t, []interface{}{".tekton/**"}, rendered["tekton"].(map[string]interface{})["includePaths"])
+	assert.Equal(t, "github", rendered["platform"])
+}
+
+func TestNewTektonJobConfigIncludePaths(t *testing.T) {
+	settings := Settings{MatchPatterns: []string{DefaultRenovateMatchPattern}}
+	config := NewTektonJobConfig("github", "https://api.github.com/", "", "author", nil, settings)
+	assert.Equal(t, []string{DefaultIncludePath}, config.Tekton.IncludePaths)
+
+	settings.IncludePaths = []string{".tekton/**", "ci/tekton/**"}
+	config = NewTektonJobConfig("github", "https://api.github.com/", "", "author", nil, settings)
+	assert.Equal(t, []string{".tekton/**", "ci/tekton/**"}, config.Tekton.IncludePaths)
+}
+
+func TestNewTektonJobConfigMatchPatterns(t *testing.T) {
+	settings := Settings{MatchPatterns: []string{DefaultRenovateMatchPattern, "^registry.internal/konflux-ci/"}}
+	repositories := []*Repository{{Repository: "org/repo", BaseBranches: []string{"main"}, Automerge: ptr.To(true)}}
+	config := NewTektonJobConfig("github", "https://api.github.com/", "", "author", repositories, settings)
+
+	assert.Len(t, config.Tekton.PackageRules, 3)
+	assert.Equal(t, DisableAllPackageRules, config.Tekton.PackageRules[0])
+	for i, pattern := range settings.MatchPatterns {
+		rule := config.Tekton.PackageRules[i+1]
+		assert.Equal(t, []string{pattern}, rule.MatchPackagePatterns)
+		assert.Equal(t, []string{pattern}, rule.MatchDepPatterns)
+		assert.Contains(t, rule.PRBodyDefinitions, pattern)
+		// All references are updated in the same pull request
+		assert.Equal(t, config.Tekton.PackageRules[1].BranchName, rule.BranchName)
+		assert.Equal(t, config.Tekton.PackageRules[1].GroupName, rule.GroupName)
+	}
+	assert.Equal(t, settings.MatchPatterns, config.Repositories[0].Tekton.PackageRules[0].MatchPackagePatterns)
+}
+
+func TestNewTektonJobConfigMonorepo(t *testing.T) {
+	settings := Settings{MatchPatterns: []string{DefaultRenovateMatchPattern}}
+	repositories := []*Repository{
+		{Repository: "org/repo", BaseBranches: []string{"main"}, Components: []string{"component1"}},
+		{Repository: "org/monorepo", BaseBranches: []string{"main"}, Components: []string{"frontend", "backend"}, Automerge: ptr.To(true)},
+	}
+	config := NewTektonJobConfig("github", "https://api.github.com/", "", "author", repositories, settings)
+
+	assert.Nil(t, config.Repositories[0].Tekton)
+	// The updates of each Component of the monorepo are proposed separately
+	rules := config.Repositories[1].Tekton.PackageRules
+	assert.Len(t, rules, 3)
+	assert.Equal(t, ptr.To(true), rules[0].Automerge)
+	assert.Equal(t, []string{".tekton/frontend-push.yaml", ".tekton/frontend-pull-request.yaml"}, rules[1].MatchFileNames)
+	assert.Equal(t, ReferencesBranchName+"-frontend", rules[1].BranchName)
+	assert.Equal(t, ReferencesGroupName+" frontend", rules[1].GroupName)
+	assert.Equal(t, []string{".tekton/backend-push.yaml", ".tekton/backend-pull-request.yaml"}, rules[2].MatchFileNames)
+	assert.Equal(t, ReferencesBranchName+"-backend", rules[2].BranchName)
+	assert.Equal(t, []string{DefaultRenovateMatchPattern}, rules[2].MatchDepPatterns)
+	assert.Nil(t, rules[2].Automerge)
+
+	settings.BranchPrefix = "deps/"
+	config = NewTektonJobConfig("github", "https://api.github.com/", "", "author", repositories, settings)
+	assert.Equal(t, "deps/references/{{baseBranch}}-frontend", config.Repositories[1].Tekton.PackageRules[1].BranchName)
+}
+
+func TestNewTektonJobConfigPinDigests(t *testing.T) {
+	settings := Settings{MatchPatterns: []string{DefaultRenovateMatchPattern}}
+	config := NewTektonJobConfig("github", "https://api.github.com/", "", "author", nil, settings)
+	assert.Nil(t, config.Tekton.PackageRules[1].PinDigests)
+	data, err := json.Marshal(config)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(data), "pinDigests")
+
+	settings.PinDigests = true
+	config = NewTektonJobConfig("github", "https://api.github.com/", "", "author", nil, settings)
+	assert.Nil(t, config.Tekton.PackageRules[0].PinDigests)
+	assert.Equal(t, ptr.To(true), config.Tekton.PackageRules[1].PinDigests)
+}
+
+func TestNewTektonJobConfigGroupAllUpdates(t *testing.T) {
+	settings := Settings{MatchPatterns: []string{DefaultRenovateMatchPattern}}
+	config := NewTektonJobConfig("github", "https://api.github.com/", "", "author", nil, settings)
+	assert.Empty(t, config.PackageRules)
+	assert.Nil(t, config.Tekton.PackageRules[1].SeparateMajorMinor)
+
+	settings.GroupAllUpdates = true
+	settings.Managers = []buildappstudiov1alpha1.RenovateManager{{Name: "dockerfile"}}
+	config = NewTektonJobConfig("github", "https://api.github.com/", "", "author", nil, settings)
+	assert.Len(t, config.PackageRules, 1)
+	assert.Equal(t, []string{"*"}, config.PackageRules[0].MatchPackagePatterns)
+	assert.Equal(t, ReferencesBranchName, config.PackageRules[0].BranchName)
+	assert.Equal(t, ptr.To(false), config.PackageRules[0].SeparateMajorMinor)
+	assert.Equal(t, ReferencesBranchName, config.Tekton.PackageRules[1].BranchName)
+	assert.Equal(t, ptr.To(false), config.Tekton.PackageRules[1].SeparateMajorMinor)
+	assert.Equal(t, ptr.To(false), config.Tekton.PackageRules[1].SeparateMultipleMajor)
+}
+
+func TestNewTektonJobConfigPostUpgradeTasks(t *testing.T) {
+	settings := Settings{MatchPatterns: []string{DefaultRenovateMatchPattern}}
+	config := NewTektonJobConfig("github", "https://api.github.com/", "", "author", nil, settings)
+	assert.Nil(t, config.Tekton.PackageRules[1].PostUpgradeTasks)
+	assert.Empty(t, config.AllowedPostUpgradeCommands)
+
+	settings.PostUpgradeTasks = &buildappstudiov1alpha1.RenovatePostUpgradeTasks{
+		Commands:      []string{"kustomize build .tekton > .tekton/generated.yaml"},
+		FileFilters:   []string{".tekton/**"},
+		ExecutionMode: "branch",
+	}
+	config = NewTektonJobConfig("github", "https://api.github.com/", "", "author", nil, settings)
+	assert.Nil(t, config.Tekton.PackageRules[0].PostUpgradeTasks)
+	assert.Equal(t, &PostUpgradeTasks{Commands: settings.PostUpgradeTasks.Commands, FileFilters: []string{".tekton/**"}, ExecutionMode: "branch"}, config.Tekton.PackageRules[1].PostUpgradeTasks)
+	// Only the exact commands are allowed by default
+	assert.Equal(t, []string{`^kustomize build \.tekton > \.tekton/generated\.yaml$`}, config.AllowedPostUpgradeCommands)
+
+	settings.PostUpgradeTasks.AllowedCommands = []string{"^kustomize "}
+	config = NewTektonJobConfig("github", "https://api.github.com/", "", "author", nil, settings)
+	assert.Equal(t, []string{"^kustomize "}, config.AllowedPostUpgradeCommands)
+}
+
+func TestNewTektonJobConfigPullRequestLimits(t *testing.T) {
+	settings := Settings{MatchPatterns: []string{DefaultRenovateMatchPattern}}
+	data, err := json.Marshal(NewTektonJobConfig("github", "https://api.github.com/", "", "author", nil, settings))
+	assert.NoError(t, err)
+	assert.NotContains(t, string(data), "prHourlyLimit")
+	assert.NotContains(t, string(data), "prConcurrentLimit")
+
+	// Zero disables the limit, so it's rendered too
+	settings.PRHourlyLimit = ptr.To(0)
+	settings.PRConcurrentLimit = ptr.To(5)
+	data, err = json.Marshal(NewTektonJobConfig("github", "https://api.github.com/", "", "author", nil, settings))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `"prHourlyLimit":0`)
+	assert.Contains(t, string(data), `"prConcurrentLimit":5`)
+}
+
+func TestNewTektonJobConfigStopUpdatingLabel(t *testing.T) {
+	settings := Settings{MatchPatterns: []string{DefaultRenovateMatchPattern}}
+	config := NewTektonJobConfig("github", "https://api.github.com/", "", "author", nil, settings)
+	assert.Empty(t, config.StopUpdatingLabel)
+	assert.NotContains(t, config.Tekton.PackageRules[1].PRFooter, "label")
+
+	settings.StopUpdatingLabel = "keep-changes"
+	config = NewTektonJobConfig("github", "https://api.github.com/", "", "author", nil, settings)
+	assert.Equal(t, "keep-changes", config.StopUpdatingLabel)
+	// Developers learn about the label from the pull request
+	assert.Contains(t, config.Tekton.PackageRules[1].PRFooter, "add the `keep-changes` label")
+}
+
+func TestNewTektonJobConfigGitAuthor(t *testing.T) {
+	settings := Settings{MatchPatterns: []string{DefaultRenovateMatchPattern}}
+	repositories := []*Repository{
+		{Repository: "org/repo1", BaseBranches: []string{"main"}},
+		{Repository: "org/repo2", BaseBranches: []string{"main"}, GitAuthor: "Tenant Bot <bot@tenant.example.com>"},
+	}
+	config := NewTektonJobConfig("github", "https://api.github.com/", "", "author", repositories, settings)
+	assert.Equal(t, "author", config.GitAuthor)
+
+	settings.GitAuthor = "Konflux <konflux@example.com>"
+	config = NewTektonJobConfig("github", "https://api.github.com/", "", "author", repositories, settings)
+	assert.Equal(t, "Konflux <konflux@example.com>", config.GitAuthor)
+	// The author of the tenant is rendered into its repository and takes precedence over the global one
+	assert.Empty(t, config.Repositories[0].GitAuthor)
+	assert.Equal(t, "Tenant Bot <bot@tenant.example.com>", config.Repositories[1].GitAuthor)
+}
+
+func TestNewTektonJobConfigRebaseWhen(t *testing.T) {
+	settings := Settings{MatchPatterns: []string{DefaultRenovateMatchPattern}}
+	config := NewTektonJobConfig("github", "https://api.github.com/", "", "author", nil, settings)
+	assert.Equal(t, DefaultRebaseWhen, config.Tekton.PackageRules[1].RebaseWhen)
+
+	settings.RebaseWhen = "conflicted"
+	config = NewTektonJobConfig("github", "https://api.github.com/", "", "author", nil, settings)
+	assert.Equal(t, "conflicted", config.Tekton.PackageRules[1].RebaseWhen)
+}
+
+func TestNewTektonJobConfigBranchPrefix(t *testing.T) {
+	settings := Settings{MatchPatterns: []string{DefaultRenovateMatchPattern}, GroupAllUpdates: true}
+	config := NewTektonJobConfig("github", "https://api.github.com/", "", "author", nil, settings)
+	assert.Empty(t, config.BranchPrefix)
+	assert.Equal(t, ReferencesBranchName, config.Tekton.PackageRules[1].BranchName)
+	assert.Equal(t, ReferencesBranchName, config.PackageRules[0].BranchName)
+
+	settings.BranchPrefix = "deps/"
+	config = NewTektonJobConfig("github", "https://api.github.com/", "", "author", nil, settings)
+	assert.Equal(t, "deps/", config.BranchPrefix)
+	assert.Equal(t, "deps/references/{{baseBranch}}", config.Tekton.PackageRules[1].BranchName)
+	assert.Equal(t, "deps/references/{{baseBranch}}", config.PackageRules[0].BranchName)
+}
+
+func TestNewTektonJobConfigPruneStaleBranches(t *testing.T) {
+	settings := Settings{MatchPatterns: []string{DefaultRenovateMatchPattern}}
+	config := NewTektonJobConfig("github", "https://api.github.com/", "", "author", nil, settings)
+	assert.Nil(t, config.PruneStaleBranches)
+	// The references branches are pruned together with the branches of the default prefix
+	assert.Equal(t, ReferencesBranchPrefix, config.BranchPrefixOld)
+
+	settings.BranchPrefix = "deps/"
+	config = NewTektonJobConfig("github", "https://api.github.com/", "", "author", nil, settings)
+	assert.Equal(t, ReferencesBranchPrefix, config.BranchPrefixOld)
+
+	settings.PruneStaleBranches = ptr.To(false)
+	config = NewTektonJobConfig("github", "https://api.github.com/", "", "author", nil, settings)
+	assert.Equal(t, ptr.To(false), config.PruneStaleBranches)
+	assert.Empty(t, config.BranchPrefixOld)
+}
+
+func TestRepositoryAddComponentSettingsKeepStaleBranches(t *testing.T) {
+	component, err := git.NewScmComponent("github", "https://github.com/org/repo", "main", "component", "tenant")
+	assert.NoError(t, err)
+	repository := &Repository{Repository: component.Repository()}
+	repository.AddComponentSettings(component)
+	assert.Nil(t, repository.PruneStaleBranches)
+
+	// Base branches of other Components of the repository which are not renovated would be considered stale
+	otherComponent, err := git.NewScmComponent("github", "https://github.com/org/repo", "release", "other", "tenant")
+	assert.NoError(t, err)
+	otherComponent.SetKeepStaleBranches(true)
+	repository.AddComponentSettings(otherComponent)
+	assert.Equal(t, ptr.To(false), repository.PruneStaleBranches)
+	repository.AddComponentSettings(component)
+	assert.Equal(t, ptr.To(false), repository.PruneStaleBranches)
+}
+
+func TestValidateGitAuthor(t *testing.T) {
+	assert.NoError(t, ValidateGitAuthor(FormatGitAuthor(" Konflux Bot ", "konflux@example.com")))
+	for _, author := range []string{"", "Konflux", "konflux@example.com", "<konflux@example.com>", "Konflux <konflux>", "Konflux <a b@example.com>", "Konflux <konflux@example.com"} {
+		assert.Error(t, ValidateGitAuthor(author), author)
+	}
+}
+
+func TestValidateMatchPattern(t *testing.T) {
+	assert.NoError(t, ValidateMatchPattern(DefaultRenovateMatchPattern))
+	assert.NoError(t, ValidateMatchPattern("^quay.io/(konflux-ci|redhat-appstudio)/"))
+	// JavaScript only syntax is left to renovate
+	assert.NoError(t, ValidateMatchPattern("^quay.io/(?!ignored/)"))
+	assert.Error(t, ValidateMatchPattern("^quay.io/konflux-ci'/"))
+	assert.Error(t, ValidateMatchPattern("^quay.io/{{name}}/"))
+	assert.Error(t, ValidateMatchPattern("^quay.io/(konflux-ci/"))
+	assert.Error(t, ValidateMatchPattern("^quay.io/[konflux-ci/"))
+	assert.Error(t, ValidateMatchPattern("^quay.io/[abc"))
+	assert.Error(t, ValidateMatchPattern("*quay.io/"))
+}
+
+func TestValidateBranchName(t *testing.T) {
+	for _, branch := range []string{"main", "release/1.0", "feature-x_y", "v1.2.3"} {
+		assert.NoError(t, ValidateBranchName(branch), branch)
+	}
+	for _, branch := range []string{"", "@", "my branch", "main~1", "a:b", "a..b", "a@{1}", "/main", "main/", "a//b", "main.", ".hidden", "a/.b", "main.lock", "a\\b", "a*"} {
+		assert.Error(t, ValidateBranchName(branch), branch)
+	}
+}
+
+func TestJobConfigValidate(t *testing.T) {
+	settings := Settings{MatchPatterns: []string{DefaultRenovateMatchPattern}}
+	repositories := []*Repository{{Repository: "org/repo", BaseBranches: []string{"main"}}}
+	assert.NoError(t, NewTektonJobConfig("github", "https://api.github.com/", "", "author", repositories, settings).Validate())
+
+	repositories[0].BaseBranches = append(repositories[0].BaseBranches, "bad branch")
+	assert.ErrorContains(t, NewTektonJobConfig("github", "https://api.github.com/", "", "author", repositories, settings).Validate(), "org/repo")
+
+	repositories[0].BaseBranches = []string{"main"}
+	settings.Templates.PRTitle = "Update {{depName"
+	assert.Error(t, NewTektonJobConfig("github", "https://api.github.com/", "", "author", repositories, settings).Validate())
+}
+
+func TestMergeConfigOverlay(t *testing.T) {
+	settings := Settings{MatchPatterns: []string{DefaultRenovateMatchPattern}}
+	config, err := json.Marshal(NewTektonJobConfig("github", "https://api.github.com/", "", "author", nil, settings))
+	assert.NoError(t, err)
+	overlay := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal([]byte(`{
+		"labels": ["konflux"],
+		"dependencyDashboard": true,
+		"tekton": {"includePaths": ["ci/**"], "packageRules": [{"matchPackagePatterns": ["^quay.io/other/"], "enabled": true}]}
+	}`), &overlay))
+
+	merged, err := MergeConfigOverlay(config, overlay)
+	assert.NoError(t, err)
+	result := map[string]interface{}{}
+	assert.NoError(t, json.Unmarshal(merged, &result))
+	assert.Equal(t, []interface{}{"konflux"}, result["labels"])
+	assert.Equal(t, true, result["dependencyDashboard"])
+	assert.Equal(t, "github", result["platform"])
+	tekton := result["tekton"].(map[string]interface{})
+	assert.Equal(t, []interface{}{"ci/**"}, tekton["includePaths"])
+	assert.Equal(t, []interface{}{"\\.yaml$", "\\.yml$"}, tekton["fileMatch"])
+	// The overlay rule is appended to the generated ones
+	rules := tekton["packageRules"].([]interface{})
+	assert.Len(t, rules, 3)
+	assert.Equal(t, []interface{}{"^quay.io/other/"}, rules[2].(map[string]interface{})["matchPackagePatterns"])
+
+	// The overlay values are validated like the generated ones
+	assert.NoError(t, json.Unmarshal([]byte(`{"tekton": {"packageRules": [{"matchDepPatterns": ["quay.io/{org}"]}]}}`), &overlay))
+	_, err = MergeConfigOverlay(config, overlay)
+	assert.ErrorContains(t, err, "invalid renovate config overlay")
+	_, err = MergeConfigOverlay(config, map[string]interface{}{"repositories": "org/repo"})
+	assert.ErrorContains(t, err, "invalid renovate config overlay")
+}
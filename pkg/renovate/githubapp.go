@@ -2,76 +2,252 @@ package renovate
 
 import (
 	"context"
+	goerrors "errors"
 	"fmt"
+	"sort"
+	"time"
 
 	"k8s.io/apimachinery/pkg/api/errors"
 	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
 
+	"github.com/konflux-ci/build-service/pkg/bometrics"
 	"github.com/konflux-ci/build-service/pkg/git"
 	"github.com/konflux-ci/build-service/pkg/git/github"
 	"github.com/konflux-ci/build-service/pkg/git/githubapp"
+	"github.com/konflux-ci/build-service/pkg/logs"
 )
 
 // GithubAppRenovaterTaskProvider is an implementation of TaskProvider that provides Renovate tasks for GitHub App installations.
+// Installations of all configured GitHub Apps are merged, a repository is handled by the first App which has it installed.
 type GithubAppRenovaterTaskProvider struct {
-	appConfigReader githubapp.ConfigReader
+	appConfigReader githubapp.MultiConfigReader
+	// Whether the numbers of matched installations and repositories are exported as metrics
+	matchMetrics bool
+	// Whether the tasks are split per namespace of the Components, with tokens scoped to the repositories of the namespace
+	tenantScoped bool
+	// Settings deciding which namespaces own the repositories, used only if the tasks are tenant scoped
+	tenantSettings Settings
+	// Time when the rate limit of an App left out by the last GetNewTasks call resets, shared by the provider copies
+	rateLimitReset *time.Time
+	// Number of installations which couldn't be listed by the last GetNewTasks call, shared by the provider copies
+	failedInstallations *int
 }
 
-func NewGithubAppRenovaterTaskProvider(appConfigReader githubapp.ConfigReader) GithubAppRenovaterTaskProvider {
-	return GithubAppRenovaterTaskProvider{appConfigReader: appConfigReader}
+func NewGithubAppRenovaterTaskProvider(appConfigReader githubapp.MultiConfigReader) GithubAppRenovaterTaskProvider {
+	return GithubAppRenovaterTaskProvider{appConfigReader: appConfigReader, rateLimitReset: &time.Time{}, failedInstallations: new(int)}
 }
+
+// Failures returns the number of installations left out by the last GetNewTasks call, because they couldn't be listed.
+func (g GithubAppRenovaterTaskProvider) Failures() int {
+	return *g.failedInstallations
+}
+
+// RetryAfter returns the time until the rate limit of the Apps whose installations were left out resets.
+func (g GithubAppRenovaterTaskProvider) RetryAfter() time.Duration {
+	if g.rateLimitReset.IsZero() {
+		return 0
+	}
+	// The reset may have passed already, the retry is never immediate
+	if retryAfter := time.Until(*g.rateLimitReset); retryAfter > time.Minute {
+		return retryAfter
+	}
+	return time.Minute
+}
+
+// WithMatchMetrics returns the task provider which exports the numbers of installations and repositories
+// matched with Components. It's meant for sweeps over all Components, other callers would overwrite the metrics
+// with partial numbers.
+func (g GithubAppRenovaterTaskProvider) WithMatchMetrics() GithubAppRenovaterTaskProvider {
+	g.matchMetrics = true
+	return g
+}
+
+// TenantScoped returns the task provider which creates a task for each namespace of the Components of an installation.
+// The tokens of the tasks are valid only for the repositories of the namespace, instead of the whole installation.
+// A namespace gets only the repositories of the GitHub owners it owns according to the settings, the other repositories
+// of the installation are renovated by a task without namespace, in the namespace of the renovate TaskRuns.
+func (g GithubAppRenovaterTaskProvider) TenantScoped(settings Settings) TaskProvider {
+	g.tenantScoped = true
+	g.tenantSettings = settings
+	return g
+}
+
 func (g GithubAppRenovaterTaskProvider) GetNewTasks(ctx context.Context, components []*git.ScmComponent) []*Task {
 	log := ctrllog.FromContext(ctx)
-	githubAppId, privateKey, err := g.appConfigReader.GetConfig(ctx)
-	if err != nil {
-		if !errors.IsNotFound(err) {
-			log.Error(err, "failed to get GitHub App configuration")
+	*g.rateLimitReset = time.Time{}
+	*g.failedInstallations = 0
+	// No installation can match without GitHub Components, so the installations are not listed and no tokens are created
+	if len(git.PlatformToComponentMap(components)["github"]) == 0 {
+		log.V(logs.DebugLevel).Info("no GitHub Components, skipping listing of GitHub App installations")
+		if g.matchMetrics {
+			bometrics.RenovateInstallationsMatchedMetric.Set(0)
+			bometrics.RenovateRepositoriesMatchedMetric.Set(0)
 		}
 		return nil
 	}
-	githubAppInstallations, slug, err := github.GetAllAppInstallations(githubAppId, privateKey)
+	githubAppConfigs, err := g.appConfigReader.GetConfigs(ctx)
 	if err != nil {
-		log.Error(err, "failed to get GitHub App installations")
+		if !errors.IsNotFound(err) {
+			log.Error(err, "failed to get GitHub App configuration")
+		}
 		return nil
 	}
 	componentUrlToBranchesMap := git.ComponentUrlToBranchesMap(components)
+	componentUrlToComponentsMap := git.ComponentUrlToComponentsMap(components)
+	processedRepositories := map[string]bool{}
+	skippedRepositories := map[string]bool{}
+	matchedInstallations := 0
 
-	// Match installed repositories with Components and get custom branch if defined
 	var newTasks []*Task
-	for _, githubAppInstallation := range githubAppInstallations {
-		var repositories []*Repository
-		for _, repository := range githubAppInstallation.Repositories {
-			branches, ok := componentUrlToBranchesMap[repository.GetHTMLURL()]
-			// Filter repositories with installed GH App but missing Component
-			if !ok {
+	for _, githubAppConfig := range githubAppConfigs {
+		githubAppInstallations, slug, err := github.GetAllAppInstallations(githubAppConfig.AppId, githubAppConfig.PrivateKeyPem)
+		var partialErr *github.PartialInstallationsError
+		if goerrors.As(err, &partialErr) {
+			// The listed installations are still renovated
+			if partialErr.RateLimit != nil {
+				log.Info("skipping some GitHub App installations, rate limit nearly exhausted", "appId", githubAppConfig.AppId, "remaining", partialErr.RateLimit.Remaining, "reset", partialErr.RateLimit.Reset)
+				g.backOffUntil(partialErr.RateLimit.Reset)
+			}
+			if len(partialErr.Failed) > 0 {
+				log.Error(err, "failed to list some GitHub App installations", "appId", githubAppConfig.AppId)
+				*g.failedInstallations += len(partialErr.Failed)
+			}
+		} else if err != nil {
+			var rateLimitErr *github.AppRateLimitError
+			if goerrors.As(err, &rateLimitErr) {
+				// The installations are left out until the rate limit resets, instead of failing some of them
+				log.Info("skipping GitHub App installations, rate limit nearly exhausted", "appId", githubAppConfig.AppId, "remaining", rateLimitErr.Remaining, "reset", rateLimitErr.Reset)
+				g.backOffUntil(rateLimitErr.Reset)
+				continue
+			}
+			log.Error(err, "failed to get GitHub App installations", "appId", githubAppConfig.AppId)
+			continue
+		}
+
+		// Match installed repositories with Components and get custom branch if defined
+		for _, githubAppInstallation := range githubAppInstallations {
+			var repositories []*Repository
+			// Repositories not owned by the namespace of their Components, renovated with the installation token
+			var unownedRepositories []*Repository
+			namespaceRepositories := map[string][]*Repository{}
+			namespaceRepositoryIds := map[string][]int64{}
+			for _, repository := range githubAppInstallation.Repositories {
+				branches, ok := componentUrlToBranchesMap[repository.GetHTMLURL()]
+				// Filter repositories with installed GH App but missing Component
+				if !ok {
+					skippedRepositories[repository.GetHTMLURL()] = true
+					continue
+				}
+				// Skip repositories already handled by another GitHub App
+				if processedRepositories[repository.GetHTMLURL()] {
+					continue
+				}
+				processedRepositories[repository.GetHTMLURL()] = true
+				// Components tracking different branches of the same repository are all covered,
+				// each branch is listed once even if it's referenced implicitly as the default branch
+				renovateRepository := &Repository{
+					BaseBranches: []string{},
+					Repository:   repository.GetFullName(),
+				}
+				for _, branch := range branches {
+					if branch == git.InternalDefaultBranch {
+						branch = repository.GetDefaultBranch()
+					}
+					renovateRepository.AddBranch(branch)
+				}
+				for _, component := range componentUrlToComponentsMap[repository.GetHTMLURL()] {
+					renovateRepository.AddComponentSettings(component)
+				}
+				repositories = append(repositories, renovateRepository)
+				if g.tenantScoped {
+					namespace := getTenantNamespace(componentUrlToComponentsMap[repository.GetHTMLURL()])
+					if !g.tenantSettings.IsTenantRepositoryOwner(namespace, repository.GetOwner().GetLogin()) {
+						log.V(logs.DebugLevel).Info("namespace doesn't own the repository, renovating it outside of the namespace", "namespace", namespace, "repository", repository.GetFullName())
+						unownedRepositories = append(unownedRepositories, renovateRepository)
+						continue
+					}
+					namespaceRepositories[namespace] = append(namespaceRepositories[namespace], renovateRepository)
+					namespaceRepositoryIds[namespace] = append(namespaceRepositoryIds[namespace], repository.GetID())
+				}
+			}
+			// Do not add installation which has no matching repositories
+			if len(repositories) == 0 {
 				continue
 			}
-			for i := range branches {
-				if branches[i] == git.InternalDefaultBranch {
-					branches[i] = repository.GetDefaultBranch()
+			matchedInstallations++
+			if g.tenantScoped {
+				newTasks = append(newTasks, g.newTenantGithubTasks(ctx, githubAppConfig, slug, githubAppInstallation.ID, namespaceRepositories, namespaceRepositoryIds)...)
+				if len(unownedRepositories) > 0 {
+					newTasks = append(newTasks, newGithubTask(slug, githubAppInstallation.Token, unownedRepositories))
 				}
+				continue
 			}
-
-			repositories = append(repositories, &Repository{
-				BaseBranches: branches,
-				Repository:   repository.GetFullName(),
-			})
+			newTasks = append(newTasks, newGithubTask(slug, githubAppInstallation.Token, repositories))
 		}
-		// Do not add installation which has no matching repositories
-		if len(repositories) == 0 {
+	}
+	if g.matchMetrics {
+		bometrics.RenovateInstallationsMatchedMetric.Set(float64(matchedInstallations))
+		bometrics.RenovateRepositoriesMatchedMetric.Set(float64(len(processedRepositories)))
+		bometrics.RenovateRepositoriesSkippedNoComponentMetric.Set(float64(len(skippedRepositories)))
+	}
+	return newTasks
+}
+
+// newTenantGithubTasks returns a task for each namespace, with the installation token scoped to the repositories of the namespace.
+// The namespaces whose token can't be created are left out, the token of the whole installation is never handed over to a tenant.
+func (g GithubAppRenovaterTaskProvider) newTenantGithubTasks(ctx context.Context, githubAppConfig githubapp.Config, slug string, installationId int64, namespaceRepositories map[string][]*Repository, namespaceRepositoryIds map[string][]int64) []*Task {
+	log := ctrllog.FromContext(ctx)
+	namespaces := make([]string, 0, len(namespaceRepositories))
+	for namespace := range namespaceRepositories {
+		namespaces = append(namespaces, namespace)
+	}
+	sort.Strings(namespaces)
+	var tasks []*Task
+	for _, namespace := range namespaces {
+		token, err := github.CreateInstallationTokenForRepositories(githubAppConfig.AppId, githubAppConfig.PrivateKeyPem, installationId, namespaceRepositoryIds[namespace])
+		if err != nil {
+			var rateLimitErr *github.AppRateLimitError
+			if goerrors.As(err, &rateLimitErr) {
+				log.Info("skipping GitHub App installation repositories, rate limit nearly exhausted", "appId", githubAppConfig.AppId, "installationId", installationId, "namespace", namespace, "reset", rateLimitErr.Reset)
+				g.backOffUntil(rateLimitErr.Reset)
+				continue
+			}
+			log.Error(err, "failed to create GitHub App installation token for namespace repositories", "appId", githubAppConfig.AppId, "installationId", installationId, "namespace", namespace)
+			*g.failedInstallations++
 			continue
 		}
-		newTasks = append(newTasks, newGithubTask(slug, githubAppInstallation.Token, repositories))
+		task := newGithubTask(slug, token, namespaceRepositories[namespace])
+		task.Namespace = namespace
+		tasks = append(tasks, task)
 	}
-	return newTasks
+	return tasks
+}
+
+// backOffUntil postpones the retry of the left out installations until the given rate limit reset.
+func (g GithubAppRenovaterTaskProvider) backOffUntil(reset time.Time) {
+	if g.rateLimitReset.IsZero() || reset.After(*g.rateLimitReset) {
+		*g.rateLimitReset = reset
+	}
+}
+
+// getTenantNamespace returns the namespace a repository is renovated in, the first one by name
+// if the repository is shared by Components of several namespaces.
+func getTenantNamespace(components []*git.ScmComponent) string {
+	namespace := ""
+	for _, component := range components {
+		if namespace == "" || component.NamespaceName() < namespace {
+			namespace = component.NamespaceName()
+		}
+	}
+	return namespace
 }
 
 func newGithubTask(slug string, token string, repositories []*Repository) *Task {
 	return &Task{
 		Platform:     "github",
-		Endpoint:     git.BuildAPIEndpoint("github").APIEndpoint("github.com"),
+		Endpoint:     github.GetAPIEndpoint(),
 		Username:     fmt.Sprintf("%s[bot]", slug),
-		GitAuthor:    fmt.Sprintf("%s <123456+%s[bot]@users.noreply.github.com>", slug, slug),
+		GitAuthor:    fmt.Sprintf("%s <123456+%s[bot]@users.noreply.%s>", slug, slug, github.GetHost()),
 		Token:        token,
 		Repositories: repositories,
 	}
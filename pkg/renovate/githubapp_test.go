@@ -0,0 +1,269 @@
+package renovate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	gogithub "github.com/google/go-github/v45/github"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/konflux-ci/build-service/pkg/bometrics"
+	"github.com/konflux-ci/build-service/pkg/git"
+	"github.com/konflux-ci/build-service/pkg/git/github"
+	"github.com/konflux-ci/build-service/pkg/git/githubapp"
+)
+
+type staticConfigReader []githubapp.Config
+
+func (s staticConfigReader) GetConfigs(ctx context.Context) ([]githubapp.Config, error) {
+	return s, nil
+}
+
+func newRepository(owner, name string) *gogithub.Repository {
+	return &gogithub.Repository{
+		FullName:      gogithub.String(owner + "/" + name),
+		HTMLURL:       gogithub.String("https://github.com/" + owner + "/" + name),
+		DefaultBranch: gogithub.String("main"),
+		Owner:         &gogithub.User{Login: gogithub.String(owner)},
+	}
+}
+
+func TestGithubAppNewTasks(t *testing.T) {
+	defer func(f func(string, []byte) ([]github.ApplicationInstallation, string, error)) {
+		github.GetAllAppInstallations = f
+	}(github.GetAllAppInstallations)
+
+	installations := map[string][]github.ApplicationInstallation{
+		"1": {{Token: "token-1", Repositories: []*gogithub.Repository{newRepository("org1", "repo1"), newRepository("org1", "repo2")}}},
+		"2": {{Token: "token-2", Repositories: []*gogithub.Repository{newRepository("org1", "repo1"), newRepository("org2", "repo1")}}},
+	}
+	github.GetAllAppInstallations = func(githubAppIdStr string, appPrivateKeyPem []byte) ([]github.ApplicationInstallation, string, error) {
+		return installations[githubAppIdStr], "app" + githubAppIdStr, nil
+	}
+
+	components := []*git.ScmComponent{
+		ignoreError(git.NewScmComponent("github", "https://github.com/org1/repo1", "", "repo1", "tenant")).(*git.ScmComponent),
+		ignoreError(git.NewScmComponent("github", "https://github.com/org2/repo1", "develop", "repo1", "tenant")).(*git.ScmComponent),
+	}
+
+	taskProvider := NewGithubAppRenovaterTaskProvider(staticConfigReader{{AppId: "1"}, {AppId: "2"}})
+	got := taskProvider.GetNewTasks(context.TODO(), components)
+
+	assert.Equal(t, []*Task{
+		newGithubTask("app1", "token-1", []*Repository{{Repository: "org1/repo1", BaseBranches: []string{"main"}, Components: []string{"repo1"}}}),
+		newGithubTask("app2", "token-2", []*Repository{{Repository: "org2/repo1", BaseBranches: []string{"develop"}, Components: []string{"repo1"}}}),
+	}, got)
+}
+
+func TestGithubAppNewTasksCollectsAllBranches(t *testing.T) {
+	defer func(f func(string, []byte) ([]github.ApplicationInstallation, string, error)) {
+		github.GetAllAppInstallations = f
+	}(github.GetAllAppInstallations)
+
+	github.GetAllAppInstallations = func(githubAppIdStr string, appPrivateKeyPem []byte) ([]github.ApplicationInstallation, string, error) {
+		return []github.ApplicationInstallation{{Token: "token-1", Repositories: []*gogithub.Repository{newRepository("org1", "repo1")}}}, "app1", nil
+	}
+
+	components := []*git.ScmComponent{
+		ignoreError(git.NewScmComponent("github", "https://github.com/org1/repo1", "", "component1", "tenant")).(*git.ScmComponent),
+		ignoreError(git.NewScmComponent("github", "https://github.com/org1/repo1", "release-1.0", "component2", "tenant")).(*git.ScmComponent),
+		ignoreError(git.NewScmComponent("github", "https://github.com/org1/repo1", "main", "component3", "tenant")).(*git.ScmComponent),
+		ignoreError(git.NewScmComponent("github", "https://github.com/org1/repo1", "release-1.0", "component4", "other-tenant")).(*git.ScmComponent),
+	}
+
+	taskProvider := NewGithubAppRenovaterTaskProvider(staticConfigReader{{AppId: "1"}})
+	got := taskProvider.GetNewTasks(context.TODO(), components)
+
+	assert.Equal(t, []*Task{
+		newGithubTask("app1", "token-1", []*Repository{{Repository: "org1/repo1", BaseBranches: []string{"main", "release-1.0"}, Components: []string{"component1", "component2", "component3", "component4"}}}),
+	}, got)
+}
+
+func TestGithubAppNewTasksExportsMatchMetrics(t *testing.T) {
+	defer func(f func(string, []byte) ([]github.ApplicationInstallation, string, error)) {
+		github.GetAllAppInstallations = f
+	}(github.GetAllAppInstallations)
+
+	installations := map[string][]github.ApplicationInstallation{
+		"1": {
+			{Token: "token-1", Repositories: []*gogithub.Repository{newRepository("org1", "repo1"), newRepository("org1", "repo2")}},
+			{Token: "token-2", Repositories: []*gogithub.Repository{newRepository("org2", "repo1")}},
+		},
+		"2": {{Token: "token-3", Repositories: []*gogithub.Repository{newRepository("org1", "repo1"), newRepository("org1", "repo2"), newRepository("org3", "repo1")}}},
+	}
+	github.GetAllAppInstallations = func(githubAppIdStr string, appPrivateKeyPem []byte) ([]github.ApplicationInstallation, string, error) {
+		return installations[githubAppIdStr], "app" + githubAppIdStr, nil
+	}
+	components := []*git.ScmComponent{
+		ignoreError(git.NewScmComponent("github", "https://github.com/org1/repo1", "", "repo1", "tenant")).(*git.ScmComponent),
+		ignoreError(git.NewScmComponent("github", "https://github.com/org3/repo1", "", "repo3", "tenant")).(*git.ScmComponent),
+	}
+
+	// Partial task lists don't change the metrics
+	bometrics.RenovateInstallationsMatchedMetric.Set(-1)
+	NewGithubAppRenovaterTaskProvider(staticConfigReader{{AppId: "1"}, {AppId: "2"}}).GetNewTasks(context.TODO(), components)
+	assert.Equal(t, float64(-1), testutil.ToFloat64(bometrics.RenovateInstallationsMatchedMetric))
+
+	NewGithubAppRenovaterTaskProvider(staticConfigReader{{AppId: "1"}, {AppId: "2"}}).WithMatchMetrics().GetNewTasks(context.TODO(), components)
+	assert.Equal(t, float64(2), testutil.ToFloat64(bometrics.RenovateInstallationsMatchedMetric))
+	assert.Equal(t, float64(2), testutil.ToFloat64(bometrics.RenovateRepositoriesMatchedMetric))
+	// The repository without Component is counted once, even if installed in more Apps
+	assert.Equal(t, float64(2), testutil.ToFloat64(bometrics.RenovateRepositoriesSkippedNoComponentMetric))
+}
+
+func TestGithubAppNewTasksBacksOffWhenRateLimitIsExhausted(t *testing.T) {
+	defer func(f func(string, []byte) ([]github.ApplicationInstallation, string, error)) {
+		github.GetAllAppInstallations = f
+	}(github.GetAllAppInstallations)
+
+	reset := time.Now().Add(30 * time.Minute)
+	rateLimited := true
+	github.GetAllAppInstallations = func(githubAppIdStr string, appPrivateKeyPem []byte) ([]github.ApplicationInstallation, string, error) {
+		if githubAppIdStr == "2" && rateLimited {
+			return nil, "", &github.AppRateLimitError{Remaining: 10, Reset: reset}
+		}
+		return []github.ApplicationInstallation{{Token: "token-" + githubAppIdStr, Repositories: []*gogithub.Repository{newRepository("org"+githubAppIdStr, "repo1")}}}, "app" + githubAppIdStr, nil
+	}
+	components := []*git.ScmComponent{
+		ignoreError(git.NewScmComponent("github", "https://github.com/org1/repo1", "", "repo1", "tenant")).(*git.ScmComponent),
+		ignoreError(git.NewScmComponent("github", "https://github.com/org2/repo1", "", "repo2", "tenant")).(*git.ScmComponent),
+	}
+	taskProvider := NewGithubAppRenovaterTaskProvider(staticConfigReader{{AppId: "1"}, {AppId: "2"}})
+
+	// Installations of the other App are still provided
+	assert.Equal(t, []*Task{
+		newGithubTask("app1", "token-1", []*Repository{{Repository: "org1/repo1", BaseBranches: []string{"main"}, Components: []string{"repo1"}}}),
+	}, taskProvider.GetNewTasks(context.TODO(), components))
+	assert.InDelta(t, 30*time.Minute, taskProvider.RetryAfter(), float64(time.Minute))
+
+	rateLimited = false
+	assert.Len(t, taskProvider.GetNewTasks(context.TODO(), components), 2)
+	assert.Zero(t, taskProvider.RetryAfter())
+
+	// The retry is postponed if the reset has passed already
+	reset = time.Now().Add(-time.Minute)
+	rateLimited = true
+	taskProvider.GetNewTasks(context.TODO(), components)
+	assert.Equal(t, time.Minute, taskProvider.RetryAfter())
+}
+
+func TestGithubAppNewTasksBacksOffWhenListingStopsOnRateLimit(t *testing.T) {
+	defer func(f func(string, []byte) ([]github.ApplicationInstallation, string, error)) {
+		github.GetAllAppInstallations = f
+	}(github.GetAllAppInstallations)
+
+	reset := time.Now().Add(30 * time.Minute)
+	github.GetAllAppInstallations = func(githubAppIdStr string, appPrivateKeyPem []byte) ([]github.ApplicationInstallation, string, error) {
+		installations := []github.ApplicationInstallation{{Token: "token-1", Repositories: []*gogithub.Repository{newRepository("org1", "repo1")}}}
+		return installations, "app1", &github.PartialInstallationsError{AppId: githubAppIdStr, Failed: map[int64]error{}, RateLimit: &github.AppRateLimitError{Remaining: 10, Reset: reset}}
+	}
+	components := []*git.ScmComponent{
+		ignoreError(git.NewScmComponent("github", "https://github.com/org1/repo1", "", "repo1", "tenant")).(*git.ScmComponent),
+	}
+	taskProvider := NewGithubAppRenovaterTaskProvider(staticConfigReader{{AppId: "1"}})
+
+	// The installations listed before the limit got low are still provided
+	assert.Equal(t, []*Task{
+		newGithubTask("app1", "token-1", []*Repository{{Repository: "org1/repo1", BaseBranches: []string{"main"}, Components: []string{"repo1"}}}),
+	}, taskProvider.GetNewTasks(context.TODO(), components))
+	assert.Zero(t, taskProvider.Failures())
+	assert.InDelta(t, 30*time.Minute, taskProvider.RetryAfter(), float64(time.Minute))
+}
+
+func TestGithubAppNewTasksReportsPartialFailures(t *testing.T) {
+	defer func(f func(string, []byte) ([]github.ApplicationInstallation, string, error)) {
+		github.GetAllAppInstallations = f
+	}(github.GetAllAppInstallations)
+
+	partial := true
+	github.GetAllAppInstallations = func(githubAppIdStr string, appPrivateKeyPem []byte) ([]github.ApplicationInstallation, string, error) {
+		installations := []github.ApplicationInstallation{{Token: "token-1", Repositories: []*gogithub.Repository{newRepository("org1", "repo1")}}}
+		if partial {
+			return installations, "app1", &github.PartialInstallationsError{AppId: githubAppIdStr, Failed: map[int64]error{2: errors.New("not found"), 3: errors.New("not found")}}
+		}
+		return installations, "app1", nil
+	}
+	components := []*git.ScmComponent{
+		ignoreError(git.NewScmComponent("github", "https://github.com/org1/repo1", "", "repo1", "tenant")).(*git.ScmComponent),
+	}
+	taskProvider := NewGithubAppRenovaterTaskProvider(staticConfigReader{{AppId: "1"}})
+
+	// The listed installations are still provided
+	assert.Equal(t, []*Task{
+		newGithubTask("app1", "token-1", []*Repository{{Repository: "org1/repo1", BaseBranches: []string{"main"}, Components: []string{"repo1"}}}),
+	}, taskProvider.GetNewTasks(context.TODO(), components))
+	assert.Equal(t, 2, taskProvider.Failures())
+
+	partial = false
+	assert.Len(t, taskProvider.GetNewTasks(context.TODO(), components), 1)
+	assert.Zero(t, taskProvider.Failures())
+}
+
+func TestGithubAppNewTasksSkipsInstallationsWithoutGithubComponents(t *testing.T) {
+	defer func(f func(string, []byte) ([]github.ApplicationInstallation, string, error)) {
+		github.GetAllAppInstallations = f
+	}(github.GetAllAppInstallations)
+
+	github.GetAllAppInstallations = func(githubAppIdStr string, appPrivateKeyPem []byte) ([]github.ApplicationInstallation, string, error) {
+		t.Errorf("GitHub App installations listed without GitHub Components")
+		return nil, "", nil
+	}
+	components := []*git.ScmComponent{
+		ignoreError(git.NewScmComponent("gitlab", "https://gitlab.com/org1/repo1", "", "repo1", "tenant")).(*git.ScmComponent),
+	}
+	taskProvider := NewGithubAppRenovaterTaskProvider(staticConfigReader{{AppId: "1"}}).WithMatchMetrics()
+
+	bometrics.RenovateInstallationsMatchedMetric.Set(5)
+	assert.Empty(t, taskProvider.GetNewTasks(context.TODO(), components))
+	assert.Empty(t, taskProvider.GetNewTasks(context.TODO(), nil))
+	assert.Equal(t, float64(0), testutil.ToFloat64(bometrics.RenovateInstallationsMatchedMetric))
+}
+
+func TestGithubAppNewTasksScopesTokensToTenants(t *testing.T) {
+	defer func(f func(string, []byte) ([]github.ApplicationInstallation, string, error)) {
+		github.GetAllAppInstallations = f
+	}(github.GetAllAppInstallations)
+	defer func(f func(string, []byte, int64, []int64) (string, error)) {
+		github.CreateInstallationTokenForRepositories = f
+	}(github.CreateInstallationTokenForRepositories)
+
+	var repositories []*gogithub.Repository
+	for i, repository := range []*gogithub.Repository{newRepository("org1", "repo1"), newRepository("org1", "repo2"), newRepository("org1", "repo3"), newRepository("org2", "repo4")} {
+		repository.ID = gogithub.Int64(int64(i + 1))
+		repositories = append(repositories, repository)
+	}
+	github.GetAllAppInstallations = func(githubAppIdStr string, appPrivateKeyPem []byte) ([]github.ApplicationInstallation, string, error) {
+		return []github.ApplicationInstallation{{Token: "installation-token", ID: 5, Repositories: repositories}}, "app1", nil
+	}
+	github.CreateInstallationTokenForRepositories = func(githubAppIdStr string, appPrivateKeyPem []byte, installationId int64, repositoryIds []int64) (string, error) {
+		if repositoryIds[0] == 3 {
+			return "", errors.New("forbidden")
+		}
+		return fmt.Sprintf("token-%d-%v", installationId, repositoryIds), nil
+	}
+
+	components := []*git.ScmComponent{
+		ignoreError(git.NewScmComponent("github", "https://github.com/org1/repo1", "", "repo1", "tenant-b")).(*git.ScmComponent),
+		ignoreError(git.NewScmComponent("github", "https://github.com/org1/repo1", "", "repo1", "tenant-a")).(*git.ScmComponent),
+		ignoreError(git.NewScmComponent("github", "https://github.com/org1/repo2", "", "repo2", "tenant-b")).(*git.ScmComponent),
+		ignoreError(git.NewScmComponent("github", "https://github.com/org1/repo3", "", "repo3", "tenant-c")).(*git.ScmComponent),
+		ignoreError(git.NewScmComponent("github", "https://github.com/org2/repo4", "", "repo4", "tenant-a")).(*git.ScmComponent),
+	}
+	settings := Settings{TenantRepositoryOwners: map[string][]string{"tenant-a": {"Org1"}, "tenant-b": {"org1"}, "tenant-c": {"org1"}}}
+	taskProvider := NewGithubAppRenovaterTaskProvider(staticConfigReader{{AppId: "1"}})
+	got := taskProvider.TenantScoped(settings).GetNewTasks(context.TODO(), components)
+
+	// The shared repository is renovated in the first namespace, the namespace without token is left out
+	// and the repository the namespace doesn't own is renovated with the installation token outside of the namespace
+	tenantA := newGithubTask("app1", "token-5-[1]", []*Repository{{Repository: "org1/repo1", BaseBranches: []string{"main"}, Components: []string{"repo1"}}})
+	tenantA.Namespace = "tenant-a"
+	tenantB := newGithubTask("app1", "token-5-[2]", []*Repository{{Repository: "org1/repo2", BaseBranches: []string{"main"}, Components: []string{"repo2"}}})
+	tenantB.Namespace = "tenant-b"
+	unowned := newGithubTask("app1", "installation-token", []*Repository{{Repository: "org2/repo4", BaseBranches: []string{"main"}, Components: []string{"repo4"}}})
+	assert.Equal(t, []*Task{tenantA, tenantB, unowned}, got)
+	assert.Equal(t, 1, taskProvider.Failures())
+}
@@ -4,21 +4,30 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"os"
-	"regexp"
-	"strconv"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	logger "sigs.k8s.io/controller-runtime/pkg/log"
 
+	imagename "github.com/google/go-containerregistry/pkg/name"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/pod"
+	tektonapi "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+
+	buildappstudiov1alpha1 "github.com/konflux-ci/build-service/api/v1alpha1"
+	"github.com/konflux-ci/build-service/pkg/bometrics"
 	. "github.com/konflux-ci/build-service/pkg/common"
 	"github.com/konflux-ci/build-service/pkg/logs"
 )
@@ -29,181 +38,875 @@ const (
 	TimeToLiveOfJob            = 24 * time.Hour
 	RenovateImageEnvName       = "RENOVATE_IMAGE"
 	DefaultRenovateImageUrl    = "quay.io/redhat-appstudio/renovate:v37.74.1"
+	CosignImageEnvName         = "RENOVATE_COSIGN_IMAGE"
+	DefaultCosignImage         = "gcr.io/projectsigstore/cosign:v2.2.3"
+	// Label set on all renovate jobs
+	RenovateJobLabelName = "build.appstudio.openshift.io/renovate-job"
+	// Label set on renovate jobs running renovate in dry-run mode
+	RenovateDryRunLabelName = "build.appstudio.openshift.io/renovate-dry-run"
+	// Annotation with comma separated keys of the tasks executed by the renovate job
+	RenovateTasksAnnotationName = "build.appstudio.openshift.io/renovate-tasks"
+	PendingJobsCheckInterval    = 30 * time.Second
+	ConfigsMountPath            = "/configs"
+	// Tokens are mounted as files, so they are not exposed in the pod environment
+	TokensMountPath  = "/tokens"
+	tokensVolumeName = "tokens"
+	// Additional trusted CA certificates are mounted from the configured ConfigMap
+	TrustedCAMountPath    = "/etc/renovate/ca"
+	TrustedCAConfigMapKey = "ca-bundle.crt"
+	trustedCAVolumeName   = "trusted-ca"
+	// Cosign public key of the renovate image is mounted from the configured ConfigMap
+	CosignKeyMountPath    = "/etc/renovate/cosign"
+	CosignKeyConfigMapKey = "cosign.pub"
+	cosignKeyVolumeName   = "cosign-key"
+	renovateContainerName = "renovate"
+	// Renovate logs are written to the configured PersistentVolumeClaim
+	LogsMountPath  = "/logs"
+	logsVolumeName = "logs"
+	// Key of the job secret with renovate host rules, it doesn't collide with the generated task ids
+	hostRulesSecretKey = "host-rules"
+	// Default type of the hosts in RenovateTektonConfig host rules
+	DefaultHostRuleType = "docker"
+	// Service account of the renovate Job pods, without any permissions.
+	// The name includes the prefix added by the kustomize deployment, see config/rbac/renovate_job_service_account.yaml
+	RenovateJobServiceAccountName = "build-service-renovate-job"
 )
 
 // JobCoordinator is responsible for creating and managing renovate k8s jobs
 type JobCoordinator struct {
-	tasksPerJob      int
-	renovateImageUrl string
-	debug            bool
-	client           client.Client
-	scheme           *runtime.Scheme
+	debug  bool
+	client client.Client
+	scheme *runtime.Scheme
+	// Reports invalid renovate config overlay, optional
+	eventRecorder record.EventRecorder
+
+	pendingLock sync.Mutex
+	// Chunks of tasks waiting for a free slot in the limit of parallel jobs
+	pending []pendingJob
+	// Sweep jobs which are created again if they fail, by job name
+	retryable map[string]pendingJob
+	// Chunks of tasks of failed sweep jobs waiting for the retry backoff
+	retries []retryJob
+}
+
+// pendingJob is a chunk of tasks executed by a single renovate job.
+type pendingJob struct {
+	tasks []*Task
+	// Number of earlier failed jobs of the same chunk
+	attempt int32
+	// Time the tasks were created at, the tokens of the tasks expire after an hour
+	createdAt time.Time
+}
+
+// retryJob is a chunk of tasks of a failed job which is queued again once the backoff passes.
+type retryJob struct {
+	pendingJob
+	retryAt time.Time
 }
 
 func NewJobCoordinator(client client.Client, scheme *runtime.Scheme) *JobCoordinator {
-	var tasksPerJobInt int
-	tasksPerJobStr := os.Getenv(InstallationsPerJobEnvName)
-	if regexp.MustCompile(`^\d{1,2}$`).MatchString(tasksPerJobStr) {
-		tasksPerJobInt, _ = strconv.Atoi(tasksPerJobStr)
-		if tasksPerJobInt == 0 {
-			tasksPerJobInt = TasksPerJob
+	return &JobCoordinator{client: client, scheme: scheme, debug: false, retryable: map[string]pendingJob{}}
+}
+
+// WithEventRecorder sets the recorder of the events about invalid renovate config overlay.
+func (j *JobCoordinator) WithEventRecorder(eventRecorder record.EventRecorder) *JobCoordinator {
+	j.eventRecorder = eventRecorder
+	return j
+}
+
+// Settings returns the current renovate jobs settings.
+// RenovateTektonConfig is read on every call, so its changes are applied on the next sweep.
+func (j *JobCoordinator) Settings(ctx context.Context) Settings {
+	log := logger.FromContext(ctx)
+	settings := NewSettingsFromEnv()
+
+	renovateConfig := &buildappstudiov1alpha1.RenovateTektonConfig{}
+	if err := j.client.Get(ctx, types.NamespacedName{Name: buildappstudiov1alpha1.RenovateTektonConfigName}, renovateConfig); err != nil {
+		if !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+			log.Error(err, "failed to read RenovateTektonConfig, using defaults", logs.Action, logs.ActionView)
 		}
-	} else {
-		tasksPerJobInt = TasksPerJob
+		return settings
 	}
-	renovateImageUrl := os.Getenv(RenovateImageEnvName)
-	if renovateImageUrl == "" {
-		renovateImageUrl = DefaultRenovateImageUrl
-	}
-	return &JobCoordinator{tasksPerJob: tasksPerJobInt, renovateImageUrl: renovateImageUrl, client: client, scheme: scheme, debug: false}
+	return settings.WithConfig(renovateConfig)
 }
 
 func (j *JobCoordinator) Execute(ctx context.Context, tasks []*Task) error {
+	_, err := j.execute(ctx, tasks, j.Settings(ctx), nil)
+	return err
+}
+
+// execute creates a renovate job for the given tasks and returns its name.
+func (j *JobCoordinator) execute(ctx context.Context, tasks []*Task, settings Settings, labels map[string]string) (string, error) {
 
 	if len(tasks) == 0 {
-		return nil
+		return "", nil
 	}
 	log := logger.FromContext(ctx)
+	// A tag can be moved to another image after the signature is verified
+	if settings.CosignKeyConfigMapName != "" {
+		if _, err := imagename.NewDigest(settings.RenovateImage()); err != nil {
+			return "", fmt.Errorf("renovate image must be referenced by digest when its signature is verified: %w", err)
+		}
+	}
 
 	timestamp := time.Now().Unix()
 	name := fmt.Sprintf("renovate-job-%d-%s", timestamp, RandomString(5))
 	log.Info(fmt.Sprintf("Creating renovate job %s for %d unique sets of scm repositories", name, len(tasks)))
 
+	// The tasks of a chunk share the namespace, see chunkJobTasks
+	namespace := settings.RunNamespace()
+	hostRulesNamespace := BuildServiceNamespaceName
+	if settings.IsTenantScoped() && tasks[0].Namespace != "" {
+		namespace = tasks[0].Namespace
+		// Registry credentials of the build-service namespace are not handed over to the tenants
+		hostRulesNamespace = namespace
+	}
+
 	secretTokens := map[string]string{}
 	configMapData := map[string]string{}
+	hostRulesEnv := ""
+	if hostRules := j.getHostRules(ctx, settings, hostRulesNamespace); len(hostRules) > 0 {
+		hostRulesJson, err := json.Marshal(hostRules)
+		if err != nil {
+			return "", err
+		}
+		secretTokens[hostRulesSecretKey] = string(hostRulesJson)
+		hostRulesEnv = fmt.Sprintf("RENOVATE_HOST_RULES=$(cat %s/%s) ", TokensMountPath, hostRulesSecretKey)
+	}
+	configOverlay, configOverlayConfigMap := j.getConfigOverlay(ctx, settings)
+	renovateArgs := ""
+	if settings.DryRun {
+		renovateArgs = " --dry-run=full"
+	}
 	var renovateCmd []string
+	var taskKeys []string
 	for _, task := range tasks {
+		taskKeys = append(taskKeys, task.Key())
 		taskId := RandomString(5)
 		secretTokens[taskId] = task.Token
 
-		config, err := json.Marshal(task.JobConfig())
+		jobConfig := task.JobConfig(settings)
+		if err := jobConfig.Validate(); err != nil {
+			return "", fmt.Errorf("invalid renovate config of %s: %w", task.Endpoint, err)
+		}
+		config, err := json.Marshal(jobConfig)
 		if err != nil {
-			return err
+			return "", err
+		}
+		if configOverlay != nil {
+			// An invalid overlay must not fail the jobs, the generated config is used instead
+			if mergedConfig, err := MergeConfigOverlay(config, configOverlay); err != nil {
+				log.Error(err, "invalid renovate config overlay, using generated config", "configmap", configOverlayConfigMap.Name)
+				j.recordInvalidConfigOverlay(configOverlayConfigMap, err)
+			} else {
+				config = mergedConfig
+			}
 		}
 		configMapData[fmt.Sprintf("%s.json", taskId)] = string(config)
 
 		log.Info(fmt.Sprintf("Creating renovate config map entry with length %d and value %s", len(config), config))
 		renovateCmd = append(renovateCmd,
-			fmt.Sprintf("RENOVATE_TOKEN=$TOKEN_%s RENOVATE_CONFIG_FILE=/configs/%s.json renovate", taskId, taskId),
+			fmt.Sprintf("%s=$(cat %s/%s) %sRENOVATE_CONFIG_FILE=%s/%s.json renovate%s", task.CredentialsEnvName(), TokensMountPath, taskId, hostRulesEnv, ConfigsMountPath, taskId, renovateArgs),
 		)
 	}
 	if len(renovateCmd) == 0 {
-		return nil
+		return "", nil
 	}
 
 	secret := &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      name,
-			Namespace: BuildServiceNamespaceName,
+			Namespace: namespace,
 		},
 		StringData: secretTokens,
 	}
 	configMap := &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      name,
-			Namespace: BuildServiceNamespaceName,
+			Namespace: namespace,
 		},
 		Data: configMapData,
 	}
 
-	job := &batchv1.Job{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      name,
-			Namespace: BuildServiceNamespaceName,
+	jobLabels := map[string]string{RenovateJobLabelName: "true"}
+	for key, value := range labels {
+		jobLabels[key] = value
+	}
+	if settings.DryRun {
+		jobLabels[RenovateDryRunLabelName] = "true"
+	}
+	objectMeta := metav1.ObjectMeta{
+		Name:      name,
+		Namespace: namespace,
+		Labels:    jobLabels,
+		Annotations: map[string]string{
+			RenovateTasksAnnotationName: strings.Join(taskKeys, ","),
+		},
+	}
+	volumes := []corev1.Volume{
+		{
+			Name: name,
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{Name: name},
+				},
+			},
+		},
+		{
+			Name: tokensVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: name,
+				},
+			},
 		},
+	}
+	renovateScript := strings.Join(renovateCmd, "; ")
+	parallelism := settings.JobParallelism(len(tasks))
+	if parallelism > 1 {
+		// Each pod of the indexed job runs renovate for the task of its completion index, with its own log file
+		var cases []string
+		for i, cmd := range renovateCmd {
+			cases = append(cases, fmt.Sprintf("%d) %s;;", i, cmd))
+		}
+		renovateScript = `LOG_FILE="${LOG_FILE%.log}-${JOB_COMPLETION_INDEX}.log"; case "$JOB_COMPLETION_INDEX" in ` + strings.Join(cases, " ") + " esac"
+	}
+	// The exit code of renovate is kept, the report is best effort
+	container := corev1.Container{
+		Name:      renovateContainerName,
+		Image:     settings.RenovateImage(),
+		Command:   []string{"bash", "-c", strings.Join([]string{renovateScript, "status=$?", reportScript, "exit $status"}, "; ")},
+		Resources: settings.Resources,
+		Env:       settings.ProxyEnv(),
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      name,
+				MountPath: ConfigsMountPath,
+			},
+			{
+				Name:      tokensVolumeName,
+				MountPath: TokensMountPath,
+				ReadOnly:  true,
+			},
+		},
+		SecurityContext: &corev1.SecurityContext{
+			Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+			RunAsNonRoot:             ptr.To(true),
+			AllowPrivilegeEscalation: ptr.To(false),
+			SeccompProfile: &corev1.SeccompProfile{
+				Type: corev1.SeccompProfileTypeRuntimeDefault,
+			},
+		},
+	}
+	if settings.TrustedCAConfigMapName != "" {
+		volumes = append(volumes, corev1.Volume{
+			Name: trustedCAVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{Name: settings.TrustedCAConfigMapName},
+					Items:                []corev1.KeyToPath{{Key: TrustedCAConfigMapKey, Path: TrustedCAConfigMapKey}},
+				},
+			},
+		})
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      trustedCAVolumeName,
+			MountPath: TrustedCAMountPath,
+			ReadOnly:  true,
+		})
+		container.Env = append(container.Env, corev1.EnvVar{Name: "NODE_EXTRA_CA_CERTS", Value: TrustedCAMountPath + "/" + TrustedCAConfigMapKey})
+	}
+	logFile := DefaultLogFile
+	if settings.LogsVolumeClaimName != "" {
+		volumes = append(volumes, corev1.Volume{
+			Name: logsVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: settings.LogsVolumeClaimName},
+			},
+		})
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      logsVolumeName,
+			MountPath: LogsMountPath,
+		})
+		logFile = fmt.Sprintf("%s/%s.log", LogsMountPath, name)
+	}
+	// Renovate keeps logging to stdout, the file additionally gets the debug logs in JSON format
+	container.Env = append(container.Env, corev1.EnvVar{Name: "LOG_FILE", Value: logFile})
+	if settings.ExecutionMode == ExecutionModeTaskRun {
+		container.Env = append(container.Env, corev1.EnvVar{Name: reportPathEnvName, Value: fmt.Sprintf("$(results.%s.path)", PullRequestsResultName)})
+	} else {
+		container.Env = append(container.Env, corev1.EnvVar{Name: reportPathEnvName, Value: corev1.TerminationMessagePathDefault})
+	}
+	if j.debug {
+		container.Env = append(container.Env, corev1.EnvVar{Name: "LOG_LEVEL", Value: "debug"})
+	}
+	var initContainers []corev1.Container
+	if settings.CosignKeyConfigMapName != "" {
+		volumes = append(volumes, corev1.Volume{
+			Name: cosignKeyVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{Name: settings.CosignKeyConfigMapName},
+					Items:                []corev1.KeyToPath{{Key: CosignKeyConfigMapKey, Path: CosignKeyConfigMapKey}},
+				},
+			},
+		})
+		initContainers = append(initContainers, newImageVerificationContainer(container, settings))
+	}
+
+	var run client.Object
+	kind := ExecutionModeJob
+	if settings.ExecutionMode == ExecutionModeTaskRun {
+		run = newRenovateTaskRun(objectMeta, volumes, initContainers, container, settings)
+		kind = ExecutionModeTaskRun
+	} else {
+		job := newRenovateJob(objectMeta, volumes, initContainers, container, settings)
+		if parallelism > 1 {
+			job.Spec.CompletionMode = ptr.To(batchv1.IndexedCompletion)
+			job.Spec.Completions = ptr.To(int32(len(tasks)))
+			job.Spec.Parallelism = ptr.To(parallelism)
+			job.Spec.BackoffLimit = ptr.To(settings.BackoffLimit * int32(len(tasks)))
+		}
+		run = job
+	}
+	// The job is created suspended first, so the secret and the config map can be created with the owner reference
+	// and are garbage collected together with the job if any of the steps below fails.
+	if err := j.client.Create(ctx, run); err != nil {
+		return "", err
+	}
+	if err := j.createJobDependents(ctx, run, secret, configMap); err != nil {
+		if deleteErr := j.client.Delete(ctx, run, client.PropagationPolicy(metav1.DeletePropagationBackground)); deleteErr != nil && !errors.IsNotFound(deleteErr) {
+			log.Error(deleteErr, "failed to delete incomplete renovate job", "jobname", name, logs.Action, logs.ActionDelete)
+		}
+		return "", err
+	}
+	log.Info("renovate job created", "jobname", name, "kind", kind, "tasks", len(tasks), logs.Action, logs.ActionAdd)
+	bometrics.RenovateJobsCreatedTotalMetric.Inc()
+	return name, nil
+}
+
+// newImageVerificationContainer returns a container verifying the signature of the renovate container image by cosign.
+// It runs before renovate and doesn't mount the tokens, so an image with invalid signature never gets access to them.
+func newImageVerificationContainer(container corev1.Container, settings Settings) corev1.Container {
+	image := GetConfigValue(CosignImageEnvName)
+	if image == "" {
+		image = DefaultCosignImage
+	}
+	verifier := corev1.Container{
+		Name:  "verify-image",
+		Image: image,
+		Args:  []string{"verify", "--key", CosignKeyMountPath + "/" + CosignKeyConfigMapKey, container.Image},
+		Env:   settings.ProxyEnv(),
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      cosignKeyVolumeName,
+				MountPath: CosignKeyMountPath,
+				ReadOnly:  true,
+			},
+		},
+		SecurityContext: container.SecurityContext,
+	}
+	// The trusted CA bundle is needed to pull the signature from a self-hosted registry
+	if settings.TrustedCAConfigMapName != "" {
+		verifier.VolumeMounts = append(verifier.VolumeMounts, corev1.VolumeMount{
+			Name:      trustedCAVolumeName,
+			MountPath: TrustedCAMountPath,
+			ReadOnly:  true,
+		})
+		verifier.Env = append(verifier.Env, corev1.EnvVar{Name: "SSL_CERT_DIR", Value: TrustedCAMountPath})
+	}
+	return verifier
+}
+
+func newRenovateJob(objectMeta metav1.ObjectMeta, volumes []corev1.Volume, initContainers []corev1.Container, container corev1.Container, settings Settings) *batchv1.Job {
+	return &batchv1.Job{
+		ObjectMeta: objectMeta,
 		Spec: batchv1.JobSpec{
-			BackoffLimit:            ptr.To(int32(1)),
-			TTLSecondsAfterFinished: ptr.To(int32(TimeToLiveOfJob.Seconds())),
+			Suspend:                 ptr.To(true),
+			BackoffLimit:            ptr.To(settings.BackoffLimit),
+			TTLSecondsAfterFinished: ptr.To(settings.TTLSecondsAfterFinished),
 			Template: corev1.PodTemplateSpec{
+				// Only renovate job pods are cached by the operator
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{RenovateJobLabelName: "true"}},
 				Spec: corev1.PodSpec{
-					Volumes: []corev1.Volume{
-						{
-							Name: name,
-							VolumeSource: corev1.VolumeSource{
-								ConfigMap: &corev1.ConfigMapVolumeSource{
-									LocalObjectReference: corev1.LocalObjectReference{Name: name},
-								},
-							},
-						},
-					},
-					Containers: []corev1.Container{
-						{
-							Name:  "renovate",
-							Image: j.renovateImageUrl,
-							EnvFrom: []corev1.EnvFromSource{
-								{
-									Prefix: "TOKEN_",
-									SecretRef: &corev1.SecretEnvSource{
-										LocalObjectReference: corev1.LocalObjectReference{
-											Name: name,
-										},
-									},
-								},
-							},
-							Command: []string{"bash", "-c", strings.Join(renovateCmd, "; ")},
-							VolumeMounts: []corev1.VolumeMount{
-								{
-									Name:      name,
-									MountPath: "/configs",
-								},
-							},
-							SecurityContext: &corev1.SecurityContext{
-								Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
-								RunAsNonRoot:             ptr.To(true),
-								AllowPrivilegeEscalation: ptr.To(false),
-								SeccompProfile: &corev1.SeccompProfile{
-									Type: corev1.SeccompProfileTypeRuntimeDefault,
-								},
-							},
-						},
-					},
-					RestartPolicy: corev1.RestartPolicyNever,
+					Volumes:            volumes,
+					InitContainers:     initContainers,
+					Containers:         []corev1.Container{container},
+					RestartPolicy:      corev1.RestartPolicyNever,
+					NodeSelector:       settings.NodeSelector,
+					Tolerations:        settings.Tolerations,
+					Affinity:           settings.PodAffinity(),
+					PriorityClassName:  settings.PriorityClassName,
+					ServiceAccountName: settings.JobServiceAccountName(),
+					ImagePullSecrets:   settings.ImagePullSecrets,
+					// Renovate doesn't access the cluster API
+					AutomountServiceAccountToken: ptr.To(false),
 				},
 			},
 		},
 	}
-	if j.debug {
-		job.Spec.Template.Spec.Containers[0].Env = append(job.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{Name: "LOG_LEVEL", Value: "debug"})
+}
+
+// newRenovateTaskRun returns a TaskRun running the init containers and the renovate container as sequential steps.
+// Finished TaskRuns are not deleted after TTL, they are expected to be pruned by the Tekton pruner.
+func newRenovateTaskRun(objectMeta metav1.ObjectMeta, volumes []corev1.Volume, initContainers []corev1.Container, container corev1.Container, settings Settings) *tektonapi.TaskRun {
+	podTemplate := &pod.PodTemplate{
+		NodeSelector:     settings.NodeSelector,
+		Tolerations:      settings.Tolerations,
+		Affinity:         settings.PodAffinity(),
+		ImagePullSecrets: settings.ImagePullSecrets,
 	}
-	if err := j.client.Create(ctx, secret); err != nil {
-		return err
+	if settings.PriorityClassName != "" {
+		podTemplate.PriorityClassName = ptr.To(settings.PriorityClassName)
 	}
-	if err := j.client.Create(ctx, configMap); err != nil {
+	var steps []tektonapi.Step
+	for _, initContainer := range initContainers {
+		steps = append(steps, newTaskRunStep(initContainer))
+	}
+	steps = append(steps, newTaskRunStep(container))
+	return &tektonapi.TaskRun{
+		ObjectMeta: objectMeta,
+		Spec: tektonapi.TaskRunSpec{
+			Retries:            int(settings.BackoffLimit),
+			ServiceAccountName: settings.JobServiceAccountName(),
+			PodTemplate:        podTemplate,
+			TaskSpec: &tektonapi.TaskSpec{
+				Volumes: volumes,
+				Steps:   steps,
+				Results: []tektonapi.TaskResult{{Name: PullRequestsResultName, Type: tektonapi.ResultsTypeString}},
+			},
+		},
+	}
+}
+
+func newTaskRunStep(container corev1.Container) tektonapi.Step {
+	return tektonapi.Step{
+		Name:             container.Name,
+		Image:            container.Image,
+		Command:          container.Command,
+		Args:             container.Args,
+		Env:              container.Env,
+		ComputeResources: container.Resources,
+		VolumeMounts:     container.VolumeMounts,
+		SecurityContext:  container.SecurityContext,
+	}
+}
+
+// getHostRules returns renovate host rules with the credentials read from the Secrets referenced in the settings,
+// the Secrets are read from the given namespace.
+// Rules with missing or invalid Secrets are skipped, so the references in public registries are still updated.
+func (j *JobCoordinator) getHostRules(ctx context.Context, settings Settings, namespace string) []HostRule {
+	log := logger.FromContext(ctx)
+	var hostRules []HostRule
+	for _, rule := range settings.HostRules {
+		secret := &corev1.Secret{}
+		if err := j.client.Get(ctx, types.NamespacedName{Name: rule.SecretName, Namespace: namespace}, secret); err != nil {
+			log.Error(err, "failed to get renovate host rule secret, skipping the host rule", "secret", rule.SecretName, "namespace", namespace, "host", rule.MatchHost, logs.Action, logs.ActionView)
+			continue
+		}
+		username, password := string(secret.Data["username"]), string(secret.Data["password"])
+		if password == "" {
+			log.Info("renovate host rule secret has no password, skipping the host rule", "secret", rule.SecretName, "host", rule.MatchHost)
+			continue
+		}
+		hostType := rule.HostType
+		if hostType == "" {
+			hostType = DefaultHostRuleType
+		}
+		hostRules = append(hostRules, HostRule{HostType: hostType, MatchHost: rule.MatchHost, Username: username, Password: password})
+	}
+	return hostRules
+}
+
+// getConfigOverlay returns the renovate config merged into the generated configs, or nil if it's not configured.
+// A missing or invalid ConfigMap is logged and the generated configs are used as they are.
+func (j *JobCoordinator) getConfigOverlay(ctx context.Context, settings Settings) (map[string]interface{}, *corev1.ConfigMap) {
+	if settings.ConfigOverlayConfigMap == "" {
+		return nil, nil
+	}
+	log := logger.FromContext(ctx)
+	configMap := &corev1.ConfigMap{}
+	if err := j.client.Get(ctx, types.NamespacedName{Name: settings.ConfigOverlayConfigMap, Namespace: BuildServiceNamespaceName}, configMap); err != nil {
+		log.Error(err, "failed to get renovate config overlay ConfigMap, using generated config", "configmap", settings.ConfigOverlayConfigMap, logs.Action, logs.ActionView)
+		return nil, nil
+	}
+	overlay := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(configMap.Data[ConfigOverlayConfigMapKey]), &overlay); err != nil {
+		log.Error(err, "invalid renovate config overlay, using generated config", "configmap", settings.ConfigOverlayConfigMap, "key", ConfigOverlayConfigMapKey)
+		j.recordInvalidConfigOverlay(configMap, err)
+		return nil, nil
+	}
+	return overlay, configMap
+}
+
+// recordInvalidConfigOverlay reports the renovate config overlay which is ignored, because it's invalid.
+func (j *JobCoordinator) recordInvalidConfigOverlay(configMap *corev1.ConfigMap, err error) {
+	if j.eventRecorder != nil {
+		j.eventRecorder.Event(configMap, "Warning", "InvalidRenovateConfigOverlay", fmt.Sprintf("renovate config overlay is ignored: %s", err.Error()))
+	}
+}
+
+// createJobDependents creates the secret and the config map owned by the suspended job and resumes the job.
+// A TaskRun can't be suspended, its pod waits until the secret and the config map volumes are available.
+func (j *JobCoordinator) createJobDependents(ctx context.Context, run client.Object, secret *corev1.Secret, configMap *corev1.ConfigMap) error {
+	if err := controllerutil.SetOwnerReference(run, secret, j.scheme); err != nil {
 		return err
 	}
-	if err := j.client.Create(ctx, job); err != nil {
+	if err := j.client.Create(ctx, secret); err != nil {
 		return err
 	}
-	log.Info("renovate job created", "jobname", job.Name, "tasks", len(tasks), logs.Action, logs.ActionAdd)
-	if err := controllerutil.SetOwnerReference(job, secret, j.scheme); err != nil {
+	if err := controllerutil.SetOwnerReference(run, configMap, j.scheme); err != nil {
 		return err
 	}
-	if err := j.client.Update(ctx, secret); err != nil {
+	if err := j.client.Create(ctx, configMap); err != nil {
 		return err
 	}
+	job, ok := run.(*batchv1.Job)
+	if !ok {
+		return nil
+	}
+	patch := client.MergeFrom(job.DeepCopy())
+	job.Spec.Suspend = ptr.To(false)
+	return j.client.Patch(ctx, job, patch)
+}
 
-	if err := controllerutil.SetOwnerReference(job, configMap, j.scheme); err != nil {
+// ExecuteWithLimits creates renovate jobs for the given tasks, respecting the limit of tasks per job,
+// and returns the number of created jobs.
+// If the number of active renovate jobs reaches the limit of parallel jobs, the remaining jobs are queued
+// and created by ExecutePending once the earlier jobs finish. The queue of a previous call is replaced,
+// because its tasks are superseded by the new ones. The same applies to the failed jobs waiting for a retry.
+func (j *JobCoordinator) ExecuteWithLimits(ctx context.Context, tasks []*Task) (int, error) {
+	settings := j.Settings(ctx)
+	// Split before skipping so the keys match the annotations of the active jobs
+	tasks, err := j.skipActiveTasks(ctx, splitTasks(tasks, settings.RepositoriesPerJob), settings)
+	if err != nil {
+		return 0, err
+	}
+	j.pendingLock.Lock()
+	j.pending = nil
+	now := time.Now()
+	for _, chunk := range chunkJobTasks(tasks, settings) {
+		j.pending = append(j.pending, pendingJob{tasks: chunk, createdAt: now})
+	}
+	j.retries = nil
+	j.pendingLock.Unlock()
+	return j.executePending(ctx, settings)
+}
+
+// ExecutePending queues failed jobs again once their retry backoff passes
+// and creates queued renovate jobs while the limit of parallel jobs allows it.
+func (j *JobCoordinator) ExecutePending(ctx context.Context) error {
+	settings := j.Settings(ctx)
+	if err := j.queueFailedJobs(ctx, settings); err != nil {
 		return err
 	}
-	if err := j.client.Update(ctx, configMap); err != nil {
+	_, err := j.executePending(ctx, settings)
+	return err
+}
+
+func (j *JobCoordinator) executePending(ctx context.Context, settings Settings) (int, error) {
+	j.pendingLock.Lock()
+	defer j.pendingLock.Unlock()
+	if len(j.pending) == 0 {
+		return 0, nil
+	}
+
+	available := len(j.pending)
+	if settings.MaxParallelJobs > 0 {
+		activeJobs, err := j.listActiveJobs(ctx, settings)
+		if err != nil {
+			return 0, err
+		}
+		available = settings.MaxParallelJobs - len(activeJobs)
+	}
+	created := 0
+	for ; available > 0 && len(j.pending) > 0; available-- {
+		job := j.pending[0]
+		j.pending = j.pending[1:]
+		name, err := j.execute(ctx, job.tasks, settings, nil)
+		if err != nil {
+			return created, err
+		}
+		if name != "" && job.attempt < settings.RetryLimit {
+			j.retryable[name] = job
+		}
+		created++
+	}
+	if len(j.pending) > 0 {
+		logger.FromContext(ctx).Info("renovate jobs queued, limit of parallel jobs reached", "queued", len(j.pending), "limit", settings.MaxParallelJobs)
+	}
+	return created, nil
+}
+
+// queueFailedJobs schedules the retry of the failed sweep jobs with exponential backoff
+// and queues the retries whose backoff passed. The retried jobs get the tasks with the original tokens,
+// so the jobs are not retried later than MaxRetryDelay after the tasks were created.
+func (j *JobCoordinator) queueFailedJobs(ctx context.Context, settings Settings) error {
+	log := logger.FromContext(ctx)
+	j.pendingLock.Lock()
+	defer j.pendingLock.Unlock()
+	if len(j.retryable) == 0 && len(j.retries) == 0 {
+		return nil
+	}
+
+	runs, err := j.listJobs(ctx, settings)
+	if err != nil {
 		return err
 	}
+	now := time.Now()
+	for name, job := range j.retryable {
+		run, found := runs[name]
+		if !found {
+			// Deleted, e.g. after its TTL
+			delete(j.retryable, name)
+			continue
+		}
+		finished, failed := getJobState(run)
+		if !finished {
+			continue
+		}
+		delete(j.retryable, name)
+		if failed {
+			backoff := settings.RetryBackoff << job.attempt
+			retryAt := now.Add(backoff)
+			if backoff > MaxRetryDelay || retryAt.Sub(job.createdAt) > MaxRetryDelay {
+				log.Info("renovate job failed, not retrying as the tokens of its tasks would expire", "jobname", name, "attempt", job.attempt+1, "backoff", backoff.String())
+				continue
+			}
+			log.Info("renovate job failed, scheduling retry", "jobname", name, "attempt", job.attempt+1, "backoff", backoff.String())
+			j.retries = append(j.retries, retryJob{pendingJob: pendingJob{tasks: job.tasks, attempt: job.attempt + 1, createdAt: job.createdAt}, retryAt: retryAt})
+		}
+	}
+
+	var waiting []retryJob
+	for _, retry := range j.retries {
+		if retry.retryAt.After(now) {
+			waiting = append(waiting, retry)
+			continue
+		}
+		j.pending = append(j.pending, retry.pendingJob)
+	}
+	j.retries = waiting
 	return nil
 }
 
-func (j *JobCoordinator) ExecuteWithLimits(ctx context.Context, tasks []*Task) error {
+// listJobs returns renovate jobs by name. In TaskRun execution mode the renovate TaskRuns are returned too.
+func (j *JobCoordinator) listJobs(ctx context.Context, settings Settings) (map[string]client.Object, error) {
+	jobList := &batchv1.JobList{}
+	if err := j.client.List(ctx, jobList, client.InNamespace(BuildServiceNamespaceName), client.MatchingLabels{RenovateJobLabelName: "true"}); err != nil {
+		return nil, err
+	}
+	jobs := map[string]client.Object{}
+	for i := range jobList.Items {
+		jobs[jobList.Items[i].Name] = &jobList.Items[i]
+	}
+	if settings.ExecutionMode != ExecutionModeTaskRun {
+		return jobs, nil
+	}
+	taskRunList := &tektonapi.TaskRunList{}
+	if err := j.client.List(ctx, taskRunList, client.InNamespace(settings.ListNamespace()), client.MatchingLabels{RenovateJobLabelName: "true"}); err != nil {
+		return nil, err
+	}
+	for i := range taskRunList.Items {
+		jobs[taskRunList.Items[i].Name] = &taskRunList.Items[i]
+	}
+	return jobs, nil
+}
 
-	for i := 0; i < len(tasks); i += j.tasksPerJob {
-		end := i + j.tasksPerJob
+// getJobState returns whether the renovate Job or TaskRun finished and whether it failed.
+func getJobState(run client.Object) (finished bool, failed bool) {
+	switch run := run.(type) {
+	case *batchv1.Job:
+		for _, condition := range run.Status.Conditions {
+			if condition.Type == batchv1.JobFailed && condition.Status == corev1.ConditionTrue {
+				return true, true
+			}
+		}
+		return isJobFinished(run), false
+	case *tektonapi.TaskRun:
+		return run.IsDone(), run.IsDone() && !run.IsSuccessful()
+	}
+	return false, false
+}
 
-		if end > len(tasks) {
-			end = len(tasks)
+// listActiveJobs returns renovate jobs which haven't finished yet.
+// In TaskRun execution mode the unfinished renovate TaskRuns are returned too.
+func (j *JobCoordinator) listActiveJobs(ctx context.Context, settings Settings) ([]client.Object, error) {
+	jobList := &batchv1.JobList{}
+	if err := j.client.List(ctx, jobList, client.InNamespace(BuildServiceNamespaceName), client.MatchingLabels{RenovateJobLabelName: "true"}); err != nil {
+		return nil, err
+	}
+	var activeJobs []client.Object
+	for i := range jobList.Items {
+		if !isJobFinished(&jobList.Items[i]) {
+			activeJobs = append(activeJobs, &jobList.Items[i])
 		}
-		err := j.Execute(ctx, tasks[i:end])
+	}
+	if settings.ExecutionMode != ExecutionModeTaskRun {
+		return activeJobs, nil
+	}
+	taskRunList := &tektonapi.TaskRunList{}
+	if err := j.client.List(ctx, taskRunList, client.InNamespace(settings.ListNamespace()), client.MatchingLabels{RenovateJobLabelName: "true"}); err != nil {
+		return nil, err
+	}
+	for i := range taskRunList.Items {
+		if !taskRunList.Items[i].IsDone() {
+			activeJobs = append(activeJobs, &taskRunList.Items[i])
+		}
+	}
+	return activeJobs, nil
+}
+
+// skipActiveTasks returns the tasks which are not being executed by an active renovate job,
+// so the same repositories are not updated by two jobs at the same time.
+func (j *JobCoordinator) skipActiveTasks(ctx context.Context, tasks []*Task, settings Settings) ([]*Task, error) {
+	activeJobs, err := j.listActiveJobs(ctx, settings)
+	if err != nil {
+		return nil, err
+	}
+	activeTaskKeys := map[string]bool{}
+	for _, job := range activeJobs {
+		for _, key := range strings.Split(job.GetAnnotations()[RenovateTasksAnnotationName], ",") {
+			activeTaskKeys[key] = true
+		}
+	}
+	var inactiveTasks []*Task
+	for _, task := range tasks {
+		if activeTaskKeys[task.Key()] {
+			logger.FromContext(ctx).Info("skipping renovate task, previous job is still running", "endpoint", task.Endpoint, "repositories", len(task.Repositories))
+			continue
+		}
+		inactiveTasks = append(inactiveTasks, task)
+	}
+	return inactiveTasks, nil
+}
+
+func isJobFinished(job *batchv1.Job) bool {
+	for _, condition := range job.Status.Conditions {
+		if (condition.Type == batchv1.JobComplete || condition.Type == batchv1.JobFailed) && condition.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+// Start periodically creates queued renovate jobs until the context is done.
+// It implements manager.Runnable, so it runs only in the leader instance.
+func (j *JobCoordinator) Start(ctx context.Context) error {
+	ticker := time.NewTicker(PendingJobsCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := j.ExecutePending(ctx); err != nil {
+				logger.FromContext(ctx).Error(err, "failed to create queued renovate job", logs.Action, logs.ActionAdd)
+			}
+		}
+	}
+}
+
+// ExecuteWithLabels creates renovate jobs for the given tasks, respecting the limits of tasks and repositories per job.
+// The given labels are set on the jobs. Returns the name of the job created for each executed task,
+// the tasks with more repositories than the limit are split and so are not the given ones.
+// The jobs are created immediately, regardless of the limit of parallel jobs.
+func (j *JobCoordinator) ExecuteWithLabels(ctx context.Context, tasks []*Task, labels map[string]string) (map[*Task]string, error) {
+	settings := j.Settings(ctx)
+	jobNames := make(map[*Task]string, len(tasks))
+	for _, chunk := range chunkJobTasks(splitTasks(tasks, settings.RepositoriesPerJob), settings) {
+		name, err := j.execute(ctx, chunk, settings, labels)
 		if err != nil {
-			return err
+			return jobNames, err
+		}
+		for _, task := range chunk {
+			jobNames[task] = name
 		}
 	}
-	return nil
+	return jobNames, nil
+}
+
+// chunkJobTasks splits the tasks into chunks, one chunk per job.
+// If the limit of repositories per job is set, the chunks are filled up to the limit of repositories,
+// the limit of tasks per job applies in both cases.
+// If the jobs run in the tenant namespaces, the tasks are chunked per namespace, a job never mixes tenants.
+func chunkJobTasks(tasks []*Task, settings Settings) [][]*Task {
+	if settings.IsTenantScoped() {
+		var namespaces []string
+		namespaceTasks := map[string][]*Task{}
+		for _, task := range tasks {
+			if _, ok := namespaceTasks[task.Namespace]; !ok {
+				namespaces = append(namespaces, task.Namespace)
+			}
+			namespaceTasks[task.Namespace] = append(namespaceTasks[task.Namespace], task)
+		}
+		settings.TenantNamespaces = false
+		var chunks [][]*Task
+		for _, namespace := range namespaces {
+			chunks = append(chunks, chunkJobTasks(namespaceTasks[namespace], settings)...)
+		}
+		return chunks
+	}
+	if settings.RepositoriesPerJob <= 0 {
+		return chunkTasks(tasks, settings.TasksPerJob)
+	}
+	var chunks [][]*Task
+	var chunk []*Task
+	repositories := 0
+	for _, task := range tasks {
+		if len(chunk) > 0 && (repositories+len(task.Repositories) > settings.RepositoriesPerJob || len(chunk) == settings.TasksPerJob) {
+			chunks = append(chunks, chunk)
+			chunk = nil
+			repositories = 0
+		}
+		chunk = append(chunk, task)
+		repositories += len(task.Repositories)
+	}
+	if len(chunk) > 0 {
+		chunks = append(chunks, chunk)
+	}
+	return chunks
+}
 
+// splitTasks splits the tasks with more repositories than the limit into several tasks with the same credentials.
+// The repositories are sorted by name, so the split tasks keep their keys across sweeps. Zero limit disables splitting.
+func splitTasks(tasks []*Task, repositoriesPerTask int) []*Task {
+	if repositoriesPerTask <= 0 {
+		return tasks
+	}
+	var result []*Task
+	for _, task := range tasks {
+		if len(task.Repositories) <= repositoriesPerTask {
+			result = append(result, task)
+			continue
+		}
+		repositories := make([]*Repository, len(task.Repositories))
+		copy(repositories, task.Repositories)
+		sort.Slice(repositories, func(i, j int) bool {
+			return repositories[i].Repository < repositories[j].Repository
+		})
+		for i := 0; i < len(repositories); i += repositoriesPerTask {
+			end := i + repositoriesPerTask
+			if end > len(repositories) {
+				end = len(repositories)
+			}
+			split := *task
+			split.Repositories = repositories[i:end]
+			result = append(result, &split)
+		}
+	}
+	return result
+}
+
+// chunkTasks splits the tasks into chunks of the given size, one chunk per job.
+func chunkTasks(tasks []*Task, tasksPerJob int) [][]*Task {
+	var chunks [][]*Task
+	for i := 0; i < len(tasks); i += tasksPerJob {
+		end := i + tasksPerJob
+
+		if end > len(tasks) {
+			end = len(tasks)
+		}
+		chunks = append(chunks, tasks[i:end])
+	}
+	return chunks
 }
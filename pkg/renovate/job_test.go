@@ -0,0 +1,734 @@
+package renovate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	tektonapi "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"knative.dev/pkg/apis"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	buildappstudiov1alpha1 "github.com/konflux-ci/build-service/api/v1alpha1"
+	. "github.com/konflux-ci/build-service/pkg/common"
+)
+
+func newTestTasks(count int) []*Task {
+	var tasks []*Task
+	for i := 0; i < count; i++ {
+		tasks = append(tasks, &Task{Platform: "github", Endpoint: "https://api.github.com/", Token: "token",
+			Repositories: []*Repository{{Repository: fmt.Sprintf("org/repo%d", i), BaseBranches: []string{"main"}}}})
+	}
+	return tasks
+}
+
+func listRenovateJobs(t *testing.T, c client.Client) []batchv1.Job {
+	jobList := &batchv1.JobList{}
+	assert.NoError(t, c.List(context.TODO(), jobList, client.InNamespace(BuildServiceNamespaceName), client.MatchingLabels{RenovateJobLabelName: "true"}))
+	return jobList.Items
+}
+
+func TestExecuteWithLimitsQueuesJobsOverParallelLimit(t *testing.T) {
+	t.Setenv(InstallationsPerJobEnvName, "1")
+	t.Setenv(MaxParallelJobsEnvName, "2")
+
+	scheme := runtime.NewScheme()
+	assert.NoError(t, clientgoscheme.AddToScheme(scheme))
+	assert.NoError(t, buildappstudiov1alpha1.AddToScheme(scheme))
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+	coordinator := NewJobCoordinator(fakeClient, scheme)
+	ctx := context.TODO()
+
+	created, err := coordinator.ExecuteWithLimits(ctx, newTestTasks(3))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, created)
+	jobs := listRenovateJobs(t, fakeClient)
+	assert.Len(t, jobs, 2)
+
+	// No free slot yet
+	assert.NoError(t, coordinator.ExecutePending(ctx))
+	assert.Len(t, listRenovateJobs(t, fakeClient), 2)
+
+	job := jobs[0]
+	job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}}
+	assert.NoError(t, fakeClient.Status().Update(ctx, &job))
+
+	assert.NoError(t, coordinator.ExecutePending(ctx))
+	assert.Len(t, listRenovateJobs(t, fakeClient), 3)
+
+	// The queue is empty now
+	job = listRenovateJobs(t, fakeClient)[1]
+	job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue}}
+	assert.NoError(t, fakeClient.Status().Update(ctx, &job))
+	assert.NoError(t, coordinator.ExecutePending(ctx))
+	assert.Len(t, listRenovateJobs(t, fakeClient), 3)
+}
+
+func TestExecuteWithLimitsWithoutParallelLimit(t *testing.T) {
+	t.Setenv(InstallationsPerJobEnvName, "1")
+	t.Setenv(MaxParallelJobsEnvName, "")
+
+	scheme := runtime.NewScheme()
+	assert.NoError(t, clientgoscheme.AddToScheme(scheme))
+	assert.NoError(t, buildappstudiov1alpha1.AddToScheme(scheme))
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+	coordinator := NewJobCoordinator(fakeClient, scheme)
+
+	_, err := coordinator.ExecuteWithLimits(context.TODO(), newTestTasks(3))
+	assert.NoError(t, err)
+	assert.Len(t, listRenovateJobs(t, fakeClient), 3)
+}
+
+func TestExecutePendingRetriesFailedJobs(t *testing.T) {
+	t.Setenv(MaxParallelJobsEnvName, "")
+	t.Setenv(RetryLimitEnvName, "")
+	t.Setenv(RetryBackoffEnvName, "")
+
+	scheme := runtime.NewScheme()
+	assert.NoError(t, clientgoscheme.AddToScheme(scheme))
+	assert.NoError(t, buildappstudiov1alpha1.AddToScheme(scheme))
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+	coordinator := NewJobCoordinator(fakeClient, scheme)
+	ctx := context.TODO()
+
+	tasks := newTestTasks(1)
+	_, err := coordinator.ExecuteWithLimits(ctx, tasks)
+	assert.NoError(t, err)
+	failActiveJob := func() {
+		for _, job := range listRenovateJobs(t, fakeClient) {
+			job := job
+			if !isJobFinished(&job) {
+				job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue}}
+				assert.NoError(t, fakeClient.Status().Update(ctx, &job))
+			}
+		}
+	}
+
+	for attempt := 1; attempt <= DefaultRetryLimit; attempt++ {
+		failActiveJob()
+		// The job is created again once the backoff passes
+		assert.NoError(t, coordinator.ExecutePending(ctx))
+		assert.Len(t, listRenovateJobs(t, fakeClient), attempt)
+		if assert.Len(t, coordinator.retries, 1) {
+			assert.Equal(t, int32(attempt), coordinator.retries[0].attempt)
+			assert.WithinDuration(t, time.Now().Add(DefaultRetryBackoff<<(attempt-1)), coordinator.retries[0].retryAt, time.Minute)
+			coordinator.retries[0].retryAt = time.Now()
+		}
+		assert.NoError(t, coordinator.ExecutePending(ctx))
+		jobs := listRenovateJobs(t, fakeClient)
+		assert.Len(t, jobs, attempt+1)
+		for _, job := range jobs {
+			assert.Equal(t, tasks[0].Key(), job.Annotations[RenovateTasksAnnotationName])
+		}
+	}
+
+	// Retry limit reached
+	failActiveJob()
+	assert.NoError(t, coordinator.ExecutePending(ctx))
+	assert.Empty(t, coordinator.retries)
+	assert.Empty(t, coordinator.retryable)
+	assert.Len(t, listRenovateJobs(t, fakeClient), DefaultRetryLimit+1)
+}
+
+func TestExecutePendingDoesNotRetryJobsWithExpiringTokens(t *testing.T) {
+	t.Setenv(MaxParallelJobsEnvName, "")
+	t.Setenv(RetryLimitEnvName, "")
+	t.Setenv(RetryBackoffEnvName, "")
+
+	scheme := runtime.NewScheme()
+	assert.NoError(t, clientgoscheme.AddToScheme(scheme))
+	assert.NoError(t, buildappstudiov1alpha1.AddToScheme(scheme))
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+	coordinator := NewJobCoordinator(fakeClient, scheme)
+	ctx := context.TODO()
+
+	_, err := coordinator.ExecuteWithLimits(ctx, newTestTasks(1))
+	assert.NoError(t, err)
+	// The tasks were created long enough ago that the tokens would expire before the retry
+	for name, job := range coordinator.retryable {
+		job.createdAt = time.Now().Add(-MaxRetryDelay)
+		coordinator.retryable[name] = job
+	}
+	for _, job := range listRenovateJobs(t, fakeClient) {
+		job := job
+		job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue}}
+		assert.NoError(t, fakeClient.Status().Update(ctx, &job))
+	}
+
+	assert.NoError(t, coordinator.ExecutePending(ctx))
+	assert.Empty(t, coordinator.retries)
+	assert.Empty(t, coordinator.retryable)
+	assert.Len(t, listRenovateJobs(t, fakeClient), 1)
+}
+
+func TestExecuteWithLimitsReplacesRetriesOfFailedJobs(t *testing.T) {
+	t.Setenv(MaxParallelJobsEnvName, "")
+
+	scheme := runtime.NewScheme()
+	assert.NoError(t, clientgoscheme.AddToScheme(scheme))
+	assert.NoError(t, buildappstudiov1alpha1.AddToScheme(scheme))
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+	coordinator := NewJobCoordinator(fakeClient, scheme)
+	ctx := context.TODO()
+
+	_, err := coordinator.ExecuteWithLimits(ctx, newTestTasks(1))
+	assert.NoError(t, err)
+	job := listRenovateJobs(t, fakeClient)[0]
+	job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue}}
+	assert.NoError(t, fakeClient.Status().Update(ctx, &job))
+	assert.NoError(t, coordinator.ExecutePending(ctx))
+	assert.Len(t, coordinator.retries, 1)
+
+	// The next sweep provides the tasks again
+	_, err = coordinator.ExecuteWithLimits(ctx, newTestTasks(1))
+	assert.NoError(t, err)
+	assert.Empty(t, coordinator.retries)
+	assert.Len(t, listRenovateJobs(t, fakeClient), 2)
+}
+
+func TestChunkJobTasks(t *testing.T) {
+	newTask := func(token string, repositories ...string) *Task {
+		task := &Task{Platform: "github", Endpoint: "https://api.github.com/", Token: token}
+		for _, repository := range repositories {
+			task.Repositories = append(task.Repositories, &Repository{Repository: repository, BaseBranches: []string{"main"}})
+		}
+		return task
+	}
+	countRepositories := func(chunk []*Task) int {
+		count := 0
+		for _, task := range chunk {
+			count += len(task.Repositories)
+		}
+		return count
+	}
+	large := newTask("large", "org/repo5", "org/repo4", "org/repo3", "org/repo2", "org/repo1")
+	tasks := []*Task{large, newTask("small1", "other/repo1"), newTask("small2", "another/repo1", "another/repo2")}
+
+	// Installation count based chunking by default
+	chunks := chunkJobTasks(splitTasks(tasks, 0), Settings{TasksPerJob: 2})
+	assert.Len(t, chunks, 2)
+	assert.Equal(t, 6, countRepositories(chunks[0]))
+
+	settings := Settings{TasksPerJob: TasksPerJob, RepositoriesPerJob: 2}
+	splitParts := splitTasks(tasks, settings.RepositoriesPerJob)
+	assert.Len(t, splitParts, 5)
+	for _, task := range splitParts[:3] {
+		assert.Equal(t, "large", task.Token)
+	}
+	assert.Equal(t, []*Repository{large.Repositories[4], large.Repositories[3]}, splitParts[0].Repositories)
+	// The split keeps the original task untouched and is stable
+	assert.Equal(t, "org/repo5", large.Repositories[0].Repository)
+	assert.Equal(t, splitParts[1].Key(), splitTasks(tasks, settings.RepositoriesPerJob)[1].Key())
+
+	chunks = chunkJobTasks(splitParts, settings)
+	assert.Len(t, chunks, 4)
+	for _, chunk := range chunks {
+		assert.LessOrEqual(t, countRepositories(chunk), 2)
+	}
+	// The last part of the large installation shares the job with the small one
+	assert.Len(t, chunks[2], 2)
+
+	settings.TasksPerJob = 1
+	assert.Len(t, chunkJobTasks(splitParts, settings), 5)
+}
+
+func TestExecuteWithLimitsSplitsTasksByRepositories(t *testing.T) {
+	t.Setenv(MaxParallelJobsEnvName, "")
+	t.Setenv(RepositoriesPerJobEnvName, "2")
+
+	scheme := runtime.NewScheme()
+	assert.NoError(t, clientgoscheme.AddToScheme(scheme))
+	assert.NoError(t, buildappstudiov1alpha1.AddToScheme(scheme))
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+	coordinator := NewJobCoordinator(fakeClient, scheme)
+	ctx := context.TODO()
+
+	task := newTestTasks(1)[0]
+	for i := 1; i < 5; i++ {
+		task.Repositories = append(task.Repositories, &Repository{Repository: fmt.Sprintf("org/repo%d", i), BaseBranches: []string{"main"}})
+	}
+	created, err := coordinator.ExecuteWithLimits(ctx, []*Task{task})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, created)
+
+	// The split parts are still running
+	created, err = coordinator.ExecuteWithLimits(ctx, []*Task{task})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, created)
+	assert.Len(t, listRenovateJobs(t, fakeClient), 3)
+}
+
+func TestExecuteWithLimitsSkipsTasksOfActiveJobs(t *testing.T) {
+	t.Setenv(InstallationsPerJobEnvName, "")
+	t.Setenv(MaxParallelJobsEnvName, "")
+
+	scheme := runtime.NewScheme()
+	assert.NoError(t, clientgoscheme.AddToScheme(scheme))
+	assert.NoError(t, buildappstudiov1alpha1.AddToScheme(scheme))
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+	coordinator := NewJobCoordinator(fakeClient, scheme)
+	ctx := context.TODO()
+
+	tasks := newTestTasks(2)
+	_, err := coordinator.ExecuteWithLimits(ctx, tasks[:1])
+	assert.NoError(t, err)
+	jobs := listRenovateJobs(t, fakeClient)
+	assert.Len(t, jobs, 1)
+	assert.Equal(t, tasks[0].Key(), jobs[0].Annotations[RenovateTasksAnnotationName])
+
+	// The first task is still running, only the second one gets a new job
+	_, err = coordinator.ExecuteWithLimits(ctx, newTestTasks(2))
+	assert.NoError(t, err)
+	jobs = listRenovateJobs(t, fakeClient)
+	assert.Len(t, jobs, 2)
+	for _, job := range jobs {
+		job := job
+		job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}}
+		assert.NoError(t, fakeClient.Status().Update(ctx, &job))
+	}
+
+	_, err = coordinator.ExecuteWithLimits(ctx, newTestTasks(2))
+	assert.NoError(t, err)
+	assert.Len(t, listRenovateJobs(t, fakeClient), 3)
+}
+
+func TestTaskKey(t *testing.T) {
+	task := &Task{Platform: "github", Endpoint: "https://api.github.com/", Token: "token1",
+		Repositories: []*Repository{{Repository: "org/repo1"}, {Repository: "org/repo2"}}}
+	sameTask := &Task{Platform: "github", Endpoint: "https://api.github.com/", Token: "token2",
+		Repositories: []*Repository{{Repository: "org/repo2"}, {Repository: "org/repo1"}}}
+	otherTask := &Task{Platform: "github", Endpoint: "https://api.github.com/", Token: "token1",
+		Repositories: []*Repository{{Repository: "org/repo1"}}}
+
+	assert.Equal(t, task.Key(), sameTask.Key())
+	assert.NotEqual(t, task.Key(), otherTask.Key())
+
+	tenantTask := *task
+	tenantTask.Namespace = "tenant"
+	assert.NotEqual(t, task.Key(), tenantTask.Key())
+}
+
+func TestExecuteCreatesJobDependentsWithOwnerReference(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, clientgoscheme.AddToScheme(scheme))
+	assert.NoError(t, buildappstudiov1alpha1.AddToScheme(scheme))
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+	coordinator := NewJobCoordinator(fakeClient, scheme)
+	ctx := context.TODO()
+
+	assert.NoError(t, coordinator.Execute(ctx, newTestTasks(1)))
+	jobs := listRenovateJobs(t, fakeClient)
+	assert.Len(t, jobs, 1)
+	assert.False(t, *jobs[0].Spec.Suspend)
+	assert.Equal(t, RenovateJobServiceAccountName, jobs[0].Spec.Template.Spec.ServiceAccountName)
+	assert.False(t, *jobs[0].Spec.Template.Spec.AutomountServiceAccountToken)
+	container := jobs[0].Spec.Template.Spec.Containers[0]
+	assert.Empty(t, container.EnvFrom)
+	assert.Contains(t, container.Command[2], "RENOVATE_TOKEN=$(cat /tokens/")
+
+	secret := &corev1.Secret{}
+	assert.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Name: jobs[0].Name, Namespace: BuildServiceNamespaceName}, secret))
+	assert.Equal(t, jobs[0].Name, secret.OwnerReferences[0].Name)
+	configMap := &corev1.ConfigMap{}
+	assert.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Name: jobs[0].Name, Namespace: BuildServiceNamespaceName}, configMap))
+	assert.Equal(t, jobs[0].Name, configMap.OwnerReferences[0].Name)
+}
+
+func TestExecuteDeletesJobIfDependentCreationFails(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, clientgoscheme.AddToScheme(scheme))
+	assert.NoError(t, buildappstudiov1alpha1.AddToScheme(scheme))
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithInterceptorFuncs(interceptor.Funcs{
+		Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			if _, ok := obj.(*corev1.ConfigMap); ok {
+				return errors.New("config map creation failed")
+			}
+			return c.Create(ctx, obj, opts...)
+		},
+	}).Build()
+	coordinator := NewJobCoordinator(fakeClient, scheme)
+
+	assert.Error(t, coordinator.Execute(context.TODO(), newTestTasks(1)))
+	assert.Empty(t, listRenovateJobs(t, fakeClient))
+}
+
+func TestExecutePassesHostRulesInSecret(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, clientgoscheme.AddToScheme(scheme))
+	assert.NoError(t, buildappstudiov1alpha1.AddToScheme(scheme))
+	registrySecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "registry-credentials", Namespace: BuildServiceNamespaceName},
+		Data:       map[string][]byte{"username": []byte("robot"), "password": []byte("secret-password")},
+	}
+	renovateConfig := &buildappstudiov1alpha1.RenovateTektonConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: buildappstudiov1alpha1.RenovateTektonConfigName},
+		Spec: buildappstudiov1alpha1.RenovateTektonConfigSpec{HostRules: []buildappstudiov1alpha1.RenovateHostRule{
+			{MatchHost: "registry.internal", SecretName: "registry-credentials"},
+			{MatchHost: "registry.missing", SecretName: "missing-credentials"},
+		}},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(registrySecret, renovateConfig).Build()
+	coordinator := NewJobCoordinator(fakeClient, scheme)
+	ctx := context.TODO()
+
+	assert.NoError(t, coordinator.Execute(ctx, newTestTasks(1)))
+	jobs := listRenovateJobs(t, fakeClient)
+	assert.Len(t, jobs, 1)
+	assert.Contains(t, jobs[0].Spec.Template.Spec.Containers[0].Command[2], "RENOVATE_HOST_RULES=$(cat /tokens/host-rules)")
+
+	secret := &corev1.Secret{}
+	assert.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Name: jobs[0].Name, Namespace: BuildServiceNamespaceName}, secret))
+	var hostRules []HostRule
+	assert.NoError(t, json.Unmarshal([]byte(secret.StringData[hostRulesSecretKey]), &hostRules))
+	assert.Equal(t, []HostRule{{HostType: DefaultHostRuleType, MatchHost: "registry.internal", Username: "robot", Password: "secret-password"}}, hostRules)
+
+	configMap := &corev1.ConfigMap{}
+	assert.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Name: jobs[0].Name, Namespace: BuildServiceNamespaceName}, configMap))
+	for _, config := range configMap.Data {
+		assert.NotContains(t, config, "secret-password")
+	}
+}
+
+func TestExecuteCreatesTaskRunInTaskRunMode(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, clientgoscheme.AddToScheme(scheme))
+	assert.NoError(t, buildappstudiov1alpha1.AddToScheme(scheme))
+	assert.NoError(t, tektonapi.AddToScheme(scheme))
+	renovateConfig := &buildappstudiov1alpha1.RenovateTektonConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: buildappstudiov1alpha1.RenovateTektonConfigName},
+		Spec: buildappstudiov1alpha1.RenovateTektonConfigSpec{JobSettings: buildappstudiov1alpha1.RenovateJobSettings{
+			ExecutionMode:      ExecutionModeTaskRun,
+			TaskRunNamespace:   "renovate-runs",
+			PriorityClassName:  "renovate",
+			MaxParallelJobs:    ptr.To(1),
+			ServiceAccountName: "renovate",
+			ImagePullSecrets:   []corev1.LocalObjectReference{{Name: "renovate-pull-secret"}},
+		}},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(renovateConfig).Build()
+	coordinator := NewJobCoordinator(fakeClient, scheme)
+	ctx := context.TODO()
+
+	t.Setenv(InstallationsPerJobEnvName, "1")
+	_, err := coordinator.ExecuteWithLimits(ctx, newTestTasks(2))
+	assert.NoError(t, err)
+	assert.Empty(t, listRenovateJobs(t, fakeClient))
+	taskRunList := &tektonapi.TaskRunList{}
+	assert.NoError(t, fakeClient.List(ctx, taskRunList, client.InNamespace("renovate-runs")))
+	// The second task waits for the running TaskRun
+	assert.Len(t, taskRunList.Items, 1)
+
+	taskRun := taskRunList.Items[0]
+	assert.Equal(t, "true", taskRun.Labels[RenovateJobLabelName])
+	assert.Equal(t, ptr.To("renovate"), taskRun.Spec.PodTemplate.PriorityClassName)
+	assert.Equal(t, "renovate", taskRun.Spec.ServiceAccountName)
+	assert.Equal(t, []corev1.LocalObjectReference{{Name: "renovate-pull-secret"}}, taskRun.Spec.PodTemplate.ImagePullSecrets)
+	assert.Equal(t, DefaultBackoffLimit, taskRun.Spec.Retries)
+	step := taskRun.Spec.TaskSpec.Steps[0]
+	assert.Contains(t, step.Command[2], "RENOVATE_TOKEN=$(cat /tokens/")
+	assert.Len(t, step.VolumeMounts, 2)
+	assert.Len(t, taskRun.Spec.TaskSpec.Volumes, 2)
+	assert.Contains(t, step.Env, corev1.EnvVar{Name: "RENOVATE_REPORT_PATH", Value: "$(results.pull-requests.path)"})
+	assert.Equal(t, []tektonapi.TaskResult{{Name: PullRequestsResultName, Type: tektonapi.ResultsTypeString}}, taskRun.Spec.TaskSpec.Results)
+
+	secret := &corev1.Secret{}
+	assert.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Name: taskRun.Name, Namespace: "renovate-runs"}, secret))
+	assert.Equal(t, "TaskRun", secret.OwnerReferences[0].Kind)
+	configMap := &corev1.ConfigMap{}
+	assert.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Name: taskRun.Name, Namespace: "renovate-runs"}, configMap))
+	assert.Equal(t, taskRun.Name, configMap.OwnerReferences[0].Name)
+
+	taskRun.Status.SetCondition(&apis.Condition{Type: apis.ConditionSucceeded, Status: corev1.ConditionTrue})
+	assert.NoError(t, fakeClient.Update(ctx, &taskRun))
+	assert.NoError(t, coordinator.ExecutePending(ctx))
+	assert.NoError(t, fakeClient.List(ctx, taskRunList, client.InNamespace("renovate-runs")))
+	assert.Len(t, taskRunList.Items, 2)
+}
+
+func TestExecuteWithLimitsRunsTaskRunsInTenantNamespaces(t *testing.T) {
+	t.Setenv(InstallationsPerJobEnvName, "")
+	t.Setenv(MaxParallelJobsEnvName, "")
+
+	scheme := runtime.NewScheme()
+	assert.NoError(t, clientgoscheme.AddToScheme(scheme))
+	assert.NoError(t, buildappstudiov1alpha1.AddToScheme(scheme))
+	assert.NoError(t, tektonapi.AddToScheme(scheme))
+	renovateConfig := &buildappstudiov1alpha1.RenovateTektonConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: buildappstudiov1alpha1.RenovateTektonConfigName},
+		Spec: buildappstudiov1alpha1.RenovateTektonConfigSpec{
+			HostRules: []buildappstudiov1alpha1.RenovateHostRule{{MatchHost: "registry.internal", SecretName: "registry-credentials"}},
+			JobSettings: buildappstudiov1alpha1.RenovateJobSettings{
+				ExecutionMode:         ExecutionModeTaskRun,
+				TaskRunNamespace:      "renovate-runs",
+				RunInTenantNamespaces: ptr.To(true),
+			},
+		},
+	}
+	globalSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "registry-credentials", Namespace: BuildServiceNamespaceName},
+		Data:       map[string][]byte{"username": []byte("robot"), "password": []byte("global-password")},
+	}
+	tenantSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "registry-credentials", Namespace: "tenant1"},
+		Data:       map[string][]byte{"username": []byte("robot"), "password": []byte("tenant-password")},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(renovateConfig, globalSecret, tenantSecret).Build()
+	coordinator := NewJobCoordinator(fakeClient, scheme)
+	ctx := context.TODO()
+
+	tasks := newTestTasks(3)
+	tasks[0].Namespace = "tenant1"
+	tasks[1].Namespace = "tenant2"
+	tasks[2].Namespace = "tenant1"
+	created, err := coordinator.ExecuteWithLimits(ctx, tasks)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, created)
+
+	taskRunList := &tektonapi.TaskRunList{}
+	assert.NoError(t, fakeClient.List(ctx, taskRunList, client.InNamespace("renovate-runs")))
+	assert.Empty(t, taskRunList.Items)
+	for namespace, password := range map[string]string{"tenant1": "tenant-password", "tenant2": ""} {
+		assert.NoError(t, fakeClient.List(ctx, taskRunList, client.InNamespace(namespace)))
+		assert.Len(t, taskRunList.Items, 1)
+		secret := &corev1.Secret{}
+		assert.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Name: taskRunList.Items[0].Name, Namespace: namespace}, secret))
+		// The host rule credentials are read from the tenant namespace only
+		assert.NotContains(t, secret.StringData[hostRulesSecretKey], "global-password")
+		if password != "" {
+			assert.Contains(t, secret.StringData[hostRulesSecretKey], password)
+		} else {
+			assert.NotContains(t, secret.StringData, hostRulesSecretKey)
+		}
+	}
+	assert.NoError(t, fakeClient.List(ctx, taskRunList, client.InNamespace("tenant1")))
+	assert.Equal(t, strings.Join([]string{tasks[0].Key(), tasks[2].Key()}, ","), taskRunList.Items[0].Annotations[RenovateTasksAnnotationName])
+
+	// The TaskRuns of all tenants are still running
+	created, err = coordinator.ExecuteWithLimits(ctx, tasks)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, created)
+}
+
+func TestExecuteMountsTrustedCA(t *testing.T) {
+	t.Setenv(TrustedCAConfigMapEnvName, "trusted-ca")
+	scheme := runtime.NewScheme()
+	assert.NoError(t, clientgoscheme.AddToScheme(scheme))
+	assert.NoError(t, buildappstudiov1alpha1.AddToScheme(scheme))
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+	coordinator := NewJobCoordinator(fakeClient, scheme)
+
+	assert.NoError(t, coordinator.Execute(context.TODO(), newTestTasks(1)))
+	jobs := listRenovateJobs(t, fakeClient)
+	assert.Len(t, jobs, 1)
+	podSpec := jobs[0].Spec.Template.Spec
+	assert.Contains(t, podSpec.Volumes, corev1.Volume{
+		Name: "trusted-ca",
+		VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "trusted-ca"},
+			Items:                []corev1.KeyToPath{{Key: TrustedCAConfigMapKey, Path: TrustedCAConfigMapKey}},
+		}},
+	})
+	container := podSpec.Containers[0]
+	assert.Contains(t, container.VolumeMounts, corev1.VolumeMount{Name: "trusted-ca", MountPath: TrustedCAMountPath, ReadOnly: true})
+	assert.Contains(t, container.Env, corev1.EnvVar{Name: "NODE_EXTRA_CA_CERTS", Value: "/etc/renovate/ca/ca-bundle.crt"})
+}
+
+func TestExecuteVerifiesImageSignature(t *testing.T) {
+	t.Setenv(CosignKeyConfigMapEnvName, "cosign-key")
+	t.Setenv(RenovateImageEnvName, "quay.io/renovate@sha256:2d3f6a1c8a5f1b6e6c4b2f0b9d0fbbd4d7e2b7b6b1f1d3c2a0e9f8d7c6b5a4f3")
+	scheme := runtime.NewScheme()
+	assert.NoError(t, clientgoscheme.AddToScheme(scheme))
+	assert.NoError(t, buildappstudiov1alpha1.AddToScheme(scheme))
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+	coordinator := NewJobCoordinator(fakeClient, scheme)
+
+	assert.NoError(t, coordinator.Execute(context.TODO(), newTestTasks(1)))
+	jobs := listRenovateJobs(t, fakeClient)
+	assert.Len(t, jobs, 1)
+	podSpec := jobs[0].Spec.Template.Spec
+	assert.Contains(t, podSpec.Volumes, corev1.Volume{
+		Name: "cosign-key",
+		VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "cosign-key"},
+			Items:                []corev1.KeyToPath{{Key: CosignKeyConfigMapKey, Path: CosignKeyConfigMapKey}},
+		}},
+	})
+	assert.Len(t, podSpec.InitContainers, 1)
+	verifier := podSpec.InitContainers[0]
+	assert.Equal(t, DefaultCosignImage, verifier.Image)
+	assert.Equal(t, []string{"verify", "--key", "/etc/renovate/cosign/cosign.pub", podSpec.Containers[0].Image}, verifier.Args)
+	// The tokens are available only to the renovate container
+	assert.Equal(t, []corev1.VolumeMount{{Name: "cosign-key", MountPath: CosignKeyMountPath, ReadOnly: true}}, verifier.VolumeMounts)
+}
+
+func TestExecuteRequiresImageDigestWithSignatureVerification(t *testing.T) {
+	t.Setenv(CosignKeyConfigMapEnvName, "cosign-key")
+	t.Setenv(RenovateImageEnvName, "quay.io/renovate:latest")
+	scheme := runtime.NewScheme()
+	assert.NoError(t, clientgoscheme.AddToScheme(scheme))
+	assert.NoError(t, buildappstudiov1alpha1.AddToScheme(scheme))
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+	coordinator := NewJobCoordinator(fakeClient, scheme)
+
+	assert.Error(t, coordinator.Execute(context.TODO(), newTestTasks(1)))
+	assert.Empty(t, listRenovateJobs(t, fakeClient))
+}
+
+func TestExecuteWritesLogsToVolumeClaim(t *testing.T) {
+	t.Setenv(LogsVolumeClaimEnvName, "renovate-logs")
+	scheme := runtime.NewScheme()
+	assert.NoError(t, clientgoscheme.AddToScheme(scheme))
+	assert.NoError(t, buildappstudiov1alpha1.AddToScheme(scheme))
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+	coordinator := NewJobCoordinator(fakeClient, scheme)
+
+	assert.NoError(t, coordinator.Execute(context.TODO(), newTestTasks(1)))
+	jobs := listRenovateJobs(t, fakeClient)
+	assert.Len(t, jobs, 1)
+	podSpec := jobs[0].Spec.Template.Spec
+	assert.Contains(t, podSpec.Volumes, corev1.Volume{
+		Name:         "logs",
+		VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "renovate-logs"}},
+	})
+	container := podSpec.Containers[0]
+	assert.Contains(t, container.VolumeMounts, corev1.VolumeMount{Name: "logs", MountPath: LogsMountPath})
+	assert.Contains(t, container.Env, corev1.EnvVar{Name: "LOG_FILE", Value: "/logs/" + jobs[0].Name + ".log"})
+}
+
+func TestExecuteDryRun(t *testing.T) {
+	t.Setenv(DryRunEnvName, "true")
+	scheme := runtime.NewScheme()
+	assert.NoError(t, clientgoscheme.AddToScheme(scheme))
+	assert.NoError(t, buildappstudiov1alpha1.AddToScheme(scheme))
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+	coordinator := NewJobCoordinator(fakeClient, scheme)
+
+	assert.NoError(t, coordinator.Execute(context.TODO(), newTestTasks(2)))
+	jobs := listRenovateJobs(t, fakeClient)
+	assert.Len(t, jobs, 1)
+	assert.Equal(t, "true", jobs[0].Labels[RenovateDryRunLabelName])
+	command := jobs[0].Spec.Template.Spec.Containers[0].Command[2]
+	assert.Equal(t, 2, strings.Count(command, "renovate --dry-run=full;"))
+}
+
+func TestExecuteRunsTasksInParallelPods(t *testing.T) {
+	t.Setenv(ParallelismEnvName, "2")
+	scheme := runtime.NewScheme()
+	assert.NoError(t, clientgoscheme.AddToScheme(scheme))
+	assert.NoError(t, buildappstudiov1alpha1.AddToScheme(scheme))
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+	coordinator := NewJobCoordinator(fakeClient, scheme)
+
+	assert.NoError(t, coordinator.Execute(context.TODO(), newTestTasks(3)))
+	jobs := listRenovateJobs(t, fakeClient)
+	assert.Len(t, jobs, 1)
+	assert.Equal(t, ptr.To(batchv1.IndexedCompletion), jobs[0].Spec.CompletionMode)
+	assert.Equal(t, ptr.To(int32(3)), jobs[0].Spec.Completions)
+	assert.Equal(t, ptr.To(int32(2)), jobs[0].Spec.Parallelism)
+	assert.Equal(t, ptr.To(int32(3*DefaultBackoffLimit)), jobs[0].Spec.BackoffLimit)
+	command := jobs[0].Spec.Template.Spec.Containers[0].Command[2]
+	assert.Contains(t, command, `case "$JOB_COMPLETION_INDEX" in 0) RENOVATE_TOKEN=`)
+	assert.Contains(t, command, " renovate;; 2) RENOVATE_TOKEN=")
+	assert.Equal(t, 3, strings.Count(command, " renovate;;"))
+
+	// A single task and TaskRuns don't need an indexed job
+	assert.Equal(t, int32(1), NewSettingsFromEnv().JobParallelism(1))
+	settings := NewSettingsFromEnv()
+	settings.ExecutionMode = ExecutionModeTaskRun
+	assert.Equal(t, int32(1), settings.JobParallelism(3))
+}
+
+func TestExecuteRejectsInvalidConfig(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, clientgoscheme.AddToScheme(scheme))
+	assert.NoError(t, buildappstudiov1alpha1.AddToScheme(scheme))
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+	coordinator := NewJobCoordinator(fakeClient, scheme)
+
+	tasks := newTestTasks(2)
+	tasks[1].Repositories[0].BaseBranches = []string{"main..release"}
+	assert.ErrorContains(t, coordinator.Execute(context.TODO(), tasks), "invalid base branch of repository org/repo1")
+	assert.Empty(t, listRenovateJobs(t, fakeClient))
+}
+
+func TestExecuteAppliesConfigOverlay(t *testing.T) {
+	t.Setenv(ConfigOverlayEnvName, "renovate-overlay")
+	scheme := runtime.NewScheme()
+	assert.NoError(t, clientgoscheme.AddToScheme(scheme))
+	assert.NoError(t, buildappstudiov1alpha1.AddToScheme(scheme))
+	overlay := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "renovate-overlay", Namespace: BuildServiceNamespaceName},
+		Data:       map[string]string{ConfigOverlayConfigMapKey: `{"labels": ["konflux"], "reviewers": ["team:build"]}`},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(overlay).Build()
+	eventRecorder := record.NewFakeRecorder(10)
+	coordinator := NewJobCoordinator(fakeClient, scheme).WithEventRecorder(eventRecorder)
+
+	assert.NoError(t, coordinator.Execute(context.TODO(), newTestTasks(1)))
+	jobs := listRenovateJobs(t, fakeClient)
+	assert.Len(t, jobs, 1)
+	configMap := &corev1.ConfigMap{}
+	assert.NoError(t, fakeClient.Get(context.TODO(), types.NamespacedName{Name: jobs[0].Name, Namespace: BuildServiceNamespaceName}, configMap))
+	assert.Len(t, configMap.Data, 1)
+	for _, data := range configMap.Data {
+		config := map[string]interface{}{}
+		assert.NoError(t, json.Unmarshal([]byte(data), &config))
+		assert.Equal(t, []interface{}{"konflux"}, config["labels"])
+		assert.Equal(t, []interface{}{"team:build"}, config["reviewers"])
+		assert.Equal(t, "github", config["platform"])
+	}
+
+	// Invalid overlay is ignored and reported
+	overlay.Data[ConfigOverlayConfigMapKey] = "{invalid"
+	assert.NoError(t, fakeClient.Update(context.TODO(), overlay))
+	assert.NoError(t, coordinator.Execute(context.TODO(), newTestTasks(1)))
+	assert.Len(t, listRenovateJobs(t, fakeClient), 2)
+	assert.Contains(t, <-eventRecorder.Events, "InvalidRenovateConfigOverlay")
+
+	// Overlay breaking the validated values is ignored, the job gets the generated config
+	overlay.Data[ConfigOverlayConfigMapKey] = `{"labels": ["konflux"], "tekton": {"packageRules": [{"matchDepPatterns": ["quay.io/{org}"]}]}}`
+	assert.NoError(t, fakeClient.Update(context.TODO(), overlay))
+	assert.NoError(t, coordinator.Execute(context.TODO(), newTestTasks(1)))
+	jobs = listRenovateJobs(t, fakeClient)
+	assert.Len(t, jobs, 3)
+	assert.Contains(t, <-eventRecorder.Events, "InvalidRenovateConfigOverlay")
+	for _, job := range jobs {
+		assert.NoError(t, fakeClient.Get(context.TODO(), types.NamespacedName{Name: job.Name, Namespace: BuildServiceNamespaceName}, configMap))
+		for _, data := range configMap.Data {
+			assert.NotContains(t, data, "quay.io/{org}")
+		}
+	}
+}
+
+func TestExecuteReportsPullRequests(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, clientgoscheme.AddToScheme(scheme))
+	assert.NoError(t, buildappstudiov1alpha1.AddToScheme(scheme))
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+	coordinator := NewJobCoordinator(fakeClient, scheme)
+
+	assert.NoError(t, coordinator.Execute(context.TODO(), newTestTasks(1)))
+	jobs := listRenovateJobs(t, fakeClient)
+	assert.Len(t, jobs, 1)
+	assert.Equal(t, "true", jobs[0].Spec.Template.Labels[RenovateJobLabelName])
+	container := jobs[0].Spec.Template.Spec.Containers[0]
+	assert.Contains(t, container.Env, corev1.EnvVar{Name: "LOG_FILE", Value: DefaultLogFile})
+	assert.Contains(t, container.Env, corev1.EnvVar{Name: "RENOVATE_REPORT_PATH", Value: "/dev/termination-log"})
+	// The job fails if renovate fails
+	assert.True(t, strings.HasSuffix(container.Command[2], "; exit $status"))
+}
@@ -0,0 +1,116 @@
+package renovate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logger "sigs.k8s.io/controller-runtime/pkg/log"
+
+	tektonapi "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+
+	. "github.com/konflux-ci/build-service/pkg/common"
+)
+
+const (
+	// Name of the renovate TaskRun result with the pull requests report
+	PullRequestsResultName = "pull-requests"
+	// Renovate JSON logs are written to the file, unless the logs are persisted
+	DefaultLogFile = "/tmp/renovate.log"
+	// Path of the pull requests report, the termination message of the Job container or the result of the TaskRun
+	reportPathEnvName = "RENOVATE_REPORT_PATH"
+	// Both termination message and Tekton results are limited to 4096 bytes
+	maxReportSize = 4000
+)
+
+// reportScript collects pull requests created by renovate from its JSON log file into the report.
+// The pull requests which don't fit into the size limit are left out.
+var reportScript = fmt.Sprintf(`node -e '
+const fs = require("fs");
+const prs = [];
+try {
+  for (const line of fs.readFileSync(process.env.LOG_FILE, "utf8").split("\n")) {
+    try {
+      const record = JSON.parse(line);
+      if (record.msg === "PR created" && record.pr && !prs.some(pr => pr.repository === record.repository && pr.number === record.pr)) {
+        prs.push({repository: record.repository, branch: record.branch, number: record.pr});
+      }
+    } catch (e) {}
+  }
+} catch (e) {}
+while (prs.length > 0 && JSON.stringify(prs).length > %d) prs.pop();
+fs.writeFileSync(process.env.%s, JSON.stringify(prs));
+'`, maxReportSize, reportPathEnvName)
+
+// PullRequestReport describes a pull request created by renovate job.
+type PullRequestReport struct {
+	Repository string `json:"repository"`
+	Branch     string `json:"branch,omitempty"`
+	Number     int    `json:"number"`
+}
+
+// ParsePullRequestReport returns the pull requests from the report written by the renovate job.
+func ParsePullRequestReport(report string) ([]PullRequestReport, error) {
+	if report == "" {
+		return nil, nil
+	}
+	var pullRequests []PullRequestReport
+	if err := json.Unmarshal([]byte(report), &pullRequests); err != nil {
+		return nil, err
+	}
+	return pullRequests, nil
+}
+
+// GetPullRequestReport returns the pull requests created by the finished renovate Job or TaskRun.
+// Reports of all the Job pods are merged, because a retried pod may have created some pull requests before it failed.
+func (j *JobCoordinator) GetPullRequestReport(ctx context.Context, run client.Object) ([]PullRequestReport, error) {
+	log := logger.FromContext(ctx)
+	var reports []string
+	switch run := run.(type) {
+	case *tektonapi.TaskRun:
+		for _, result := range run.Status.Results {
+			if result.Name == PullRequestsResultName {
+				reports = append(reports, result.Value.StringVal)
+			}
+		}
+	case *batchv1.Job:
+		podList := &corev1.PodList{}
+		if err := j.client.List(ctx, podList, client.InNamespace(BuildServiceNamespaceName), client.MatchingLabels{RenovateJobLabelName: "true", "job-name": run.Name}); err != nil {
+			return nil, err
+		}
+		for _, pod := range podList.Items {
+			for _, containerStatus := range pod.Status.ContainerStatuses {
+				if containerStatus.Name == renovateContainerName && containerStatus.State.Terminated != nil {
+					reports = append(reports, containerStatus.State.Terminated.Message)
+				}
+			}
+		}
+	}
+
+	var pullRequests []PullRequestReport
+	for _, report := range reports {
+		reportPullRequests, err := ParsePullRequestReport(report)
+		if err != nil {
+			log.Error(err, "failed to parse renovate pull requests report", "jobname", run.GetName())
+			continue
+		}
+		for _, pullRequest := range reportPullRequests {
+			if !containsPullRequest(pullRequests, pullRequest) {
+				pullRequests = append(pullRequests, pullRequest)
+			}
+		}
+	}
+	return pullRequests, nil
+}
+
+func containsPullRequest(pullRequests []PullRequestReport, pullRequest PullRequestReport) bool {
+	for _, pr := range pullRequests {
+		if pr.Repository == pullRequest.Repository && pr.Number == pullRequest.Number {
+			return true
+		}
+	}
+	return false
+}
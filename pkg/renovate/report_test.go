@@ -0,0 +1,78 @@
+package renovate
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	tektonapi "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	. "github.com/konflux-ci/build-service/pkg/common"
+)
+
+func TestParsePullRequestReport(t *testing.T) {
+	pullRequests, err := ParsePullRequestReport(`[{"repository":"org/repo","branch":"konflux/references/main","number":12}]`)
+	assert.NoError(t, err)
+	assert.Equal(t, []PullRequestReport{{Repository: "org/repo", Branch: "konflux/references/main", Number: 12}}, pullRequests)
+
+	pullRequests, err = ParsePullRequestReport("")
+	assert.NoError(t, err)
+	assert.Empty(t, pullRequests)
+
+	_, err = ParsePullRequestReport("Error: renovate failed")
+	assert.Error(t, err)
+}
+
+func newTestRenovatePod(name, jobName, message string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: BuildServiceNamespaceName,
+			Labels:    map[string]string{RenovateJobLabelName: "true", "job-name": jobName},
+		},
+		Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{
+			Name:  "renovate",
+			State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Message: message}},
+		}}},
+	}
+}
+
+func TestGetPullRequestReportOfJob(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, clientgoscheme.AddToScheme(scheme))
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		// The job was retried, the first pod created a pull request before it failed
+		newTestRenovatePod("renovate-job-1-a", "renovate-job-1", `[{"repository":"org/repo1","branch":"konflux/references/main","number":1}]`),
+		newTestRenovatePod("renovate-job-1-b", "renovate-job-1", `[{"repository":"org/repo1","branch":"konflux/references/main","number":1},{"repository":"org/repo2","number":7}]`),
+		newTestRenovatePod("renovate-job-2-a", "renovate-job-2", `[{"repository":"org/repo3","number":3}]`),
+	).Build()
+	coordinator := NewJobCoordinator(fakeClient, scheme)
+
+	job := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "renovate-job-1", Namespace: BuildServiceNamespaceName}}
+	pullRequests, err := coordinator.GetPullRequestReport(context.TODO(), job)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []PullRequestReport{
+		{Repository: "org/repo1", Branch: "konflux/references/main", Number: 1},
+		{Repository: "org/repo2", Number: 7},
+	}, pullRequests)
+}
+
+func TestGetPullRequestReportOfTaskRun(t *testing.T) {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, clientgoscheme.AddToScheme(scheme))
+	coordinator := NewJobCoordinator(fake.NewClientBuilder().WithScheme(scheme).Build(), scheme)
+
+	taskRun := &tektonapi.TaskRun{ObjectMeta: metav1.ObjectMeta{Name: "renovate-job-1"}}
+	taskRun.Status.Results = []tektonapi.TaskRunResult{
+		{Name: PullRequestsResultName, Value: *tektonapi.NewStructuredValues(`[{"repository":"org/repo","number":5}]`)},
+	}
+	pullRequests, err := coordinator.GetPullRequestReport(context.TODO(), taskRun)
+	assert.NoError(t, err)
+	assert.Equal(t, []PullRequestReport{{Repository: "org/repo", Number: 5}}, pullRequests)
+}
@@ -0,0 +1,713 @@
+package renovate
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/strings/slices"
+
+	buildappstudiov1alpha1 "github.com/konflux-ci/build-service/api/v1alpha1"
+	. "github.com/konflux-ci/build-service/pkg/common"
+	"github.com/konflux-ci/build-service/pkg/featuregates"
+)
+
+const (
+	DefaultBackoffLimit  = 1
+	DefaultParallelism   = 1
+	RetryLimitEnvName    = "RENOVATE_RETRY_LIMIT"
+	DefaultRetryLimit    = 2
+	MaxRetryLimit        = 10
+	RetryBackoffEnvName  = "RENOVATE_RETRY_BACKOFF"
+	DefaultRetryBackoff  = 5 * time.Minute
+	SweepIntervalEnvName = "RENOVATE_SWEEP_INTERVAL"
+	DefaultSweepInterval = 6 * time.Hour
+	MinSweepInterval     = 10 * time.Minute
+	// Retried jobs reuse the installation tokens of the sweep, which expire after an hour,
+	// so all the retries have to start within this time since the sweep.
+	MaxRetryDelay = 45 * time.Minute
+
+	CpuRequestEnvName    = "RENOVATE_CPU_REQUEST"
+	MemoryRequestEnvName = "RENOVATE_MEMORY_REQUEST"
+	CpuLimitEnvName      = "RENOVATE_CPU_LIMIT"
+	MemoryLimitEnvName   = "RENOVATE_MEMORY_LIMIT"
+	DefaultCpuRequest    = "100m"
+	DefaultMemoryRequest = "512Mi"
+	DefaultCpuLimit      = "1"
+	DefaultMemoryLimit   = "2Gi"
+
+	PriorityClassNameEnvName = "RENOVATE_PRIORITY_CLASS_NAME"
+	ServiceAccountEnvName    = "RENOVATE_SERVICE_ACCOUNT"
+	MaxParallelJobsEnvName   = "RENOVATE_MAX_PARALLEL_JOBS"
+	ParallelismEnvName       = "RENOVATE_PARALLELISM"
+	AutomergeEnvName         = "RENOVATE_AUTOMERGE"
+	PinDigestsEnvName        = "RENOVATE_PIN_DIGESTS"
+	GroupAllUpdatesEnvName   = "RENOVATE_GROUP_ALL_UPDATES"
+	DryRunEnvName            = "RENOVATE_DRY_RUN"
+	PRHourlyLimitEnvName     = "RENOVATE_PR_HOURLY_LIMIT"
+	PRConcurrentLimitEnvName = "RENOVATE_PR_CONCURRENT_LIMIT"
+	StopUpdatingLabelEnvName = "RENOVATE_STOP_UPDATING_LABEL"
+	DefaultStopUpdatingLabel = "stop-updating"
+	// Author of renovate commits in 'Name <email>' form
+	GitAuthorEnvName    = "RENOVATE_GIT_AUTHOR"
+	BranchPrefixEnvName = "RENOVATE_BRANCH_PREFIX"
+	RebaseWhenEnvName   = "RENOVATE_REBASE_WHEN"
+	// Set to "false" to keep the stale renovate branches
+	PruneStaleBranchesEnvName = "RENOVATE_PRUNE_STALE_BRANCHES"
+	// Comma separated list of paths with Tekton PipelineRuns
+	IncludePathsEnvName = "RENOVATE_INCLUDE_PATHS"
+	// Comma separated list of regular expressions of excluded repository full names
+	ExcludeRepositoriesEnvName = "RENOVATE_EXCLUDE_REPOSITORIES"
+	// Comma separated lists of namespaces whose Components are or aren't renovated
+	IncludeNamespacesEnvName = "RENOVATE_INCLUDE_NAMESPACES"
+	ExcludeNamespacesEnvName = "RENOVATE_EXCLUDE_NAMESPACES"
+
+	// Proxy settings of the operator are passed to renovate
+	HttpProxyEnvName  = "HTTP_PROXY"
+	HttpsProxyEnvName = "HTTPS_PROXY"
+	NoProxyEnvName    = "NO_PROXY"
+
+	TrustedCAConfigMapEnvName = "RENOVATE_TRUSTED_CA_CONFIGMAP"
+
+	ArchitectureEnvName = "RENOVATE_ARCHITECTURE"
+
+	CosignKeyConfigMapEnvName = "RENOVATE_COSIGN_KEY_CONFIGMAP"
+
+	LogsVolumeClaimEnvName = "RENOVATE_LOGS_PVC"
+
+	RepositoriesPerJobEnvName = "RENOVATE_REPOSITORIES_PER_JOB"
+
+	ConfigOverlayEnvName = "RENOVATE_CONFIG_OVERLAY_CONFIGMAP"
+
+	ExecutionModeEnvName    = "RENOVATE_EXECUTION_MODE"
+	TaskRunNamespaceEnvName = "RENOVATE_TASKRUN_NAMESPACE"
+	// Supported only in TaskRun execution mode, Jobs always run in the build-service namespace
+	TenantNamespacesEnvName = "RENOVATE_TENANT_NAMESPACES"
+	ExecutionModeJob        = "Job"
+	ExecutionModeTaskRun    = "TaskRun"
+)
+
+// Settings holds the configuration of renovate jobs.
+// The defaults are taken from the environment variables and can be overridden by RenovateTektonConfig.
+type Settings struct {
+	Image                   string
+	Architecture            string
+	ArchitectureImages      map[string]string
+	MatchPatterns           []string
+	TasksPerJob             int
+	RepositoriesPerJob      int
+	MaxParallelJobs         int
+	Schedule                []string
+	Automerge               bool
+	PinDigests              bool
+	GroupAllUpdates         bool
+	DryRun                  bool
+	PRHourlyLimit           *int
+	PRConcurrentLimit       *int
+	StopUpdatingLabel       string
+	RebaseWhen              string
+	GitAuthor               string
+	NamespaceGitAuthors     map[string]string
+	BranchPrefix            string
+	PruneStaleBranches      *bool
+	Templates               buildappstudiov1alpha1.RenovateTemplates
+	PostUpgradeTasks        *buildappstudiov1alpha1.RenovatePostUpgradeTasks
+	IncludePaths            []string
+	ExcludeRepositories     []string
+	IncludeNamespaces       []string
+	ExcludeNamespaces       []string
+	Managers                []buildappstudiov1alpha1.RenovateManager
+	HostRules               []buildappstudiov1alpha1.RenovateHostRule
+	ConfigOverlayConfigMap  string
+	TTLSecondsAfterFinished int32
+	BackoffLimit            int32
+	Parallelism             int32
+	RetryLimit              int32
+	RetryBackoff            time.Duration
+	Resources               corev1.ResourceRequirements
+	NodeSelector            map[string]string
+	Tolerations             []corev1.Toleration
+	Affinity                *corev1.Affinity
+	PriorityClassName       string
+	ServiceAccountName      string
+	ImagePullSecrets        []corev1.LocalObjectReference
+	Proxy                   buildappstudiov1alpha1.RenovateProxy
+	TrustedCAConfigMapName  string
+	CosignKeyConfigMapName  string
+	LogsVolumeClaimName     string
+	ExecutionMode           string
+	TaskRunNamespace        string
+	TenantNamespaces        bool
+	TenantRepositoryOwners  map[string][]string
+	SweepInterval           time.Duration
+	SweepSchedule           string
+	SuspendSweeps           bool
+}
+
+// NewSettingsFromEnv returns renovate settings based on the environment variables and built-in defaults.
+func NewSettingsFromEnv() Settings {
+	var tasksPerJobInt int
+	tasksPerJobStr := GetConfigValue(InstallationsPerJobEnvName)
+	if regexp.MustCompile(`^\d{1,2}$`).MatchString(tasksPerJobStr) {
+		tasksPerJobInt, _ = strconv.Atoi(tasksPerJobStr)
+		if tasksPerJobInt == 0 {
+			tasksPerJobInt = TasksPerJob
+		}
+	} else {
+		tasksPerJobInt = TasksPerJob
+	}
+	var executionMode string
+	if GetConfigValue(ExecutionModeEnvName) == ExecutionModeTaskRun {
+		executionMode = ExecutionModeTaskRun
+	}
+	renovateImageUrl := GetConfigValue(RenovateImageEnvName)
+	if renovateImageUrl == "" {
+		renovateImageUrl = DefaultRenovateImageUrl
+	}
+	return Settings{
+		Image:                   renovateImageUrl,
+		Architecture:            GetConfigValue(ArchitectureEnvName),
+		MatchPatterns:           GetRenovatePatternsConfiguration(),
+		TasksPerJob:             tasksPerJobInt,
+		RepositoriesPerJob:      getRepositoriesPerJobFromEnv(),
+		TTLSecondsAfterFinished: int32(TimeToLiveOfJob / time.Second),
+		BackoffLimit:            DefaultBackoffLimit,
+		Parallelism:             getParallelismFromEnv(),
+		RetryLimit:              getRetryLimitFromEnv(),
+		RetryBackoff:            getRetryBackoffFromEnv(),
+		SweepInterval:           getSweepIntervalFromEnv(),
+		PriorityClassName:       GetConfigValue(PriorityClassNameEnvName),
+		ServiceAccountName:      GetConfigValue(ServiceAccountEnvName),
+		ExecutionMode:           executionMode,
+		TrustedCAConfigMapName:  GetConfigValue(TrustedCAConfigMapEnvName),
+		CosignKeyConfigMapName:  GetConfigValue(CosignKeyConfigMapEnvName),
+		LogsVolumeClaimName:     GetConfigValue(LogsVolumeClaimEnvName),
+		ConfigOverlayConfigMap:  GetConfigValue(ConfigOverlayEnvName),
+		TaskRunNamespace:        GetConfigValue(TaskRunNamespaceEnvName),
+		TenantNamespaces:        GetConfigValue(TenantNamespacesEnvName) == "true",
+		MaxParallelJobs:         getMaxParallelJobsFromEnv(),
+		Automerge:               GetConfigValue(AutomergeEnvName) == "true",
+		PinDigests:              GetConfigValue(PinDigestsEnvName) == "true",
+		GroupAllUpdates:         GetConfigValue(GroupAllUpdatesEnvName) == "true",
+		DryRun:                  GetConfigValue(DryRunEnvName) == "true",
+		PRHourlyLimit:           getPullRequestLimitFromEnv(PRHourlyLimitEnvName),
+		PRConcurrentLimit:       getPullRequestLimitFromEnv(PRConcurrentLimitEnvName),
+		StopUpdatingLabel:       getStopUpdatingLabelFromEnv(),
+		RebaseWhen:              getRebaseWhenFromEnv(),
+		GitAuthor:               strings.TrimSpace(GetConfigValue(GitAuthorEnvName)),
+		BranchPrefix:            strings.TrimSpace(GetConfigValue(BranchPrefixEnvName)),
+		PruneStaleBranches:      getPruneStaleBranchesFromEnv(),
+		IncludePaths:            getListFromEnv(IncludePathsEnvName),
+		ExcludeRepositories:     getListFromEnv(ExcludeRepositoriesEnvName),
+		IncludeNamespaces:       getListFromEnv(IncludeNamespacesEnvName),
+		ExcludeNamespaces:       getListFromEnv(ExcludeNamespacesEnvName),
+		Proxy: buildappstudiov1alpha1.RenovateProxy{
+			HttpProxy:  os.Getenv(HttpProxyEnvName),
+			HttpsProxy: os.Getenv(HttpsProxyEnvName),
+			NoProxy:    os.Getenv(NoProxyEnvName),
+		},
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceCPU:    getResourceQuantityFromEnv(CpuRequestEnvName, DefaultCpuRequest),
+				corev1.ResourceMemory: getResourceQuantityFromEnv(MemoryRequestEnvName, DefaultMemoryRequest),
+			},
+			Limits: corev1.ResourceList{
+				corev1.ResourceCPU:    getResourceQuantityFromEnv(CpuLimitEnvName, DefaultCpuLimit),
+				corev1.ResourceMemory: getResourceQuantityFromEnv(MemoryLimitEnvName, DefaultMemoryLimit),
+			},
+		},
+	}
+}
+
+// getMaxParallelJobsFromEnv returns the limit of simultaneously running renovate jobs, 0 means no limit.
+func getMaxParallelJobsFromEnv() int {
+	maxParallelJobs, err := strconv.Atoi(GetConfigValue(MaxParallelJobsEnvName))
+	if err != nil || maxParallelJobs < 0 {
+		return 0
+	}
+	return maxParallelJobs
+}
+
+// getRepositoriesPerJobFromEnv returns the maximum number of repositories per job, 0 means no limit.
+func getRepositoriesPerJobFromEnv() int {
+	repositoriesPerJob, err := strconv.Atoi(GetConfigValue(RepositoriesPerJobEnvName))
+	if err != nil || repositoriesPerJob < 0 {
+		return 0
+	}
+	return repositoriesPerJob
+}
+
+// getPullRequestLimitFromEnv returns the renovate pull request limit from the given environment variable, 0 means no limit.
+// Invalid and negative values are ignored, nil leaves the limit to the renovate default.
+func getPullRequestLimitFromEnv(envName string) *int {
+	limit, err := strconv.Atoi(GetConfigValue(envName))
+	if err != nil || limit < 0 {
+		return nil
+	}
+	return &limit
+}
+
+// getStopUpdatingLabelFromEnv returns the label which stops renovate from updating a pull request.
+func getStopUpdatingLabelFromEnv() string {
+	if label := strings.TrimSpace(GetConfigValue(StopUpdatingLabelEnvName)); label != "" {
+		return label
+	}
+	return DefaultStopUpdatingLabel
+}
+
+// getRebaseWhenFromEnv returns the renovate rebaseWhen setting, empty if not set or unknown.
+func getRebaseWhenFromEnv() string {
+	rebaseWhen := strings.TrimSpace(GetConfigValue(RebaseWhenEnvName))
+	if !slices.Contains(RebaseWhenValues, rebaseWhen) {
+		return ""
+	}
+	return rebaseWhen
+}
+
+// getPruneStaleBranchesFromEnv returns whether stale renovate branches are deleted, nil if not set or invalid.
+func getPruneStaleBranchesFromEnv() *bool {
+	prune, err := strconv.ParseBool(GetConfigValue(PruneStaleBranchesEnvName))
+	if err != nil {
+		return nil
+	}
+	return &prune
+}
+
+// getParallelismFromEnv returns the number of installations of a job processed in parallel pods.
+// Invalid and non-positive values are ignored.
+func getParallelismFromEnv() int32 {
+	parallelism, err := strconv.ParseInt(GetConfigValue(ParallelismEnvName), 10, 32)
+	if err != nil || parallelism < 1 {
+		return DefaultParallelism
+	}
+	return int32(parallelism)
+}
+
+// getRetryLimitFromEnv returns the number of times a failed sweep job is created again, 0 disables the retries.
+// Values over MaxRetryLimit are ignored, the backoff doubles with each retry.
+func getRetryLimitFromEnv() int32 {
+	retryLimit, err := strconv.ParseInt(GetConfigValue(RetryLimitEnvName), 10, 32)
+	if err != nil || retryLimit < 0 || retryLimit > MaxRetryLimit {
+		return DefaultRetryLimit
+	}
+	return int32(retryLimit)
+}
+
+// getRetryBackoffFromEnv returns the delay before the first retry of a failed sweep job.
+// Invalid and non-positive values are ignored.
+func getRetryBackoffFromEnv() time.Duration {
+	backoff, err := time.ParseDuration(GetConfigValue(RetryBackoffEnvName))
+	if err != nil || backoff <= 0 {
+		return DefaultRetryBackoff
+	}
+	return backoff
+}
+
+// totalRetryDelay returns the delay of the last retry of a failed job since the first attempt,
+// the backoff doubles with each retry.
+func totalRetryDelay(backoff time.Duration, retryLimit int32) time.Duration {
+	if retryLimit <= 0 {
+		return 0
+	}
+	return backoff * time.Duration((int64(1)<<retryLimit)-1)
+}
+
+// getListFromEnv returns the non-empty items of the given comma separated environment variable.
+func getListFromEnv(envName string) []string {
+	var list []string
+	for _, item := range strings.Split(GetConfigValue(envName), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// getResourceQuantityFromEnv returns the resource quantity from the given environment variable, e.g. '500m' or '1Gi'.
+// Invalid values are ignored.
+func getResourceQuantityFromEnv(envName string, defaultValue string) resource.Quantity {
+	if quantity, err := resource.ParseQuantity(GetConfigValue(envName)); err == nil {
+		return quantity
+	}
+	return resource.MustParse(defaultValue)
+}
+
+// getSweepIntervalFromEnv returns the interval between periodic renovate sweeps, e.g. '1h' or '24h'.
+// Invalid values and values shorter than MinSweepInterval are ignored.
+func getSweepIntervalFromEnv() time.Duration {
+	interval, err := time.ParseDuration(GetConfigValue(SweepIntervalEnvName))
+	if err != nil || interval < MinSweepInterval {
+		return DefaultSweepInterval
+	}
+	return interval
+}
+
+// IsRepositoryExcluded checks whether the repository full name, e.g. 'my-org/my-repo', matches any of the exclusion patterns.
+// Invalid patterns don't match any repository.
+func (s Settings) IsRepositoryExcluded(repository string) bool {
+	for _, pattern := range s.ExcludeRepositories {
+		if matched, err := regexp.MatchString(pattern, repository); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks the settings which are formatted into the renovate config of every job,
+// so an invalid value would break the updates of all repositories.
+func (s Settings) Validate() error {
+	for _, pattern := range s.MatchPatterns {
+		if err := ValidateMatchPattern(pattern); err != nil {
+			return err
+		}
+	}
+	for _, template := range []string{s.Templates.CommitMessagePrefix, s.Templates.CommitMessageTopic, s.Templates.PRTitle, s.Templates.SemanticCommitScope} {
+		if err := ValidateTemplate(template); err != nil {
+			return err
+		}
+	}
+	if s.GitAuthor != "" {
+		if err := ValidateGitAuthor(s.GitAuthor); err != nil {
+			return err
+		}
+	}
+	for _, author := range s.NamespaceGitAuthors {
+		if err := ValidateGitAuthor(author); err != nil {
+			return err
+		}
+	}
+	if s.BranchPrefix != "" {
+		if err := ValidateBranchName(referencesBranchName(s.BranchPrefix)); err != nil {
+			return fmt.Errorf("invalid branch prefix %q: %w", s.BranchPrefix, err)
+		}
+	}
+	if totalDelay := totalRetryDelay(s.RetryBackoff, s.RetryLimit); totalDelay > MaxRetryDelay {
+		return fmt.Errorf("retry backoff %s with %d retries delays the last retry by %s, more than %s the tokens are valid for", s.RetryBackoff, s.RetryLimit, totalDelay, MaxRetryDelay)
+	}
+	if s.PostUpgradeTasks != nil {
+		for _, command := range s.PostUpgradeTasks.AllowedCommands {
+			if err := ValidateAllowedCommand(command); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ProxyEnv returns the proxy environment variables of the renovate container.
+// Both upper and lower case variants are set, as the tools run by renovate, e.g. git, differ in which one they read.
+func (s Settings) ProxyEnv() []corev1.EnvVar {
+	var env []corev1.EnvVar
+	for _, variable := range []struct{ name, value string }{
+		{HttpProxyEnvName, s.Proxy.HttpProxy},
+		{HttpsProxyEnvName, s.Proxy.HttpsProxy},
+		{NoProxyEnvName, s.Proxy.NoProxy},
+	} {
+		if variable.value != "" {
+			env = append(env,
+				corev1.EnvVar{Name: variable.name, Value: variable.value},
+				corev1.EnvVar{Name: strings.ToLower(variable.name), Value: variable.value})
+		}
+	}
+	return env
+}
+
+// selectedArchitecture returns the architecture of the renovate pods or empty string if it's not selected
+// or the selection is disabled by MultiArch feature gate.
+func (s Settings) selectedArchitecture() string {
+	if !featuregates.Enabled(featuregates.MultiArch) {
+		return ""
+	}
+	return s.Architecture
+}
+
+// RenovateImage returns the renovate image for the selected architecture.
+func (s Settings) RenovateImage() string {
+	architecture := s.selectedArchitecture()
+	if image := s.ArchitectureImages[architecture]; architecture != "" && image != "" {
+		return image
+	}
+	return s.Image
+}
+
+// PodAffinity returns the affinity of the renovate pods,
+// requiring nodes with the selected architecture in addition to the configured affinity.
+func (s Settings) PodAffinity() *corev1.Affinity {
+	architecture := s.selectedArchitecture()
+	if architecture == "" {
+		return s.Affinity
+	}
+	affinity := &corev1.Affinity{}
+	if s.Affinity != nil {
+		affinity = s.Affinity.DeepCopy()
+	}
+	if affinity.NodeAffinity == nil {
+		affinity.NodeAffinity = &corev1.NodeAffinity{}
+	}
+	if affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution = &corev1.NodeSelector{}
+	}
+	nodeSelector := affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
+	if len(nodeSelector.NodeSelectorTerms) == 0 {
+		nodeSelector.NodeSelectorTerms = []corev1.NodeSelectorTerm{{}}
+	}
+	// The terms are ORed, so the architecture is required in each of them
+	for i := range nodeSelector.NodeSelectorTerms {
+		nodeSelector.NodeSelectorTerms[i].MatchExpressions = append(nodeSelector.NodeSelectorTerms[i].MatchExpressions, corev1.NodeSelectorRequirement{
+			Key:      corev1.LabelArchStable,
+			Operator: corev1.NodeSelectorOpIn,
+			Values:   []string{architecture},
+		})
+	}
+	return affinity
+}
+
+// JobParallelism returns the number of parallel pods of a renovate job with the given number of tasks.
+// TaskRun steps can't run in parallel, so TaskRuns always process the tasks sequentially in one pod.
+func (s Settings) JobParallelism(tasks int) int32 {
+	if s.ExecutionMode == ExecutionModeTaskRun || s.Parallelism <= 1 || tasks <= 1 {
+		return 1
+	}
+	if int32(tasks) < s.Parallelism {
+		return int32(tasks)
+	}
+	return s.Parallelism
+}
+
+// RunNamespace returns the namespace of the renovate Jobs or TaskRuns.
+func (s Settings) RunNamespace() string {
+	if s.ExecutionMode == ExecutionModeTaskRun && s.TaskRunNamespace != "" {
+		return s.TaskRunNamespace
+	}
+	return BuildServiceNamespaceName
+}
+
+// JobServiceAccountName returns the service account of the renovate pods. The default service account exists only
+// in the build-service namespace, so the TaskRuns use the Tekton default unless the service account is set.
+func (s Settings) JobServiceAccountName() string {
+	if s.ServiceAccountName != "" || s.ExecutionMode == ExecutionModeTaskRun {
+		return s.ServiceAccountName
+	}
+	return RenovateJobServiceAccountName
+}
+
+// IsTenantScoped checks whether the renovate TaskRuns run in the namespaces of the renovated Components.
+// Only TaskRuns can run in the tenant namespaces, TenantNamespaces is ignored in Job execution mode
+// and the Jobs run in the build-service namespace.
+func (s Settings) IsTenantScoped() bool {
+	return s.ExecutionMode == ExecutionModeTaskRun && s.TenantNamespaces
+}
+
+// IsTenantRepositoryOwner checks whether the namespace owns the repositories of the given GitHub owner,
+// so the token of the repositories may be handed over to the namespace.
+func (s Settings) IsTenantRepositoryOwner(namespace string, owner string) bool {
+	for _, namespaceOwner := range s.TenantRepositoryOwners[namespace] {
+		// GitHub logins are case insensitive
+		if strings.EqualFold(namespaceOwner, owner) {
+			return true
+		}
+	}
+	return false
+}
+
+// ListNamespace returns the namespace the renovate Jobs or TaskRuns are listed in, all namespaces if they are tenant scoped.
+func (s Settings) ListNamespace() string {
+	if s.IsTenantScoped() {
+		return ""
+	}
+	return s.RunNamespace()
+}
+
+// NamespaceGitAuthor returns the author of renovate commits in the repositories of the Components of the namespace,
+// empty if the namespace has no specific author.
+func (s Settings) NamespaceGitAuthor(namespace string) string {
+	return s.NamespaceGitAuthors[namespace]
+}
+
+// IsNamespaceIncluded checks whether the Components of the namespace are renovated.
+func (s Settings) IsNamespaceIncluded(namespace string) bool {
+	if slices.Contains(s.ExcludeNamespaces, namespace) {
+		return false
+	}
+	return len(s.IncludeNamespaces) == 0 || slices.Contains(s.IncludeNamespaces, namespace)
+}
+
+// WithConfig returns a copy of the settings with the values set in the given RenovateTektonConfig.
+func (s Settings) WithConfig(config *buildappstudiov1alpha1.RenovateTektonConfig) Settings {
+	if config == nil {
+		return s
+	}
+	spec := config.Spec
+	if spec.Image != "" {
+		s.Image = spec.Image
+	}
+	if spec.Architecture != "" {
+		s.Architecture = spec.Architecture
+	}
+	if len(spec.ArchitectureImages) > 0 {
+		s.ArchitectureImages = spec.ArchitectureImages
+	}
+	if len(spec.MatchPatterns) > 0 {
+		s.MatchPatterns = spec.MatchPatterns
+	} else if spec.MatchPattern != "" {
+		s.MatchPatterns = []string{spec.MatchPattern}
+	}
+	if spec.InstallationsPerJob > 0 {
+		s.TasksPerJob = spec.InstallationsPerJob
+	}
+	if spec.RepositoriesPerJob > 0 {
+		s.RepositoriesPerJob = spec.RepositoriesPerJob
+	}
+	if len(spec.Schedule) > 0 {
+		s.Schedule = spec.Schedule
+	}
+	if spec.Automerge != nil {
+		s.Automerge = *spec.Automerge
+	}
+	if spec.GroupAllUpdates != nil {
+		s.GroupAllUpdates = *spec.GroupAllUpdates
+	}
+	if spec.PinDigests != nil {
+		s.PinDigests = *spec.PinDigests
+	}
+	if spec.DryRun != nil {
+		s.DryRun = *spec.DryRun
+	}
+	if spec.PRHourlyLimit != nil {
+		s.PRHourlyLimit = spec.PRHourlyLimit
+	}
+	if spec.PRConcurrentLimit != nil {
+		s.PRConcurrentLimit = spec.PRConcurrentLimit
+	}
+	if spec.StopUpdatingLabel != "" {
+		s.StopUpdatingLabel = spec.StopUpdatingLabel
+	}
+	if spec.RebaseWhen != "" {
+		s.RebaseWhen = spec.RebaseWhen
+	}
+	if spec.GitAuthor != nil {
+		s.GitAuthor = FormatGitAuthor(spec.GitAuthor.Name, spec.GitAuthor.Email)
+	}
+	if spec.BranchPrefix != "" {
+		s.BranchPrefix = spec.BranchPrefix
+	}
+	if spec.PruneStaleBranches != nil {
+		s.PruneStaleBranches = spec.PruneStaleBranches
+	}
+	if len(spec.NamespaceGitAuthors) > 0 {
+		s.NamespaceGitAuthors = map[string]string{}
+		for _, author := range spec.NamespaceGitAuthors {
+			s.NamespaceGitAuthors[author.Namespace] = FormatGitAuthor(author.Name, author.Email)
+		}
+	}
+	if len(spec.ExcludeRepositories) > 0 {
+		s.ExcludeRepositories = spec.ExcludeRepositories
+	}
+	if len(spec.IncludeNamespaces) > 0 {
+		s.IncludeNamespaces = spec.IncludeNamespaces
+	}
+	if len(spec.ExcludeNamespaces) > 0 {
+		s.ExcludeNamespaces = spec.ExcludeNamespaces
+	}
+	if len(spec.IncludePaths) > 0 {
+		s.IncludePaths = spec.IncludePaths
+	}
+	s.Templates = spec.Templates
+	s.PostUpgradeTasks = spec.PostUpgradeTasks
+	s.Managers = spec.Managers
+	s.HostRules = spec.HostRules
+	if spec.ConfigOverlayConfigMapName != "" {
+		s.ConfigOverlayConfigMap = spec.ConfigOverlayConfigMapName
+	}
+	if spec.JobSettings.TTLSecondsAfterFinished != nil {
+		s.TTLSecondsAfterFinished = *spec.JobSettings.TTLSecondsAfterFinished
+	}
+	if spec.JobSettings.BackoffLimit != nil {
+		s.BackoffLimit = *spec.JobSettings.BackoffLimit
+	}
+	if spec.JobSettings.Parallelism != nil && *spec.JobSettings.Parallelism > 0 {
+		s.Parallelism = *spec.JobSettings.Parallelism
+	}
+	if spec.JobSettings.RetryLimit != nil {
+		s.RetryLimit = *spec.JobSettings.RetryLimit
+	}
+	if spec.JobSettings.RetryBackoff != nil && spec.JobSettings.RetryBackoff.Duration > 0 {
+		s.RetryBackoff = spec.JobSettings.RetryBackoff.Duration
+	}
+	if resources := spec.JobSettings.Resources; resources != nil {
+		s.Resources = *s.Resources.DeepCopy()
+		for name, quantity := range resources.Requests {
+			s.Resources.Requests[name] = quantity
+		}
+		for name, quantity := range resources.Limits {
+			s.Resources.Limits[name] = quantity
+		}
+	}
+	if len(spec.JobSettings.NodeSelector) > 0 {
+		s.NodeSelector = spec.JobSettings.NodeSelector
+	}
+	if len(spec.JobSettings.Tolerations) > 0 {
+		s.Tolerations = spec.JobSettings.Tolerations
+	}
+	if spec.JobSettings.Affinity != nil {
+		s.Affinity = spec.JobSettings.Affinity
+	}
+	if spec.JobSettings.MaxParallelJobs != nil {
+		s.MaxParallelJobs = *spec.JobSettings.MaxParallelJobs
+	}
+	if spec.JobSettings.PriorityClassName != "" {
+		s.PriorityClassName = spec.JobSettings.PriorityClassName
+	}
+	if spec.JobSettings.ServiceAccountName != "" {
+		s.ServiceAccountName = spec.JobSettings.ServiceAccountName
+	}
+	if spec.JobSettings.ImagePullSecrets != nil {
+		s.ImagePullSecrets = spec.JobSettings.ImagePullSecrets
+	}
+	if proxy := spec.JobSettings.Proxy; proxy != nil {
+		if proxy.HttpProxy != "" {
+			s.Proxy.HttpProxy = proxy.HttpProxy
+		}
+		if proxy.HttpsProxy != "" {
+			s.Proxy.HttpsProxy = proxy.HttpsProxy
+		}
+		if proxy.NoProxy != "" {
+			s.Proxy.NoProxy = proxy.NoProxy
+		}
+	}
+	if spec.JobSettings.TrustedCAConfigMapName != "" {
+		s.TrustedCAConfigMapName = spec.JobSettings.TrustedCAConfigMapName
+	}
+	if spec.JobSettings.CosignKeyConfigMapName != "" {
+		s.CosignKeyConfigMapName = spec.JobSettings.CosignKeyConfigMapName
+	}
+	if spec.JobSettings.LogsVolumeClaimName != "" {
+		s.LogsVolumeClaimName = spec.JobSettings.LogsVolumeClaimName
+	}
+	if spec.JobSettings.ExecutionMode != "" {
+		s.ExecutionMode = spec.JobSettings.ExecutionMode
+	}
+	if spec.JobSettings.TaskRunNamespace != "" {
+		s.TaskRunNamespace = spec.JobSettings.TaskRunNamespace
+	}
+	if spec.JobSettings.RunInTenantNamespaces != nil {
+		s.TenantNamespaces = *spec.JobSettings.RunInTenantNamespaces
+	}
+	if len(spec.JobSettings.TenantRepositoryOwners) > 0 {
+		s.TenantRepositoryOwners = map[string][]string{}
+		for _, owners := range spec.JobSettings.TenantRepositoryOwners {
+			s.TenantRepositoryOwners[owners.Namespace] = append(s.TenantRepositoryOwners[owners.Namespace], owners.Owners...)
+		}
+	}
+	if spec.SweepInterval != nil && spec.SweepInterval.Duration >= MinSweepInterval {
+		s.SweepInterval = spec.SweepInterval.Duration
+	}
+	s.SweepSchedule = spec.SweepSchedule
+	s.SuspendSweeps = spec.SuspendSweeps
+	return s
+}
@@ -0,0 +1,468 @@
+package renovate
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	buildappstudiov1alpha1 "github.com/konflux-ci/build-service/api/v1alpha1"
+	. "github.com/konflux-ci/build-service/pkg/common"
+	"github.com/konflux-ci/build-service/pkg/featuregates"
+)
+
+func TestSettings(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      map[string]string
+		config   *buildappstudiov1alpha1.RenovateTektonConfig
+		expected Settings
+	}{
+		{
+			name: "should use defaults",
+			expected: Settings{
+				Image:                   DefaultRenovateImageUrl,
+				MatchPatterns:           []string{DefaultRenovateMatchPattern},
+				TasksPerJob:             TasksPerJob,
+				TTLSecondsAfterFinished: 86400,
+				BackoffLimit:            DefaultBackoffLimit,
+				Parallelism:             DefaultParallelism,
+				StopUpdatingLabel:       DefaultStopUpdatingLabel,
+				RetryLimit:              DefaultRetryLimit,
+				RetryBackoff:            DefaultRetryBackoff,
+				SweepInterval:           DefaultSweepInterval,
+				Resources:               newResources("100m", "512Mi", "1", "2Gi"),
+			},
+		},
+		{
+			name: "should use environment variables",
+			env: map[string]string{
+				RenovateImageEnvName:        "quay.io/renovate:latest",
+				RenovateMatchPatternEnvName: "^quay.io/konflux-ci/, ^registry.internal/konflux-ci/",
+				InstallationsPerJobEnvName:  "5",
+				SweepIntervalEnvName:        "1h",
+				MemoryRequestEnvName:        "1Gi",
+				MemoryLimitEnvName:          "4Gi",
+				CpuLimitEnvName:             "invalid",
+				PriorityClassNameEnvName:    "low-priority",
+				ServiceAccountEnvName:       "renovate",
+				ExecutionModeEnvName:        "TaskRun",
+				TaskRunNamespaceEnvName:     "renovate-runs",
+				TenantNamespacesEnvName:     "true",
+				HttpsProxyEnvName:           "http://proxy:3128",
+				NoProxyEnvName:              ".cluster.local",
+				TrustedCAConfigMapEnvName:   "trusted-ca",
+				CosignKeyConfigMapEnvName:   "cosign-key",
+				LogsVolumeClaimEnvName:      "renovate-logs",
+				ConfigOverlayEnvName:        "renovate-overlay",
+				ArchitectureEnvName:         "arm64",
+				MaxParallelJobsEnvName:      "10",
+				RepositoriesPerJobEnvName:   "50",
+				AutomergeEnvName:            "true",
+				PinDigestsEnvName:           "true",
+				GroupAllUpdatesEnvName:      "true",
+				DryRunEnvName:               "true",
+				PRHourlyLimitEnvName:        "0",
+				PRConcurrentLimitEnvName:    "invalid",
+				StopUpdatingLabelEnvName:    "keep-changes",
+				GitAuthorEnvName:            " Konflux Bot <konflux@example.com> ",
+				BranchPrefixEnvName:         "konflux/",
+				RebaseWhenEnvName:           "conflicted",
+				PruneStaleBranchesEnvName:   "false",
+				IncludePathsEnvName:         ".tekton/**, ci/tekton/**,",
+				ExcludeRepositoriesEnvName:  "^org/archived-,^org/frozen$",
+				IncludeNamespacesEnvName:    "tenant1,tenant2",
+				ExcludeNamespacesEnvName:    "tenant2",
+				RetryLimitEnvName:           "5",
+				RetryBackoffEnvName:         "1m",
+				ParallelismEnvName:          "4",
+			},
+			expected: Settings{
+				Image:                   "quay.io/renovate:latest",
+				MatchPatterns:           []string{"^quay.io/konflux-ci/", "^registry.internal/konflux-ci/"},
+				TasksPerJob:             5,
+				TTLSecondsAfterFinished: 86400,
+				BackoffLimit:            DefaultBackoffLimit,
+				Parallelism:             4,
+				StopUpdatingLabel:       "keep-changes",
+				GitAuthor:               "Konflux Bot <konflux@example.com>",
+				BranchPrefix:            "konflux/",
+				RebaseWhen:              "conflicted",
+				PruneStaleBranches:      ptr.To(false),
+				RetryLimit:              5,
+				RetryBackoff:            time.Minute,
+				SweepInterval:           time.Hour,
+				Resources:               newResources("100m", "1Gi", "1", "4Gi"),
+				PriorityClassName:       "low-priority",
+				ServiceAccountName:      "renovate",
+				ExecutionMode:           ExecutionModeTaskRun,
+				TaskRunNamespace:        "renovate-runs",
+				TenantNamespaces:        true,
+				Proxy:                   buildappstudiov1alpha1.RenovateProxy{HttpsProxy: "http://proxy:3128", NoProxy: ".cluster.local"},
+				TrustedCAConfigMapName:  "trusted-ca",
+				CosignKeyConfigMapName:  "cosign-key",
+				LogsVolumeClaimName:     "renovate-logs",
+				ConfigOverlayConfigMap:  "renovate-overlay",
+				Architecture:            "arm64",
+				MaxParallelJobs:         10,
+				RepositoriesPerJob:      50,
+				Automerge:               true,
+				PinDigests:              true,
+				GroupAllUpdates:         true,
+				DryRun:                  true,
+				PRHourlyLimit:           ptr.To(0),
+				IncludePaths:            []string{".tekton/**", "ci/tekton/**"},
+				ExcludeRepositories:     []string{"^org/archived-", "^org/frozen$"},
+				IncludeNamespaces:       []string{"tenant1", "tenant2"},
+				ExcludeNamespaces:       []string{"tenant2"},
+			},
+		},
+		{
+			name: "should override environment variables by RenovateTektonConfig",
+			env: map[string]string{
+				RenovateImageEnvName:       "quay.io/renovate:latest",
+				InstallationsPerJobEnvName: "5",
+				RepositoriesPerJobEnvName:  "50",
+				IncludePathsEnvName:        ".tekton/**",
+				ExecutionModeEnvName:       "TaskRun",
+				HttpProxyEnvName:           "http://proxy:3128",
+				NoProxyEnvName:             ".cluster.local",
+				PinDigestsEnvName:          "true",
+				DryRunEnvName:              "true",
+				PRHourlyLimitEnvName:       "0",
+				ConfigOverlayEnvName:       "renovate-overlay",
+				ParallelismEnvName:         "4",
+				TenantNamespacesEnvName:    "true",
+			},
+			config: &buildappstudiov1alpha1.RenovateTektonConfig{
+				Spec: buildappstudiov1alpha1.RenovateTektonConfigSpec{
+					Image:               "quay.io/renovate:v38",
+					Architecture:        "arm64",
+					ArchitectureImages:  map[string]string{"arm64": "quay.io/renovate:v38-arm64"},
+					MatchPattern:        "^quay.io/konflux-ci/",
+					InstallationsPerJob: 10,
+					RepositoriesPerJob:  100,
+					Schedule:            []string{"every weekend"},
+					Automerge:           ptr.To(true),
+					PinDigests:          ptr.To(false),
+					GroupAllUpdates:     ptr.To(true),
+					DryRun:              ptr.To(false),
+					PRHourlyLimit:       ptr.To(2),
+					PRConcurrentLimit:   ptr.To(5),
+					StopUpdatingLabel:   "frozen",
+					GitAuthor:           &buildappstudiov1alpha1.RenovateGitAuthor{Name: "Konflux", Email: "konflux@example.com"},
+					BranchPrefix:        "deps/",
+					RebaseWhen:          "never",
+					PruneStaleBranches:  ptr.To(true),
+					NamespaceGitAuthors: []buildappstudiov1alpha1.RenovateNamespaceGitAuthor{
+						{Namespace: "tenant1", RenovateGitAuthor: buildappstudiov1alpha1.RenovateGitAuthor{Name: "Tenant Bot", Email: "bot@tenant1.example.com"}},
+					},
+					IncludePaths:     []string{"ci/tekton/**"},
+					Templates:        buildappstudiov1alpha1.RenovateTemplates{CommitMessagePrefix: "chore(deps):"},
+					PostUpgradeTasks: &buildappstudiov1alpha1.RenovatePostUpgradeTasks{Commands: []string{"make fmt"}},
+					JobSettings: buildappstudiov1alpha1.RenovateJobSettings{
+						TTLSecondsAfterFinished: ptr.To(int32(3600)),
+						BackoffLimit:            ptr.To(int32(0)),
+						Parallelism:             ptr.To(int32(2)),
+						RetryLimit:              ptr.To(int32(0)),
+						RetryBackoff:            &metav1.Duration{Duration: time.Minute},
+						Resources: &corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m")},
+							Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("3Gi")},
+						},
+						NodeSelector:           map[string]string{"node-role.kubernetes.io/infra": ""},
+						Tolerations:            []corev1.Toleration{{Key: "infra", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule}},
+						Affinity:               &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{}},
+						PriorityClassName:      "renovate",
+						ServiceAccountName:     "renovate-sa",
+						ImagePullSecrets:       []corev1.LocalObjectReference{{Name: "renovate-pull-secret"}},
+						MaxParallelJobs:        ptr.To(3),
+						ExecutionMode:          ExecutionModeJob,
+						RunInTenantNamespaces:  ptr.To(false),
+						TenantRepositoryOwners: []buildappstudiov1alpha1.RenovateTenantRepositoryOwners{{Namespace: "tenant1", Owners: []string{"org1", "org2"}}},
+						Proxy:                  &buildappstudiov1alpha1.RenovateProxy{HttpsProxy: "http://secure-proxy:3128"},
+						TrustedCAConfigMapName: "renovate-ca",
+						CosignKeyConfigMapName: "renovate-cosign-key",
+						LogsVolumeClaimName:    "renovate-job-logs",
+					},
+					ConfigOverlayConfigMapName: "renovate-config-overlay",
+					SweepInterval:              &metav1.Duration{Duration: 24 * time.Hour},
+					SweepSchedule:              "0 2 * * *",
+					SuspendSweeps:              true,
+				},
+			},
+			expected: Settings{
+				Image:                   "quay.io/renovate:v38",
+				Architecture:            "arm64",
+				ArchitectureImages:      map[string]string{"arm64": "quay.io/renovate:v38-arm64"},
+				MatchPatterns:           []string{"^quay.io/konflux-ci/"},
+				TasksPerJob:             10,
+				RepositoriesPerJob:      100,
+				Schedule:                []string{"every weekend"},
+				Automerge:               true,
+				GroupAllUpdates:         true,
+				PRHourlyLimit:           ptr.To(2),
+				PRConcurrentLimit:       ptr.To(5),
+				IncludePaths:            []string{"ci/tekton/**"},
+				Templates:               buildappstudiov1alpha1.RenovateTemplates{CommitMessagePrefix: "chore(deps):"},
+				PostUpgradeTasks:        &buildappstudiov1alpha1.RenovatePostUpgradeTasks{Commands: []string{"make fmt"}},
+				ConfigOverlayConfigMap:  "renovate-config-overlay",
+				TTLSecondsAfterFinished: 3600,
+				BackoffLimit:            0,
+				Parallelism:             2,
+				StopUpdatingLabel:       "frozen",
+				GitAuthor:               "Konflux <konflux@example.com>",
+				BranchPrefix:            "deps/",
+				RebaseWhen:              "never",
+				PruneStaleBranches:      ptr.To(true),
+				NamespaceGitAuthors:     map[string]string{"tenant1": "Tenant Bot <bot@tenant1.example.com>"},
+				RetryLimit:              0,
+				RetryBackoff:            time.Minute,
+				SweepInterval:           24 * time.Hour,
+				Resources:               newResources("500m", "512Mi", "1", "3Gi"),
+				NodeSelector:            map[string]string{"node-role.kubernetes.io/infra": ""},
+				Tolerations:             []corev1.Toleration{{Key: "infra", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule}},
+				Affinity:                &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{}},
+				PriorityClassName:       "renovate",
+				ServiceAccountName:      "renovate-sa",
+				ImagePullSecrets:        []corev1.LocalObjectReference{{Name: "renovate-pull-secret"}},
+				MaxParallelJobs:         3,
+				ExecutionMode:           ExecutionModeJob,
+				TenantRepositoryOwners:  map[string][]string{"tenant1": {"org1", "org2"}},
+				Proxy:                   buildappstudiov1alpha1.RenovateProxy{HttpProxy: "http://proxy:3128", HttpsProxy: "http://secure-proxy:3128", NoProxy: ".cluster.local"},
+				TrustedCAConfigMapName:  "renovate-ca",
+				CosignKeyConfigMapName:  "renovate-cosign-key",
+				LogsVolumeClaimName:     "renovate-job-logs",
+				SweepSchedule:           "0 2 * * *",
+				SuspendSweeps:           true,
+			},
+		},
+		{
+			name: "should keep environment variables if RenovateTektonConfig is empty",
+			env: map[string]string{
+				RenovateImageEnvName: "quay.io/renovate:latest",
+			},
+			config: &buildappstudiov1alpha1.RenovateTektonConfig{},
+			expected: Settings{
+				Image:                   "quay.io/renovate:latest",
+				MatchPatterns:           []string{DefaultRenovateMatchPattern},
+				TasksPerJob:             TasksPerJob,
+				TTLSecondsAfterFinished: 86400,
+				BackoffLimit:            DefaultBackoffLimit,
+				Parallelism:             DefaultParallelism,
+				StopUpdatingLabel:       DefaultStopUpdatingLabel,
+				RetryLimit:              DefaultRetryLimit,
+				RetryBackoff:            DefaultRetryBackoff,
+				SweepInterval:           DefaultSweepInterval,
+				Resources:               newResources("100m", "512Mi", "1", "2Gi"),
+			},
+		},
+		{
+			name: "should prefer match patterns list in RenovateTektonConfig",
+			env: map[string]string{
+				RenovateMatchPatternEnvName: "^quay.io/konflux-ci/",
+			},
+			config: &buildappstudiov1alpha1.RenovateTektonConfig{
+				Spec: buildappstudiov1alpha1.RenovateTektonConfigSpec{
+					MatchPattern:  "^quay.io/ignored/",
+					MatchPatterns: []string{DefaultRenovateMatchPattern, "^registry.internal/konflux-ci/"},
+				},
+			},
+			expected: Settings{
+				Image:                   DefaultRenovateImageUrl,
+				MatchPatterns:           []string{DefaultRenovateMatchPattern, "^registry.internal/konflux-ci/"},
+				TasksPerJob:             TasksPerJob,
+				TTLSecondsAfterFinished: 86400,
+				BackoffLimit:            DefaultBackoffLimit,
+				Parallelism:             DefaultParallelism,
+				StopUpdatingLabel:       DefaultStopUpdatingLabel,
+				RetryLimit:              DefaultRetryLimit,
+				RetryBackoff:            DefaultRetryBackoff,
+				SweepInterval:           DefaultSweepInterval,
+				Resources:               newResources("100m", "512Mi", "1", "2Gi"),
+			},
+		},
+		{
+			name: "should ignore too short sweep interval",
+			env: map[string]string{
+				SweepIntervalEnvName: "1m",
+			},
+			config: &buildappstudiov1alpha1.RenovateTektonConfig{
+				Spec: buildappstudiov1alpha1.RenovateTektonConfigSpec{
+					SweepInterval: &metav1.Duration{Duration: time.Second},
+				},
+			},
+			expected: Settings{
+				Image:                   DefaultRenovateImageUrl,
+				MatchPatterns:           []string{DefaultRenovateMatchPattern},
+				TasksPerJob:             TasksPerJob,
+				TTLSecondsAfterFinished: 86400,
+				BackoffLimit:            DefaultBackoffLimit,
+				Parallelism:             DefaultParallelism,
+				StopUpdatingLabel:       DefaultStopUpdatingLabel,
+				RetryLimit:              DefaultRetryLimit,
+				RetryBackoff:            DefaultRetryBackoff,
+				SweepInterval:           DefaultSweepInterval,
+				Resources:               newResources("100m", "512Mi", "1", "2Gi"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, name := range []string{RenovateImageEnvName, RenovateMatchPatternEnvName, InstallationsPerJobEnvName, SweepIntervalEnvName,
+				CpuRequestEnvName, MemoryRequestEnvName, CpuLimitEnvName, MemoryLimitEnvName, PriorityClassNameEnvName, ServiceAccountEnvName, MaxParallelJobsEnvName, AutomergeEnvName, IncludePathsEnvName, PinDigestsEnvName, GroupAllUpdatesEnvName, DryRunEnvName, PRHourlyLimitEnvName, PRConcurrentLimitEnvName, StopUpdatingLabelEnvName, GitAuthorEnvName, BranchPrefixEnvName, RebaseWhenEnvName, PruneStaleBranchesEnvName, ExcludeRepositoriesEnvName,
+				IncludeNamespacesEnvName, ExcludeNamespacesEnvName, ExecutionModeEnvName, TaskRunNamespaceEnvName,
+				HttpProxyEnvName, HttpsProxyEnvName, NoProxyEnvName, TrustedCAConfigMapEnvName, ArchitectureEnvName, CosignKeyConfigMapEnvName,
+				LogsVolumeClaimEnvName, RetryLimitEnvName, RetryBackoffEnvName, ConfigOverlayEnvName, RepositoriesPerJobEnvName, ParallelismEnvName, TenantNamespacesEnvName} {
+				t.Setenv(name, tt.env[name])
+			}
+			assert.Equal(t, tt.expected, NewSettingsFromEnv().WithConfig(tt.config))
+		})
+	}
+}
+
+func newResources(cpuRequest, memoryRequest, cpuLimit, memoryLimit string) corev1.ResourceRequirements {
+	return corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse(cpuRequest),
+			corev1.ResourceMemory: resource.MustParse(memoryRequest),
+		},
+		Limits: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse(cpuLimit),
+			corev1.ResourceMemory: resource.MustParse(memoryLimit),
+		},
+	}
+}
+
+func TestSettingsValidate(t *testing.T) {
+	assert.NoError(t, NewSettingsFromEnv().Validate())
+	assert.NoError(t, Settings{Templates: buildappstudiov1alpha1.RenovateTemplates{PRTitle: "Update {{depName}}"}}.Validate())
+	assert.Error(t, Settings{MatchPatterns: []string{DefaultRenovateMatchPattern, "^quay.io/it's/"}}.Validate())
+	assert.Error(t, Settings{Templates: buildappstudiov1alpha1.RenovateTemplates{CommitMessageTopic: "{{depName}} }}"}}.Validate())
+	assert.NoError(t, Settings{PostUpgradeTasks: &buildappstudiov1alpha1.RenovatePostUpgradeTasks{AllowedCommands: []string{"^make (fmt|generate)$"}}}.Validate())
+	assert.Error(t, Settings{PostUpgradeTasks: &buildappstudiov1alpha1.RenovatePostUpgradeTasks{AllowedCommands: []string{"^make (fmt"}}}.Validate())
+	assert.NoError(t, Settings{GitAuthor: "Konflux <konflux@example.com>", NamespaceGitAuthors: map[string]string{"tenant1": "Tenant Bot <bot@tenant1.example.com>"}}.Validate())
+	assert.Error(t, Settings{GitAuthor: "konflux@example.com"}.Validate())
+	assert.Error(t, Settings{NamespaceGitAuthors: map[string]string{"tenant1": "Tenant Bot <bot>"}}.Validate())
+	assert.NoError(t, Settings{BranchPrefix: "konflux/"}.Validate())
+	assert.Error(t, Settings{BranchPrefix: "my deps/"}.Validate())
+	assert.Error(t, Settings{BranchPrefix: "/"}.Validate())
+	// The last retry starts 5+10+20 minutes after the first attempt
+	assert.NoError(t, Settings{RetryBackoff: 5 * time.Minute, RetryLimit: 3}.Validate())
+	assert.Error(t, Settings{RetryBackoff: 5 * time.Minute, RetryLimit: 4}.Validate())
+	assert.NoError(t, Settings{RetryBackoff: 24 * time.Hour, RetryLimit: 0}.Validate())
+}
+
+func TestIsRepositoryExcluded(t *testing.T) {
+	settings := Settings{ExcludeRepositories: []string{"^org/archived-", "^org/frozen$", "[invalid"}}
+	assert.True(t, settings.IsRepositoryExcluded("org/archived-repo"))
+	assert.True(t, settings.IsRepositoryExcluded("org/frozen"))
+	assert.False(t, settings.IsRepositoryExcluded("org/frozen-not"))
+	assert.False(t, settings.IsRepositoryExcluded("other/archived-repo"))
+	assert.False(t, Settings{}.IsRepositoryExcluded("org/repo"))
+}
+
+func TestIsNamespaceIncluded(t *testing.T) {
+	assert.True(t, Settings{}.IsNamespaceIncluded("tenant"))
+	assert.False(t, Settings{ExcludeNamespaces: []string{"staging"}}.IsNamespaceIncluded("staging"))
+	assert.True(t, Settings{ExcludeNamespaces: []string{"staging"}}.IsNamespaceIncluded("tenant"))
+
+	settings := Settings{IncludeNamespaces: []string{"tenant1", "tenant2"}, ExcludeNamespaces: []string{"tenant2"}}
+	assert.True(t, settings.IsNamespaceIncluded("tenant1"))
+	assert.False(t, settings.IsNamespaceIncluded("tenant2"))
+	assert.False(t, settings.IsNamespaceIncluded("tenant3"))
+}
+
+func TestRunNamespace(t *testing.T) {
+	assert.Equal(t, BuildServiceNamespaceName, Settings{}.RunNamespace())
+	assert.Equal(t, BuildServiceNamespaceName, Settings{TaskRunNamespace: "renovate-runs"}.RunNamespace())
+	assert.Equal(t, BuildServiceNamespaceName, Settings{ExecutionMode: ExecutionModeTaskRun}.RunNamespace())
+	assert.Equal(t, "renovate-runs", Settings{ExecutionMode: ExecutionModeTaskRun, TaskRunNamespace: "renovate-runs"}.RunNamespace())
+}
+
+func TestJobServiceAccountName(t *testing.T) {
+	assert.Equal(t, RenovateJobServiceAccountName, Settings{}.JobServiceAccountName())
+	assert.Equal(t, "", Settings{ExecutionMode: ExecutionModeTaskRun}.JobServiceAccountName())
+	assert.Equal(t, "renovate", Settings{ExecutionMode: ExecutionModeTaskRun, ServiceAccountName: "renovate"}.JobServiceAccountName())
+}
+
+func TestListNamespace(t *testing.T) {
+	assert.Equal(t, BuildServiceNamespaceName, Settings{TenantNamespaces: true}.ListNamespace())
+	assert.Equal(t, "renovate-runs", Settings{ExecutionMode: ExecutionModeTaskRun, TaskRunNamespace: "renovate-runs"}.ListNamespace())
+	settings := Settings{ExecutionMode: ExecutionModeTaskRun, TaskRunNamespace: "renovate-runs", TenantNamespaces: true}
+	assert.True(t, settings.IsTenantScoped())
+	assert.Equal(t, "", settings.ListNamespace())
+}
+
+func TestIsTenantRepositoryOwner(t *testing.T) {
+	settings := Settings{TenantRepositoryOwners: map[string][]string{"tenant1": {"My-Org"}}}
+	assert.True(t, settings.IsTenantRepositoryOwner("tenant1", "my-org"))
+	assert.False(t, settings.IsTenantRepositoryOwner("tenant1", "other-org"))
+	assert.False(t, settings.IsTenantRepositoryOwner("tenant2", "my-org"))
+}
+
+func TestProxyEnv(t *testing.T) {
+	assert.Empty(t, Settings{}.ProxyEnv())
+	settings := Settings{Proxy: buildappstudiov1alpha1.RenovateProxy{HttpsProxy: "http://proxy:3128", NoProxy: ".cluster.local"}}
+	assert.Equal(t, []corev1.EnvVar{
+		{Name: "HTTPS_PROXY", Value: "http://proxy:3128"},
+		{Name: "https_proxy", Value: "http://proxy:3128"},
+		{Name: "NO_PROXY", Value: ".cluster.local"},
+		{Name: "no_proxy", Value: ".cluster.local"},
+	}, settings.ProxyEnv())
+}
+
+func TestRenovateImage(t *testing.T) {
+	settings := Settings{Image: "quay.io/renovate:v38", ArchitectureImages: map[string]string{"arm64": "quay.io/renovate:v38-arm64"}}
+	assert.Equal(t, "quay.io/renovate:v38", settings.RenovateImage())
+	settings.Architecture = "amd64"
+	assert.Equal(t, "quay.io/renovate:v38", settings.RenovateImage())
+	settings.Architecture = "arm64"
+	assert.Equal(t, "quay.io/renovate:v38-arm64", settings.RenovateImage())
+}
+
+func TestPodAffinity(t *testing.T) {
+	archRequirement := corev1.NodeSelectorRequirement{Key: "kubernetes.io/arch", Operator: corev1.NodeSelectorOpIn, Values: []string{"arm64"}}
+	zoneRequirement := corev1.NodeSelectorRequirement{Key: "topology.kubernetes.io/zone", Operator: corev1.NodeSelectorOpIn, Values: []string{"a"}}
+	preferred := []corev1.PreferredSchedulingTerm{{Weight: 1, Preference: corev1.NodeSelectorTerm{MatchExpressions: []corev1.NodeSelectorRequirement{zoneRequirement}}}}
+
+	assert.Nil(t, Settings{}.PodAffinity())
+	affinity := &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{PreferredDuringSchedulingIgnoredDuringExecution: preferred}}
+	assert.Equal(t, affinity, Settings{Affinity: affinity}.PodAffinity())
+
+	assert.Equal(t, &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+		RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{
+			{MatchExpressions: []corev1.NodeSelectorRequirement{archRequirement}},
+		}},
+	}}, Settings{Architecture: "arm64"}.PodAffinity())
+
+	affinity = &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+		RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{
+			{MatchExpressions: []corev1.NodeSelectorRequirement{zoneRequirement}},
+			{MatchFields: []corev1.NodeSelectorRequirement{{Key: "metadata.name", Operator: corev1.NodeSelectorOpIn, Values: []string{"node"}}}},
+		}},
+		PreferredDuringSchedulingIgnoredDuringExecution: preferred,
+	}}
+	got := Settings{Architecture: "arm64", Affinity: affinity}.PodAffinity()
+	terms := got.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+	assert.Equal(t, []corev1.NodeSelectorRequirement{zoneRequirement, archRequirement}, terms[0].MatchExpressions)
+	assert.Equal(t, []corev1.NodeSelectorRequirement{archRequirement}, terms[1].MatchExpressions)
+	assert.Equal(t, preferred, got.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution)
+	// The configured affinity is not modified
+	assert.Len(t, affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms[0].MatchExpressions, 1)
+}
+
+func TestArchitectureIgnoredWhenMultiArchDisabled(t *testing.T) {
+	featuregates.SetOverrides(map[string]bool{string(featuregates.MultiArch): false})
+	defer featuregates.SetOverrides(nil)
+
+	affinity := &corev1.Affinity{PodAntiAffinity: &corev1.PodAntiAffinity{}}
+	settings := Settings{Image: "quay.io/renovate:v38", ArchitectureImages: map[string]string{"arm64": "quay.io/renovate:v38-arm64"}, Architecture: "arm64", Affinity: affinity}
+	assert.Equal(t, "quay.io/renovate:v38", settings.RenovateImage())
+	assert.Equal(t, affinity, settings.PodAffinity())
+}
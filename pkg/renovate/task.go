@@ -2,6 +2,11 @@ package renovate
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/konflux-ci/build-service/pkg/git"
 	"github.com/konflux-ci/build-service/pkg/git/credentials"
@@ -15,6 +20,8 @@ type Task struct {
 	Token        string
 	Endpoint     string
 	Repositories []*Repository
+	// Namespace the task is executed in when renovate runs in the tenant namespaces, empty otherwise
+	Namespace string
 }
 
 // AddNewBranchToTheExistedRepositoryTasksOnTheSameHosts iterates over the tasks and adds a new branch to the repository if it already exists
@@ -24,6 +31,7 @@ func AddNewBranchToTheExistedRepositoryTasksOnTheSameHosts(tasks []*Task, compon
 		for _, r := range t.Repositories {
 			if r.Repository == component.Repository() {
 				r.AddBranch(component.Branch())
+				r.AddComponentSettings(component)
 				return true
 			}
 		}
@@ -42,10 +50,12 @@ func AddNewRepoToTasksOnTheSameHostsWithSameCredentials(tasks []*Task, component
 					return true
 				}
 			}
-			t.Repositories = append(t.Repositories, &Repository{
+			repository := &Repository{
 				Repository:   component.Repository(),
 				BaseBranches: []string{component.Branch()},
-			})
+			}
+			repository.AddComponentSettings(component)
+			t.Repositories = append(t.Repositories, repository)
 			return true
 		}
 	}
@@ -57,6 +67,67 @@ type TaskProvider interface {
 	GetNewTasks(ctx context.Context, components []*git.ScmComponent) []*Task
 }
 
-func (t *Task) JobConfig() JobConfig {
-	return NewTektonJobConfig(t.Platform, t.Endpoint, t.Username, t.GitAuthor, t.Repositories)
+// BackoffTaskProvider is implemented by task providers which may leave out some tasks, e.g. because of API rate limits.
+// RetryAfter returns the delay after which the tasks left out by the last GetNewTasks call can be provided,
+// or zero if all tasks were provided.
+type BackoffTaskProvider interface {
+	RetryAfter() time.Duration
+}
+
+// FailureReportingTaskProvider is implemented by task providers which may leave out some tasks because of errors,
+// e.g. installations whose repositories couldn't be listed. Failures returns the number of tasks
+// left out by the last GetNewTasks call.
+type FailureReportingTaskProvider interface {
+	Failures() int
+}
+
+// TenantScopedTaskProvider is implemented by task providers which can provide tasks executed in the namespaces of the Components.
+// TenantScoped returns the task provider whose tasks have the namespace set and credentials which are valid only
+// for the repositories of the namespace, so they can be handed over to the tenant.
+type TenantScopedTaskProvider interface {
+	TenantScoped(settings Settings) TaskProvider
+}
+
+// CredentialsEnvName returns the name of the environment variable used to pass the task credentials to renovate.
+// Bitbucket Cloud app passwords work only together with the username, so they are passed as a password instead of a token.
+func (t *Task) CredentialsEnvName() string {
+	if t.Platform == "bitbucket" {
+		return "RENOVATE_PASSWORD"
+	}
+	return "RENOVATE_TOKEN"
+}
+
+// Key identifies the task by its platform, endpoint, user and repositories, the token is not part of the key.
+// Tasks of the same GitHub App installation or with the same credentials have the same key across sweeps,
+// as long as the set of their repositories doesn't change. The namespace is part of the key only if it's set,
+// so the keys of the tasks executed in the build-service namespace are not affected.
+func (t *Task) Key() string {
+	var repositories []string
+	for _, r := range t.Repositories {
+		repositories = append(repositories, r.Repository)
+	}
+	sort.Strings(repositories)
+	fields := []string{t.Platform, t.Endpoint, t.Username, t.GitAuthor}
+	if t.Namespace != "" {
+		fields = append(fields, t.Namespace)
+	}
+	hash := sha256.Sum256([]byte(strings.Join(append(fields, repositories...), "\n")))
+	return hex.EncodeToString(hash[:8])
+}
+
+// HasComponentRepository returns true if the task updates the repository of the given component.
+func (t *Task) HasComponentRepository(component *git.ScmComponent) bool {
+	if t.Platform != component.Platform() {
+		return false
+	}
+	for _, r := range t.Repositories {
+		if r.Repository == component.Repository() {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *Task) JobConfig(settings Settings) JobConfig {
+	return NewTektonJobConfig(t.Platform, t.Endpoint, t.Username, t.GitAuthor, t.Repositories, settings)
 }